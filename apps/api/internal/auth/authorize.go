@@ -0,0 +1,83 @@
+package auth
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"slices"
+
+	"github.com/max-cloud/shared/pkg/models"
+)
+
+// ErrPermissionDenied wird zurückgegeben, wenn Rolle oder API-Key-Scopes eine Berechtigung nicht erlauben.
+var ErrPermissionDenied = errors.New("permission denied")
+
+// RoleResolver ist das Interface das Require zum Nachschlagen der Rolle braucht.
+type RoleResolver interface {
+	GetAuthInfo(ctx context.Context, orgID, userID string) (*models.AuthInfo, error)
+}
+
+// Require erstellt eine HTTP-Middleware, die die Berechtigung perm anhand der
+// Berechtigungsmatrix der Rolle und der Scopes des API-Keys erzwingt. Die Rolle wird aus
+// dem Context übernommen (z.B. Dev-Mode) oder über den Resolver nachgeschlagen und im
+// Context abgelegt.
+func Require(logger *slog.Logger, resolver RoleResolver, perm models.Permission) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			role, err := Authorize(r.Context(), resolver, perm)
+			if err != nil {
+				if errors.Is(err, ErrPermissionDenied) {
+					http.Error(w, `{"error":"`+err.Error()+`"}`, http.StatusForbidden)
+					return
+				}
+				logger.Error("failed to resolve role", "error", err)
+				http.Error(w, `{"error":"internal server error"}`, http.StatusInternalServerError)
+				return
+			}
+			next.ServeHTTP(w, r.WithContext(WithRole(r.Context(), role)))
+		})
+	}
+}
+
+// Authorize prüft, ob Rolle und API-Key-Scopes des Benutzers die Berechtigung erlauben,
+// und gibt die Rolle zurück. Fehlt die Berechtigung, wird ErrPermissionDenied gewrappt.
+func Authorize(ctx context.Context, resolver RoleResolver, perm models.Permission) (models.OrgRole, error) {
+	role, err := ResolveRole(ctx, resolver)
+	if err != nil {
+		return "", err
+	}
+	if !role.Can(perm) {
+		return role, fmt.Errorf("%w: role %s lacks %s", ErrPermissionDenied, role, perm)
+	}
+	if !ScopeAllows(ctx, perm) {
+		return role, fmt.Errorf("%w: api key lacks scope %s", ErrPermissionDenied, perm)
+	}
+	return role, nil
+}
+
+// ScopeAllows prüft, ob der API-Key im Context die Berechtigung umfasst. Keys ohne
+// Scopes und Requests ohne API-Key (Dev-Mode) sind nicht eingeschränkt.
+func ScopeAllows(ctx context.Context, perm models.Permission) bool {
+	key, ok := APIKeyFromContext(ctx)
+	if !ok || len(key.Scopes) == 0 {
+		return true
+	}
+	return slices.Contains(key.Scopes, perm)
+}
+
+// ResolveRole gibt die Rolle des Benutzers aus dem Context zurück oder schlägt sie
+// über den Resolver nach, falls keine gesetzt ist.
+func ResolveRole(ctx context.Context, resolver RoleResolver) (models.OrgRole, error) {
+	if role, ok := RoleFromContext(ctx); ok {
+		return role, nil
+	}
+	orgID, _ := OrgIDFromContext(ctx)
+	userID, _ := UserIDFromContext(ctx)
+	info, err := resolver.GetAuthInfo(ctx, orgID, userID)
+	if err != nil {
+		return "", fmt.Errorf("getting auth info: %w", err)
+	}
+	return info.Role, nil
+}
@@ -0,0 +1,124 @@
+package auth
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/max-cloud/shared/pkg/models"
+)
+
+type mockResolver struct {
+	role  models.OrgRole
+	err   error
+	calls int
+}
+
+func (m *mockResolver) GetAuthInfo(_ context.Context, _, _ string) (*models.AuthInfo, error) {
+	m.calls++
+	if m.err != nil {
+		return nil, m.err
+	}
+	return &models.AuthInfo{Role: m.role}, nil
+}
+
+func roleHandler() http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		role, _ := RoleFromContext(r.Context())
+		w.Write([]byte("ok:" + string(role)))
+	})
+}
+
+func TestRequire(t *testing.T) {
+	tests := []struct {
+		name string
+		role models.OrgRole
+		perm models.Permission
+		code int
+	}{
+		{"owner manages org", models.OrgRoleOwner, models.PermissionManageOrg, http.StatusOK},
+		{"admin cannot manage org", models.OrgRoleAdmin, models.PermissionManageOrg, http.StatusForbidden},
+		{"developer deploys", models.OrgRoleDeveloper, models.PermissionDeploy, http.StatusOK},
+		{"developer cannot delete", models.OrgRoleDeveloper, models.PermissionDelete, http.StatusForbidden},
+		{"viewer reads logs", models.OrgRoleViewer, models.PermissionReadLogs, http.StatusOK},
+		{"viewer cannot push", models.OrgRoleViewer, models.PermissionPushImages, http.StatusForbidden},
+		{"unknown role", models.OrgRole("member"), models.PermissionDeploy, http.StatusForbidden},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			handler := Require(slog.Default(), &mockResolver{role: tt.role}, tt.perm)(roleHandler())
+
+			req := httptest.NewRequest("GET", "/test", nil)
+			req = req.WithContext(WithTenant(req.Context(), "org-1", "user-1"))
+			w := httptest.NewRecorder()
+			handler.ServeHTTP(w, req)
+
+			if w.Code != tt.code {
+				t.Fatalf("expected %d, got %d: %s", tt.code, w.Code, w.Body.String())
+			}
+			if tt.code == http.StatusOK && w.Body.String() != "ok:"+string(tt.role) {
+				t.Fatalf("expected role in context, got %s", w.Body.String())
+			}
+		})
+	}
+}
+
+func TestRequireAPIKeyScopes(t *testing.T) {
+	key := &models.APIKeyInfo{Scopes: []models.Permission{models.PermissionDeploy}}
+	tests := []struct {
+		name string
+		key  *models.APIKeyInfo
+		perm models.Permission
+		code int
+	}{
+		{"scope granted", key, models.PermissionDeploy, http.StatusOK},
+		{"scope missing", key, models.PermissionDelete, http.StatusForbidden},
+		{"unscoped key", &models.APIKeyInfo{}, models.PermissionDelete, http.StatusOK},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			handler := Require(slog.Default(), &mockResolver{role: models.OrgRoleOwner}, tt.perm)(roleHandler())
+
+			req := httptest.NewRequest("GET", "/test", nil)
+			req = req.WithContext(WithAPIKey(WithTenant(req.Context(), "org-1", "user-1"), tt.key))
+			w := httptest.NewRecorder()
+			handler.ServeHTTP(w, req)
+
+			if w.Code != tt.code {
+				t.Fatalf("expected %d, got %d: %s", tt.code, w.Code, w.Body.String())
+			}
+		})
+	}
+}
+
+func TestRequireRoleFromContext(t *testing.T) {
+	resolver := &mockResolver{err: errors.New("not a member")}
+	handler := Require(slog.Default(), resolver, models.PermissionDelete)(roleHandler())
+
+	req := httptest.NewRequest("GET", "/test", nil)
+	req = req.WithContext(WithRole(WithTenant(req.Context(), "dev-org", "dev-user"), models.OrgRoleOwner))
+	w := httptest.NewRecorder()
+	handler.ServeHTTP(w, req)
+
+	if w.Code != http.StatusOK {
+		t.Fatalf("expected 200, got %d: %s", w.Code, w.Body.String())
+	}
+	if resolver.calls != 0 {
+		t.Fatalf("expected no lookup when role is in context, got %d", resolver.calls)
+	}
+}
+
+func TestRequireResolverError(t *testing.T) {
+	handler := Require(slog.Default(), &mockResolver{err: errors.New("db down")}, models.PermissionDeploy)(roleHandler())
+
+	req := httptest.NewRequest("GET", "/test", nil)
+	w := httptest.NewRecorder()
+	handler.ServeHTTP(w, req)
+
+	if w.Code != http.StatusInternalServerError {
+		t.Fatalf("expected 500, got %d", w.Code)
+	}
+}
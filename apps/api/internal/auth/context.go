@@ -1,12 +1,18 @@
 package auth
 
-import "context"
+import (
+	"context"
+
+	"github.com/max-cloud/shared/pkg/models"
+)
 
 type contextKey int
 
 const (
 	orgIDKey  contextKey = iota
 	userIDKey
+	roleKey
+	apiKeyKey
 )
 
 // WithTenant reichert den Context mit Tenant-Informationen an.
@@ -29,3 +35,49 @@ func UserIDFromContext(ctx context.Context) (string, bool) {
 	v, ok := ctx.Value(userIDKey).(string)
 	return v, ok
 }
+
+// WithRole reichert den Context mit der Rolle des Benutzers in der Organisation an.
+func WithRole(ctx context.Context, role models.OrgRole) context.Context {
+	return context.WithValue(ctx, roleKey, role)
+}
+
+// RoleFromContext gibt die Rolle aus dem Context zurück.
+// Gibt "", false zurück wenn keine Rolle gesetzt ist.
+func RoleFromContext(ctx context.Context) (models.OrgRole, bool) {
+	v, ok := ctx.Value(roleKey).(models.OrgRole)
+	return v, ok
+}
+
+// WithAPIKey reichert den Context mit dem API-Key an, mit dem sich der Benutzer authentifiziert hat.
+func WithAPIKey(ctx context.Context, key *models.APIKeyInfo) context.Context {
+	return context.WithValue(ctx, apiKeyKey, key)
+}
+
+// APIKeyFromContext gibt den API-Key aus dem Context zurück.
+// Gibt nil, false zurück wenn der Request nicht per API-Key authentifiziert wurde (z.B. Dev-Mode).
+func APIKeyFromContext(ctx context.Context) (*models.APIKeyInfo, bool) {
+	v, ok := ctx.Value(apiKeyKey).(*models.APIKeyInfo)
+	return v, ok
+}
+
+// ServiceAccountIDFromContext gibt die ID des Service-Accounts zurück, dessen API-Key den
+// Request authentifiziert hat. Gibt "", false zurück wenn ein Benutzer angemeldet ist.
+func ServiceAccountIDFromContext(ctx context.Context) (string, bool) {
+	key, ok := APIKeyFromContext(ctx)
+	if !ok || key.ServiceAccountID == "" {
+		return "", false
+	}
+	return key.ServiceAccountID, true
+}
+
+// Actor gibt an, wer den Request ausgelöst hat, für Logs und Ereignisse: "service-account:<id>"
+// oder "user:<id>". Ohne Tenant (z.B. Reconciler) wird "" zurückgegeben.
+func Actor(ctx context.Context) string {
+	if id, ok := ServiceAccountIDFromContext(ctx); ok {
+		return "service-account:" + id
+	}
+	if id, ok := UserIDFromContext(ctx); ok && id != "" {
+		return "user:" + id
+	}
+	return ""
+}
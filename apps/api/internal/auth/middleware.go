@@ -57,7 +57,9 @@ func Middleware(logger *slog.Logger, validator KeyValidator) func(http.Handler)
 				}
 			}()
 
-			ctx := WithTenant(r.Context(), info.OrgID, info.UserID)
+			// Bei Keys eines Service-Accounts tritt dessen ID an die Stelle der User-ID
+			ctx := WithTenant(r.Context(), info.OrgID, info.OwnerID())
+			ctx = WithAPIKey(ctx, info)
 			next.ServeHTTP(w, r.WithContext(ctx))
 		})
 	}
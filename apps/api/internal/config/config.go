@@ -3,6 +3,7 @@ package config
 import (
 	"log/slog"
 	"os"
+	"strconv"
 	"time"
 
 	"github.com/joho/godotenv"
@@ -14,6 +15,11 @@ type Config struct {
 	LogLevel            slog.Level
 	DatabaseURL         string
 	ReconcileInterval   time.Duration
+	ReconcileWorkers    int
+	GCInterval          time.Duration
+	GCGracePeriod       time.Duration
+	GCDryRun            bool
+	LeaderID            string
 	KubeconfigPath      string
 	KnativeNamespace    string
 	ResendAPIKey        string
@@ -24,6 +30,9 @@ type Config struct {
 	RegistryURL         string
 	RegistryJWTSecret   string
 	RegistryTokenExpiry time.Duration
+	PullTokenExpiry     time.Duration
+	// SecretsKey ist der base64-kodierte 32-Byte-Schlüssel für die Verschlüsselung von Secrets.
+	SecretsKey string
 }
 
 // Load reads configuration from environment variables with sensible defaults.
@@ -41,6 +50,33 @@ func Load() *Config {
 		}
 	}
 
+	reconcileWorkers := 4
+	if v := os.Getenv("RECONCILE_WORKERS"); v != "" {
+		if n, err := strconv.Atoi(v); err == nil && n > 0 {
+			reconcileWorkers = n
+		}
+	}
+
+	gcInterval := 10 * time.Minute
+	if v := os.Getenv("GC_INTERVAL"); v != "" {
+		if d, err := time.ParseDuration(v); err == nil {
+			gcInterval = d
+		}
+	}
+
+	gcGracePeriod := 1 * time.Hour
+	if v := os.Getenv("GC_GRACE_PERIOD"); v != "" {
+		if d, err := time.ParseDuration(v); err == nil {
+			gcGracePeriod = d
+		}
+	}
+
+	// Identität der Replika bei der Leader-Wahl; in Kubernetes ist der Hostname der Pod-Name
+	leaderID := os.Getenv("LEADER_ID")
+	if leaderID == "" {
+		leaderID, _ = os.Hostname()
+	}
+
 	knativeNamespace := os.Getenv("KNATIVE_NAMESPACE")
 	if knativeNamespace == "" {
 		knativeNamespace = "default"
@@ -70,11 +106,24 @@ func Load() *Config {
 		}
 	}
 
+	// Gültigkeit des Tokens im Registry-Pull-Secret jeder Organisation
+	pullTokenExpiry := 720 * time.Hour // 30 Tage
+	if v := os.Getenv("REGISTRY_PULL_TOKEN_EXPIRY"); v != "" {
+		if d, err := time.ParseDuration(v); err == nil {
+			pullTokenExpiry = d
+		}
+	}
+
 	return &Config{
 		Port:                port,
 		LogLevel:            slog.LevelInfo,
 		DatabaseURL:         os.Getenv("DATABASE_URL"),
 		ReconcileInterval:   reconcileInterval,
+		ReconcileWorkers:    reconcileWorkers,
+		GCInterval:          gcInterval,
+		GCGracePeriod:       gcGracePeriod,
+		GCDryRun:            os.Getenv("GC_DRY_RUN") == "true",
+		LeaderID:            leaderID,
 		KubeconfigPath:      os.Getenv("KUBECONFIG"),
 		KnativeNamespace:    knativeNamespace,
 		ResendAPIKey:        os.Getenv("RESEND_API_KEY"),
@@ -85,5 +134,7 @@ func Load() *Config {
 		RegistryURL:         registryURL,
 		RegistryJWTSecret:   os.Getenv("REGISTRY_JWT_SECRET"),
 		RegistryTokenExpiry: registryTokenExpiry,
+		PullTokenExpiry:     pullTokenExpiry,
+		SecretsKey:          os.Getenv("SECRETS_KEY"),
 	}
 }
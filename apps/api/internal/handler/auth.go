@@ -1,9 +1,13 @@
 package handler
 
 import (
+	"context"
 	"encoding/json"
 	"errors"
 	"net/http"
+	"slices"
+	"strconv"
+	"time"
 
 	"github.com/go-chi/chi/v5"
 	"github.com/max-cloud/api/internal/auth"
@@ -41,6 +45,13 @@ func (h *Handler) Register(w http.ResponseWriter, r *http.Request) {
 
 	h.logger.Info("user registered", "email", req.Email, "org", req.OrgName, "org_id", org.ID)
 
+	// Namespace und Pull-Secret vorab anlegen; schlägt das fehl, holt der Reconciler es vor dem ersten Deploy nach
+	if h.orchestrator != nil {
+		if err := h.orchestrator.CreateNamespace(r.Context(), org.ID); err != nil {
+			h.logger.Warn("failed to provision namespace", "error", err, "org_id", org.ID)
+		}
+	}
+
 	resp := models.RegisterResponse{
 		User:         user,
 		Organization: org,
@@ -52,6 +63,34 @@ func (h *Handler) Register(w http.ResponseWriter, r *http.Request) {
 	json.NewEncoder(w).Encode(resp)
 }
 
+// DeleteOrganization löscht die eigene Organisation samt Namespace und allen Daten (Berechtigung org:manage).
+func (h *Handler) DeleteOrganization(w http.ResponseWriter, r *http.Request) {
+	orgID, _ := auth.OrgIDFromContext(r.Context())
+
+	// Erst den Namespace löschen: schlägt das fehl, bleibt die Organisation bestehen und
+	// das Löschen kann wiederholt werden
+	if h.orchestrator != nil {
+		if err := h.orchestrator.DeleteNamespace(r.Context(), orgID); err != nil {
+			h.logger.Error("failed to delete namespace", "error", err, "org_id", orgID)
+			http.Error(w, `{"error":"failed to delete organization resources"}`, http.StatusInternalServerError)
+			return
+		}
+	}
+
+	if err := h.authStore.DeleteOrganization(r.Context(), orgID); err != nil {
+		if errors.Is(err, store.ErrNotFound) {
+			http.Error(w, `{"error":"organization not found"}`, http.StatusNotFound)
+			return
+		}
+		h.logger.Error("failed to delete organization", "error", err)
+		http.Error(w, `{"error":"internal server error"}`, http.StatusInternalServerError)
+		return
+	}
+
+	h.logger.Info("organization deleted", "org_id", orgID, "actor", auth.Actor(r.Context()))
+	w.WriteHeader(http.StatusNoContent)
+}
+
 // CreateAPIKey erstellt einen neuen API-Key für die aktuelle Organisation.
 func (h *Handler) CreateAPIKey(w http.ResponseWriter, r *http.Request) {
 	var req models.CreateAPIKeyRequest
@@ -60,21 +99,29 @@ func (h *Handler) CreateAPIKey(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	if req.Name == "" {
-		http.Error(w, `{"error":"name is required"}`, http.StatusBadRequest)
+	if !h.checkAPIKeyRequest(w, r, req) {
 		return
 	}
 
 	orgID, _ := auth.OrgIDFromContext(r.Context())
 	userID, _ := auth.UserIDFromContext(r.Context())
 
-	rawKey, info, err := h.authStore.CreateAPIKey(r.Context(), orgID, userID, req.Name)
+	// Keys, die ein Service-Account erzeugt, gehören wieder dem Service-Account
+	var rawKey string
+	var info *models.APIKeyInfo
+	var err error
+	if saID, ok := auth.ServiceAccountIDFromContext(r.Context()); ok {
+		rawKey, info, err = h.authStore.CreateServiceAccountAPIKey(r.Context(), orgID, saID, req)
+	} else {
+		rawKey, info, err = h.authStore.CreateAPIKey(r.Context(), orgID, userID, req)
+	}
 	if err != nil {
 		h.logger.Error("failed to create api key", "error", err)
 		http.Error(w, `{"error":"internal server error"}`, http.StatusInternalServerError)
 		return
 	}
 
+	h.logger.Info("api key created", "org_id", orgID, "key_id", info.ID, "actor", auth.Actor(r.Context()))
 	resp := models.CreateAPIKeyResponse{
 		APIKey: rawKey,
 		Info:   *info,
@@ -85,7 +132,61 @@ func (h *Handler) CreateAPIKey(w http.ResponseWriter, r *http.Request) {
 	json.NewEncoder(w).Encode(resp)
 }
 
-// ListAPIKeys gibt alle API-Keys der aktuellen Organisation zurück.
+// checkAPIKeyRequest validiert Name, Scopes und Ablauf eines neuen Keys. Ein eingeschränkter
+// Key darf keinen Key mit mehr Rechten oder längerer Laufzeit erzeugen. Andernfalls wird die
+// Fehlerantwort geschrieben.
+func (h *Handler) checkAPIKeyRequest(w http.ResponseWriter, r *http.Request, req models.CreateAPIKeyRequest) bool {
+	if req.Name == "" {
+		http.Error(w, `{"error":"name is required"}`, http.StatusBadRequest)
+		return false
+	}
+
+	for _, scope := range req.Scopes {
+		if !scope.Valid() {
+			http.Error(w, `{"error":"unknown scope `+string(scope)+`"}`, http.StatusBadRequest)
+			return false
+		}
+	}
+	if req.ExpiresAt != nil && !req.ExpiresAt.After(time.Now()) {
+		http.Error(w, `{"error":"expires_at must be in the future"}`, http.StatusBadRequest)
+		return false
+	}
+
+	if caller, ok := auth.APIKeyFromContext(r.Context()); ok {
+		if len(caller.Scopes) > 0 && (len(req.Scopes) == 0 || !isSubset(req.Scopes, caller.Scopes)) {
+			http.Error(w, `{"error":"scopes must be a subset of the current api key's scopes"}`, http.StatusForbidden)
+			return false
+		}
+		if caller.ExpiresAt != nil && (req.ExpiresAt == nil || req.ExpiresAt.After(*caller.ExpiresAt)) {
+			http.Error(w, `{"error":"expires_at must not be later than the current api key's expiry"}`, http.StatusForbidden)
+			return false
+		}
+	}
+	return true
+}
+
+// ownsAPIKey prüft, ob der Key dem Aufrufer gehört. Ein Key mit Scopes gilt nur für sich
+// selbst als Besitzer, damit etwa ein CI-Key mit logs:read nicht die übrigen Keys seines
+// Users löschen kann.
+func ownsAPIKey(ctx context.Context, key models.APIKeyInfo) bool {
+	if caller, ok := auth.APIKeyFromContext(ctx); ok && len(caller.Scopes) > 0 {
+		return caller.ID == key.ID
+	}
+	userID, _ := auth.UserIDFromContext(ctx)
+	return key.OwnerID() == userID
+}
+
+func isSubset(scopes, allowed []models.Permission) bool {
+	for _, scope := range scopes {
+		if !slices.Contains(allowed, scope) {
+			return false
+		}
+	}
+	return true
+}
+
+// ListAPIKeys gibt die API-Keys der aktuellen Organisation zurück. Ohne members:manage
+// sieht der Aufrufer nur die Keys, die er selbst verwalten darf.
 func (h *Handler) ListAPIKeys(w http.ResponseWriter, r *http.Request) {
 	orgID, _ := auth.OrgIDFromContext(r.Context())
 
@@ -96,16 +197,30 @@ func (h *Handler) ListAPIKeys(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	if _, err := auth.Authorize(r.Context(), h.authStore, models.PermissionManageMembers); err != nil {
+		if !errors.Is(err, auth.ErrPermissionDenied) {
+			h.logger.Error("failed to resolve role", "error", err)
+			http.Error(w, `{"error":"internal server error"}`, http.StatusInternalServerError)
+			return
+		}
+		keys = slices.DeleteFunc(keys, func(k models.APIKeyInfo) bool { return !ownsAPIKey(r.Context(), k) })
+	}
+
 	w.Header().Set("Content-Type", "application/json")
 	json.NewEncoder(w).Encode(keys)
 }
 
-// DeleteAPIKey löscht einen API-Key.
+// DeleteAPIKey löscht einen API-Key. Eigene Keys darf jeder löschen, Keys anderer
+// Mitglieder und von Service-Accounts erfordern members:manage.
 func (h *Handler) DeleteAPIKey(w http.ResponseWriter, r *http.Request) {
-	keyID := chi.URLParam(r, "id")
 	orgID, _ := auth.OrgIDFromContext(r.Context())
 
-	if err := h.authStore.DeleteAPIKey(r.Context(), orgID, keyID); err != nil {
+	key, ok := h.manageableAPIKey(w, r, chi.URLParam(r, "id"))
+	if !ok {
+		return
+	}
+
+	if err := h.authStore.DeleteAPIKey(r.Context(), orgID, key.OwnerID(), key.ID); err != nil {
 		if errors.Is(err, store.ErrKeyNotFound) {
 			http.Error(w, `{"error":"api key not found"}`, http.StatusNotFound)
 			return
@@ -115,9 +230,112 @@ func (h *Handler) DeleteAPIKey(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	h.logger.Info("api key deleted", "org_id", orgID, "key_id", key.ID, "actor", auth.Actor(r.Context()))
 	w.WriteHeader(http.StatusNoContent)
 }
 
+// manageableAPIKey lädt den Key und prüft, ob der Aufrufer ihn verwalten darf. Keys anderer
+// erfordern members:manage in Rolle und Scopes, Keys von Ownern zusätzlich die Owner-Rolle.
+// Andernfalls wird die Fehlerantwort geschrieben.
+func (h *Handler) manageableAPIKey(w http.ResponseWriter, r *http.Request, keyID string) (*models.APIKeyInfo, bool) {
+	orgID, _ := auth.OrgIDFromContext(r.Context())
+
+	key, err := h.authStore.GetAPIKey(r.Context(), orgID, keyID)
+	if err != nil {
+		if errors.Is(err, store.ErrKeyNotFound) {
+			http.Error(w, `{"error":"api key not found"}`, http.StatusNotFound)
+			return nil, false
+		}
+		h.logger.Error("failed to get api key", "error", err)
+		http.Error(w, `{"error":"internal server error"}`, http.StatusInternalServerError)
+		return nil, false
+	}
+	if ownsAPIKey(r.Context(), *key) {
+		return key, true
+	}
+
+	if _, err := auth.Authorize(r.Context(), h.authStore, models.PermissionManageMembers); err != nil {
+		if errors.Is(err, auth.ErrPermissionDenied) {
+			http.Error(w, `{"error":"`+err.Error()+`"}`, http.StatusForbidden)
+			return nil, false
+		}
+		h.logger.Error("failed to resolve role", "error", err)
+		http.Error(w, `{"error":"internal server error"}`, http.StatusInternalServerError)
+		return nil, false
+	}
+	// Service-Accounts sind nie Owner, für Keys von Usern gelten die Regeln der Mitgliederverwaltung
+	if key.UserID != "" && !h.canManageMember(w, r, key.UserID) {
+		return nil, false
+	}
+	return key, true
+}
+
+// RotateAPIKey stellt einen Nachfolger mit Name und Scopes des Keys aus. Der alte Key bleibt
+// für die Übergangszeit gültig, damit alle Verbraucher umgestellt werden können. Es gelten
+// dieselben Regeln wie beim Löschen: Keys anderer erfordern members:manage. Jeder Key kann
+// nur einmal rotiert werden.
+func (h *Handler) RotateAPIKey(w http.ResponseWriter, r *http.Request) {
+	orgID, _ := auth.OrgIDFromContext(r.Context())
+
+	var req models.RotateAPIKeyRequest
+	if r.ContentLength != 0 {
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			http.Error(w, `{"error":"invalid JSON"}`, http.StatusBadRequest)
+			return
+		}
+	}
+	if req.GracePeriodSeconds < 0 || req.GracePeriodSeconds > models.MaxAPIKeyGracePeriodSeconds {
+		http.Error(w, `{"error":"grace_period_seconds must be between 0 and `+strconv.Itoa(models.MaxAPIKeyGracePeriodSeconds)+`"}`, http.StatusBadRequest)
+		return
+	}
+	if req.GracePeriodSeconds == 0 {
+		req.GracePeriodSeconds = models.DefaultAPIKeyGracePeriodSeconds
+	}
+
+	key, ok := h.manageableAPIKey(w, r, chi.URLParam(r, "id"))
+	if !ok {
+		return
+	}
+
+	// Der Nachfolger darf nicht mehr Rechte oder eine längere Laufzeit haben als der aufrufende Key
+	if caller, ok := auth.APIKeyFromContext(r.Context()); ok {
+		if len(caller.Scopes) > 0 && (len(key.Scopes) == 0 || !isSubset(key.Scopes, caller.Scopes)) {
+			http.Error(w, `{"error":"scopes must be a subset of the current api key's scopes"}`, http.StatusForbidden)
+			return
+		}
+		if expiresAt := key.RotatedExpiry(time.Now()); caller.ExpiresAt != nil && (expiresAt == nil || expiresAt.After(*caller.ExpiresAt)) {
+			http.Error(w, `{"error":"expires_at must not be later than the current api key's expiry"}`, http.StatusForbidden)
+			return
+		}
+	}
+
+	gracePeriod := time.Duration(req.GracePeriodSeconds) * time.Second
+	rawKey, info, err := h.authStore.RotateAPIKey(r.Context(), orgID, key.OwnerID(), key.ID, gracePeriod)
+	if err != nil {
+		if errors.Is(err, store.ErrKeyNotFound) {
+			http.Error(w, `{"error":"api key not found"}`, http.StatusNotFound)
+			return
+		}
+		if errors.Is(err, store.ErrKeyRotated) {
+			http.Error(w, `{"error":"api key was already rotated, rotate its successor instead"}`, http.StatusConflict)
+			return
+		}
+		h.logger.Error("failed to rotate api key", "error", err)
+		http.Error(w, `{"error":"internal server error"}`, http.StatusInternalServerError)
+		return
+	}
+
+	h.logger.Info("api key rotated", "org_id", orgID, "key_id", key.ID, "new_key_id", info.ID, "grace_period", gracePeriod, "actor", auth.Actor(r.Context()))
+	resp := models.CreateAPIKeyResponse{
+		APIKey: rawKey,
+		Info:   *info,
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusCreated)
+	json.NewEncoder(w).Encode(resp)
+}
+
 // AuthStatus gibt Informationen über den aktuellen Benutzer zurück.
 func (h *Handler) AuthStatus(w http.ResponseWriter, r *http.Request) {
 	orgID, _ := auth.OrgIDFromContext(r.Context())
@@ -4,6 +4,7 @@ import (
 	"bytes"
 	"context"
 	"encoding/json"
+	"errors"
 	"log/slog"
 	"net/http"
 	"net/http/httptest"
@@ -21,7 +22,7 @@ import (
 func setupAuth() (*Handler, *store.MemoryStore) {
 	s := store.NewMemory()
 	orch := orchestrator.NewNoop(slog.Default())
-	h := New(slog.Default(), s, s, orch, email.NewMock(), 7*24*time.Hour, true, "registry.local", "test-secret", 1*time.Hour)
+	h := New(slog.Default(), s, s, s, s, s, orch, email.NewMock(), 7*24*time.Hour, true, "registry.local", "test-secret", 1*time.Hour)
 	return h, s
 }
 
@@ -128,6 +129,178 @@ func TestCreateAPIKeyHandler(t *testing.T) {
 	}
 }
 
+func TestCreateScopedAPIKeyHandler(t *testing.T) {
+	h, s := setupAuth()
+	ctx := context.Background()
+
+	user, org, _, err := s.Register(ctx, "test@example.com", "TestOrg")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	tenant := auth.WithTenant(ctx, org.ID, user.ID)
+
+	expires := time.Now().Add(time.Hour)
+	scoped := &models.APIKeyInfo{Scopes: []models.Permission{models.PermissionDeploy}, ExpiresAt: &expires}
+	future := time.Now().Add(2 * time.Hour).Format(time.RFC3339)
+	soon := time.Now().Add(30 * time.Minute).Format(time.RFC3339)
+
+	tests := []struct {
+		name   string
+		caller *models.APIKeyInfo
+		body   string
+		code   int
+	}{
+		{"scoped with expiry", nil, `{"name":"ci","scopes":["services:deploy","logs:read"],"expires_at":"` + future + `"}`, http.StatusCreated},
+		{"unknown scope", nil, `{"name":"ci","scopes":["services:everything"]}`, http.StatusBadRequest},
+		{"expiry in the past", nil, `{"name":"ci","expires_at":"2000-01-01T00:00:00Z"}`, http.StatusBadRequest},
+		{"scoped key creates unscoped key", scoped, `{"name":"ci","expires_at":"` + soon + `"}`, http.StatusForbidden},
+		{"scoped key widens scopes", scoped, `{"name":"ci","scopes":["services:delete"],"expires_at":"` + soon + `"}`, http.StatusForbidden},
+		{"scoped key extends expiry", scoped, `{"name":"ci","scopes":["services:deploy"],"expires_at":"` + future + `"}`, http.StatusForbidden},
+		{"scoped key creates narrower key", scoped, `{"name":"ci","scopes":["services:deploy"],"expires_at":"` + soon + `"}`, http.StatusCreated},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			reqCtx := tenant
+			if tt.caller != nil {
+				reqCtx = auth.WithAPIKey(reqCtx, tt.caller)
+			}
+			req := httptest.NewRequest("POST", "/api/v1/auth/api-keys", bytes.NewBufferString(tt.body)).WithContext(reqCtx)
+			w := httptest.NewRecorder()
+			h.CreateAPIKey(w, req)
+			if w.Code != tt.code {
+				t.Fatalf("expected %d, got %d: %s", tt.code, w.Code, w.Body.String())
+			}
+		})
+	}
+}
+
+func TestRotateAPIKeyHandler(t *testing.T) {
+	h, s := setupAuth()
+	ctx := context.Background()
+
+	user, org, _, err := s.Register(ctx, "test@example.com", "TestOrg")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	tenant := auth.WithTenant(ctx, org.ID, user.ID)
+
+	createKey := func(req models.CreateAPIKeyRequest) string {
+		t.Helper()
+		_, info, err := s.CreateAPIKey(ctx, org.ID, user.ID, req)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		return info.ID
+	}
+	expires := time.Now().Add(time.Hour)
+	// Nur mit members:manage darf ein Key mit Scopes andere Keys rotieren
+	scoped := &models.APIKeyInfo{Scopes: []models.Permission{models.PermissionDeploy, models.PermissionManageMembers}, ExpiresAt: &expires}
+	soon := time.Now().Add(30 * time.Minute)
+	rotated := createKey(models.CreateAPIKeyRequest{Name: "ci"})
+	if _, _, err := s.RotateAPIKey(ctx, org.ID, user.ID, rotated, time.Hour); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	r := chi.NewRouter()
+	r.Post("/api/v1/auth/api-keys/{id}/rotate", h.RotateAPIKey)
+
+	tests := []struct {
+		name   string
+		caller *models.APIKeyInfo
+		keyID  string
+		body   string
+		code   int
+	}{
+		{"default grace period", nil, createKey(models.CreateAPIKeyRequest{Name: "ci"}), ``, http.StatusCreated},
+		{"custom grace period", nil, createKey(models.CreateAPIKeyRequest{Name: "ci"}), `{"grace_period_seconds":60}`, http.StatusCreated},
+		{"grace period too long", nil, createKey(models.CreateAPIKeyRequest{Name: "ci"}), `{"grace_period_seconds":99999999}`, http.StatusBadRequest},
+		{"unknown key", nil, "nonexistent", ``, http.StatusNotFound},
+		{"already rotated key", nil, rotated, ``, http.StatusConflict},
+		{"scoped key rotates unscoped key", scoped, createKey(models.CreateAPIKeyRequest{Name: "ci"}), ``, http.StatusForbidden},
+		{"scoped key rotates itself", scoped, createKey(models.CreateAPIKeyRequest{
+			Name: "ci", Scopes: []models.Permission{models.PermissionDeploy}, ExpiresAt: &expires,
+		}), ``, http.StatusForbidden},
+		{"scoped key rotates narrower key", scoped, createKey(models.CreateAPIKeyRequest{
+			Name: "ci", Scopes: []models.Permission{models.PermissionDeploy}, ExpiresAt: &soon,
+		}), ``, http.StatusCreated},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			reqCtx := tenant
+			if tt.caller != nil {
+				reqCtx = auth.WithAPIKey(reqCtx, tt.caller)
+			}
+			req := httptest.NewRequest("POST", "/api/v1/auth/api-keys/"+tt.keyID+"/rotate", bytes.NewBufferString(tt.body)).WithContext(reqCtx)
+			w := httptest.NewRecorder()
+			r.ServeHTTP(w, req)
+			if w.Code != tt.code {
+				t.Fatalf("expected %d, got %d: %s", tt.code, w.Code, w.Body.String())
+			}
+			if tt.code != http.StatusCreated {
+				return
+			}
+			var resp models.CreateAPIKeyResponse
+			json.NewDecoder(w.Body).Decode(&resp)
+			if resp.APIKey == "" || resp.Info.ID == tt.keyID || resp.Info.Name != "ci" {
+				t.Fatalf("unexpected response: %+v", resp)
+			}
+		})
+	}
+}
+
+func TestRotateAPIKeyPermissions(t *testing.T) {
+	h, s := setupInvite()
+	owner, org, ownerCtx := registerOwner(t, s)
+	viewer, _, viewerCtx := inviteMember(t, h, org, owner.ID, "viewer@example.org", models.OrgRoleViewer)
+	sa, err := s.CreateServiceAccount(context.Background(), org.ID, "ci", models.OrgRoleAdmin)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	_, ownerKey, err := s.CreateAPIKey(context.Background(), org.ID, owner.ID, models.CreateAPIKeyRequest{Name: "owner"})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	_, saKey, err := s.CreateServiceAccountAPIKey(context.Background(), org.ID, sa.ID, models.CreateAPIKeyRequest{Name: "deploy"})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	_, viewerKey, err := s.CreateAPIKey(context.Background(), org.ID, viewer.ID, models.CreateAPIKeyRequest{Name: "viewer"})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	r := chi.NewRouter()
+	r.Post("/api/v1/auth/api-keys/{id}/rotate", h.RotateAPIKey)
+
+	tests := []struct {
+		name  string
+		ctx   context.Context
+		keyID string
+		code  int
+	}{
+		{"viewer rotates owner key", viewerCtx, ownerKey.ID, http.StatusForbidden},
+		{"viewer rotates service account key", viewerCtx, saKey.ID, http.StatusForbidden},
+		{"viewer rotates own key", viewerCtx, viewerKey.ID, http.StatusCreated},
+		{"owner rotates service account key", ownerCtx, saKey.ID, http.StatusCreated},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest("POST", "/api/v1/auth/api-keys/"+tt.keyID+"/rotate", nil).WithContext(tt.ctx)
+			w := httptest.NewRecorder()
+			r.ServeHTTP(w, req)
+			if w.Code != tt.code {
+				t.Fatalf("expected %d, got %d: %s", tt.code, w.Code, w.Body.String())
+			}
+		})
+	}
+
+	// Ohne Berechtigung bleibt der Key unverändert gültig
+	if key, err := s.GetAPIKey(context.Background(), org.ID, ownerKey.ID); err != nil || key.ExpiresAt != nil {
+		t.Fatalf("expected owner key untouched, got %+v, %v", key, err)
+	}
+}
+
 func TestListAPIKeysHandler(t *testing.T) {
 	h, s := setupAuth()
 	ctx := context.Background()
@@ -163,7 +336,7 @@ func TestDeleteAPIKeyHandler(t *testing.T) {
 		t.Fatalf("unexpected error: %v", err)
 	}
 
-	_, info, err := s.CreateAPIKey(ctx, org.ID, user.ID, "to-delete")
+	_, info, err := s.CreateAPIKey(ctx, org.ID, user.ID, models.CreateAPIKeyRequest{Name: "to-delete"})
 	if err != nil {
 		t.Fatalf("unexpected error: %v", err)
 	}
@@ -203,6 +376,108 @@ func TestDeleteAPIKeyNotFound(t *testing.T) {
 	}
 }
 
+func TestDeleteAPIKeyPermissions(t *testing.T) {
+	h, s := setupInvite()
+	owner, org, ownerCtx := registerOwner(t, s)
+	admin, _, adminCtx := inviteMember(t, h, org, owner.ID, "admin@example.org", models.OrgRoleAdmin)
+	viewer, _, viewerCtx := inviteMember(t, h, org, owner.ID, "viewer@example.org", models.OrgRoleViewer)
+
+	createKey := func(userID string) string {
+		t.Helper()
+		_, info, err := s.CreateAPIKey(context.Background(), org.ID, userID, models.CreateAPIKeyRequest{Name: "ci"})
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		return info.ID
+	}
+
+	r := chi.NewRouter()
+	r.Delete("/api/v1/auth/api-keys/{id}", h.DeleteAPIKey)
+
+	tests := []struct {
+		name  string
+		ctx   context.Context
+		keyID string
+		code  int
+	}{
+		{"viewer deletes owner key", viewerCtx, createKey(owner.ID), http.StatusForbidden},
+		{"viewer deletes admin key", viewerCtx, createKey(admin.ID), http.StatusForbidden},
+		{"viewer deletes own key", viewerCtx, createKey(viewer.ID), http.StatusNoContent},
+		{"admin deletes owner key", adminCtx, createKey(owner.ID), http.StatusForbidden},
+		{"admin deletes viewer key", adminCtx, createKey(viewer.ID), http.StatusNoContent},
+		{"owner deletes admin key", ownerCtx, createKey(admin.ID), http.StatusNoContent},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest("DELETE", "/api/v1/auth/api-keys/"+tt.keyID, nil).WithContext(tt.ctx)
+			w := httptest.NewRecorder()
+			r.ServeHTTP(w, req)
+			if w.Code != tt.code {
+				t.Fatalf("expected %d, got %d: %s", tt.code, w.Code, w.Body.String())
+			}
+			_, err := s.GetAPIKey(context.Background(), org.ID, tt.keyID)
+			if deleted := errors.Is(err, store.ErrKeyNotFound); deleted != (tt.code == http.StatusNoContent) {
+				t.Fatalf("expected key deleted=%v, got error %v", tt.code == http.StatusNoContent, err)
+			}
+		})
+	}
+}
+
+func TestScopedAPIKeyManagesOnlyItself(t *testing.T) {
+	h, s := setupInvite()
+	owner, org, ownerCtx := registerOwner(t, s)
+
+	createKey := func(scopes ...models.Permission) *models.APIKeyInfo {
+		t.Helper()
+		_, info, err := s.CreateAPIKey(context.Background(), org.ID, owner.ID, models.CreateAPIKeyRequest{Name: "ci", Scopes: scopes})
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		return info
+	}
+	logsKey := createKey(models.PermissionReadLogs)
+	logsCtx := auth.WithAPIKey(ownerCtx, logsKey)
+	other := createKey()
+
+	r := chi.NewRouter()
+	r.Get("/api/v1/auth/api-keys", h.ListAPIKeys)
+	r.Delete("/api/v1/auth/api-keys/{id}", h.DeleteAPIKey)
+
+	// Ein Key mit logs:read sieht nur sich selbst
+	req := httptest.NewRequest("GET", "/api/v1/auth/api-keys", nil).WithContext(logsCtx)
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+	var keys []models.APIKeyInfo
+	json.NewDecoder(w.Body).Decode(&keys)
+	if len(keys) != 1 || keys[0].ID != logsKey.ID {
+		t.Fatalf("expected only the calling key, got %+v", keys)
+	}
+
+	// Die übrigen Keys desselben Owners darf er nicht löschen
+	req = httptest.NewRequest("DELETE", "/api/v1/auth/api-keys/"+other.ID, nil).WithContext(logsCtx)
+	w = httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+	if w.Code != http.StatusForbidden {
+		t.Fatalf("expected 403, got %d: %s", w.Code, w.Body.String())
+	}
+
+	// Mit dem Scope members:manage ist die Verwaltung fremder Keys erlaubt
+	manageCtx := auth.WithAPIKey(ownerCtx, createKey(models.PermissionManageMembers))
+	req = httptest.NewRequest("DELETE", "/api/v1/auth/api-keys/"+other.ID, nil).WithContext(manageCtx)
+	w = httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+	if w.Code != http.StatusNoContent {
+		t.Fatalf("expected 204, got %d: %s", w.Code, w.Body.String())
+	}
+
+	req = httptest.NewRequest("DELETE", "/api/v1/auth/api-keys/"+logsKey.ID, nil).WithContext(logsCtx)
+	w = httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+	if w.Code != http.StatusNoContent {
+		t.Fatalf("expected 204 for deleting itself, got %d: %s", w.Code, w.Body.String())
+	}
+}
+
 func TestAuthStatusHandler(t *testing.T) {
 	h, s := setupAuth()
 	ctx := context.Background()
@@ -227,7 +502,59 @@ func TestAuthStatusHandler(t *testing.T) {
 	if info.User.Email != "test@example.com" {
 		t.Fatalf("expected email test@example.com, got %s", info.User.Email)
 	}
-	if info.Role != "admin" {
-		t.Fatalf("expected role admin, got %s", info.Role)
+	if info.Role != models.OrgRoleOwner {
+		t.Fatalf("expected role owner, got %s", info.Role)
+	}
+}
+
+func TestDeleteOrganizationHandler(t *testing.T) {
+	h, s := setupAuth()
+	ctx := context.Background()
+
+	admin, org, _, err := s.Register(ctx, "admin@example.com", "TestOrg")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	expires := time.Now().Add(7 * 24 * time.Hour)
+	_, rawToken, err := s.CreateInvite(ctx, org.ID, "member@example.com", models.OrgRoleDeveloper, admin.ID, expires)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	member, _, _, _, err := s.AcceptInvite(ctx, rawToken)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	// Mitglieder dürfen die Organisation nicht löschen
+	req := httptest.NewRequest("DELETE", "/api/v1/auth/org", nil)
+	req = req.WithContext(auth.WithTenant(req.Context(), org.ID, member.ID))
+	w := httptest.NewRecorder()
+	withPermission(h, models.PermissionManageOrg, h.DeleteOrganization).ServeHTTP(w, req)
+	if w.Code != http.StatusForbidden {
+		t.Fatalf("expected 403 for member, got %d: %s", w.Code, w.Body.String())
+	}
+
+	// Auch Admins nicht, nur Owner
+	if _, err := s.UpdateMemberRole(ctx, org.ID, member.ID, models.OrgRoleAdmin); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	req = httptest.NewRequest("DELETE", "/api/v1/auth/org", nil)
+	req = req.WithContext(auth.WithTenant(req.Context(), org.ID, member.ID))
+	w = httptest.NewRecorder()
+	withPermission(h, models.PermissionManageOrg, h.DeleteOrganization).ServeHTTP(w, req)
+	if w.Code != http.StatusForbidden {
+		t.Fatalf("expected 403 for admin, got %d: %s", w.Code, w.Body.String())
+	}
+
+	req = httptest.NewRequest("DELETE", "/api/v1/auth/org", nil)
+	req = req.WithContext(auth.WithTenant(req.Context(), org.ID, admin.ID))
+	w = httptest.NewRecorder()
+	withPermission(h, models.PermissionManageOrg, h.DeleteOrganization).ServeHTTP(w, req)
+	if w.Code != http.StatusNoContent {
+		t.Fatalf("expected 204, got %d: %s", w.Code, w.Body.String())
+	}
+
+	if _, err := s.GetOrganization(ctx, org.ID); !errors.Is(err, store.ErrNotFound) {
+		t.Fatalf("expected organization to be deleted, got %v", err)
 	}
 }
@@ -0,0 +1,195 @@
+package handler
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"slices"
+	"strings"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/max-cloud/api/internal/auth"
+	"github.com/max-cloud/api/internal/store"
+	"github.com/max-cloud/shared/pkg/models"
+)
+
+// CreateDomain legt eine Custom Domain für einen Service an. Die Domain muss
+// anschließend über den TXT-Challenge-Record verifiziert werden.
+func (h *Handler) CreateDomain(w http.ResponseWriter, r *http.Request) {
+	var req models.CreateDomainRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		errorWithRequestID(w, r, "invalid JSON", http.StatusBadRequest)
+		return
+	}
+
+	req.Hostname = normalizeHostname(req.Hostname)
+	if len(req.Hostname) > models.MaxHostnameLength || !models.DomainHostnamePattern.MatchString(req.Hostname) {
+		errorWithRequestID(w, r, "invalid hostname: use a fully qualified domain name like www.example.com", http.StatusBadRequest)
+		return
+	}
+	if req.ServiceID == "" {
+		errorWithRequestID(w, r, "service_id is required", http.StatusBadRequest)
+		return
+	}
+
+	if _, err := h.store.Get(r.Context(), req.ServiceID); err != nil {
+		if errors.Is(err, store.ErrNotFound) || isUUIDError(err) {
+			errorWithRequestID(w, r, fmt.Sprintf("unknown service %q", req.ServiceID), http.StatusBadRequest)
+			return
+		}
+		h.logger.Error("failed to get service for domain", "error", err, "service_id", req.ServiceID)
+		errorWithRequestID(w, r, "internal server error", http.StatusInternalServerError)
+		return
+	}
+
+	domain, err := h.domainStore.CreateDomain(r.Context(), req)
+	if err != nil {
+		if errors.Is(err, store.ErrDuplicateDomain) {
+			errorWithRequestID(w, r, "domain already added", http.StatusConflict)
+			return
+		}
+		h.logger.Error("failed to create domain", "error", err, "hostname", req.Hostname)
+		errorWithRequestID(w, r, "internal server error", http.StatusInternalServerError)
+		return
+	}
+
+	h.logger.Info("domain created", "id", domain.ID, "hostname", domain.Hostname, "service_id", domain.ServiceID, "actor", auth.Actor(r.Context()))
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusCreated)
+	json.NewEncoder(w).Encode(domain)
+}
+
+// ListDomains gibt die Custom Domains der Organisation zurück.
+func (h *Handler) ListDomains(w http.ResponseWriter, r *http.Request) {
+	domains, err := h.domainStore.ListDomains(r.Context())
+	if err != nil {
+		h.logger.Error("failed to list domains", "error", err)
+		errorWithRequestID(w, r, "internal server error", http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(domains)
+}
+
+// GetDomain gibt eine Custom Domain zurück.
+func (h *Handler) GetDomain(w http.ResponseWriter, r *http.Request) {
+	domain, ok := h.loadDomain(w, r)
+	if !ok {
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(domain)
+}
+
+// VerifyDomain prüft den TXT-Challenge-Record einer Domain. Enthält er das
+// Verifizierungs-Token, gilt die Domain als verifiziert und der Reconciler legt das Mapping an.
+func (h *Handler) VerifyDomain(w http.ResponseWriter, r *http.Request) {
+	domain, ok := h.loadDomain(w, r)
+	if !ok {
+		return
+	}
+
+	if domain.VerifiedAt == nil {
+		records, err := h.lookupTXT(r.Context(), domain.ChallengeRecord())
+		if err != nil {
+			h.logger.Info("domain verification lookup failed", "error", err, "hostname", domain.Hostname)
+		}
+		if !slices.Contains(records, domain.VerificationToken) {
+			errorWithRequestID(w, r, fmt.Sprintf("TXT record %s with value %q not found", domain.ChallengeRecord(), domain.VerificationToken), http.StatusBadRequest)
+			return
+		}
+	}
+
+	verified, err := h.domainStore.VerifyDomain(r.Context(), domain.ID)
+	if err != nil {
+		if errors.Is(err, store.ErrDomainNotFound) {
+			http.Error(w, `{"error":"domain not found"}`, http.StatusNotFound)
+			return
+		}
+		if errors.Is(err, store.ErrDuplicateDomain) {
+			errorWithRequestID(w, r, "domain is already verified by another organization", http.StatusConflict)
+			return
+		}
+		h.logger.Error("failed to verify domain", "error", err, "id", domain.ID)
+		errorWithRequestID(w, r, "internal server error", http.StatusInternalServerError)
+		return
+	}
+
+	h.logger.Info("domain verified", "id", verified.ID, "hostname", verified.Hostname, "actor", auth.Actor(r.Context()))
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(verified)
+}
+
+// DeleteDomain entfernt das Mapping beim Orchestrator und löscht die Domain.
+func (h *Handler) DeleteDomain(w http.ResponseWriter, r *http.Request) {
+	domain, ok := h.loadDomain(w, r)
+	if !ok {
+		return
+	}
+
+	if h.orchestrator != nil && domain.VerifiedAt != nil {
+		if err := h.orchestrator.RemoveDomain(r.Context(), domain); err != nil {
+			h.logger.Error("orchestrator remove domain failed", "error", err, "id", domain.ID)
+			errorWithRequestID(w, r, "internal server error", http.StatusInternalServerError)
+			return
+		}
+	}
+
+	if err := h.domainStore.DeleteDomain(r.Context(), domain.ID); err != nil {
+		if errors.Is(err, store.ErrDomainNotFound) {
+			http.Error(w, `{"error":"domain not found"}`, http.StatusNotFound)
+			return
+		}
+		h.logger.Error("failed to delete domain", "error", err, "id", domain.ID)
+		errorWithRequestID(w, r, "internal server error", http.StatusInternalServerError)
+		return
+	}
+
+	h.logger.Info("domain deleted", "id", domain.ID, "hostname", domain.Hostname, "actor", auth.Actor(r.Context()))
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// loadDomain liest die Domain aus dem URL-Parameter und schreibt bei Fehlern die Antwort.
+func (h *Handler) loadDomain(w http.ResponseWriter, r *http.Request) (models.Domain, bool) {
+	id := chi.URLParam(r, "id")
+
+	domain, err := h.domainStore.GetDomain(r.Context(), id)
+	if err != nil {
+		if errors.Is(err, store.ErrDomainNotFound) || isUUIDError(err) {
+			http.Error(w, `{"error":"domain not found"}`, http.StatusNotFound)
+			return models.Domain{}, false
+		}
+		h.logger.Error("failed to get domain", "error", err, "id", id)
+		errorWithRequestID(w, r, "internal server error", http.StatusInternalServerError)
+		return models.Domain{}, false
+	}
+	return domain, true
+}
+
+// checkServiceDomains prüft, ob noch Custom Domains auf den Service zeigen, und schreibt
+// in diesem Fall eine Fehlerantwort. Gibt true zurück, wenn der Service gelöscht werden darf.
+func (h *Handler) checkServiceDomains(w http.ResponseWriter, r *http.Request, serviceID string) bool {
+	domains, err := h.domainStore.ListDomains(r.Context())
+	if err != nil {
+		h.logger.Error("failed to list domains", "error", err)
+		errorWithRequestID(w, r, "internal server error", http.StatusInternalServerError)
+		return false
+	}
+	for _, d := range domains {
+		if d.ServiceID == serviceID {
+			errorWithRequestID(w, r, fmt.Sprintf("service is used by domain %q, remove it first", d.Hostname), http.StatusConflict)
+			return false
+		}
+	}
+	return true
+}
+
+// normalizeHostname entfernt Leerzeichen und den abschließenden Punkt und wandelt in Kleinbuchstaben um.
+func normalizeHostname(hostname string) string {
+	return strings.TrimSuffix(strings.ToLower(strings.TrimSpace(hostname)), ".")
+}
@@ -0,0 +1,205 @@
+package handler
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/max-cloud/api/internal/auth"
+	"github.com/max-cloud/shared/pkg/models"
+)
+
+func domainsRouter(h *Handler) *chi.Mux {
+	r := chi.NewRouter()
+	r.Get("/api/v1/domains", h.ListDomains)
+	r.Post("/api/v1/domains", h.CreateDomain)
+	r.Get("/api/v1/domains/{id}", h.GetDomain)
+	r.Post("/api/v1/domains/{id}/verify", h.VerifyDomain)
+	r.Delete("/api/v1/domains/{id}", h.DeleteDomain)
+	r.Post("/api/v1/services", h.CreateService)
+	r.Delete("/api/v1/services/{id}", h.DeleteService)
+	return r
+}
+
+// fakeTXT liefert TXT-Records aus einer Map statt aus dem DNS.
+func fakeTXT(records map[string][]string) func(context.Context, string) ([]string, error) {
+	return func(_ context.Context, name string) ([]string, error) {
+		return records[name], nil
+	}
+}
+
+func TestDomainsLifecycle(t *testing.T) {
+	h, _ := setup()
+	txt := map[string][]string{}
+	h.lookupTXT = fakeTXT(txt)
+	r := domainsRouter(h)
+	ctx := auth.WithTenant(context.Background(), "org-1", "user-1")
+
+	req := httptest.NewRequest("POST", "/api/v1/services", bytes.NewBufferString(`{"name":"web","image":"nginx:latest"}`)).WithContext(ctx)
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+	var svc models.Service
+	json.NewDecoder(w.Body).Decode(&svc)
+
+	payload := `{"hostname":"WWW.Example.com.","service_id":"` + svc.ID + `"}`
+	req = httptest.NewRequest("POST", "/api/v1/domains", bytes.NewBufferString(payload)).WithContext(ctx)
+	w = httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+	if w.Code != http.StatusCreated {
+		t.Fatalf("expected 201, got %d: %s", w.Code, w.Body.String())
+	}
+	var domain models.Domain
+	json.NewDecoder(w.Body).Decode(&domain)
+	if domain.Hostname != "www.example.com" || domain.Status != models.DomainStatusPendingVerification {
+		t.Fatalf("unexpected domain: %+v", domain)
+	}
+	if domain.VerificationToken == "" {
+		t.Fatal("expected verification token")
+	}
+
+	req = httptest.NewRequest("POST", "/api/v1/domains", bytes.NewBufferString(payload)).WithContext(ctx)
+	w = httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+	if w.Code != http.StatusConflict {
+		t.Fatalf("expected 409 for duplicate domain, got %d", w.Code)
+	}
+
+	// Ohne TXT-Record schlägt die Verifizierung fehl
+	req = httptest.NewRequest("POST", "/api/v1/domains/"+domain.ID+"/verify", nil).WithContext(ctx)
+	w = httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+	if w.Code != http.StatusBadRequest {
+		t.Fatalf("expected 400 without TXT record, got %d: %s", w.Code, w.Body.String())
+	}
+
+	txt["_maxcloud-challenge.www.example.com"] = []string{"v=spf1 -all", domain.VerificationToken}
+	req = httptest.NewRequest("POST", "/api/v1/domains/"+domain.ID+"/verify", nil).WithContext(ctx)
+	w = httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+	if w.Code != http.StatusOK {
+		t.Fatalf("expected 200, got %d: %s", w.Code, w.Body.String())
+	}
+	var verified models.Domain
+	json.NewDecoder(w.Body).Decode(&verified)
+	if verified.Status != models.DomainStatusProvisioning || verified.VerifiedAt == nil {
+		t.Fatalf("expected verified domain, got %+v", verified)
+	}
+
+	// Der Service kann nicht gelöscht werden, solange eine Domain auf ihn zeigt
+	req = httptest.NewRequest("DELETE", "/api/v1/services/"+svc.ID, nil).WithContext(ctx)
+	w = httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+	if w.Code != http.StatusConflict {
+		t.Fatalf("expected 409 for service with domain, got %d", w.Code)
+	}
+
+	// Andere Organisationen sehen die Domain nicht
+	other := auth.WithTenant(context.Background(), "org-2", "user-2")
+	req = httptest.NewRequest("GET", "/api/v1/domains/"+domain.ID, nil).WithContext(other)
+	w = httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+	if w.Code != http.StatusNotFound {
+		t.Fatalf("expected 404 across tenants, got %d", w.Code)
+	}
+
+	req = httptest.NewRequest("DELETE", "/api/v1/domains/"+domain.ID, nil).WithContext(ctx)
+	w = httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+	if w.Code != http.StatusNoContent {
+		t.Fatalf("expected 204, got %d: %s", w.Code, w.Body.String())
+	}
+
+	req = httptest.NewRequest("GET", "/api/v1/domains", nil).WithContext(ctx)
+	w = httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+	var domains []models.Domain
+	json.NewDecoder(w.Body).Decode(&domains)
+	if len(domains) != 0 {
+		t.Fatalf("expected no domains, got %+v", domains)
+	}
+
+	req = httptest.NewRequest("DELETE", "/api/v1/services/"+svc.ID, nil).WithContext(ctx)
+	w = httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+	if w.Code != http.StatusNoContent {
+		t.Fatalf("expected 204 after removing domain, got %d", w.Code)
+	}
+}
+
+func TestDomainsVerifyClaimedHostname(t *testing.T) {
+	h, _ := setup()
+	txt := map[string][]string{}
+	h.lookupTXT = fakeTXT(txt)
+	r := domainsRouter(h)
+
+	var domains []models.Domain
+	for _, org := range []string{"org-1", "org-2"} {
+		ctx := auth.WithTenant(context.Background(), org, "user")
+		req := httptest.NewRequest("POST", "/api/v1/services", bytes.NewBufferString(`{"name":"web","image":"nginx:latest"}`)).WithContext(ctx)
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, req)
+		var svc models.Service
+		json.NewDecoder(w.Body).Decode(&svc)
+
+		req = httptest.NewRequest("POST", "/api/v1/domains", bytes.NewBufferString(`{"hostname":"app.example.com","service_id":"`+svc.ID+`"}`)).WithContext(ctx)
+		w = httptest.NewRecorder()
+		r.ServeHTTP(w, req)
+		if w.Code != http.StatusCreated {
+			t.Fatalf("expected 201 for %s, got %d: %s", org, w.Code, w.Body.String())
+		}
+		var d models.Domain
+		json.NewDecoder(w.Body).Decode(&d)
+		domains = append(domains, d)
+	}
+
+	// Beide Tokens sind veröffentlicht, aber nur die erste Verifizierung gewinnt
+	txt["_maxcloud-challenge.app.example.com"] = []string{domains[0].VerificationToken, domains[1].VerificationToken}
+
+	for i, code := range []int{http.StatusOK, http.StatusConflict} {
+		ctx := auth.WithTenant(context.Background(), domains[i].OrgID, "user")
+		req := httptest.NewRequest("POST", "/api/v1/domains/"+domains[i].ID+"/verify", nil).WithContext(ctx)
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, req)
+		if w.Code != code {
+			t.Fatalf("expected %d for %s, got %d: %s", code, domains[i].OrgID, w.Code, w.Body.String())
+		}
+	}
+}
+
+func TestDomainsValidation(t *testing.T) {
+	h, _ := setup()
+	r := domainsRouter(h)
+
+	req := httptest.NewRequest("POST", "/api/v1/services", bytes.NewBufferString(`{"name":"web","image":"nginx:latest"}`))
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+	var svc models.Service
+	json.NewDecoder(w.Body).Decode(&svc)
+
+	tests := []struct {
+		name string
+		body string
+		code int
+	}{
+		{"invalid json", `{`, http.StatusBadRequest},
+		{"single label", `{"hostname":"localhost","service_id":"` + svc.ID + `"}`, http.StatusBadRequest},
+		{"wildcard", `{"hostname":"*.example.com","service_id":"` + svc.ID + `"}`, http.StatusBadRequest},
+		{"underscore", `{"hostname":"my_app.example.com","service_id":"` + svc.ID + `"}`, http.StatusBadRequest},
+		{"missing service", `{"hostname":"www.example.com"}`, http.StatusBadRequest},
+		{"unknown service", `{"hostname":"www.example.com","service_id":"00000000-0000-0000-0000-000000000000"}`, http.StatusBadRequest},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest("POST", "/api/v1/domains", bytes.NewBufferString(tt.body))
+			w := httptest.NewRecorder()
+			r.ServeHTTP(w, req)
+			if w.Code != tt.code {
+				t.Fatalf("expected %d, got %d: %s", tt.code, w.Code, w.Body.String())
+			}
+		})
+	}
+}
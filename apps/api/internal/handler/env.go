@@ -0,0 +1,136 @@
+package handler
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"maps"
+	"net/http"
+	"slices"
+	"strings"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/max-cloud/api/internal/auth"
+	"github.com/max-cloud/api/internal/store"
+	"github.com/max-cloud/shared/pkg/models"
+)
+
+// GetServiceEnv gibt die Umgebungsvariablen und Secret-Referenzen eines Services zurück.
+func (h *Handler) GetServiceEnv(w http.ResponseWriter, r *http.Request) {
+	id := chi.URLParam(r, "id")
+
+	svc, err := h.store.Get(r.Context(), id)
+	if err != nil {
+		if errors.Is(err, store.ErrNotFound) {
+			http.Error(w, `{"error":"service not found"}`, http.StatusNotFound)
+			return
+		}
+		h.logger.Error("failed to get service env", "error", err, "id", id)
+		errorWithRequestID(w, r, "internal server error", http.StatusInternalServerError)
+		return
+	}
+
+	env := models.ServiceEnv{EnvVars: svc.EnvVars, SecretEnv: svc.SecretEnv}
+	if env.EnvVars == nil {
+		env.EnvVars = map[string]string{}
+	}
+	if env.SecretEnv == nil {
+		env.SecretEnv = map[string]string{}
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(env)
+}
+
+// UpdateServiceEnv setzt und entfernt Umgebungsvariablen eines Services.
+// Ändert sich dadurch etwas, entsteht eine neue Revision, die der Reconciler ausrollt.
+// Eine bestehende Traffic-Aufteilung wird wie bei UpdateService nur auf ausdrücklichen Wunsch verworfen.
+func (h *Handler) UpdateServiceEnv(w http.ResponseWriter, r *http.Request) {
+	id := chi.URLParam(r, "id")
+
+	var req models.UpdateEnvRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		errorWithRequestID(w, r, "invalid JSON", http.StatusBadRequest)
+		return
+	}
+	if len(req.Set) == 0 && len(req.Unset) == 0 {
+		errorWithRequestID(w, r, "set or unset is required", http.StatusBadRequest)
+		return
+	}
+
+	svc, err := h.store.Get(r.Context(), id)
+	if err != nil {
+		if errors.Is(err, store.ErrNotFound) {
+			http.Error(w, `{"error":"service not found"}`, http.StatusNotFound)
+			return
+		}
+		h.logger.Error("failed to get service for env update", "error", err, "id", id)
+		errorWithRequestID(w, r, "internal server error", http.StatusInternalServerError)
+		return
+	}
+
+	if svc.Status == models.ServiceStatusDeleting {
+		errorWithRequestID(w, r, "service is being deleted", http.StatusConflict)
+		return
+	}
+	if req.Generation != 0 && req.Generation != svc.Generation {
+		errorWithRequestID(w, r, "service was modified concurrently, reload and retry", http.StatusConflict)
+		return
+	}
+
+	current := svc
+	applyServiceUpdate(&svc, models.UpdateServiceRequest{EnvVars: req.Set, RemoveEnv: req.Unset})
+
+	// Ohne effektive Änderung kein Redeploy: die bestehende Traffic-Aufteilung bleibt erhalten
+	if maps.Equal(current.EnvVars, svc.EnvVars) && maps.Equal(current.SecretEnv, svc.SecretEnv) {
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(current)
+		return
+	}
+
+	if !checkTrafficReset(w, r, current, "", req.ResetTraffic) {
+		return
+	}
+	if msg := validateServiceSpec(svc); msg != "" {
+		errorWithRequestID(w, r, msg, http.StatusBadRequest)
+		return
+	}
+
+	updated, err := h.store.Update(r.Context(), svc)
+	if err != nil {
+		if errors.Is(err, store.ErrNotFound) {
+			http.Error(w, `{"error":"service not found"}`, http.StatusNotFound)
+			return
+		}
+		if errors.Is(err, store.ErrConflict) {
+			errorWithRequestID(w, r, "service was modified concurrently, reload and retry", http.StatusConflict)
+			return
+		}
+		h.logger.Error("failed to update service env", "error", err, "id", id)
+		errorWithRequestID(w, r, "internal server error", http.StatusInternalServerError)
+		return
+	}
+
+	h.logger.Info("service env updated", "id", updated.ID, "generation", updated.Generation, "actor", auth.Actor(r.Context()))
+	message := fmt.Sprintf("environment changed (generation %d)", updated.Generation)
+	if len(current.Traffic) > 0 {
+		message += ", traffic split reset"
+	}
+	h.recordEvent(r.Context(), updated, models.ServiceEventUpdated, message)
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(updated)
+}
+
+// validateEnvVars prüft die Namen der Umgebungsvariablen einer Spec.
+func validateEnvVars(envVars map[string]string) string {
+	for _, k := range slices.Sorted(maps.Keys(envVars)) {
+		if k == "" {
+			return "env var names must not be empty"
+		}
+		if strings.ContainsAny(k, "= \t\n") {
+			return fmt.Sprintf("invalid env var name %q", k)
+		}
+	}
+	return ""
+}
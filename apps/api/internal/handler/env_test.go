@@ -0,0 +1,169 @@
+package handler
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/max-cloud/shared/pkg/models"
+)
+
+func envRouter(h *Handler) *chi.Mux {
+	r := chi.NewRouter()
+	r.Post("/api/v1/services", h.CreateService)
+	r.Get("/api/v1/services/{id}/env", h.GetServiceEnv)
+	r.Patch("/api/v1/services/{id}/env", h.UpdateServiceEnv)
+	r.Get("/api/v1/services/{id}/revisions", h.ListRevisions)
+	return r
+}
+
+func TestServiceEnv(t *testing.T) {
+	h, _ := setup()
+	r := envRouter(h)
+
+	payload := `{"name":"app","image":"nginx:latest","env_vars":{"LOG_LEVEL":"info","KEEP":"1"}}`
+	req := httptest.NewRequest("POST", "/api/v1/services", bytes.NewBufferString(payload))
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+	if w.Code != http.StatusCreated {
+		t.Fatalf("expected 201, got %d: %s", w.Code, w.Body.String())
+	}
+	var svc models.Service
+	json.NewDecoder(w.Body).Decode(&svc)
+
+	req = httptest.NewRequest("PATCH", "/api/v1/services/"+svc.ID+"/env",
+		bytes.NewBufferString(`{"set":{"LOG_LEVEL":"debug","NEW":"x"},"unset":["KEEP"]}`))
+	w = httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+	if w.Code != http.StatusOK {
+		t.Fatalf("expected 200, got %d: %s", w.Code, w.Body.String())
+	}
+	var updated models.Service
+	json.NewDecoder(w.Body).Decode(&updated)
+	if updated.Generation != svc.Generation+1 {
+		t.Fatalf("expected generation %d, got %d", svc.Generation+1, updated.Generation)
+	}
+	if updated.Status != models.ServiceStatusPending {
+		t.Fatalf("expected status pending, got %s", updated.Status)
+	}
+
+	req = httptest.NewRequest("GET", "/api/v1/services/"+svc.ID+"/env", nil)
+	w = httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+	var env models.ServiceEnv
+	json.NewDecoder(w.Body).Decode(&env)
+	if len(env.EnvVars) != 2 || env.EnvVars["LOG_LEVEL"] != "debug" || env.EnvVars["NEW"] != "x" {
+		t.Fatalf("unexpected env: %v", env.EnvVars)
+	}
+	if env.SecretEnv == nil {
+		t.Fatal("expected secret_env to be an empty object")
+	}
+
+	// Ohne effektive Änderung entsteht keine neue Revision
+	req = httptest.NewRequest("PATCH", "/api/v1/services/"+svc.ID+"/env",
+		bytes.NewBufferString(`{"set":{"NEW":"x"},"unset":["MISSING"]}`))
+	w = httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+	var unchanged models.Service
+	json.NewDecoder(w.Body).Decode(&unchanged)
+	if unchanged.Generation != updated.Generation {
+		t.Fatalf("expected generation %d to stay, got %d", updated.Generation, unchanged.Generation)
+	}
+
+	req = httptest.NewRequest("GET", "/api/v1/services/"+svc.ID+"/revisions", nil)
+	w = httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+	var revisions []models.Revision
+	json.NewDecoder(w.Body).Decode(&revisions)
+	if len(revisions) != 2 {
+		t.Fatalf("expected 2 revisions, got %d", len(revisions))
+	}
+	if revisions[0].EnvVars["LOG_LEVEL"] != "debug" || revisions[1].EnvVars["LOG_LEVEL"] != "info" {
+		t.Fatalf("unexpected revision env: %v / %v", revisions[0].EnvVars, revisions[1].EnvVars)
+	}
+}
+
+func TestServiceEnvWithTrafficSplit(t *testing.T) {
+	h, s := setup()
+	r := envRouter(h)
+	r.Put("/api/v1/services/{id}/traffic", h.SetTraffic)
+
+	created, _ := s.Create(context.Background(), models.DeployRequest{Name: "app", Image: "img:1"})
+	created.Image = "img:2"
+	if _, err := s.Update(context.Background(), created); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	body := `{"targets":[{"revision_name":"app-00001","percent":90},{"revision_name":"app-00002","percent":10}]}`
+	req := httptest.NewRequest("PUT", "/api/v1/services/"+created.ID+"/traffic", bytes.NewBufferString(body))
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+	if w.Code != http.StatusOK {
+		t.Fatalf("expected 200, got %d: %s", w.Code, w.Body.String())
+	}
+	var split models.Service
+	json.NewDecoder(w.Body).Decode(&split)
+
+	// Die Aufteilung wird nicht stillschweigend verworfen
+	req = httptest.NewRequest("PATCH", "/api/v1/services/"+created.ID+"/env", bytes.NewBufferString(`{"set":{"LOG_LEVEL":"debug"}}`))
+	w = httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+	if w.Code != http.StatusConflict {
+		t.Fatalf("expected 409, got %d: %s", w.Code, w.Body.String())
+	}
+	unchanged, _ := s.Get(context.Background(), created.ID)
+	if unchanged.Generation != split.Generation || len(unchanged.Traffic) != 2 || len(unchanged.EnvVars) != 0 {
+		t.Fatalf("expected service unchanged, got generation=%d traffic=%+v env=%v", unchanged.Generation, unchanged.Traffic, unchanged.EnvVars)
+	}
+
+	req = httptest.NewRequest("PATCH", "/api/v1/services/"+created.ID+"/env", bytes.NewBufferString(`{"set":{"LOG_LEVEL":"debug"},"reset_traffic":true}`))
+	w = httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+	if w.Code != http.StatusOK {
+		t.Fatalf("expected 200, got %d: %s", w.Code, w.Body.String())
+	}
+	var updated models.Service
+	json.NewDecoder(w.Body).Decode(&updated)
+	if updated.Traffic != nil || updated.EnvVars["LOG_LEVEL"] != "debug" || updated.Generation != split.Generation+1 {
+		t.Fatalf("expected reset traffic with new env, got generation=%d traffic=%+v env=%v", updated.Generation, updated.Traffic, updated.EnvVars)
+	}
+}
+
+func TestServiceEnvValidation(t *testing.T) {
+	h, _ := setup()
+	r := envRouter(h)
+
+	req := httptest.NewRequest("POST", "/api/v1/services", bytes.NewBufferString(`{"name":"app","image":"nginx:latest"}`))
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+	var svc models.Service
+	json.NewDecoder(w.Body).Decode(&svc)
+
+	tests := []struct {
+		name string
+		id   string
+		body string
+		code int
+	}{
+		{"empty request", svc.ID, `{}`, http.StatusBadRequest},
+		{"invalid json", svc.ID, `{`, http.StatusBadRequest},
+		{"empty name", svc.ID, `{"set":{"":"x"}}`, http.StatusBadRequest},
+		{"name with equals", svc.ID, `{"set":{"A=B":"x"}}`, http.StatusBadRequest},
+		{"stale generation", svc.ID, `{"set":{"A":"x"},"generation":42}`, http.StatusConflict},
+		{"unknown service", "00000000-0000-0000-0000-000000000000", `{"set":{"A":"x"}}`, http.StatusNotFound},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest("PATCH", "/api/v1/services/"+tt.id+"/env", bytes.NewBufferString(tt.body))
+			w := httptest.NewRecorder()
+			r.ServeHTTP(w, req)
+			if w.Code != tt.code {
+				t.Fatalf("expected %d, got %d: %s", tt.code, w.Code, w.Body.String())
+			}
+		})
+	}
+}
@@ -0,0 +1,108 @@
+package handler
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"slices"
+	"strconv"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/max-cloud/api/internal/auth"
+	"github.com/max-cloud/api/internal/store"
+	"github.com/max-cloud/shared/pkg/models"
+)
+
+// ListServiceEvents gibt die Ereignishistorie eines Services zurück, neueste zuerst.
+// Die Kubernetes-Events der Pods werden vom Orchestrator ergänzt. Nach dem Löschen
+// bleibt die gespeicherte Historie abrufbar, dann ohne Kubernetes-Events.
+func (h *Handler) ListServiceEvents(w http.ResponseWriter, r *http.Request) {
+	id := chi.URLParam(r, "id")
+
+	limit := models.DefaultEventLimit
+	if v := r.URL.Query().Get("limit"); v != "" {
+		n, err := strconv.Atoi(v)
+		if err != nil || n < 1 || n > models.MaxEventLimit {
+			errorWithRequestID(w, r, "limit must be between 1 and "+strconv.Itoa(models.MaxEventLimit), http.StatusBadRequest)
+			return
+		}
+		limit = n
+	}
+
+	svc, err := h.store.Get(r.Context(), id)
+	if err != nil {
+		if isUUIDError(err) {
+			http.Error(w, `{"error":"service not found"}`, http.StatusNotFound)
+			return
+		}
+		if errors.Is(err, store.ErrNotFound) {
+			h.listDeletedServiceEvents(w, r, id, limit)
+			return
+		}
+		h.logger.Error("failed to get service for events", "error", err, "id", id)
+		errorWithRequestID(w, r, "internal server error", http.StatusInternalServerError)
+		return
+	}
+
+	events, err := h.eventStore.ListEvents(r.Context(), svc.ID, limit)
+	if err != nil {
+		h.logger.Error("failed to list events", "error", err, "id", svc.ID)
+		errorWithRequestID(w, r, "internal server error", http.StatusInternalServerError)
+		return
+	}
+
+	// Ohne Cluster-Events bleibt die gespeicherte Historie verwendbar
+	if h.orchestrator != nil {
+		clusterEvents, err := h.orchestrator.Events(r.Context(), svc)
+		if err != nil {
+			h.logger.Warn("failed to read cluster events", "error", err, "id", svc.ID)
+		}
+		events = append(events, clusterEvents...)
+		slices.SortStableFunc(events, func(a, b models.ServiceEvent) int {
+			return b.CreatedAt.Compare(a.CreatedAt)
+		})
+		if len(events) > limit {
+			events = events[:limit]
+		}
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(events)
+}
+
+// listDeletedServiceEvents gibt die gespeicherte Historie eines gelöschten Services zurück.
+// Da der Service fehlt, wird die Organisation anhand der Ereignisse geprüft.
+func (h *Handler) listDeletedServiceEvents(w http.ResponseWriter, r *http.Request, id string, limit int) {
+	events, err := h.eventStore.ListEvents(r.Context(), id, limit)
+	if err != nil {
+		h.logger.Error("failed to list events", "error", err, "id", id)
+		errorWithRequestID(w, r, "internal server error", http.StatusInternalServerError)
+		return
+	}
+	if orgID, ok := auth.OrgIDFromContext(r.Context()); ok {
+		events = slices.DeleteFunc(events, func(e models.ServiceEvent) bool { return e.OrgID != orgID })
+	}
+	if len(events) == 0 {
+		http.Error(w, `{"error":"service not found"}`, http.StatusNotFound)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(events)
+}
+
+// recordEvent hängt ein Ereignis an die Historie von svc an. Fehler werden nur geloggt,
+// damit die eigentliche Aktion nicht am Protokoll scheitert.
+func (h *Handler) recordEvent(ctx context.Context, svc models.Service, eventType models.ServiceEventType, message string) {
+	err := h.eventStore.RecordEvent(ctx, models.ServiceEvent{
+		ServiceID: svc.ID,
+		OrgID:     svc.OrgID,
+		Type:      eventType,
+		Message:   message,
+		Actor:     auth.Actor(ctx),
+	})
+	if err != nil {
+		h.logger.Error("failed to record event", "error", err, "id", svc.ID, "type", eventType)
+	}
+}
@@ -0,0 +1,171 @@
+package handler
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/max-cloud/api/internal/auth"
+	"github.com/max-cloud/shared/pkg/models"
+)
+
+func eventsRouter(h *Handler) *chi.Mux {
+	r := chi.NewRouter()
+	r.Post("/api/v1/services", h.CreateService)
+	r.Patch("/api/v1/services/{id}", h.UpdateService)
+	r.Delete("/api/v1/services/{id}", h.DeleteService)
+	r.Get("/api/v1/services/{id}/events", h.ListServiceEvents)
+	return r
+}
+
+func TestServiceEvents(t *testing.T) {
+	orch := &mockOrchestrator{events: []models.ServiceEvent{{
+		Type:      models.ServiceEventKubernetes,
+		Reason:    "BackOff",
+		Message:   "pod app-00001-deployment-abc: Back-off restarting failed container",
+		CreatedAt: time.Now().Add(time.Hour),
+	}}}
+	h, s := setupWithMockOrch(orch)
+	r := eventsRouter(h)
+
+	req := httptest.NewRequest("POST", "/api/v1/services", bytes.NewBufferString(`{"name":"app","image":"nginx:latest"}`))
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+	var svc models.Service
+	json.NewDecoder(w.Body).Decode(&svc)
+
+	req = httptest.NewRequest("PATCH", "/api/v1/services/"+svc.ID, bytes.NewBufferString(`{"min_scale":1,"max_scale":5}`))
+	w = httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+	if w.Code != http.StatusOK {
+		t.Fatalf("expected 200, got %d: %s", w.Code, w.Body.String())
+	}
+
+	req = httptest.NewRequest("GET", "/api/v1/services/"+svc.ID+"/events", nil)
+	w = httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+	if w.Code != http.StatusOK {
+		t.Fatalf("expected 200, got %d: %s", w.Code, w.Body.String())
+	}
+	var events []models.ServiceEvent
+	json.NewDecoder(w.Body).Decode(&events)
+
+	want := []models.ServiceEventType{models.ServiceEventKubernetes, models.ServiceEventScaled, models.ServiceEventCreated}
+	if len(events) != len(want) {
+		t.Fatalf("expected %d events, got %+v", len(want), events)
+	}
+	for i, typ := range want {
+		if events[i].Type != typ {
+			t.Fatalf("expected event %d to be %s, got %s", i, typ, events[i].Type)
+		}
+	}
+
+	req = httptest.NewRequest("GET", "/api/v1/services/"+svc.ID+"/events?limit=1", nil)
+	w = httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+	json.NewDecoder(w.Body).Decode(&events)
+	if len(events) != 1 {
+		t.Fatalf("expected 1 event with limit, got %d", len(events))
+	}
+
+	// Das Löschen wird protokolliert, auch wenn der Service danach nicht mehr abrufbar ist
+	req = httptest.NewRequest("DELETE", "/api/v1/services/"+svc.ID, nil)
+	w = httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+	stored, _ := s.ListEvents(req.Context(), svc.ID, 10)
+	if len(stored) == 0 || stored[0].Type != models.ServiceEventDeleted {
+		t.Fatalf("expected deleted event, got %+v", stored)
+	}
+}
+
+func TestServiceEventsAfterDelete(t *testing.T) {
+	orch := &mockOrchestrator{events: []models.ServiceEvent{{
+		Type:      models.ServiceEventKubernetes,
+		Reason:    "Killing",
+		CreatedAt: time.Now().Add(time.Hour),
+	}}}
+	h, s := setupWithMockOrch(orch)
+	r := eventsRouter(h)
+	ctx := auth.WithTenant(context.Background(), "org-1", "user-1")
+
+	req := httptest.NewRequest("POST", "/api/v1/services", bytes.NewBufferString(`{"name":"app","image":"nginx:latest"}`)).WithContext(ctx)
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+	var svc models.Service
+	json.NewDecoder(w.Body).Decode(&svc)
+
+	req = httptest.NewRequest("DELETE", "/api/v1/services/"+svc.ID, nil).WithContext(ctx)
+	w = httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+	if w.Code != http.StatusNoContent {
+		t.Fatalf("expected 204, got %d: %s", w.Code, w.Body.String())
+	}
+	if _, err := s.Get(ctx, svc.ID); err == nil {
+		t.Fatal("expected service to be gone")
+	}
+
+	// Die gespeicherte Historie bleibt abrufbar, Kubernetes-Events entfallen
+	req = httptest.NewRequest("GET", "/api/v1/services/"+svc.ID+"/events", nil).WithContext(ctx)
+	w = httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+	if w.Code != http.StatusOK {
+		t.Fatalf("expected 200, got %d: %s", w.Code, w.Body.String())
+	}
+	var events []models.ServiceEvent
+	json.NewDecoder(w.Body).Decode(&events)
+	want := []models.ServiceEventType{models.ServiceEventDeleted, models.ServiceEventCreated}
+	if len(events) != len(want) {
+		t.Fatalf("expected %d events, got %+v", len(want), events)
+	}
+	for i, typ := range want {
+		if events[i].Type != typ {
+			t.Fatalf("expected event %d to be %s, got %s", i, typ, events[i].Type)
+		}
+	}
+
+	// Andere Organisationen sehen die Historie nicht
+	other := auth.WithTenant(context.Background(), "org-2", "user-2")
+	req = httptest.NewRequest("GET", "/api/v1/services/"+svc.ID+"/events", nil).WithContext(other)
+	w = httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+	if w.Code != http.StatusNotFound {
+		t.Fatalf("expected 404 for foreign organization, got %d", w.Code)
+	}
+}
+
+func TestServiceEventsValidation(t *testing.T) {
+	h, _ := setup()
+	r := eventsRouter(h)
+
+	req := httptest.NewRequest("POST", "/api/v1/services", bytes.NewBufferString(`{"name":"app","image":"nginx:latest"}`))
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+	var svc models.Service
+	json.NewDecoder(w.Body).Decode(&svc)
+
+	tests := []struct {
+		name string
+		path string
+		code int
+	}{
+		{"zero limit", "/api/v1/services/" + svc.ID + "/events?limit=0", http.StatusBadRequest},
+		{"limit too large", "/api/v1/services/" + svc.ID + "/events?limit=100000", http.StatusBadRequest},
+		{"invalid limit", "/api/v1/services/" + svc.ID + "/events?limit=abc", http.StatusBadRequest},
+		{"unknown service", "/api/v1/services/00000000-0000-0000-0000-000000000000/events", http.StatusNotFound},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest("GET", tt.path, nil)
+			w := httptest.NewRecorder()
+			r.ServeHTTP(w, req)
+			if w.Code != tt.code {
+				t.Fatalf("expected %d, got %d: %s", tt.code, w.Code, w.Body.String())
+			}
+		})
+	}
+}
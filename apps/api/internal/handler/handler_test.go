@@ -4,6 +4,7 @@ import (
 	"bytes"
 	"context"
 	"encoding/json"
+	"fmt"
 	"log/slog"
 	"net/http"
 	"net/http/httptest"
@@ -11,13 +12,14 @@ import (
 	"time"
 
 	"github.com/go-chi/chi/v5"
+	"github.com/max-cloud/api/internal/auth"
 	"github.com/max-cloud/api/internal/store"
 	"github.com/max-cloud/shared/pkg/models"
 )
 
 func setup() (*Handler, *store.MemoryStore) {
 	s := store.NewMemory()
-	h := New(slog.Default(), s, s, nil, nil, 24*time.Hour, true, "registry.local", "test-secret", 1*time.Hour)
+	h := New(slog.Default(), s, s, s, s, s, nil, nil, 24*time.Hour, true, "registry.local", "test-secret", 1*time.Hour)
 	return h, s
 }
 
@@ -99,6 +101,153 @@ func TestCreateServiceWithPortCommandArgs(t *testing.T) {
 	}
 }
 
+func TestCreateServiceResources(t *testing.T) {
+	h, _ := setup()
+
+	tests := []struct {
+		name    string
+		payload string
+		cpu     string
+		memory  string
+	}{
+		{"defaults", `{"name":"a","image":"nginx:latest"}`, models.DefaultCPU, models.DefaultMemory},
+		{"explicit tiers", `{"name":"b","image":"nginx:latest","cpu":"250m","memory":"2Gi"}`, "250m", "2Gi"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest("POST", "/api/v1/services", bytes.NewBufferString(tt.payload))
+			w := httptest.NewRecorder()
+			h.CreateService(w, req)
+			if w.Code != http.StatusCreated {
+				t.Fatalf("expected 201, got %d: %s", w.Code, w.Body.String())
+			}
+
+			var svc models.Service
+			json.NewDecoder(w.Body).Decode(&svc)
+			if svc.CPU != tt.cpu || svc.Memory != tt.memory {
+				t.Fatalf("expected cpu=%s memory=%s, got cpu=%s memory=%s", tt.cpu, tt.memory, svc.CPU, svc.Memory)
+			}
+		})
+	}
+}
+
+func TestCreateServiceAutoscaling(t *testing.T) {
+	h, _ := setup()
+	payload := `{"name":"api","image":"node:20","min_scale":1,"max_scale":5,"container_concurrency":80,"autoscaling_target":70,"scale_down_delay_seconds":60,"scale_to_zero_retention_seconds":300}`
+	req := httptest.NewRequest("POST", "/api/v1/services", bytes.NewBufferString(payload))
+	w := httptest.NewRecorder()
+
+	h.CreateService(w, req)
+
+	if w.Code != http.StatusCreated {
+		t.Fatalf("expected 201, got %d: %s", w.Code, w.Body.String())
+	}
+
+	var svc models.Service
+	json.NewDecoder(w.Body).Decode(&svc)
+	if svc.MinScale != 1 || svc.MaxScale != 5 {
+		t.Fatalf("expected scale 1-5, got %d-%d", svc.MinScale, svc.MaxScale)
+	}
+	if svc.ContainerConcurrency != 80 || svc.AutoscalingTarget != 70 {
+		t.Fatalf("expected concurrency 80 target 70, got %d %d", svc.ContainerConcurrency, svc.AutoscalingTarget)
+	}
+	if svc.ScaleDownDelay != 60 || svc.ScaleToZeroRetention != 300 {
+		t.Fatalf("expected delays 60/300, got %d/%d", svc.ScaleDownDelay, svc.ScaleToZeroRetention)
+	}
+}
+
+func TestCreateServiceTimeouts(t *testing.T) {
+	h, s := setup()
+	payload := `{"name":"reports","image":"node:20","timeout_seconds":900,"response_start_timeout_seconds":600,"idle_timeout_seconds":120}`
+	req := httptest.NewRequest("POST", "/api/v1/services", bytes.NewBufferString(payload))
+	w := httptest.NewRecorder()
+
+	h.CreateService(w, req)
+
+	if w.Code != http.StatusCreated {
+		t.Fatalf("expected 201, got %d: %s", w.Code, w.Body.String())
+	}
+
+	var svc models.Service
+	json.NewDecoder(w.Body).Decode(&svc)
+	if svc.TimeoutSeconds != 900 || svc.ResponseStartTimeoutSeconds != 600 || svc.IdleTimeoutSeconds != 120 {
+		t.Fatalf("expected timeouts 900/600/120, got %d/%d/%d", svc.TimeoutSeconds, svc.ResponseStartTimeoutSeconds, svc.IdleTimeoutSeconds)
+	}
+
+	revisions, _ := s.ListRevisions(context.Background(), svc.ID)
+	if len(revisions) != 1 || revisions[0].TimeoutSeconds != 900 {
+		t.Fatalf("expected timeout recorded in revision, got %+v", revisions)
+	}
+}
+
+func TestServiceProbes(t *testing.T) {
+	h, _ := setup()
+	payload := `{"name":"slowboot","image":"node:20","probes":{"readiness":{"path":"/healthz"},"startup":{"path":"/healthz","period_seconds":5,"failure_threshold":24}}}`
+	req := httptest.NewRequest("POST", "/api/v1/services", bytes.NewBufferString(payload))
+	w := httptest.NewRecorder()
+
+	h.CreateService(w, req)
+
+	if w.Code != http.StatusCreated {
+		t.Fatalf("expected 201, got %d: %s", w.Code, w.Body.String())
+	}
+
+	var svc models.Service
+	json.NewDecoder(w.Body).Decode(&svc)
+	if svc.Probes == nil || svc.Probes.Startup == nil || svc.Probes.Startup.FailureThreshold != 24 {
+		t.Fatalf("expected startup probe with failure threshold 24, got %+v", svc.Probes)
+	}
+	if svc.Probes.Liveness != nil {
+		t.Fatalf("expected no liveness probe, got %+v", svc.Probes.Liveness)
+	}
+
+	// Ein leeres Objekt entfernt alle Probes
+	r := chi.NewRouter()
+	r.Patch("/api/v1/services/{id}", h.UpdateService)
+	req = httptest.NewRequest("PATCH", "/api/v1/services/"+svc.ID, bytes.NewBufferString(`{"probes":{}}`))
+	w = httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+	if w.Code != http.StatusOK {
+		t.Fatalf("expected 200, got %d: %s", w.Code, w.Body.String())
+	}
+
+	var updated models.Service
+	json.NewDecoder(w.Body).Decode(&updated)
+	if updated.Probes != nil {
+		t.Fatalf("expected probes removed, got %+v", updated.Probes)
+	}
+}
+
+func TestCreateServiceOrgScaleLimit(t *testing.T) {
+	h, s := setup()
+	_, org, _, err := s.Register(context.Background(), "a@example.com", "Org1")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	ctx := auth.WithTenant(context.Background(), org.ID, "user-1")
+
+	tests := []struct {
+		name    string
+		payload string
+		code    int
+	}{
+		{"at limit", fmt.Sprintf(`{"name":"a","image":"nginx:latest","max_scale":%d}`, models.DefaultMaxScaleLimit), http.StatusCreated},
+		{"above limit", fmt.Sprintf(`{"name":"b","image":"nginx:latest","max_scale":%d}`, models.DefaultMaxScaleLimit+1), http.StatusBadRequest},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest("POST", "/api/v1/services", bytes.NewBufferString(tt.payload)).WithContext(ctx)
+			w := httptest.NewRecorder()
+			h.CreateService(w, req)
+			if w.Code != tt.code {
+				t.Fatalf("expected %d, got %d: %s", tt.code, w.Code, w.Body.String())
+			}
+		})
+	}
+}
+
 func TestCreateServiceValidation(t *testing.T) {
 	h, _ := setup()
 
@@ -109,6 +258,18 @@ func TestCreateServiceValidation(t *testing.T) {
 		{"missing name", `{"image":"nginx:latest"}`},
 		{"missing image", `{"name":"myapp"}`},
 		{"invalid json", `{invalid`},
+		{"unknown cpu tier", `{"name":"myapp","image":"nginx:latest","cpu":"3"}`},
+		{"unknown memory tier", `{"name":"myapp","image":"nginx:latest","memory":"1.5Gi"}`},
+		{"min greater than max", `{"name":"myapp","image":"nginx:latest","min_scale":5,"max_scale":2}`},
+		{"max above org limit", `{"name":"myapp","image":"nginx:latest","max_scale":500}`},
+		{"target above concurrency", `{"name":"myapp","image":"nginx:latest","container_concurrency":10,"autoscaling_target":20}`},
+		{"negative scale down delay", `{"name":"myapp","image":"nginx:latest","scale_down_delay_seconds":-1}`},
+		{"retention too long", `{"name":"myapp","image":"nginx:latest","scale_to_zero_retention_seconds":7200}`},
+		{"timeout too long", `{"name":"myapp","image":"nginx:latest","timeout_seconds":7200}`},
+		{"response start above timeout", `{"name":"myapp","image":"nginx:latest","timeout_seconds":60,"response_start_timeout_seconds":120}`},
+		{"probe path without slash", `{"name":"myapp","image":"nginx:latest","probes":{"readiness":{"path":"healthz"}}}`},
+		{"unknown probe type", `{"name":"myapp","image":"nginx:latest","probes":{"liveness":{"type":"exec"}}}`},
+		{"liveness success threshold", `{"name":"myapp","image":"nginx:latest","probes":{"liveness":{"path":"/","success_threshold":3}}}`},
 	}
 
 	for _, tt := range tests {
@@ -230,3 +391,113 @@ func TestDeleteServiceNotFound(t *testing.T) {
 		t.Fatalf("expected 404, got %d", w.Code)
 	}
 }
+
+func TestUpdateService(t *testing.T) {
+	h, s := setup()
+	created, _ := s.Create(context.Background(), models.DeployRequest{
+		Name:    "app",
+		Image:   "img:1",
+		EnvVars: map[string]string{"KEEP": "1", "DROP": "x"},
+	})
+	s.UpdateStatus(context.Background(), created.ID, models.ServiceStatusReady, "https://app.maxcloud.dev")
+
+	r := chi.NewRouter()
+	r.Patch("/api/v1/services/{id}", h.UpdateService)
+
+	payload := `{"image":"img:2","env_vars":{"NEW":"2"},"remove_env":["DROP"]}`
+	req := httptest.NewRequest("PATCH", "/api/v1/services/"+created.ID, bytes.NewBufferString(payload))
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+
+	if w.Code != http.StatusOK {
+		t.Fatalf("expected 200, got %d: %s", w.Code, w.Body.String())
+	}
+
+	var svc models.Service
+	json.NewDecoder(w.Body).Decode(&svc)
+	if svc.Image != "img:2" {
+		t.Fatalf("expected image img:2, got %s", svc.Image)
+	}
+	if svc.Status != models.ServiceStatusPending {
+		t.Fatalf("expected pending, got %s", svc.Status)
+	}
+	if svc.EnvVars["KEEP"] != "1" || svc.EnvVars["NEW"] != "2" {
+		t.Fatalf("expected merged env vars, got %v", svc.EnvVars)
+	}
+	if _, ok := svc.EnvVars["DROP"]; ok {
+		t.Fatalf("expected DROP to be removed, got %v", svc.EnvVars)
+	}
+	if svc.Generation != created.Generation+1 {
+		t.Fatalf("expected generation %d, got %d", created.Generation+1, svc.Generation)
+	}
+	if svc.URL != "https://app.maxcloud.dev" {
+		t.Fatalf("expected URL to be kept, got %s", svc.URL)
+	}
+}
+
+func TestUpdateServiceConflict(t *testing.T) {
+	h, s := setup()
+	created, _ := s.Create(context.Background(), models.DeployRequest{Name: "app", Image: "img:1"})
+
+	r := chi.NewRouter()
+	r.Patch("/api/v1/services/{id}", h.UpdateService)
+
+	payload := fmt.Sprintf(`{"image":"img:2","generation":%d}`, created.Generation+5)
+	req := httptest.NewRequest("PATCH", "/api/v1/services/"+created.ID, bytes.NewBufferString(payload))
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+
+	if w.Code != http.StatusConflict {
+		t.Fatalf("expected 409, got %d", w.Code)
+	}
+}
+
+func TestUpdateServiceValidation(t *testing.T) {
+	h, s := setup()
+	created, _ := s.Create(context.Background(), models.DeployRequest{Name: "app", Image: "img:1"})
+
+	r := chi.NewRouter()
+	r.Patch("/api/v1/services/{id}", h.UpdateService)
+
+	tests := []struct {
+		name    string
+		payload string
+	}{
+		{"empty image", `{"image":""}`},
+		{"invalid port", `{"port":70000}`},
+		{"min greater than max", `{"min_scale":5,"max_scale":2}`},
+		{"unknown cpu tier", `{"cpu":"100"}`},
+		{"unknown memory tier", `{"memory":"64Ki"}`},
+		{"max above org limit", `{"max_scale":500}`},
+		{"concurrency too high", `{"container_concurrency":5000}`},
+		{"negative idle timeout", `{"idle_timeout_seconds":-1}`},
+		{"tcp probe with path", `{"probes":{"startup":{"type":"tcp","path":"/healthz"}}}`},
+		{"invalid json", `{invalid`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest("PATCH", "/api/v1/services/"+created.ID, bytes.NewBufferString(tt.payload))
+			w := httptest.NewRecorder()
+			r.ServeHTTP(w, req)
+			if w.Code != http.StatusBadRequest {
+				t.Fatalf("expected 400, got %d", w.Code)
+			}
+		})
+	}
+}
+
+func TestUpdateServiceNotFound(t *testing.T) {
+	h, _ := setup()
+
+	r := chi.NewRouter()
+	r.Patch("/api/v1/services/{id}", h.UpdateService)
+
+	req := httptest.NewRequest("PATCH", "/api/v1/services/nonexistent", bytes.NewBufferString(`{"image":"img:2"}`))
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+
+	if w.Code != http.StatusNotFound {
+		t.Fatalf("expected 404, got %d", w.Code)
+	}
+}
@@ -1,15 +1,21 @@
 package handler
 
 import (
+	"cmp"
+	"context"
 	"encoding/json"
 	"errors"
+	"fmt"
 	"log/slog"
+	"net"
 	"net/http"
+	"slices"
 	"strings"
 	"time"
 
 	"github.com/go-chi/chi/v5"
 	"github.com/go-chi/chi/v5/middleware"
+	"github.com/max-cloud/api/internal/auth"
 	"github.com/max-cloud/api/internal/email"
 	"github.com/max-cloud/api/internal/orchestrator"
 	"github.com/max-cloud/api/internal/store"
@@ -20,6 +26,9 @@ type Handler struct {
 	logger              *slog.Logger
 	store               store.ServiceStore
 	authStore           store.AuthStore
+	secretStore         store.SecretStore
+	domainStore         store.DomainStore
+	eventStore          store.EventStore
 	orchestrator        orchestrator.Orchestrator
 	emailSender         email.Sender
 	inviteExpiry        time.Duration
@@ -27,13 +36,19 @@ type Handler struct {
 	registryURL         string
 	registryJWTSecret   string
 	registryTokenExpiry time.Duration
+
+	// lookupTXT löst TXT-Records für die Domain-Verifizierung auf (in Tests ersetzbar).
+	lookupTXT func(ctx context.Context, name string) ([]string, error)
 }
 
-func New(logger *slog.Logger, st store.ServiceStore, authSt store.AuthStore, orch orchestrator.Orchestrator, emailSender email.Sender, inviteExpiry time.Duration, devMode bool, registryURL string, registryJWTSecret string, registryTokenExpiry time.Duration) *Handler {
+func New(logger *slog.Logger, st store.ServiceStore, authSt store.AuthStore, secretSt store.SecretStore, domainSt store.DomainStore, eventSt store.EventStore, orch orchestrator.Orchestrator, emailSender email.Sender, inviteExpiry time.Duration, devMode bool, registryURL string, registryJWTSecret string, registryTokenExpiry time.Duration) *Handler {
 	return &Handler{
 		logger:              logger,
 		store:               st,
 		authStore:           authSt,
+		secretStore:         secretSt,
+		domainStore:         domainSt,
+		eventStore:          eventSt,
 		orchestrator:        orch,
 		emailSender:         emailSender,
 		inviteExpiry:        inviteExpiry,
@@ -41,6 +56,7 @@ func New(logger *slog.Logger, st store.ServiceStore, authSt store.AuthStore, orc
 		registryURL:         registryURL,
 		registryJWTSecret:   registryJWTSecret,
 		registryTokenExpiry: registryTokenExpiry,
+		lookupTXT:           net.DefaultResolver.LookupTXT,
 	}
 }
 
@@ -71,6 +87,42 @@ func (h *Handler) CreateService(w http.ResponseWriter, r *http.Request) {
 		errorWithRequestID(w, r, "name and image are required", http.StatusBadRequest)
 		return
 	}
+	if req.Tag != "" {
+		if msg := validateTag(req.Tag); msg != "" {
+			errorWithRequestID(w, r, msg, http.StatusBadRequest)
+			return
+		}
+	}
+
+	// Spec mit Defaults wie im Store, damit die Validierung dieselben Werte sieht
+	spec := models.Service{
+		Image:                       req.Image,
+		Port:                        req.Port,
+		MinScale:                    req.MinScale,
+		MaxScale:                    cmp.Or(req.MaxScale, models.DefaultMaxScale),
+		CPU:                         cmp.Or(req.CPU, models.DefaultCPU),
+		Memory:                      cmp.Or(req.Memory, models.DefaultMemory),
+		ContainerConcurrency:        req.ContainerConcurrency,
+		AutoscalingTarget:           req.AutoscalingTarget,
+		ScaleDownDelay:              req.ScaleDownDelay,
+		ScaleToZeroRetention:        req.ScaleToZeroRetention,
+		TimeoutSeconds:              req.TimeoutSeconds,
+		ResponseStartTimeoutSeconds: req.ResponseStartTimeoutSeconds,
+		IdleTimeoutSeconds:          req.IdleTimeoutSeconds,
+		Probes:                      req.Probes,
+		EnvVars:                     req.EnvVars,
+		SecretEnv:                   req.SecretEnv,
+	}
+	if msg := validateServiceSpec(spec); msg != "" {
+		errorWithRequestID(w, r, msg, http.StatusBadRequest)
+		return
+	}
+	if !h.checkScaleLimit(w, r, spec.MaxScale) {
+		return
+	}
+	if !h.checkSecretRefs(w, r, spec.SecretEnv) {
+		return
+	}
 
 	svc, err := h.store.Create(r.Context(), req)
 	if err != nil {
@@ -82,6 +134,7 @@ func (h *Handler) CreateService(w http.ResponseWriter, r *http.Request) {
 		errorWithRequestID(w, r, "internal server error", http.StatusInternalServerError)
 		return
 	}
+	h.recordEvent(r.Context(), svc, models.ServiceEventCreated, "service created with image "+svc.Image)
 
 	w.Header().Set("Content-Type", "application/json")
 	w.WriteHeader(http.StatusCreated)
@@ -118,6 +171,251 @@ func (h *Handler) GetService(w http.ResponseWriter, r *http.Request) {
 	json.NewEncoder(w).Encode(svc)
 }
 
+// UpdateService ändert die Spec eines bestehenden Services partiell und stößt ein Redeploy an.
+func (h *Handler) UpdateService(w http.ResponseWriter, r *http.Request) {
+	id := chi.URLParam(r, "id")
+
+	var req models.UpdateServiceRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		h.logger.Error("invalid request body", "error", err)
+		errorWithRequestID(w, r, "invalid JSON", http.StatusBadRequest)
+		return
+	}
+
+	svc, err := h.store.Get(r.Context(), id)
+	if err != nil {
+		if errors.Is(err, store.ErrNotFound) {
+			http.Error(w, `{"error":"service not found"}`, http.StatusNotFound)
+			return
+		}
+		h.logger.Error("failed to get service for update", "error", err, "id", id)
+		errorWithRequestID(w, r, "internal server error", http.StatusInternalServerError)
+		return
+	}
+
+	if svc.Status == models.ServiceStatusDeleting {
+		errorWithRequestID(w, r, "service is being deleted", http.StatusConflict)
+		return
+	}
+	if req.Generation != 0 && req.Generation != svc.Generation {
+		errorWithRequestID(w, r, "service was modified concurrently, reload and retry", http.StatusConflict)
+		return
+	}
+	if req.Tag != "" {
+		if msg := validateTag(req.Tag); msg != "" {
+			errorWithRequestID(w, r, msg, http.StatusBadRequest)
+			return
+		}
+	}
+	if !checkTrafficReset(w, r, svc, req.Tag, req.ResetTraffic) {
+		return
+	}
+
+	before := svc
+	applyServiceUpdate(&svc, req)
+
+	if !h.checkServiceSpec(w, r, svc) {
+		return
+	}
+
+	updated, err := h.store.Update(r.Context(), svc)
+	if err != nil {
+		if errors.Is(err, store.ErrNotFound) {
+			http.Error(w, `{"error":"service not found"}`, http.StatusNotFound)
+			return
+		}
+		if errors.Is(err, store.ErrConflict) {
+			errorWithRequestID(w, r, "service was modified concurrently, reload and retry", http.StatusConflict)
+			return
+		}
+		h.logger.Error("failed to update service", "error", err, "id", id)
+		errorWithRequestID(w, r, "internal server error", http.StatusInternalServerError)
+		return
+	}
+
+	h.logger.Info("service updated", "id", updated.ID, "generation", updated.Generation, "actor", auth.Actor(r.Context()))
+	eventType, message := updateEvent(before, updated)
+	h.recordEvent(r.Context(), updated, eventType, message)
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(updated)
+}
+
+// updateEvent beschreibt eine Spec-Änderung für die Ereignishistorie.
+// Ändert sich die Skalierung ohne neues Image, wird sie als scaled protokolliert.
+func updateEvent(before, after models.Service) (models.ServiceEventType, string) {
+	eventType := models.ServiceEventUpdated
+	message := fmt.Sprintf("spec updated (generation %d)", after.Generation)
+	if after.Image != before.Image {
+		message = fmt.Sprintf("image changed to %s (generation %d)", after.Image, after.Generation)
+	} else if after.MinScale != before.MinScale || after.MaxScale != before.MaxScale {
+		eventType = models.ServiceEventScaled
+		message = fmt.Sprintf("scale changed from %d-%d to %d-%d (generation %d)",
+			before.MinScale, before.MaxScale, after.MinScale, after.MaxScale, after.Generation)
+	}
+	if len(before.Traffic) > 0 && len(after.Traffic) == 0 {
+		message += ", traffic split reset"
+	}
+	return eventType, message
+}
+
+// applyServiceUpdate übernimmt alle gesetzten Felder aus req in svc.
+// Ohne Tag wird eine bestehende Traffic-Aufteilung verworfen und die neue Revision erhält 100%;
+// das muss der Aufrufer vorher per checkTrafficReset bestätigt haben.
+// Mit Tag bleibt die Aufteilung bestehen und die neue Revision ist nur über die Tag-URL erreichbar.
+func applyServiceUpdate(svc *models.Service, req models.UpdateServiceRequest) {
+	if req.Tag != "" {
+		svc.Traffic = stageTaggedRevision(*svc, req.Tag)
+	} else {
+		svc.Traffic = nil
+	}
+	if req.Image != nil {
+		svc.Image = *req.Image
+	}
+	if req.Port != nil {
+		svc.Port = *req.Port
+	}
+	if req.Command != nil {
+		svc.Command = *req.Command
+	}
+	if req.Args != nil {
+		svc.Args = *req.Args
+	}
+	if req.MinScale != nil {
+		svc.MinScale = *req.MinScale
+	}
+	if req.MaxScale != nil {
+		svc.MaxScale = *req.MaxScale
+	}
+	if req.CPU != nil {
+		svc.CPU = *req.CPU
+	}
+	if req.Memory != nil {
+		svc.Memory = *req.Memory
+	}
+	if req.ContainerConcurrency != nil {
+		svc.ContainerConcurrency = *req.ContainerConcurrency
+	}
+	if req.AutoscalingTarget != nil {
+		svc.AutoscalingTarget = *req.AutoscalingTarget
+	}
+	if req.ScaleDownDelay != nil {
+		svc.ScaleDownDelay = *req.ScaleDownDelay
+	}
+	if req.ScaleToZeroRetention != nil {
+		svc.ScaleToZeroRetention = *req.ScaleToZeroRetention
+	}
+	if req.TimeoutSeconds != nil {
+		svc.TimeoutSeconds = *req.TimeoutSeconds
+	}
+	if req.ResponseStartTimeoutSeconds != nil {
+		svc.ResponseStartTimeoutSeconds = *req.ResponseStartTimeoutSeconds
+	}
+	if req.IdleTimeoutSeconds != nil {
+		svc.IdleTimeoutSeconds = *req.IdleTimeoutSeconds
+	}
+	if req.Probes != nil {
+		svc.Probes = req.Probes.Clone()
+	}
+
+	if len(req.EnvVars) > 0 || len(req.SecretEnv) > 0 || len(req.RemoveEnv) > 0 {
+		merged := make(map[string]string, len(svc.EnvVars)+len(req.EnvVars))
+		for k, v := range svc.EnvVars {
+			merged[k] = v
+		}
+		mergedSecrets := make(map[string]string, len(svc.SecretEnv)+len(req.SecretEnv))
+		for k, v := range svc.SecretEnv {
+			mergedSecrets[k] = v
+		}
+		// Eine Variable ist entweder Klartext oder Secret-Referenz: das Update ersetzt die andere Art
+		for k, v := range req.EnvVars {
+			merged[k] = v
+			delete(mergedSecrets, k)
+		}
+		for k, v := range req.SecretEnv {
+			mergedSecrets[k] = v
+			delete(merged, k)
+		}
+		for _, k := range req.RemoveEnv {
+			delete(merged, k)
+			delete(mergedSecrets, k)
+		}
+		svc.EnvVars = merged
+		svc.SecretEnv = mergedSecrets
+	}
+}
+
+// checkServiceSpec prüft eine geänderte Spec auf Gültigkeit, das Skalierungslimit der
+// Organisation und vorhandene Secrets. Andernfalls wird die Fehlerantwort geschrieben.
+func (h *Handler) checkServiceSpec(w http.ResponseWriter, r *http.Request, svc models.Service) bool {
+	if msg := validateServiceSpec(svc); msg != "" {
+		errorWithRequestID(w, r, msg, http.StatusBadRequest)
+		return false
+	}
+	return h.checkScaleLimit(w, r, svc.MaxScale) && h.checkSecretRefs(w, r, svc.SecretEnv)
+}
+
+// validateServiceSpec prüft eine zusammengeführte Service-Spec und liefert eine Fehlermeldung oder "".
+func validateServiceSpec(svc models.Service) string {
+	if svc.Image == "" {
+		return "image must not be empty"
+	}
+	if svc.Port < 0 || svc.Port > 65535 {
+		return "port must be between 0 and 65535"
+	}
+	if svc.MinScale < 0 {
+		return "min_scale must not be negative"
+	}
+	if svc.MaxScale < svc.MinScale {
+		return "max_scale must be greater than or equal to min_scale"
+	}
+	if msg := validateAutoscaling(svc); msg != "" {
+		return msg
+	}
+	if msg := validateTimeouts(svc); msg != "" {
+		return msg
+	}
+	if msg := validateProbes(svc.Probes); msg != "" {
+		return msg
+	}
+	if msg := validateEnvVars(svc.EnvVars); msg != "" {
+		return msg
+	}
+	if msg := validateSecretEnv(svc.EnvVars, svc.SecretEnv); msg != "" {
+		return msg
+	}
+	return validateResources(svc.CPU, svc.Memory)
+}
+
+// validateResources prüft CPU und Memory gegen die erlaubten Tiers.
+func validateResources(cpu, memory string) string {
+	if !slices.Contains(models.CPUTiers, cpu) {
+		return "cpu must be one of " + strings.Join(models.CPUTiers, ", ")
+	}
+	if !slices.Contains(models.MemoryTiers, memory) {
+		return "memory must be one of " + strings.Join(models.MemoryTiers, ", ")
+	}
+	return ""
+}
+
+// validateTimeouts prüft die Request-Timeouts einer Service-Spec. 0 steht für den Knative-Default.
+// Der Response-Start-Timeout darf den Gesamt-Timeout nicht überschreiten.
+func validateTimeouts(svc models.Service) string {
+	if svc.TimeoutSeconds < 0 || svc.TimeoutSeconds > models.MaxTimeoutSeconds {
+		return fmt.Sprintf("timeout_seconds must be between 0 and %d", models.MaxTimeoutSeconds)
+	}
+	if svc.ResponseStartTimeoutSeconds < 0 || svc.ResponseStartTimeoutSeconds > models.MaxTimeoutSeconds {
+		return fmt.Sprintf("response_start_timeout_seconds must be between 0 and %d", models.MaxTimeoutSeconds)
+	}
+	if svc.IdleTimeoutSeconds < 0 || svc.IdleTimeoutSeconds > models.MaxTimeoutSeconds {
+		return fmt.Sprintf("idle_timeout_seconds must be between 0 and %d", models.MaxTimeoutSeconds)
+	}
+	if svc.TimeoutSeconds > 0 && svc.ResponseStartTimeoutSeconds > svc.TimeoutSeconds {
+		return "response_start_timeout_seconds must not exceed timeout_seconds"
+	}
+	return ""
+}
+
 func (h *Handler) DeleteService(w http.ResponseWriter, r *http.Request) {
 	id := chi.URLParam(r, "id")
 
@@ -141,6 +439,10 @@ func (h *Handler) DeleteService(w http.ResponseWriter, r *http.Request) {
 		svc = svcByName
 	}
 
+	if !h.checkServiceDomains(w, r, svc.ID) {
+		return
+	}
+
 	if h.orchestrator != nil {
 		if err := h.orchestrator.Remove(r.Context(), svc); err != nil {
 			h.logger.Error("orchestrator remove failed", "error", err, "id", svc.ID)
@@ -157,7 +459,8 @@ func (h *Handler) DeleteService(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	h.logger.Info("service deleted", "id", svc.ID)
+	h.logger.Info("service deleted", "id", svc.ID, "actor", auth.Actor(r.Context()))
+	h.recordEvent(r.Context(), svc, models.ServiceEventDeleted, "service deleted")
 	w.WriteHeader(http.StatusNoContent)
 }
 
@@ -12,20 +12,14 @@ import (
 	"github.com/max-cloud/shared/pkg/models"
 )
 
-// CreateInvite erstellt eine neue Einladung (nur für Admins).
+// CreateInvite erstellt eine neue Einladung (Berechtigung members:manage).
 func (h *Handler) CreateInvite(w http.ResponseWriter, r *http.Request) {
 	orgID, _ := auth.OrgIDFromContext(r.Context())
 	userID, _ := auth.UserIDFromContext(r.Context())
 
-	// Admin-Check
-	info, err := h.authStore.GetAuthInfo(r.Context(), orgID, userID)
-	if err != nil {
-		h.logger.Error("failed to get auth info", "error", err)
-		http.Error(w, `{"error":"internal server error"}`, http.StatusInternalServerError)
-		return
-	}
-	if info.Role != models.OrgRoleAdmin {
-		http.Error(w, `{"error":"admin role required"}`, http.StatusForbidden)
+	// Einladungen verweisen auf den einladenden Benutzer
+	if _, ok := auth.ServiceAccountIDFromContext(r.Context()); ok {
+		http.Error(w, `{"error":"service accounts cannot invite members"}`, http.StatusForbidden)
 		return
 	}
 
@@ -41,10 +35,20 @@ func (h *Handler) CreateInvite(w http.ResponseWriter, r *http.Request) {
 	}
 
 	if req.Role == "" {
-		req.Role = models.OrgRoleMember
+		req.Role = models.OrgRoleDeveloper
+	}
+	if !req.Role.Valid() {
+		http.Error(w, `{"error":"role must be owner, admin, developer or viewer"}`, http.StatusBadRequest)
+		return
+	}
+	if !h.canAssignRole(w, r, req.Role) {
+		return
 	}
-	if req.Role != models.OrgRoleAdmin && req.Role != models.OrgRoleMember {
-		http.Error(w, `{"error":"role must be admin or member"}`, http.StatusBadRequest)
+
+	org, err := h.authStore.GetOrganization(r.Context(), orgID)
+	if err != nil {
+		h.logger.Error("failed to get organization", "error", err)
+		http.Error(w, `{"error":"internal server error"}`, http.StatusInternalServerError)
 		return
 	}
 
@@ -61,7 +65,7 @@ func (h *Handler) CreateInvite(w http.ResponseWriter, r *http.Request) {
 	}
 
 	// E-Mail senden
-	if err := h.emailSender.SendInvite(r.Context(), req.Email, info.Organization.Name, rawToken); err != nil {
+	if err := h.emailSender.SendInvite(r.Context(), req.Email, org.Name, rawToken); err != nil {
 		h.logger.Error("failed to send invite email", "error", err, "email", req.Email)
 		http.Error(w, `{"error":"failed to send invite email"}`, http.StatusInternalServerError)
 		return
@@ -74,28 +78,16 @@ func (h *Handler) CreateInvite(w http.ResponseWriter, r *http.Request) {
 		resp.Token = rawToken
 	}
 
-	h.logger.Info("invite created and email sent", "email", req.Email, "org", orgID)
+	h.logger.Info("invite created and email sent", "email", req.Email, "org", orgID, "actor", auth.Actor(r.Context()))
 
 	w.Header().Set("Content-Type", "application/json")
 	w.WriteHeader(http.StatusCreated)
 	json.NewEncoder(w).Encode(resp)
 }
 
-// ListInvites gibt alle pending Einladungen der aktuellen Org zurück (nur für Admins).
+// ListInvites gibt alle pending Einladungen der aktuellen Org zurück (Berechtigung members:manage).
 func (h *Handler) ListInvites(w http.ResponseWriter, r *http.Request) {
 	orgID, _ := auth.OrgIDFromContext(r.Context())
-	userID, _ := auth.UserIDFromContext(r.Context())
-
-	info, err := h.authStore.GetAuthInfo(r.Context(), orgID, userID)
-	if err != nil {
-		h.logger.Error("failed to get auth info", "error", err)
-		http.Error(w, `{"error":"internal server error"}`, http.StatusInternalServerError)
-		return
-	}
-	if info.Role != models.OrgRoleAdmin {
-		http.Error(w, `{"error":"admin role required"}`, http.StatusForbidden)
-		return
-	}
 
 	invites, err := h.authStore.ListInvites(r.Context(), orgID)
 	if err != nil {
@@ -108,23 +100,11 @@ func (h *Handler) ListInvites(w http.ResponseWriter, r *http.Request) {
 	json.NewEncoder(w).Encode(invites)
 }
 
-// RevokeInvite widerruft eine Einladung (nur für Admins).
+// RevokeInvite widerruft eine Einladung (Berechtigung members:manage).
 func (h *Handler) RevokeInvite(w http.ResponseWriter, r *http.Request) {
 	orgID, _ := auth.OrgIDFromContext(r.Context())
-	userID, _ := auth.UserIDFromContext(r.Context())
 	inviteID := chi.URLParam(r, "id")
 
-	info, err := h.authStore.GetAuthInfo(r.Context(), orgID, userID)
-	if err != nil {
-		h.logger.Error("failed to get auth info", "error", err)
-		http.Error(w, `{"error":"internal server error"}`, http.StatusInternalServerError)
-		return
-	}
-	if info.Role != models.OrgRoleAdmin {
-		http.Error(w, `{"error":"admin role required"}`, http.StatusForbidden)
-		return
-	}
-
 	if err := h.authStore.RevokeInvite(r.Context(), orgID, inviteID); err != nil {
 		if errors.Is(err, store.ErrInviteNotFound) {
 			http.Error(w, `{"error":"invite not found"}`, http.StatusNotFound)
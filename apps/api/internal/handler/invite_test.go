@@ -21,12 +21,12 @@ import (
 func setupInvite() (*Handler, *store.MemoryStore) {
 	s := store.NewMemory()
 	orch := orchestrator.NewNoop(slog.Default())
-	h := New(slog.Default(), s, s, orch, email.NewMock(), 7*24*time.Hour, true, "registry.local", "test-secret", 1*time.Hour)
+	h := New(slog.Default(), s, s, s, s, s, orch, email.NewMock(), 7*24*time.Hour, true, "registry.local", "test-secret", 1*time.Hour)
 	return h, s
 }
 
-// registerAdmin erstellt einen Admin-User und gibt User, Org und authentifizierten Context zurück.
-func registerAdmin(t *testing.T, s *store.MemoryStore) (models.User, models.Organization, context.Context) {
+// registerOwner erstellt den Owner einer neuen Org und gibt User, Org und authentifizierten Context zurück.
+func registerOwner(t *testing.T, s *store.MemoryStore) (models.User, models.Organization, context.Context) {
 	t.Helper()
 	user, org, _, err := s.Register(context.Background(), "admin@example.com", "TestOrg")
 	if err != nil {
@@ -36,11 +36,16 @@ func registerAdmin(t *testing.T, s *store.MemoryStore) (models.User, models.Orga
 	return user, org, ctx
 }
 
+// withPermission schaltet die Berechtigungsprüfung wie im Server vor den Handler.
+func withPermission(h *Handler, perm models.Permission, fn http.HandlerFunc) http.Handler {
+	return auth.Require(slog.Default(), h.authStore, perm)(fn)
+}
+
 func TestCreateInviteHandler(t *testing.T) {
 	h, s := setupInvite()
-	_, _, ctx := registerAdmin(t, s)
+	_, _, ctx := registerOwner(t, s)
 
-	payload := `{"email":"new@example.com","role":"member"}`
+	payload := `{"email":"new@example.com","role":"developer"}`
 	req := httptest.NewRequest("POST", "/api/v1/auth/invites", bytes.NewBufferString(payload))
 	req = req.WithContext(ctx)
 	w := httptest.NewRecorder()
@@ -57,8 +62,8 @@ func TestCreateInviteHandler(t *testing.T) {
 	if resp.Invitation.Email != "new@example.com" {
 		t.Fatalf("expected email new@example.com, got %s", resp.Invitation.Email)
 	}
-	if resp.Invitation.Role != models.OrgRoleMember {
-		t.Fatalf("expected role member, got %s", resp.Invitation.Role)
+	if resp.Invitation.Role != models.OrgRoleDeveloper {
+		t.Fatalf("expected role developer, got %s", resp.Invitation.Role)
 	}
 	if resp.Token == "" {
 		t.Fatal("expected non-empty token in dev mode")
@@ -76,7 +81,7 @@ func TestCreateInviteNotAdmin(t *testing.T) {
 	}
 
 	expires := time.Now().Add(7 * 24 * time.Hour)
-	_, rawToken, err := s.CreateInvite(ctx, org.ID, "member@example.com", models.OrgRoleMember, admin.ID, expires)
+	_, rawToken, err := s.CreateInvite(ctx, org.ID, "member@example.com", models.OrgRoleDeveloper, admin.ID, expires)
 	if err != nil {
 		t.Fatalf("unexpected error: %v", err)
 	}
@@ -88,12 +93,12 @@ func TestCreateInviteNotAdmin(t *testing.T) {
 
 	// Member versucht einzuladen
 	memberCtx := auth.WithTenant(ctx, org.ID, member.ID)
-	payload := `{"email":"other@example.com","role":"member"}`
+	payload := `{"email":"other@example.com","role":"developer"}`
 	req := httptest.NewRequest("POST", "/api/v1/auth/invites", bytes.NewBufferString(payload))
 	req = req.WithContext(memberCtx)
 	w := httptest.NewRecorder()
 
-	h.CreateInvite(w, req)
+	withPermission(h, models.PermissionManageMembers, h.CreateInvite).ServeHTTP(w, req)
 
 	if w.Code != http.StatusForbidden {
 		t.Fatalf("expected 403, got %d: %s", w.Code, w.Body.String())
@@ -102,10 +107,10 @@ func TestCreateInviteNotAdmin(t *testing.T) {
 
 func TestCreateInviteAlreadyMember(t *testing.T) {
 	h, s := setupInvite()
-	_, _, ctx := registerAdmin(t, s)
+	_, _, ctx := registerOwner(t, s)
 
 	// Admin versucht sich selbst einzuladen
-	payload := `{"email":"admin@example.com","role":"member"}`
+	payload := `{"email":"admin@example.com","role":"developer"}`
 	req := httptest.NewRequest("POST", "/api/v1/auth/invites", bytes.NewBufferString(payload))
 	req = req.WithContext(ctx)
 	w := httptest.NewRecorder()
@@ -119,11 +124,11 @@ func TestCreateInviteAlreadyMember(t *testing.T) {
 
 func TestAcceptInviteHandler(t *testing.T) {
 	h, s := setupInvite()
-	admin, org, ctx := registerAdmin(t, s)
+	admin, org, ctx := registerOwner(t, s)
 
 	// Einladung erstellen
 	expires := time.Now().Add(7 * 24 * time.Hour)
-	_, rawToken, err := s.CreateInvite(context.Background(), org.ID, "new@example.com", models.OrgRoleMember, admin.ID, expires)
+	_, rawToken, err := s.CreateInvite(context.Background(), org.ID, "new@example.com", models.OrgRoleDeveloper, admin.ID, expires)
 	if err != nil {
 		t.Fatalf("unexpected error: %v", err)
 	}
@@ -170,11 +175,11 @@ func TestAcceptInviteBadToken(t *testing.T) {
 
 func TestAcceptInviteExpired(t *testing.T) {
 	h, s := setupInvite()
-	admin, org, _ := registerAdmin(t, s)
+	admin, org, _ := registerOwner(t, s)
 
 	// Abgelaufene Einladung
 	expires := time.Now().Add(-1 * time.Hour)
-	_, rawToken, err := s.CreateInvite(context.Background(), org.ID, "new@example.com", models.OrgRoleMember, admin.ID, expires)
+	_, rawToken, err := s.CreateInvite(context.Background(), org.ID, "new@example.com", models.OrgRoleDeveloper, admin.ID, expires)
 	if err != nil {
 		t.Fatalf("unexpected error: %v", err)
 	}
@@ -192,11 +197,11 @@ func TestAcceptInviteExpired(t *testing.T) {
 
 func TestListInvitesHandler(t *testing.T) {
 	h, s := setupInvite()
-	admin, org, ctx := registerAdmin(t, s)
+	admin, org, ctx := registerOwner(t, s)
 
 	// Einladung erstellen
 	expires := time.Now().Add(7 * 24 * time.Hour)
-	if _, _, err := s.CreateInvite(context.Background(), org.ID, "a@example.com", models.OrgRoleMember, admin.ID, expires); err != nil {
+	if _, _, err := s.CreateInvite(context.Background(), org.ID, "a@example.com", models.OrgRoleDeveloper, admin.ID, expires); err != nil {
 		t.Fatalf("unexpected error: %v", err)
 	}
 
@@ -227,7 +232,7 @@ func TestListInvitesNotAdmin(t *testing.T) {
 	}
 
 	expires := time.Now().Add(7 * 24 * time.Hour)
-	_, rawToken, err := s.CreateInvite(ctx, org.ID, "member@example.com", models.OrgRoleMember, admin.ID, expires)
+	_, rawToken, err := s.CreateInvite(ctx, org.ID, "member@example.com", models.OrgRoleDeveloper, admin.ID, expires)
 	if err != nil {
 		t.Fatalf("unexpected error: %v", err)
 	}
@@ -242,7 +247,7 @@ func TestListInvitesNotAdmin(t *testing.T) {
 	req = req.WithContext(memberCtx)
 	w := httptest.NewRecorder()
 
-	h.ListInvites(w, req)
+	withPermission(h, models.PermissionManageMembers, h.ListInvites).ServeHTTP(w, req)
 
 	if w.Code != http.StatusForbidden {
 		t.Fatalf("expected 403, got %d", w.Code)
@@ -251,10 +256,10 @@ func TestListInvitesNotAdmin(t *testing.T) {
 
 func TestRevokeInviteHandler(t *testing.T) {
 	h, s := setupInvite()
-	admin, org, ctx := registerAdmin(t, s)
+	admin, org, ctx := registerOwner(t, s)
 
 	expires := time.Now().Add(7 * 24 * time.Hour)
-	invite, _, err := s.CreateInvite(context.Background(), org.ID, "new@example.com", models.OrgRoleMember, admin.ID, expires)
+	invite, _, err := s.CreateInvite(context.Background(), org.ID, "new@example.com", models.OrgRoleDeveloper, admin.ID, expires)
 	if err != nil {
 		t.Fatalf("unexpected error: %v", err)
 	}
@@ -274,7 +279,7 @@ func TestRevokeInviteHandler(t *testing.T) {
 
 func TestRevokeInviteNotFound(t *testing.T) {
 	h, s := setupInvite()
-	_, _, ctx := registerAdmin(t, s)
+	_, _, ctx := registerOwner(t, s)
 
 	r := chi.NewRouter()
 	r.Delete("/api/v1/auth/invites/{id}", h.RevokeInvite)
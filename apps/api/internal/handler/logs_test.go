@@ -23,6 +23,7 @@ import (
 type mockOrchestrator struct {
 	logsReader io.ReadCloser
 	logsErr    error
+	events     []models.ServiceEvent
 }
 
 func (m *mockOrchestrator) Deploy(_ context.Context, _ models.Service) (*orchestrator.DeployResult, error) {
@@ -33,6 +34,34 @@ func (m *mockOrchestrator) Remove(_ context.Context, _ models.Service) error {
 	return nil
 }
 
+func (m *mockOrchestrator) ListManaged(_ context.Context) ([]orchestrator.ManagedResource, error) {
+	return nil, nil
+}
+
+func (m *mockOrchestrator) RemoveManaged(_ context.Context, _ orchestrator.ManagedResource) error {
+	return nil
+}
+
+func (m *mockOrchestrator) ApplySecrets(_ context.Context, _ models.Service, _ map[string]string) error {
+	return nil
+}
+
+func (m *mockOrchestrator) RestartRevisions(_ context.Context, _ models.Service, _ []string) error {
+	return nil
+}
+
+func (m *mockOrchestrator) ApplyDomain(_ context.Context, _ models.Domain, _ models.Service) error {
+	return nil
+}
+
+func (m *mockOrchestrator) RemoveDomain(_ context.Context, _ models.Domain) error {
+	return nil
+}
+
+func (m *mockOrchestrator) DomainStatus(_ context.Context, _ models.Domain) (*orchestrator.DomainResult, error) {
+	return nil, orchestrator.ErrNotFound
+}
+
 func (m *mockOrchestrator) Status(_ context.Context, _ models.Service) (*orchestrator.DeployResult, error) {
 	return &orchestrator.DeployResult{Status: models.ServiceStatusReady}, nil
 }
@@ -44,17 +73,25 @@ func (m *mockOrchestrator) Logs(_ context.Context, _ models.Service, _ orchestra
 	return m.logsReader, nil
 }
 
+func (m *mockOrchestrator) Events(_ context.Context, _ models.Service) ([]models.ServiceEvent, error) {
+	return m.events, nil
+}
+
 func (m *mockOrchestrator) CreateNamespace(_ context.Context, _ string) error {
 	return nil
 }
 
+func (m *mockOrchestrator) DeleteNamespace(_ context.Context, _ string) error {
+	return nil
+}
+
 func (m *mockOrchestrator) NamespaceExists(_ context.Context, _ string) (bool, error) {
 	return true, nil
 }
 
 func setupWithMockOrch(orch orchestrator.Orchestrator) (*Handler, *store.MemoryStore) {
 	s := store.NewMemory()
-	h := New(slog.Default(), s, s, orch, email.NewMock(), 7*24*time.Hour, true, "registry.local", "test-secret", 1*time.Hour)
+	h := New(slog.Default(), s, s, s, s, s, orch, email.NewMock(), 7*24*time.Hour, true, "registry.local", "test-secret", 1*time.Hour)
 	return h, s
 }
 
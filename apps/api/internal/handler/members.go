@@ -0,0 +1,132 @@
+package handler
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/max-cloud/api/internal/auth"
+	"github.com/max-cloud/api/internal/store"
+	"github.com/max-cloud/shared/pkg/models"
+)
+
+// ListMembers gibt alle Mitglieder der aktuellen Organisation zurück.
+func (h *Handler) ListMembers(w http.ResponseWriter, r *http.Request) {
+	orgID, _ := auth.OrgIDFromContext(r.Context())
+
+	members, err := h.authStore.ListMembers(r.Context(), orgID)
+	if err != nil {
+		h.logger.Error("failed to list members", "error", err)
+		http.Error(w, `{"error":"internal server error"}`, http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(members)
+}
+
+// UpdateMemberRole ändert die Rolle eines Mitglieds (Berechtigung members:manage).
+// Owner können nur von Ownern ernannt, herabgestuft oder entfernt werden.
+func (h *Handler) UpdateMemberRole(w http.ResponseWriter, r *http.Request) {
+	orgID, _ := auth.OrgIDFromContext(r.Context())
+	memberID := chi.URLParam(r, "id")
+
+	var req models.UpdateMemberRoleRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, `{"error":"invalid JSON"}`, http.StatusBadRequest)
+		return
+	}
+	if !req.Role.Valid() {
+		http.Error(w, `{"error":"role must be owner, admin, developer or viewer"}`, http.StatusBadRequest)
+		return
+	}
+	if !h.canAssignRole(w, r, req.Role) || !h.canManageMember(w, r, memberID) {
+		return
+	}
+
+	member, err := h.authStore.UpdateMemberRole(r.Context(), orgID, memberID, req.Role)
+	if err != nil {
+		h.writeMemberError(w, err, "failed to update member role")
+		return
+	}
+
+	h.logger.Info("member role updated", "org_id", orgID, "member_id", memberID, "role", req.Role, "actor", auth.Actor(r.Context()))
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(member)
+}
+
+// RemoveMember entfernt ein Mitglied aus der Organisation (Berechtigung members:manage).
+// Die API-Keys des Mitglieds in dieser Organisation werden dabei widerrufen.
+func (h *Handler) RemoveMember(w http.ResponseWriter, r *http.Request) {
+	orgID, _ := auth.OrgIDFromContext(r.Context())
+	memberID := chi.URLParam(r, "id")
+
+	if !h.canManageMember(w, r, memberID) {
+		return
+	}
+
+	if err := h.authStore.RemoveMember(r.Context(), orgID, memberID); err != nil {
+		h.writeMemberError(w, err, "failed to remove member")
+		return
+	}
+
+	h.logger.Info("member removed", "org_id", orgID, "member_id", memberID, "actor", auth.Actor(r.Context()))
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// canAssignRole prüft, ob der aufrufende Benutzer die Rolle vergeben darf. Die Owner-Rolle
+// dürfen nur Owner vergeben. Andernfalls wird die Fehlerantwort geschrieben.
+func (h *Handler) canAssignRole(w http.ResponseWriter, r *http.Request, role models.OrgRole) bool {
+	if role != models.OrgRoleOwner {
+		return true
+	}
+	return h.requireOwner(w, r)
+}
+
+// canManageMember prüft, ob der aufrufende Benutzer das Mitglied ändern darf. Owner dürfen
+// nur von Ownern geändert werden. Andernfalls wird die Fehlerantwort geschrieben.
+func (h *Handler) canManageMember(w http.ResponseWriter, r *http.Request, memberID string) bool {
+	orgID, _ := auth.OrgIDFromContext(r.Context())
+
+	info, err := h.authStore.GetAuthInfo(r.Context(), orgID, memberID)
+	if err != nil {
+		if errors.Is(err, store.ErrNotFound) {
+			http.Error(w, `{"error":"member not found"}`, http.StatusNotFound)
+			return false
+		}
+		h.logger.Error("failed to get auth info", "error", err)
+		http.Error(w, `{"error":"internal server error"}`, http.StatusInternalServerError)
+		return false
+	}
+	if info.Role != models.OrgRoleOwner {
+		return true
+	}
+	return h.requireOwner(w, r)
+}
+
+func (h *Handler) requireOwner(w http.ResponseWriter, r *http.Request) bool {
+	role, err := auth.ResolveRole(r.Context(), h.authStore)
+	if err != nil {
+		h.logger.Error("failed to resolve role", "error", err)
+		http.Error(w, `{"error":"internal server error"}`, http.StatusInternalServerError)
+		return false
+	}
+	if role != models.OrgRoleOwner {
+		http.Error(w, `{"error":"owner role required"}`, http.StatusForbidden)
+		return false
+	}
+	return true
+}
+
+func (h *Handler) writeMemberError(w http.ResponseWriter, err error, msg string) {
+	switch {
+	case errors.Is(err, store.ErrMemberNotFound):
+		http.Error(w, `{"error":"member not found"}`, http.StatusNotFound)
+	case errors.Is(err, store.ErrLastOwner):
+		http.Error(w, `{"error":"organization must keep at least one owner"}`, http.StatusConflict)
+	default:
+		h.logger.Error(msg, "error", err)
+		http.Error(w, `{"error":"internal server error"}`, http.StatusInternalServerError)
+	}
+}
@@ -0,0 +1,127 @@
+package handler
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/max-cloud/api/internal/auth"
+	"github.com/max-cloud/shared/pkg/models"
+)
+
+func membersRouter(h *Handler) *chi.Mux {
+	r := chi.NewRouter()
+	r.Get("/api/v1/org/members", h.ListMembers)
+	r.Method("PATCH", "/api/v1/org/members/{id}", withPermission(h, models.PermissionManageMembers, h.UpdateMemberRole))
+	r.Method("DELETE", "/api/v1/org/members/{id}", withPermission(h, models.PermissionManageMembers, h.RemoveMember))
+	return r
+}
+
+// inviteMember lädt ein neues Mitglied mit der Rolle ein und gibt User, API-Key und Context zurück.
+func inviteMember(t *testing.T, h *Handler, org models.Organization, inviter, email string, role models.OrgRole) (models.User, string, context.Context) {
+	t.Helper()
+	_, rawToken, err := h.authStore.CreateInvite(context.Background(), org.ID, email, role, inviter, time.Now().Add(time.Hour))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	user, _, _, rawKey, err := h.authStore.AcceptInvite(context.Background(), rawToken)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	return user, rawKey, auth.WithTenant(context.Background(), org.ID, user.ID)
+}
+
+func TestMembersHandler(t *testing.T) {
+	h, s := setupInvite()
+	r := membersRouter(h)
+	owner, org, ownerCtx := registerOwner(t, s)
+	member, memberKey, memberCtx := inviteMember(t, h, org, owner.ID, "member@example.com", models.OrgRoleDeveloper)
+
+	req := httptest.NewRequest("GET", "/api/v1/org/members", nil).WithContext(memberCtx)
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+	if w.Code != http.StatusOK {
+		t.Fatalf("expected 200, got %d: %s", w.Code, w.Body.String())
+	}
+	var members []models.OrgMember
+	json.NewDecoder(w.Body).Decode(&members)
+	if len(members) != 2 {
+		t.Fatalf("expected 2 members, got %+v", members)
+	}
+
+	// Developer dürfen keine Rollen ändern
+	req = httptest.NewRequest("PATCH", "/api/v1/org/members/"+member.ID, bytes.NewBufferString(`{"role":"admin"}`)).WithContext(memberCtx)
+	w = httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+	if w.Code != http.StatusForbidden {
+		t.Fatalf("expected 403 for developer, got %d", w.Code)
+	}
+
+	req = httptest.NewRequest("PATCH", "/api/v1/org/members/"+member.ID, bytes.NewBufferString(`{"role":"member"}`)).WithContext(ownerCtx)
+	w = httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+	if w.Code != http.StatusBadRequest {
+		t.Fatalf("expected 400 for invalid role, got %d", w.Code)
+	}
+
+	// Der letzte Owner kann sich nicht selbst herabstufen
+	req = httptest.NewRequest("PATCH", "/api/v1/org/members/"+owner.ID, bytes.NewBufferString(`{"role":"admin"}`)).WithContext(ownerCtx)
+	w = httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+	if w.Code != http.StatusConflict {
+		t.Fatalf("expected 409 for last owner, got %d: %s", w.Code, w.Body.String())
+	}
+
+	req = httptest.NewRequest("PATCH", "/api/v1/org/members/unknown", bytes.NewBufferString(`{"role":"admin"}`)).WithContext(ownerCtx)
+	w = httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+	if w.Code != http.StatusNotFound {
+		t.Fatalf("expected 404 for unknown member, got %d", w.Code)
+	}
+
+	req = httptest.NewRequest("DELETE", "/api/v1/org/members/"+member.ID, nil).WithContext(ownerCtx)
+	w = httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+	if w.Code != http.StatusNoContent {
+		t.Fatalf("expected 204, got %d: %s", w.Code, w.Body.String())
+	}
+	if _, err := s.ValidateAPIKey(context.Background(), memberKey); err == nil {
+		t.Fatal("expected api key of removed member to be revoked")
+	}
+}
+
+func TestMembersHandlerOwnerRules(t *testing.T) {
+	h, s := setupInvite()
+	r := membersRouter(h)
+	owner, org, _ := registerOwner(t, s)
+	_, _, adminCtx := inviteMember(t, h, org, owner.ID, "admin@example.org", models.OrgRoleAdmin)
+	viewer, _, _ := inviteMember(t, h, org, owner.ID, "viewer@example.com", models.OrgRoleViewer)
+
+	tests := []struct {
+		name   string
+		method string
+		target string
+		body   string
+		code   int
+	}{
+		{"admin promotes to owner", "PATCH", viewer.ID, `{"role":"owner"}`, http.StatusForbidden},
+		{"admin demotes owner", "PATCH", owner.ID, `{"role":"viewer"}`, http.StatusForbidden},
+		{"admin removes owner", "DELETE", owner.ID, ``, http.StatusForbidden},
+		{"admin promotes to developer", "PATCH", viewer.ID, `{"role":"developer"}`, http.StatusOK},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(tt.method, "/api/v1/org/members/"+tt.target, bytes.NewBufferString(tt.body)).WithContext(adminCtx)
+			w := httptest.NewRecorder()
+			r.ServeHTTP(w, req)
+			if w.Code != tt.code {
+				t.Fatalf("expected %d, got %d: %s", tt.code, w.Code, w.Body.String())
+			}
+		})
+	}
+}
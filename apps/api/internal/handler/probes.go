@@ -0,0 +1,69 @@
+package handler
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/max-cloud/shared/pkg/models"
+)
+
+// validateProbes prüft die Health-Checks einer Service-Spec.
+func validateProbes(probes *models.Probes) string {
+	if probes == nil {
+		return ""
+	}
+	if msg := validateProbe("liveness", probes.Liveness); msg != "" {
+		return msg
+	}
+	if msg := validateProbe("readiness", probes.Readiness); msg != "" {
+		return msg
+	}
+	if msg := validateProbe("startup", probes.Startup); msg != "" {
+		return msg
+	}
+	return ""
+}
+
+// validateProbe prüft eine einzelne Probe; kind erscheint in der Fehlermeldung.
+func validateProbe(kind string, p *models.Probe) string {
+	if p == nil {
+		return ""
+	}
+
+	switch p.Type {
+	case "", models.ProbeTypeHTTP:
+		if !strings.HasPrefix(p.Path, "/") {
+			return fmt.Sprintf("%s probe path must start with /", kind)
+		}
+	case models.ProbeTypeTCP, models.ProbeTypeGRPC:
+		if p.Path != "" {
+			return fmt.Sprintf("%s probe path is only allowed for http probes", kind)
+		}
+	default:
+		return fmt.Sprintf("%s probe type must be one of %s, %s, %s", kind, models.ProbeTypeHTTP, models.ProbeTypeTCP, models.ProbeTypeGRPC)
+	}
+
+	if p.Port < 0 || p.Port > 65535 {
+		return fmt.Sprintf("%s probe port must be between 0 and 65535", kind)
+	}
+	for name, v := range map[string]int{
+		"initial_delay_seconds": p.InitialDelaySeconds,
+		"period_seconds":        p.PeriodSeconds,
+		"timeout_seconds":       p.TimeoutSeconds,
+	} {
+		if v < 0 || v > models.MaxTimeoutSeconds {
+			return fmt.Sprintf("%s probe %s must be between 0 and %d", kind, name, models.MaxTimeoutSeconds)
+		}
+	}
+	if p.FailureThreshold < 0 || p.FailureThreshold > models.MaxProbeThreshold {
+		return fmt.Sprintf("%s probe failure_threshold must be between 0 and %d", kind, models.MaxProbeThreshold)
+	}
+	if p.SuccessThreshold < 0 || p.SuccessThreshold > models.MaxProbeThreshold {
+		return fmt.Sprintf("%s probe success_threshold must be between 0 and %d", kind, models.MaxProbeThreshold)
+	}
+	// Kubernetes verlangt für Liveness- und Startup-Probes einen Success-Threshold von 1
+	if kind != "readiness" && p.SuccessThreshold > 1 {
+		return fmt.Sprintf("%s probe success_threshold must be 1", kind)
+	}
+	return ""
+}
@@ -2,14 +2,15 @@ package handler
 
 import (
 	"encoding/json"
+	"errors"
 	"fmt"
 	"net/http"
 	"strings"
 	"time"
 
 	"github.com/go-chi/chi/v5/middleware"
-	"github.com/golang-jwt/jwt/v5"
 	"github.com/max-cloud/api/internal/auth"
+	"github.com/max-cloud/api/internal/registry"
 	"github.com/max-cloud/shared/pkg/models"
 )
 
@@ -48,23 +49,27 @@ func (h *Handler) GetRegistryToken(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	perm := models.PermissionRead
+	if requestsWrite(access) {
+		perm = models.PermissionPushImages
+	}
+	if _, err := auth.Authorize(r.Context(), h.authStore, perm); err != nil {
+		if errors.Is(err, auth.ErrPermissionDenied) {
+			errorWithRequestID(w, r, err.Error(), http.StatusForbidden)
+			return
+		}
+		h.logger.Error("failed to resolve role", "error", err)
+		errorWithRequestID(w, r, "internal server error", http.StatusInternalServerError)
+		return
+	}
+
 	now := time.Now()
 	expiry := h.registryTokenExpiry
 	if expiry == 0 {
 		expiry = 1 * time.Hour
 	}
 
-	token := jwt.NewWithClaims(jwt.SigningMethodHS256, jwt.MapClaims{
-		"iss":    "max-cloud",
-		"sub":    orgID,
-		"aud":    service,
-		"exp":    now.Add(expiry).Unix(),
-		"nbf":    now.Unix(),
-		"iat":    now.Unix(),
-		"access": access,
-	})
-
-	tokenString, err := token.SignedString([]byte(h.registryJWTSecret))
+	tokenString, err := registry.SignToken(h.registryJWTSecret, orgID, service, access, now, expiry)
 	if err != nil {
 		h.logger.Error("failed to sign token", "error", err)
 		errorWithRequestID(w, r, "internal server error", http.StatusInternalServerError)
@@ -141,6 +146,22 @@ func (h *Handler) validateAccessScope(access []map[string]interface{}, orgID str
 	return true
 }
 
+// requestsWrite prüft, ob ein Repository-Scope mehr als Lesezugriff (pull) verlangt.
+func requestsWrite(access []map[string]interface{}) bool {
+	for _, a := range access {
+		if a["type"] != "repository" {
+			continue
+		}
+		actions, _ := a["actions"].([]string)
+		for _, action := range actions {
+			if action != "pull" {
+				return true
+			}
+		}
+	}
+	return false
+}
+
 func (h *Handler) isOrgRepository(name string, orgID string) bool {
 	expectedPrefix := fmt.Sprintf("%s/", orgID)
 	return strings.HasPrefix(name, expectedPrefix)
@@ -0,0 +1,162 @@
+package handler
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/max-cloud/api/internal/auth"
+	"github.com/max-cloud/api/internal/store"
+	"github.com/max-cloud/shared/pkg/models"
+)
+
+// ListRevisions gibt die Revisionshistorie eines Services zurück (neueste zuerst).
+func (h *Handler) ListRevisions(w http.ResponseWriter, r *http.Request) {
+	id := chi.URLParam(r, "id")
+
+	revisions, err := h.store.ListRevisions(r.Context(), id)
+	if err != nil {
+		if errors.Is(err, store.ErrNotFound) {
+			http.Error(w, `{"error":"service not found"}`, http.StatusNotFound)
+			return
+		}
+		h.logger.Error("failed to list revisions", "error", err, "id", id)
+		errorWithRequestID(w, r, "internal server error", http.StatusInternalServerError)
+		return
+	}
+
+	svc, err := h.store.Get(r.Context(), id)
+	if err != nil {
+		if errors.Is(err, store.ErrNotFound) {
+			http.Error(w, `{"error":"service not found"}`, http.StatusNotFound)
+			return
+		}
+		h.logger.Error("failed to get service for revisions", "error", err, "id", id)
+		errorWithRequestID(w, r, "internal server error", http.StatusInternalServerError)
+		return
+	}
+
+	for i := range revisions {
+		revisions[i].Traffic = trafficPercent(svc, revisions[i].Name)
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(revisions)
+}
+
+// RollbackService setzt einen Service auf die Spec einer früheren Revision zurück.
+// Dabei entsteht eine neue Revision mit identischer Spec, die der Reconciler ausrollt.
+// Die Spec muss die aktuellen Regeln und Limits erfüllen, die sich seitdem geändert haben können.
+func (h *Handler) RollbackService(w http.ResponseWriter, r *http.Request) {
+	id := chi.URLParam(r, "id")
+
+	var req models.RollbackRequest
+	if r.ContentLength != 0 {
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			errorWithRequestID(w, r, "invalid JSON", http.StatusBadRequest)
+			return
+		}
+	}
+
+	svc, err := h.store.Get(r.Context(), id)
+	if err != nil {
+		if errors.Is(err, store.ErrNotFound) {
+			http.Error(w, `{"error":"service not found"}`, http.StatusNotFound)
+			return
+		}
+		h.logger.Error("failed to get service for rollback", "error", err, "id", id)
+		errorWithRequestID(w, r, "internal server error", http.StatusInternalServerError)
+		return
+	}
+
+	if svc.Status == models.ServiceStatusDeleting {
+		errorWithRequestID(w, r, "service is being deleted", http.StatusConflict)
+		return
+	}
+
+	revisions, err := h.store.ListRevisions(r.Context(), svc.ID)
+	if err != nil {
+		h.logger.Error("failed to list revisions for rollback", "error", err, "id", id)
+		errorWithRequestID(w, r, "internal server error", http.StatusInternalServerError)
+		return
+	}
+
+	target, ok := findRollbackTarget(revisions, svc.LatestRevision, req.Revision)
+	if !ok {
+		if req.Revision == "" {
+			errorWithRequestID(w, r, "service has no previous revision", http.StatusConflict)
+			return
+		}
+		errorWithRequestID(w, r, "revision not found", http.StatusNotFound)
+		return
+	}
+
+	applyRevision(&svc, target)
+	if !h.checkServiceSpec(w, r, svc) {
+		return
+	}
+
+	updated, err := h.store.Update(r.Context(), svc)
+	if err != nil {
+		if errors.Is(err, store.ErrNotFound) {
+			http.Error(w, `{"error":"service not found"}`, http.StatusNotFound)
+			return
+		}
+		if errors.Is(err, store.ErrConflict) {
+			errorWithRequestID(w, r, "service was modified concurrently, reload and retry", http.StatusConflict)
+			return
+		}
+		h.logger.Error("failed to roll back service", "error", err, "id", id)
+		errorWithRequestID(w, r, "internal server error", http.StatusInternalServerError)
+		return
+	}
+
+	h.logger.Info("service rolled back", "id", updated.ID, "revision", target.Name, "generation", updated.Generation, "actor", auth.Actor(r.Context()))
+	h.recordEvent(r.Context(), updated, models.ServiceEventUpdated, fmt.Sprintf("rolled back to revision %s (generation %d)", target.Name, updated.Generation))
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(updated)
+}
+
+// findRollbackTarget sucht die Revision per ID oder Name. Ohne ref wird die
+// Revision direkt vor latest gewählt. revisions ist neueste zuerst sortiert.
+func findRollbackTarget(revisions []models.Revision, latest string, ref string) (models.Revision, bool) {
+	for i, rev := range revisions {
+		if ref == "" {
+			if rev.Name == latest && i+1 < len(revisions) {
+				return revisions[i+1], true
+			}
+			continue
+		}
+		if rev.ID == ref || rev.Name == ref {
+			return rev, true
+		}
+	}
+	return models.Revision{}, false
+}
+
+// applyRevision übernimmt die gespeicherte Spec einer Revision in svc.
+// Eine bestehende Traffic-Aufteilung wird verworfen, die neue Revision erhält 100%.
+func applyRevision(svc *models.Service, rev models.Revision) {
+	svc.Traffic = nil
+	svc.Image = rev.Image
+	svc.Port = rev.Port
+	svc.Command = rev.Command
+	svc.Args = rev.Args
+	svc.EnvVars = rev.EnvVars
+	svc.MinScale = rev.MinScale
+	svc.MaxScale = rev.MaxScale
+	svc.CPU = rev.CPU
+	svc.Memory = rev.Memory
+	svc.ContainerConcurrency = rev.ContainerConcurrency
+	svc.AutoscalingTarget = rev.AutoscalingTarget
+	svc.ScaleDownDelay = rev.ScaleDownDelay
+	svc.ScaleToZeroRetention = rev.ScaleToZeroRetention
+	svc.TimeoutSeconds = rev.TimeoutSeconds
+	svc.ResponseStartTimeoutSeconds = rev.ResponseStartTimeoutSeconds
+	svc.IdleTimeoutSeconds = rev.IdleTimeoutSeconds
+	svc.Probes = rev.Probes.Clone()
+	svc.SecretEnv = rev.SecretEnv
+}
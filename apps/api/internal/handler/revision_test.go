@@ -0,0 +1,205 @@
+package handler
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/max-cloud/shared/pkg/models"
+)
+
+func TestListRevisions(t *testing.T) {
+	h, s := setup()
+	created, _ := s.Create(context.Background(), models.DeployRequest{Name: "app", Image: "img:1"})
+
+	r := chi.NewRouter()
+	r.Patch("/api/v1/services/{id}", h.UpdateService)
+	r.Get("/api/v1/services/{id}/revisions", h.ListRevisions)
+
+	req := httptest.NewRequest("PATCH", "/api/v1/services/"+created.ID, bytes.NewBufferString(`{"image":"img:2"}`))
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+	if w.Code != http.StatusOK {
+		t.Fatalf("expected 200, got %d: %s", w.Code, w.Body.String())
+	}
+
+	req = httptest.NewRequest("GET", "/api/v1/services/"+created.ID+"/revisions", nil)
+	w = httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+
+	if w.Code != http.StatusOK {
+		t.Fatalf("expected 200, got %d", w.Code)
+	}
+
+	var revisions []models.Revision
+	json.NewDecoder(w.Body).Decode(&revisions)
+	if len(revisions) != 2 {
+		t.Fatalf("expected 2 revisions, got %d", len(revisions))
+	}
+	if revisions[0].Image != "img:2" || revisions[0].Name != "app-00002" {
+		t.Fatalf("expected newest revision app-00002 with img:2, got %s with %s", revisions[0].Name, revisions[0].Image)
+	}
+	if revisions[1].Image != "img:1" || revisions[1].Name != "app-00001" {
+		t.Fatalf("expected oldest revision app-00001 with img:1, got %s with %s", revisions[1].Name, revisions[1].Image)
+	}
+}
+
+func TestListRevisionsNotFound(t *testing.T) {
+	h, _ := setup()
+
+	r := chi.NewRouter()
+	r.Get("/api/v1/services/{id}/revisions", h.ListRevisions)
+
+	req := httptest.NewRequest("GET", "/api/v1/services/nonexistent/revisions", nil)
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+
+	if w.Code != http.StatusNotFound {
+		t.Fatalf("expected 404, got %d", w.Code)
+	}
+}
+
+func TestRollbackServiceToPrevious(t *testing.T) {
+	h, s := setup()
+	created, _ := s.Create(context.Background(), models.DeployRequest{
+		Name:    "app",
+		Image:   "img:1",
+		Port:    8080,
+		EnvVars: map[string]string{"MODE": "stable"},
+	})
+
+	r := chi.NewRouter()
+	r.Patch("/api/v1/services/{id}", h.UpdateService)
+	r.Post("/api/v1/services/{id}/rollback", h.RollbackService)
+
+	req := httptest.NewRequest("PATCH", "/api/v1/services/"+created.ID,
+		bytes.NewBufferString(`{"image":"img:broken","port":9090,"env_vars":{"MODE":"canary"}}`))
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+	if w.Code != http.StatusOK {
+		t.Fatalf("expected 200, got %d: %s", w.Code, w.Body.String())
+	}
+
+	req = httptest.NewRequest("POST", "/api/v1/services/"+created.ID+"/rollback", nil)
+	w = httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+	if w.Code != http.StatusOK {
+		t.Fatalf("expected 200, got %d: %s", w.Code, w.Body.String())
+	}
+
+	var svc models.Service
+	json.NewDecoder(w.Body).Decode(&svc)
+	if svc.Image != "img:1" || svc.Port != 8080 || svc.EnvVars["MODE"] != "stable" {
+		t.Fatalf("expected spec of revision 1, got image=%s port=%d env=%v", svc.Image, svc.Port, svc.EnvVars)
+	}
+	if svc.Status != models.ServiceStatusPending {
+		t.Fatalf("expected pending, got %s", svc.Status)
+	}
+	if svc.Generation != 3 {
+		t.Fatalf("expected generation 3, got %d", svc.Generation)
+	}
+
+	revs, _ := s.ListRevisions(context.Background(), created.ID)
+	if len(revs) != 3 {
+		t.Fatalf("expected rollback to record a new revision, got %d revisions", len(revs))
+	}
+}
+
+func TestRollbackServiceToNamedRevision(t *testing.T) {
+	h, s := setup()
+	created, _ := s.Create(context.Background(), models.DeployRequest{Name: "app", Image: "img:1"})
+
+	r := chi.NewRouter()
+	r.Patch("/api/v1/services/{id}", h.UpdateService)
+	r.Post("/api/v1/services/{id}/rollback", h.RollbackService)
+
+	for _, image := range []string{"img:2", "img:3"} {
+		req := httptest.NewRequest("PATCH", "/api/v1/services/"+created.ID, bytes.NewBufferString(`{"image":"`+image+`"}`))
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, req)
+		if w.Code != http.StatusOK {
+			t.Fatalf("expected 200, got %d: %s", w.Code, w.Body.String())
+		}
+	}
+
+	req := httptest.NewRequest("POST", "/api/v1/services/"+created.ID+"/rollback", bytes.NewBufferString(`{"revision":"app-00001"}`))
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+	if w.Code != http.StatusOK {
+		t.Fatalf("expected 200, got %d: %s", w.Code, w.Body.String())
+	}
+
+	var svc models.Service
+	json.NewDecoder(w.Body).Decode(&svc)
+	if svc.Image != "img:1" {
+		t.Fatalf("expected image img:1, got %s", svc.Image)
+	}
+}
+
+func TestRollbackServiceRejectsRevisionAboveScaleLimit(t *testing.T) {
+	h, s := setup()
+	// Revision 1 stammt aus einer Zeit, in der ein höheres Limit galt.
+	created, _ := s.Create(context.Background(), models.DeployRequest{
+		Name:     "app",
+		Image:    "img:1",
+		MaxScale: models.DefaultMaxScaleLimit + 5,
+	})
+
+	r := chi.NewRouter()
+	r.Patch("/api/v1/services/{id}", h.UpdateService)
+	r.Post("/api/v1/services/{id}/rollback", h.RollbackService)
+
+	req := httptest.NewRequest("PATCH", "/api/v1/services/"+created.ID, bytes.NewBufferString(`{"image":"img:2","max_scale":5}`))
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+	if w.Code != http.StatusOK {
+		t.Fatalf("expected 200, got %d: %s", w.Code, w.Body.String())
+	}
+
+	req = httptest.NewRequest("POST", "/api/v1/services/"+created.ID+"/rollback", nil)
+	w = httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+	if w.Code != http.StatusBadRequest {
+		t.Fatalf("expected 400, got %d: %s", w.Code, w.Body.String())
+	}
+
+	svc, _ := s.Get(context.Background(), created.ID)
+	if svc.Generation != 2 || svc.MaxScale != 5 {
+		t.Fatalf("expected service to stay at generation 2 with max_scale 5, got generation=%d max_scale=%d", svc.Generation, svc.MaxScale)
+	}
+}
+
+func TestRollbackServiceErrors(t *testing.T) {
+	h, s := setup()
+	created, _ := s.Create(context.Background(), models.DeployRequest{Name: "app", Image: "img:1"})
+
+	r := chi.NewRouter()
+	r.Post("/api/v1/services/{id}/rollback", h.RollbackService)
+
+	tests := []struct {
+		name    string
+		id      string
+		payload string
+		code    int
+	}{
+		{"no previous revision", created.ID, ``, http.StatusConflict},
+		{"unknown revision", created.ID, `{"revision":"app-00042"}`, http.StatusNotFound},
+		{"unknown service", "nonexistent", ``, http.StatusNotFound},
+		{"invalid json", created.ID, `{invalid`, http.StatusBadRequest},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest("POST", "/api/v1/services/"+tt.id+"/rollback", bytes.NewBufferString(tt.payload))
+			w := httptest.NewRecorder()
+			r.ServeHTTP(w, req)
+			if w.Code != tt.code {
+				t.Fatalf("expected %d, got %d", tt.code, w.Code)
+			}
+		})
+	}
+}
@@ -0,0 +1,68 @@
+package handler
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+
+	"github.com/max-cloud/api/internal/auth"
+	"github.com/max-cloud/api/internal/store"
+	"github.com/max-cloud/shared/pkg/models"
+)
+
+// validateAutoscaling prüft die Autoscaling-Einstellungen einer Service-Spec.
+func validateAutoscaling(svc models.Service) string {
+	if svc.ContainerConcurrency < 0 || svc.ContainerConcurrency > models.MaxContainerConcurrency {
+		return fmt.Sprintf("container_concurrency must be between 0 and %d", models.MaxContainerConcurrency)
+	}
+	if svc.AutoscalingTarget < 0 || svc.AutoscalingTarget > models.MaxContainerConcurrency {
+		return fmt.Sprintf("autoscaling_target must be between 0 and %d", models.MaxContainerConcurrency)
+	}
+	if svc.ContainerConcurrency > 0 && svc.AutoscalingTarget > svc.ContainerConcurrency {
+		return "autoscaling_target must not exceed container_concurrency"
+	}
+	if svc.ScaleDownDelay < 0 || svc.ScaleDownDelay > models.MaxScaleDelay {
+		return fmt.Sprintf("scale_down_delay_seconds must be between 0 and %d", models.MaxScaleDelay)
+	}
+	if svc.ScaleToZeroRetention < 0 || svc.ScaleToZeroRetention > models.MaxScaleDelay {
+		return fmt.Sprintf("scale_to_zero_retention_seconds must be between 0 and %d", models.MaxScaleDelay)
+	}
+	return ""
+}
+
+// checkScaleLimit prüft maxScale gegen die Obergrenze der Organisation und
+// schreibt bei Überschreitung eine Fehlerantwort. Gibt true zurück, wenn maxScale erlaubt ist.
+func (h *Handler) checkScaleLimit(w http.ResponseWriter, r *http.Request, maxScale int) bool {
+	limit, err := h.maxScaleLimit(r)
+	if err != nil {
+		h.logger.Error("failed to load organization limits", "error", err)
+		errorWithRequestID(w, r, "internal server error", http.StatusInternalServerError)
+		return false
+	}
+	if maxScale > limit {
+		errorWithRequestID(w, r, fmt.Sprintf("max_scale exceeds the organization limit of %d", limit), http.StatusBadRequest)
+		return false
+	}
+	return true
+}
+
+// maxScaleLimit gibt die Obergrenze für MaxScale der aktuellen Organisation zurück.
+// Ohne Tenant oder ohne konfigurierten Wert gilt models.DefaultMaxScaleLimit.
+func (h *Handler) maxScaleLimit(r *http.Request) (int, error) {
+	orgID, ok := auth.OrgIDFromContext(r.Context())
+	if !ok {
+		return models.DefaultMaxScaleLimit, nil
+	}
+
+	org, err := h.authStore.GetOrganization(r.Context(), orgID)
+	if err != nil {
+		if errors.Is(err, store.ErrNotFound) {
+			return models.DefaultMaxScaleLimit, nil
+		}
+		return 0, err
+	}
+	if org.MaxScaleLimit <= 0 {
+		return models.DefaultMaxScaleLimit, nil
+	}
+	return org.MaxScaleLimit, nil
+}
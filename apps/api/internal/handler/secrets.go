@@ -0,0 +1,211 @@
+package handler
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"maps"
+	"net/http"
+	"slices"
+	"strings"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/max-cloud/api/internal/auth"
+	"github.com/max-cloud/api/internal/store"
+	"github.com/max-cloud/shared/pkg/models"
+)
+
+// SetSecret legt ein Secret der Organisation an oder überschreibt seinen Wert.
+// Die Antwort enthält nur Metadaten, nie den Wert.
+func (h *Handler) SetSecret(w http.ResponseWriter, r *http.Request) {
+	name := chi.URLParam(r, "name")
+	orgID, _ := auth.OrgIDFromContext(r.Context())
+
+	if !models.SecretNamePattern.MatchString(name) {
+		errorWithRequestID(w, r, "invalid secret name: use letters, digits, '_', '.' and '-' (max 63 characters)", http.StatusBadRequest)
+		return
+	}
+
+	var req models.SetSecretRequest
+	if err := json.NewDecoder(http.MaxBytesReader(w, r.Body, 2*models.MaxSecretSize)).Decode(&req); err != nil {
+		errorWithRequestID(w, r, "invalid JSON", http.StatusBadRequest)
+		return
+	}
+	if req.Value == "" {
+		errorWithRequestID(w, r, "value is required", http.StatusBadRequest)
+		return
+	}
+	if len(req.Value) > models.MaxSecretSize {
+		errorWithRequestID(w, r, fmt.Sprintf("value must not exceed %d bytes", models.MaxSecretSize), http.StatusBadRequest)
+		return
+	}
+
+	previous, err := h.secretStore.SecretValues(r.Context(), orgID, []string{name})
+	if err != nil && !errors.Is(err, store.ErrSecretNotFound) {
+		h.logger.Error("failed to read secret", "error", err, "name", name)
+		errorWithRequestID(w, r, "internal server error", http.StatusInternalServerError)
+		return
+	}
+
+	secret, err := h.secretStore.SetSecret(r.Context(), orgID, name, req.Value)
+	if err != nil {
+		h.logger.Error("failed to set secret", "error", err, "name", name)
+		errorWithRequestID(w, r, "internal server error", http.StatusInternalServerError)
+		return
+	}
+
+	h.logger.Info("secret set", "org_id", orgID, "name", name, "actor", auth.Actor(r.Context()))
+	if previous[name] != req.Value {
+		h.refreshSecretUsers(r.Context(), name)
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(secret)
+}
+
+// ListSecrets gibt die Secrets der Organisation ohne Werte zurück.
+func (h *Handler) ListSecrets(w http.ResponseWriter, r *http.Request) {
+	orgID, _ := auth.OrgIDFromContext(r.Context())
+
+	secrets, err := h.secretStore.ListSecrets(r.Context(), orgID)
+	if err != nil {
+		h.logger.Error("failed to list secrets", "error", err)
+		errorWithRequestID(w, r, "internal server error", http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(secrets)
+}
+
+// DeleteSecret löscht ein Secret. Solange ein Service es referenziert, wird das Löschen abgelehnt.
+func (h *Handler) DeleteSecret(w http.ResponseWriter, r *http.Request) {
+	name := chi.URLParam(r, "name")
+	orgID, _ := auth.OrgIDFromContext(r.Context())
+
+	services, err := h.store.List(r.Context())
+	if err != nil {
+		h.logger.Error("failed to list services for secret delete", "error", err)
+		errorWithRequestID(w, r, "internal server error", http.StatusInternalServerError)
+		return
+	}
+	for _, svc := range services {
+		if referencesSecret(svc, name) {
+			errorWithRequestID(w, r, fmt.Sprintf("secret is used by service %q", svc.Name), http.StatusConflict)
+			return
+		}
+	}
+
+	if err := h.secretStore.DeleteSecret(r.Context(), orgID, name); err != nil {
+		if errors.Is(err, store.ErrSecretNotFound) {
+			http.Error(w, `{"error":"secret not found"}`, http.StatusNotFound)
+			return
+		}
+		h.logger.Error("failed to delete secret", "error", err, "name", name)
+		errorWithRequestID(w, r, "internal server error", http.StatusInternalServerError)
+		return
+	}
+
+	h.logger.Info("secret deleted", "org_id", orgID, "name", name, "actor", auth.Actor(r.Context()))
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// refreshSecretUsers schreibt die Secret-Werte aller Services, die das Secret referenzieren,
+// neu in den Cluster und startet die Revisionen neu, die gerade Traffic erhalten. Es entsteht
+// keine neue Revision, eine festgelegte Traffic-Aufteilung bleibt also unverändert.
+// Fehler werden nur geloggt, der neue Wert ist bereits gespeichert.
+func (h *Handler) refreshSecretUsers(ctx context.Context, name string) {
+	if h.orchestrator == nil {
+		return
+	}
+
+	services, err := h.store.List(ctx)
+	if err != nil {
+		h.logger.Error("failed to list services for secret refresh", "error", err, "name", name)
+		return
+	}
+
+	for _, svc := range services {
+		if svc.Status == models.ServiceStatusDeleting || !referencesSecret(svc, name) {
+			continue
+		}
+		// Ohne ausgerollte Revision löst der Reconciler die Secrets beim ersten Deploy auf
+		revisions := servingRevisions(svc)
+		if len(revisions) == 0 {
+			continue
+		}
+
+		names := slices.Compact(slices.Sorted(maps.Values(svc.SecretEnv)))
+		values, err := h.secretStore.SecretValues(ctx, svc.OrgID, names)
+		if err != nil {
+			h.logger.Error("failed to resolve secrets for refresh", "error", err, "id", svc.ID, "name", name)
+			continue
+		}
+		if err := h.orchestrator.ApplySecrets(ctx, svc, values); err != nil {
+			h.logger.Error("failed to apply secrets after secret change", "error", err, "id", svc.ID, "name", name)
+			continue
+		}
+		if err := h.orchestrator.RestartRevisions(ctx, svc, revisions); err != nil {
+			h.logger.Error("failed to restart revisions after secret change", "error", err, "id", svc.ID, "name", name)
+			continue
+		}
+
+		h.logger.Info("service restarted for secret change", "id", svc.ID, "revisions", revisions, "name", name)
+		h.recordEvent(ctx, svc, models.ServiceEventUpdated, fmt.Sprintf("secret %q changed, restarted %s", name, strings.Join(revisions, ", ")))
+	}
+}
+
+func referencesSecret(svc models.Service, name string) bool {
+	for _, ref := range svc.SecretEnv {
+		if ref == name {
+			return true
+		}
+	}
+	return false
+}
+
+// validateSecretEnv prüft die Secret-Referenzen einer Service-Spec.
+// Eine Variable darf nicht gleichzeitig als Klartext und als Secret gesetzt sein.
+func validateSecretEnv(envVars, secretEnv map[string]string) string {
+	for _, k := range slices.Sorted(maps.Keys(secretEnv)) {
+		if k == "" {
+			return "secret_env names must not be empty"
+		}
+		if _, ok := envVars[k]; ok {
+			return fmt.Sprintf("env var %q is set both as value and as secret", k)
+		}
+		if !models.SecretNamePattern.MatchString(secretEnv[k]) {
+			return fmt.Sprintf("invalid secret name %q for env var %q", secretEnv[k], k)
+		}
+	}
+	return ""
+}
+
+// checkSecretRefs prüft, ob alle referenzierten Secrets in der Organisation existieren,
+// und schreibt andernfalls eine Fehlerantwort. Gibt true zurück, wenn alle vorhanden sind.
+func (h *Handler) checkSecretRefs(w http.ResponseWriter, r *http.Request, secretEnv map[string]string) bool {
+	if len(secretEnv) == 0 {
+		return true
+	}
+
+	orgID, _ := auth.OrgIDFromContext(r.Context())
+	secrets, err := h.secretStore.ListSecrets(r.Context(), orgID)
+	if err != nil {
+		h.logger.Error("failed to list secrets", "error", err)
+		errorWithRequestID(w, r, "internal server error", http.StatusInternalServerError)
+		return false
+	}
+
+	names := make([]string, 0, len(secrets))
+	for _, s := range secrets {
+		names = append(names, s.Name)
+	}
+	for _, k := range slices.Sorted(maps.Keys(secretEnv)) {
+		if !slices.Contains(names, secretEnv[k]) {
+			errorWithRequestID(w, r, fmt.Sprintf("unknown secret %q", secretEnv[k]), http.StatusBadRequest)
+			return false
+		}
+	}
+	return true
+}
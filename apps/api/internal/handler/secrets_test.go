@@ -0,0 +1,211 @@
+package handler
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/max-cloud/api/internal/auth"
+	"github.com/max-cloud/api/internal/orchestrator"
+	"github.com/max-cloud/api/internal/store"
+	"github.com/max-cloud/shared/pkg/models"
+)
+
+func secretsRouter(h *Handler) *chi.Mux {
+	r := chi.NewRouter()
+	r.Get("/api/v1/secrets", h.ListSecrets)
+	r.Put("/api/v1/secrets/{name}", h.SetSecret)
+	r.Delete("/api/v1/secrets/{name}", h.DeleteSecret)
+	r.Post("/api/v1/services", h.CreateService)
+	r.Patch("/api/v1/services/{id}", h.UpdateService)
+	return r
+}
+
+func TestSecretsLifecycle(t *testing.T) {
+	h, s := setup()
+	r := secretsRouter(h)
+	ctx := auth.WithTenant(context.Background(), "org-1", "user-1")
+
+	req := httptest.NewRequest("PUT", "/api/v1/secrets/db-password", bytes.NewBufferString(`{"value":"hunter2"}`)).WithContext(ctx)
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+	if w.Code != http.StatusOK {
+		t.Fatalf("expected 200, got %d: %s", w.Code, w.Body.String())
+	}
+	if strings.Contains(w.Body.String(), "hunter2") {
+		t.Fatalf("expected response without plaintext, got %s", w.Body.String())
+	}
+
+	req = httptest.NewRequest("GET", "/api/v1/secrets", nil).WithContext(ctx)
+	w = httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+	if strings.Contains(w.Body.String(), "hunter2") {
+		t.Fatalf("expected list without plaintext, got %s", w.Body.String())
+	}
+	var secrets []models.Secret
+	json.NewDecoder(w.Body).Decode(&secrets)
+	if len(secrets) != 1 || secrets[0].Name != "db-password" || secrets[0].OrgID != "org-1" {
+		t.Fatalf("unexpected secrets: %+v", secrets)
+	}
+
+	// Ein Service referenziert das Secret, Löschen wird abgelehnt
+	payload := `{"name":"app","image":"nginx:latest","secret_env":{"DB_PASSWORD":"db-password"}}`
+	req = httptest.NewRequest("POST", "/api/v1/services", bytes.NewBufferString(payload)).WithContext(ctx)
+	w = httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+	if w.Code != http.StatusCreated {
+		t.Fatalf("expected 201, got %d: %s", w.Code, w.Body.String())
+	}
+	var svc models.Service
+	json.NewDecoder(w.Body).Decode(&svc)
+
+	req = httptest.NewRequest("DELETE", "/api/v1/secrets/db-password", nil).WithContext(ctx)
+	w = httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+	if w.Code != http.StatusConflict {
+		t.Fatalf("expected 409 for referenced secret, got %d", w.Code)
+	}
+
+	// Ein Klartext-Wert ersetzt die Secret-Referenz, danach ist das Löschen erlaubt
+	req = httptest.NewRequest("PATCH", "/api/v1/services/"+svc.ID, bytes.NewBufferString(`{"env_vars":{"DB_PASSWORD":"plain"}}`)).WithContext(ctx)
+	w = httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+	var updated models.Service
+	json.NewDecoder(w.Body).Decode(&updated)
+	if len(updated.SecretEnv) != 0 || updated.EnvVars["DB_PASSWORD"] != "plain" {
+		t.Fatalf("expected secret ref replaced by value, got env=%v secret_env=%v", updated.EnvVars, updated.SecretEnv)
+	}
+
+	req = httptest.NewRequest("DELETE", "/api/v1/secrets/db-password", nil).WithContext(ctx)
+	w = httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+	if w.Code != http.StatusNoContent {
+		t.Fatalf("expected 204, got %d: %s", w.Code, w.Body.String())
+	}
+
+	if list, _ := s.ListSecrets(ctx, "org-1"); len(list) != 0 {
+		t.Fatalf("expected no secrets left, got %+v", list)
+	}
+}
+
+func TestSetSecretRestartsServingRevisions(t *testing.T) {
+	s := store.NewMemory()
+	orch := orchestrator.NewNoop(slog.Default())
+	h := New(slog.Default(), s, s, s, s, s, orch, nil, 24*time.Hour, true, "registry.local", "test-secret", 1*time.Hour)
+	r := secretsRouter(h)
+	ctx := auth.WithTenant(context.Background(), "org-1", "user-1")
+
+	if _, err := s.SetSecret(ctx, "org-1", "db-password", "hunter2"); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	user, err := s.Create(ctx, models.DeployRequest{Name: "app", Image: "nginx:1", SecretEnv: map[string]string{"DB_PASSWORD": "db-password"}})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	other, err := s.Create(ctx, models.DeployRequest{Name: "web", Image: "nginx:latest"})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	// Zwei Revisionen mit festgelegter Aufteilung
+	user.Image = "nginx:2"
+	user, err = s.Update(ctx, user)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	split := []models.TrafficTarget{
+		{RevisionName: "app-00001", Percent: 90},
+		{RevisionName: "app-00002", Percent: 10},
+	}
+	user, err = s.SetTraffic(ctx, user.ID, user.Generation, split)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	req := httptest.NewRequest("PUT", "/api/v1/secrets/db-password", bytes.NewBufferString(`{"value":"rotated"}`)).WithContext(ctx)
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+	if w.Code != http.StatusOK {
+		t.Fatalf("expected 200, got %d: %s", w.Code, w.Body.String())
+	}
+
+	// Die Aufteilung bleibt bestehen, es entsteht keine neue Revision
+	updated, _ := s.Get(ctx, user.ID)
+	if updated.Generation != user.Generation || len(updated.Traffic) != 2 || updated.Traffic[0] != split[0] || updated.Traffic[1] != split[1] {
+		t.Fatalf("expected generation %d with split %+v, got generation %d traffic %+v", user.Generation, split, updated.Generation, updated.Traffic)
+	}
+	if revs, _ := s.ListRevisions(ctx, user.ID); len(revs) != 2 {
+		t.Fatalf("expected no new revision, got %d revisions", len(revs))
+	}
+
+	if got := orch.Secrets(user.ID); got["db-password"] != "rotated" {
+		t.Fatalf("expected rotated secret to be applied, got %v", got)
+	}
+	if got := orch.Restarts(user.ID); len(got) != 2 || got[0] != "app-00001" || got[1] != "app-00002" {
+		t.Fatalf("expected both serving revisions restarted, got %v", got)
+	}
+	if got := orch.Restarts(other.ID); len(got) != 0 {
+		t.Fatalf("expected unrelated service untouched, got %v", got)
+	}
+
+	events, err := s.ListEvents(ctx, user.ID, 10)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(events) == 0 || events[0].Type != models.ServiceEventUpdated || !strings.Contains(events[0].Message, "db-password") {
+		t.Fatalf("expected update event for secret change, got %+v", events)
+	}
+
+	// Derselbe Wert erneut gesetzt startet nichts neu
+	req = httptest.NewRequest("PUT", "/api/v1/secrets/db-password", bytes.NewBufferString(`{"value":"rotated"}`)).WithContext(ctx)
+	w = httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+	if w.Code != http.StatusOK {
+		t.Fatalf("expected 200, got %d: %s", w.Code, w.Body.String())
+	}
+	if got := orch.Restarts(user.ID); len(got) != 2 {
+		t.Fatalf("expected no restart for unchanged value, got %v", got)
+	}
+}
+
+func TestSecretsValidation(t *testing.T) {
+	h, s := setup()
+	r := secretsRouter(h)
+	ctx := auth.WithTenant(context.Background(), "org-1", "user-1")
+	s.SetSecret(ctx, "org-1", "token", "abc")
+	s.SetSecret(ctx, "org-2", "foreign", "xyz")
+
+	tests := []struct {
+		name   string
+		method string
+		path   string
+		body   string
+		code   int
+	}{
+		{"invalid name", "PUT", "/api/v1/secrets/bad%20name", `{"value":"x"}`, http.StatusBadRequest},
+		{"empty value", "PUT", "/api/v1/secrets/token", `{"value":""}`, http.StatusBadRequest},
+		{"too large", "PUT", "/api/v1/secrets/token", `{"value":"` + strings.Repeat("x", models.MaxSecretSize+1) + `"}`, http.StatusBadRequest},
+		{"delete unknown", "DELETE", "/api/v1/secrets/missing", "", http.StatusNotFound},
+		{"unknown secret ref", "POST", "/api/v1/services", `{"name":"a","image":"nginx","secret_env":{"TOKEN":"missing"}}`, http.StatusBadRequest},
+		{"foreign secret ref", "POST", "/api/v1/services", `{"name":"b","image":"nginx","secret_env":{"TOKEN":"foreign"}}`, http.StatusBadRequest},
+		{"value and secret", "POST", "/api/v1/services", `{"name":"c","image":"nginx","env_vars":{"TOKEN":"x"},"secret_env":{"TOKEN":"token"}}`, http.StatusBadRequest},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(tt.method, tt.path, bytes.NewBufferString(tt.body)).WithContext(ctx)
+			w := httptest.NewRecorder()
+			r.ServeHTTP(w, req)
+			if w.Code != tt.code {
+				t.Fatalf("expected %d, got %d: %s", tt.code, w.Code, w.Body.String())
+			}
+		})
+	}
+}
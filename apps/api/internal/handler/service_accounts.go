@@ -0,0 +1,159 @@
+package handler
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/max-cloud/api/internal/auth"
+	"github.com/max-cloud/api/internal/store"
+	"github.com/max-cloud/shared/pkg/models"
+)
+
+// CreateServiceAccount legt einen Service-Account an (Berechtigung members:manage).
+func (h *Handler) CreateServiceAccount(w http.ResponseWriter, r *http.Request) {
+	orgID, _ := auth.OrgIDFromContext(r.Context())
+
+	var req models.CreateServiceAccountRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, `{"error":"invalid JSON"}`, http.StatusBadRequest)
+		return
+	}
+	if !models.ServiceAccountNamePattern.MatchString(req.Name) {
+		http.Error(w, `{"error":"name must consist of lowercase letters, digits and dashes (max 63 characters)"}`, http.StatusBadRequest)
+		return
+	}
+	if req.Role == "" {
+		req.Role = models.OrgRoleDeveloper
+	}
+	if !models.ValidServiceAccountRole(req.Role) {
+		http.Error(w, `{"error":"role must be admin, developer or viewer"}`, http.StatusBadRequest)
+		return
+	}
+
+	sa, err := h.authStore.CreateServiceAccount(r.Context(), orgID, req.Name, req.Role)
+	if err != nil {
+		h.writeServiceAccountError(w, err, "failed to create service account")
+		return
+	}
+
+	h.logger.Info("service account created", "org_id", orgID, "service_account_id", sa.ID, "role", sa.Role, "actor", auth.Actor(r.Context()))
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusCreated)
+	json.NewEncoder(w).Encode(sa)
+}
+
+// ListServiceAccounts gibt alle Service-Accounts der aktuellen Organisation zurück.
+func (h *Handler) ListServiceAccounts(w http.ResponseWriter, r *http.Request) {
+	orgID, _ := auth.OrgIDFromContext(r.Context())
+
+	accounts, err := h.authStore.ListServiceAccounts(r.Context(), orgID)
+	if err != nil {
+		h.logger.Error("failed to list service accounts", "error", err)
+		http.Error(w, `{"error":"internal server error"}`, http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(accounts)
+}
+
+// GetServiceAccount gibt einen Service-Account der aktuellen Organisation zurück.
+func (h *Handler) GetServiceAccount(w http.ResponseWriter, r *http.Request) {
+	orgID, _ := auth.OrgIDFromContext(r.Context())
+
+	sa, err := h.authStore.GetServiceAccount(r.Context(), orgID, chi.URLParam(r, "id"))
+	if err != nil {
+		h.writeServiceAccountError(w, err, "failed to get service account")
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(sa)
+}
+
+// UpdateServiceAccount ändert die Rolle eines Service-Accounts (Berechtigung members:manage).
+func (h *Handler) UpdateServiceAccount(w http.ResponseWriter, r *http.Request) {
+	orgID, _ := auth.OrgIDFromContext(r.Context())
+	id := chi.URLParam(r, "id")
+
+	var req models.UpdateServiceAccountRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, `{"error":"invalid JSON"}`, http.StatusBadRequest)
+		return
+	}
+	if !models.ValidServiceAccountRole(req.Role) {
+		http.Error(w, `{"error":"role must be admin, developer or viewer"}`, http.StatusBadRequest)
+		return
+	}
+
+	sa, err := h.authStore.UpdateServiceAccountRole(r.Context(), orgID, id, req.Role)
+	if err != nil {
+		h.writeServiceAccountError(w, err, "failed to update service account")
+		return
+	}
+
+	h.logger.Info("service account updated", "org_id", orgID, "service_account_id", id, "role", sa.Role, "actor", auth.Actor(r.Context()))
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(sa)
+}
+
+// DeleteServiceAccount löscht einen Service-Account und widerruft damit alle seine API-Keys
+// (Berechtigung members:manage).
+func (h *Handler) DeleteServiceAccount(w http.ResponseWriter, r *http.Request) {
+	orgID, _ := auth.OrgIDFromContext(r.Context())
+	id := chi.URLParam(r, "id")
+
+	if err := h.authStore.DeleteServiceAccount(r.Context(), orgID, id); err != nil {
+		h.writeServiceAccountError(w, err, "failed to delete service account")
+		return
+	}
+
+	h.logger.Info("service account deleted", "org_id", orgID, "service_account_id", id, "actor", auth.Actor(r.Context()))
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// CreateServiceAccountAPIKey erstellt einen API-Key für einen Service-Account (Berechtigung
+// members:manage). Es gelten dieselben Regeln für Scopes und Ablauf wie bei CreateAPIKey.
+func (h *Handler) CreateServiceAccountAPIKey(w http.ResponseWriter, r *http.Request) {
+	orgID, _ := auth.OrgIDFromContext(r.Context())
+	id := chi.URLParam(r, "id")
+
+	var req models.CreateAPIKeyRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, `{"error":"invalid JSON"}`, http.StatusBadRequest)
+		return
+	}
+	if !h.checkAPIKeyRequest(w, r, req) {
+		return
+	}
+
+	rawKey, info, err := h.authStore.CreateServiceAccountAPIKey(r.Context(), orgID, id, req)
+	if err != nil {
+		h.writeServiceAccountError(w, err, "failed to create api key")
+		return
+	}
+
+	h.logger.Info("api key created", "org_id", orgID, "key_id", info.ID, "service_account_id", id, "actor", auth.Actor(r.Context()))
+	resp := models.CreateAPIKeyResponse{
+		APIKey: rawKey,
+		Info:   *info,
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusCreated)
+	json.NewEncoder(w).Encode(resp)
+}
+
+func (h *Handler) writeServiceAccountError(w http.ResponseWriter, err error, msg string) {
+	switch {
+	case errors.Is(err, store.ErrServiceAccountNotFound):
+		http.Error(w, `{"error":"service account not found"}`, http.StatusNotFound)
+	case errors.Is(err, store.ErrDuplicateServiceAccount):
+		http.Error(w, `{"error":"service account name already exists"}`, http.StatusConflict)
+	default:
+		h.logger.Error(msg, "error", err)
+		http.Error(w, `{"error":"internal server error"}`, http.StatusInternalServerError)
+	}
+}
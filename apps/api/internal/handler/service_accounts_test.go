@@ -0,0 +1,172 @@
+package handler
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/max-cloud/api/internal/auth"
+	"github.com/max-cloud/shared/pkg/models"
+)
+
+func serviceAccountsRouter(h *Handler) *chi.Mux {
+	r := chi.NewRouter()
+	r.Method("GET", "/api/v1/org/service-accounts", withPermission(h, models.PermissionRead, h.ListServiceAccounts))
+	r.Method("POST", "/api/v1/org/service-accounts", withPermission(h, models.PermissionManageMembers, h.CreateServiceAccount))
+	r.Method("PATCH", "/api/v1/org/service-accounts/{id}", withPermission(h, models.PermissionManageMembers, h.UpdateServiceAccount))
+	r.Method("DELETE", "/api/v1/org/service-accounts/{id}", withPermission(h, models.PermissionManageMembers, h.DeleteServiceAccount))
+	r.Method("POST", "/api/v1/org/service-accounts/{id}/api-keys", withPermission(h, models.PermissionManageMembers, h.CreateServiceAccountAPIKey))
+	return r
+}
+
+func TestServiceAccountsHandler(t *testing.T) {
+	h, s := setupInvite()
+	r := serviceAccountsRouter(h)
+	owner, org, ownerCtx := registerOwner(t, s)
+	_, _, viewerCtx := inviteMember(t, h, org, owner.ID, "viewer@example.com", models.OrgRoleViewer)
+
+	tests := []struct {
+		name string
+		ctx  context.Context
+		body string
+		code int
+	}{
+		{"create", ownerCtx, `{"name":"ci","role":"developer"}`, http.StatusCreated},
+		{"duplicate name", ownerCtx, `{"name":"ci"}`, http.StatusConflict},
+		{"invalid name", ownerCtx, `{"name":"CI Bot"}`, http.StatusBadRequest},
+		{"owner role", ownerCtx, `{"name":"root","role":"owner"}`, http.StatusBadRequest},
+		{"viewer cannot create", viewerCtx, `{"name":"other"}`, http.StatusForbidden},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest("POST", "/api/v1/org/service-accounts", bytes.NewBufferString(tt.body)).WithContext(tt.ctx)
+			w := httptest.NewRecorder()
+			r.ServeHTTP(w, req)
+			if w.Code != tt.code {
+				t.Fatalf("expected %d, got %d: %s", tt.code, w.Code, w.Body.String())
+			}
+		})
+	}
+
+	req := httptest.NewRequest("GET", "/api/v1/org/service-accounts", nil).WithContext(viewerCtx)
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+	var accounts []models.ServiceAccount
+	json.NewDecoder(w.Body).Decode(&accounts)
+	if w.Code != http.StatusOK || len(accounts) != 1 || accounts[0].Role != models.OrgRoleDeveloper {
+		t.Fatalf("unexpected service accounts (%d): %+v", w.Code, accounts)
+	}
+	sa := accounts[0]
+
+	req = httptest.NewRequest("PATCH", "/api/v1/org/service-accounts/"+sa.ID, bytes.NewBufferString(`{"role":"viewer"}`)).WithContext(ownerCtx)
+	w = httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+	if w.Code != http.StatusOK {
+		t.Fatalf("expected 200, got %d: %s", w.Code, w.Body.String())
+	}
+
+	req = httptest.NewRequest("POST", "/api/v1/org/service-accounts/"+sa.ID+"/api-keys", bytes.NewBufferString(`{"name":"deploy"}`)).WithContext(ownerCtx)
+	w = httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+	if w.Code != http.StatusCreated {
+		t.Fatalf("expected 201, got %d: %s", w.Code, w.Body.String())
+	}
+	var resp models.CreateAPIKeyResponse
+	json.NewDecoder(w.Body).Decode(&resp)
+	if resp.Info.ServiceAccountID != sa.ID || resp.Info.UserID != "" {
+		t.Fatalf("expected key owned by service account, got %+v", resp.Info)
+	}
+
+	req = httptest.NewRequest("DELETE", "/api/v1/org/service-accounts/"+sa.ID, nil).WithContext(ownerCtx)
+	w = httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+	if w.Code != http.StatusNoContent {
+		t.Fatalf("expected 204, got %d: %s", w.Code, w.Body.String())
+	}
+	if _, err := s.ValidateAPIKey(context.Background(), resp.APIKey); err == nil {
+		t.Fatal("expected api key of deleted service account to be revoked")
+	}
+
+	req = httptest.NewRequest("DELETE", "/api/v1/org/service-accounts/"+sa.ID, nil).WithContext(ownerCtx)
+	w = httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+	if w.Code != http.StatusNotFound {
+		t.Fatalf("expected 404, got %d", w.Code)
+	}
+}
+
+func TestServiceAccountAPIKey(t *testing.T) {
+	h, s := setupInvite()
+	owner, org, ownerCtx := registerOwner(t, s)
+	admin, _, _ := inviteMember(t, h, org, owner.ID, "admin@example.org", models.OrgRoleAdmin)
+
+	sa, err := s.CreateServiceAccount(context.Background(), org.ID, "ci", models.OrgRoleDeveloper)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	rawKey, _, err := s.CreateServiceAccountAPIKey(context.Background(), org.ID, sa.ID, models.CreateAPIKeyRequest{Name: "deploy"})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	// Der Key bleibt gültig, wenn Mitglieder die Organisation verlassen
+	if err := s.RemoveMember(ownerCtx, org.ID, admin.ID); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	r := chi.NewRouter()
+	r.Use(auth.Middleware(slog.Default(), s))
+	r.Method("POST", "/api/v1/services", withPermission(h, models.PermissionDeploy, h.CreateService))
+	r.Method("DELETE", "/api/v1/services/{id}", withPermission(h, models.PermissionDelete, h.DeleteService))
+	r.Post("/api/v1/auth/api-keys", h.CreateAPIKey)
+	r.Post("/api/v1/auth/invites", h.CreateInvite)
+	r.Get("/api/v1/auth/status", h.AuthStatus)
+
+	send := func(method, target, body string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(method, target, bytes.NewBufferString(body))
+		req.Header.Set("Authorization", "Bearer "+rawKey)
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, req)
+		return w
+	}
+
+	w := send("POST", "/api/v1/services", `{"name":"app","image":"nginx:latest"}`)
+	if w.Code != http.StatusCreated {
+		t.Fatalf("expected 201, got %d: %s", w.Code, w.Body.String())
+	}
+	var svc models.Service
+	json.NewDecoder(w.Body).Decode(&svc)
+
+	// Ereignisse nennen den Service-Account als Auslöser
+	events, _ := s.ListEvents(context.Background(), svc.ID, 10)
+	if len(events) != 1 || events[0].Actor != "service-account:"+sa.ID {
+		t.Fatalf("expected service account as actor, got %+v", events)
+	}
+
+	if w := send("DELETE", "/api/v1/services/"+svc.ID, ``); w.Code != http.StatusForbidden {
+		t.Fatalf("expected 403 for developer service account, got %d", w.Code)
+	}
+	if w := send("POST", "/api/v1/auth/invites", `{"email":"x@example.com"}`); w.Code != http.StatusForbidden {
+		t.Fatalf("expected 403 for invite by service account, got %d", w.Code)
+	}
+
+	w = send("GET", "/api/v1/auth/status", ``)
+	var info models.AuthInfo
+	json.NewDecoder(w.Body).Decode(&info)
+	if info.ServiceAccount == nil || info.ServiceAccount.ID != sa.ID || info.Role != models.OrgRoleDeveloper {
+		t.Fatalf("unexpected auth status: %+v", info)
+	}
+
+	// Keys, die der Service-Account erzeugt, gehören wieder ihm
+	w = send("POST", "/api/v1/auth/api-keys", `{"name":"second"}`)
+	var resp models.CreateAPIKeyResponse
+	json.NewDecoder(w.Body).Decode(&resp)
+	if w.Code != http.StatusCreated || resp.Info.ServiceAccountID != sa.ID {
+		t.Fatalf("expected key owned by service account (%d): %+v", w.Code, resp.Info)
+	}
+}
@@ -0,0 +1,208 @@
+package handler
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"regexp"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/max-cloud/api/internal/auth"
+	"github.com/max-cloud/api/internal/store"
+	"github.com/max-cloud/shared/pkg/models"
+)
+
+// tagPattern entspricht einem DNS-Label, da Knative den Tag als Subdomain-Präfix verwendet.
+var tagPattern = regexp.MustCompile(`^[a-z]([-a-z0-9]{0,61}[a-z0-9])?$`)
+
+// SetTraffic verteilt den Traffic eines Services auf bestehende Revisionen.
+// Eine leere Liste setzt die Aufteilung zurück, dann erhält die neueste Revision 100%.
+func (h *Handler) SetTraffic(w http.ResponseWriter, r *http.Request) {
+	id := chi.URLParam(r, "id")
+
+	var req models.SetTrafficRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		h.logger.Error("invalid request body", "error", err)
+		errorWithRequestID(w, r, "invalid JSON", http.StatusBadRequest)
+		return
+	}
+
+	svc, err := h.store.Get(r.Context(), id)
+	if err != nil {
+		if errors.Is(err, store.ErrNotFound) {
+			http.Error(w, `{"error":"service not found"}`, http.StatusNotFound)
+			return
+		}
+		h.logger.Error("failed to get service for traffic", "error", err, "id", id)
+		errorWithRequestID(w, r, "internal server error", http.StatusInternalServerError)
+		return
+	}
+
+	if svc.Status == models.ServiceStatusDeleting {
+		errorWithRequestID(w, r, "service is being deleted", http.StatusConflict)
+		return
+	}
+
+	revisions, err := h.store.ListRevisions(r.Context(), svc.ID)
+	if err != nil {
+		h.logger.Error("failed to list revisions for traffic", "error", err, "id", id)
+		errorWithRequestID(w, r, "internal server error", http.StatusInternalServerError)
+		return
+	}
+
+	if msg := validateTraffic(req.Targets, revisions); msg != "" {
+		errorWithRequestID(w, r, msg, http.StatusBadRequest)
+		return
+	}
+
+	updated, err := h.store.SetTraffic(r.Context(), svc.ID, svc.Generation, req.Targets)
+	if err != nil {
+		if errors.Is(err, store.ErrNotFound) {
+			http.Error(w, `{"error":"service not found"}`, http.StatusNotFound)
+			return
+		}
+		if errors.Is(err, store.ErrConflict) {
+			errorWithRequestID(w, r, "service was modified concurrently, reload and retry", http.StatusConflict)
+			return
+		}
+		h.logger.Error("failed to set traffic", "error", err, "id", id)
+		errorWithRequestID(w, r, "internal server error", http.StatusInternalServerError)
+		return
+	}
+
+	h.logger.Info("service traffic updated", "id", updated.ID, "targets", len(updated.Traffic), "generation", updated.Generation, "actor", auth.Actor(r.Context()))
+	h.recordEvent(r.Context(), updated, models.ServiceEventUpdated, fmt.Sprintf("traffic split changed (generation %d)", updated.Generation))
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(updated)
+}
+
+// validateTraffic prüft eine Traffic-Aufteilung und gibt bei Fehlern eine Meldung zurück.
+// Jede Revision darf nur einmal vorkommen und die Prozente müssen 100 ergeben.
+func validateTraffic(targets []models.TrafficTarget, revisions []models.Revision) string {
+	if len(targets) == 0 {
+		return ""
+	}
+
+	known := make(map[string]bool, len(revisions))
+	for _, rev := range revisions {
+		known[rev.Name] = true
+	}
+
+	seen := make(map[string]bool, len(targets))
+	tags := make(map[string]bool, len(targets))
+	sum := 0
+	for _, t := range targets {
+		if t.RevisionName == "" {
+			return "revision_name is required"
+		}
+		if !known[t.RevisionName] {
+			return fmt.Sprintf("unknown revision %q", t.RevisionName)
+		}
+		if seen[t.RevisionName] {
+			return fmt.Sprintf("revision %q listed more than once", t.RevisionName)
+		}
+		seen[t.RevisionName] = true
+		if t.Percent < 0 || t.Percent > 100 {
+			return "percent must be between 0 and 100"
+		}
+		if t.Tag != "" {
+			if msg := validateTag(t.Tag); msg != "" {
+				return msg
+			}
+			if tags[t.Tag] {
+				return fmt.Sprintf("tag %q used more than once", t.Tag)
+			}
+			tags[t.Tag] = true
+		}
+		sum += t.Percent
+	}
+
+	if sum != 100 {
+		return fmt.Sprintf("traffic percentages must sum to 100, got %d", sum)
+	}
+	return ""
+}
+
+// validateTag prüft einen Traffic-Tag und gibt bei Fehlern eine Meldung zurück.
+func validateTag(tag string) string {
+	if !tagPattern.MatchString(tag) {
+		return fmt.Sprintf("invalid tag %q: must be a lowercase DNS label", tag)
+	}
+	return ""
+}
+
+// checkTrafficReset verhindert, dass ein Update ohne Tag eine bestehende Traffic-Aufteilung
+// stillschweigend verwirft. Der Aufrufer muss das Zurücksetzen explizit anfordern.
+func checkTrafficReset(w http.ResponseWriter, r *http.Request, svc models.Service, tag string, reset bool) bool {
+	if tag != "" && reset {
+		errorWithRequestID(w, r, "tag and reset_traffic are mutually exclusive", http.StatusBadRequest)
+		return false
+	}
+	if tag == "" && !reset && len(svc.Traffic) > 0 {
+		errorWithRequestID(w, r, "service has a traffic split: set a tag to stage the new revision or reset_traffic to route all traffic to it", http.StatusConflict)
+		return false
+	}
+	return true
+}
+
+// stageTaggedRevision gibt die Traffic-Aufteilung für ein Update mit Tag zurück.
+// Die bisherige Aufteilung bleibt erhalten, die neue Revision erhält 0% und den Tag.
+// Trägt bereits eine andere Revision den Tag, wandert er zur neuen Revision.
+func stageTaggedRevision(svc models.Service, tag string) []models.TrafficTarget {
+	current := svc.Traffic
+	if len(current) == 0 {
+		current = []models.TrafficTarget{{RevisionName: svc.LatestRevision, Percent: 100}}
+	}
+
+	result := make([]models.TrafficTarget, 0, len(current)+1)
+	for _, t := range current {
+		if t.Tag == tag {
+			if t.Percent == 0 {
+				continue
+			}
+			t.Tag = ""
+		}
+		result = append(result, t)
+	}
+
+	return append(result, models.TrafficTarget{
+		RevisionName: models.RevisionName(svc.Name, svc.Generation+1),
+		Percent:      0,
+		Tag:          tag,
+	})
+}
+
+// servingRevisions gibt die Revisionen zurück, die laut Traffic-Aufteilung laufen,
+// einschließlich getaggter Revisionen ohne Anteil.
+func servingRevisions(svc models.Service) []string {
+	if len(svc.Traffic) == 0 {
+		if svc.LatestRevision == "" {
+			return nil
+		}
+		return []string{svc.LatestRevision}
+	}
+
+	revisions := make([]string, 0, len(svc.Traffic))
+	for _, t := range svc.Traffic {
+		revisions = append(revisions, t.RevisionName)
+	}
+	return revisions
+}
+
+// trafficPercent gibt den Traffic-Anteil einer Revision an svc zurück.
+func trafficPercent(svc models.Service, revisionName string) int {
+	if len(svc.Traffic) == 0 {
+		if revisionName == svc.LatestRevision {
+			return 100
+		}
+		return 0
+	}
+	for _, t := range svc.Traffic {
+		if t.RevisionName == revisionName {
+			return t.Percent
+		}
+	}
+	return 0
+}
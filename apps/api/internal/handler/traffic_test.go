@@ -0,0 +1,175 @@
+package handler
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/max-cloud/shared/pkg/models"
+)
+
+func TestSetTraffic(t *testing.T) {
+	h, s := setup()
+	created, _ := s.Create(context.Background(), models.DeployRequest{Name: "app", Image: "img:1"})
+
+	r := chi.NewRouter()
+	r.Patch("/api/v1/services/{id}", h.UpdateService)
+	r.Put("/api/v1/services/{id}/traffic", h.SetTraffic)
+	r.Get("/api/v1/services/{id}/revisions", h.ListRevisions)
+
+	req := httptest.NewRequest("PATCH", "/api/v1/services/"+created.ID, bytes.NewBufferString(`{"image":"img:2"}`))
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+	if w.Code != http.StatusOK {
+		t.Fatalf("expected 200, got %d: %s", w.Code, w.Body.String())
+	}
+
+	body := `{"targets":[{"revision_name":"app-00001","percent":90},{"revision_name":"app-00002","percent":10}]}`
+	req = httptest.NewRequest("PUT", "/api/v1/services/"+created.ID+"/traffic", bytes.NewBufferString(body))
+	w = httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+	if w.Code != http.StatusOK {
+		t.Fatalf("expected 200, got %d: %s", w.Code, w.Body.String())
+	}
+
+	var svc models.Service
+	json.NewDecoder(w.Body).Decode(&svc)
+	if len(svc.Traffic) != 2 || svc.Traffic[0].Percent != 90 {
+		t.Fatalf("unexpected traffic: %+v", svc.Traffic)
+	}
+	if svc.Status != models.ServiceStatusPending {
+		t.Fatalf("expected pending, got %s", svc.Status)
+	}
+
+	req = httptest.NewRequest("GET", "/api/v1/services/"+created.ID+"/revisions", nil)
+	w = httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+
+	var revisions []models.Revision
+	json.NewDecoder(w.Body).Decode(&revisions)
+	if len(revisions) != 2 || revisions[0].Traffic != 10 || revisions[1].Traffic != 90 {
+		t.Fatalf("expected revisions with 10%% and 90%%, got %+v", revisions)
+	}
+
+	// Ohne Tag verwirft ein Update die Aufteilung nur auf ausdrücklichen Wunsch
+	req = httptest.NewRequest("PATCH", "/api/v1/services/"+created.ID, bytes.NewBufferString(`{"image":"img:3"}`))
+	w = httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+	if w.Code != http.StatusConflict {
+		t.Fatalf("expected 409 for untagged update over a split, got %d: %s", w.Code, w.Body.String())
+	}
+	unchanged, _ := s.Get(context.Background(), created.ID)
+	if len(unchanged.Traffic) != 2 || unchanged.Image != "img:2" {
+		t.Fatalf("expected split and image to stay, got image=%s traffic=%+v", unchanged.Image, unchanged.Traffic)
+	}
+
+	req = httptest.NewRequest("PATCH", "/api/v1/services/"+created.ID, bytes.NewBufferString(`{"image":"img:3","tag":"candidate","reset_traffic":true}`))
+	w = httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+	if w.Code != http.StatusBadRequest {
+		t.Fatalf("expected 400 for tag with reset_traffic, got %d", w.Code)
+	}
+
+	// Mit reset_traffic erhält die neue Revision 100%
+	req = httptest.NewRequest("PATCH", "/api/v1/services/"+created.ID, bytes.NewBufferString(`{"image":"img:3","reset_traffic":true}`))
+	w = httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+	if w.Code != http.StatusOK {
+		t.Fatalf("expected 200, got %d: %s", w.Code, w.Body.String())
+	}
+	var reset models.Service
+	json.NewDecoder(w.Body).Decode(&reset)
+	if reset.Traffic != nil || reset.LatestRevision != "app-00004" {
+		t.Fatalf("expected traffic reset to app-00004, got latest=%s traffic=%+v", reset.LatestRevision, reset.Traffic)
+	}
+}
+
+func TestUpdateServiceWithTag(t *testing.T) {
+	h, s := setup()
+	created, _ := s.Create(context.Background(), models.DeployRequest{Name: "app", Image: "img:1"})
+
+	r := chi.NewRouter()
+	r.Patch("/api/v1/services/{id}", h.UpdateService)
+
+	req := httptest.NewRequest("PATCH", "/api/v1/services/"+created.ID, bytes.NewBufferString(`{"image":"img:2","tag":"candidate"}`))
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+	if w.Code != http.StatusOK {
+		t.Fatalf("expected 200, got %d: %s", w.Code, w.Body.String())
+	}
+
+	var svc models.Service
+	json.NewDecoder(w.Body).Decode(&svc)
+	want := []models.TrafficTarget{
+		{RevisionName: "app-00001", Percent: 100},
+		{RevisionName: "app-00002", Percent: 0, Tag: "candidate"},
+	}
+	if len(svc.Traffic) != 2 || svc.Traffic[0] != want[0] || svc.Traffic[1] != want[1] {
+		t.Fatalf("expected traffic %+v, got %+v", want, svc.Traffic)
+	}
+
+	// Ein weiteres Update mit demselben Tag verschiebt den Tag auf die neue Revision
+	req = httptest.NewRequest("PATCH", "/api/v1/services/"+created.ID, bytes.NewBufferString(`{"image":"img:3","tag":"candidate"}`))
+	w = httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+
+	var next models.Service
+	json.NewDecoder(w.Body).Decode(&next)
+	want = []models.TrafficTarget{
+		{RevisionName: "app-00001", Percent: 100},
+		{RevisionName: "app-00003", Percent: 0, Tag: "candidate"},
+	}
+	if len(next.Traffic) != 2 || next.Traffic[0] != want[0] || next.Traffic[1] != want[1] {
+		t.Fatalf("expected traffic %+v, got %+v", want, next.Traffic)
+	}
+
+	req = httptest.NewRequest("PATCH", "/api/v1/services/"+created.ID, bytes.NewBufferString(`{"tag":"Not_A_Label"}`))
+	w = httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+	if w.Code != http.StatusBadRequest {
+		t.Fatalf("expected 400 for invalid tag, got %d", w.Code)
+	}
+}
+
+func TestSetTrafficValidation(t *testing.T) {
+	h, s := setup()
+	created, _ := s.Create(context.Background(), models.DeployRequest{Name: "app", Image: "img:1"})
+	next := created
+	next.Image = "img:2"
+	s.Update(context.Background(), next)
+
+	r := chi.NewRouter()
+	r.Put("/api/v1/services/{id}/traffic", h.SetTraffic)
+
+	tests := []struct {
+		name    string
+		id      string
+		payload string
+		code    int
+	}{
+		{"sum below 100", created.ID, `{"targets":[{"revision_name":"app-00001","percent":50},{"revision_name":"app-00002","percent":40}]}`, http.StatusBadRequest},
+		{"negative percent", created.ID, `{"targets":[{"revision_name":"app-00001","percent":110},{"revision_name":"app-00002","percent":-10}]}`, http.StatusBadRequest},
+		{"duplicate revision", created.ID, `{"targets":[{"revision_name":"app-00001","percent":50},{"revision_name":"app-00001","percent":50}]}`, http.StatusBadRequest},
+		{"unknown revision", created.ID, `{"targets":[{"revision_name":"app-00042","percent":100}]}`, http.StatusBadRequest},
+		{"unknown service", "nonexistent", `{"targets":[]}`, http.StatusNotFound},
+		{"invalid json", created.ID, `{invalid`, http.StatusBadRequest},
+		{"duplicate tag", created.ID, `{"targets":[{"revision_name":"app-00001","percent":100,"tag":"a"},{"revision_name":"app-00002","percent":0,"tag":"a"}]}`, http.StatusBadRequest},
+		{"invalid tag", created.ID, `{"targets":[{"revision_name":"app-00001","percent":100,"tag":"-bad"}]}`, http.StatusBadRequest},
+		{"reset", created.ID, `{"targets":[]}`, http.StatusOK},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest("PUT", "/api/v1/services/"+tt.id+"/traffic", bytes.NewBufferString(tt.payload))
+			w := httptest.NewRecorder()
+			r.ServeHTTP(w, req)
+			if w.Code != tt.code {
+				t.Fatalf("expected %d, got %d: %s", tt.code, w.Code, w.Body.String())
+			}
+		})
+	}
+}
@@ -0,0 +1,79 @@
+package leader
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"sync/atomic"
+)
+
+// Elector sorgt dafür, dass von mehreren API-Replikas nur eine den Reconciler ausführt.
+type Elector interface {
+	// Run bewirbt sich wiederholt um die Führung und ruft lead auf, sobald sie erlangt ist.
+	// Der ctx von lead wird abgebrochen, wenn die Führung verloren geht; die Führung gilt
+	// erst als abgegeben, wenn lead zurückkehrt. Blockiert, bis ctx abgebrochen wird.
+	Run(ctx context.Context, lead func(ctx context.Context))
+}
+
+// Always ist der Elector für eine einzelne Replika ohne gemeinsamen Zustand (In-Memory-Store
+// ohne Cluster): sie führt immer.
+type Always struct{}
+
+func (Always) Run(ctx context.Context, lead func(ctx context.Context)) {
+	lead(ctx)
+}
+
+// Status hält fest, ob diese Replika gerade führt, und stellt das als Prometheus-Metrik bereit.
+type Status struct {
+	logger      *slog.Logger
+	identity    string
+	leading     atomic.Bool
+	transitions atomic.Int64
+}
+
+// NewStatus erstellt den Status der Replika mit der angegebenen Identität (z.B. Pod-Name).
+func NewStatus(logger *slog.Logger, identity string) *Status {
+	return &Status{logger: logger, identity: identity}
+}
+
+// Identity gibt die Identität der Replika zurück.
+func (s *Status) Identity() string {
+	return s.identity
+}
+
+// Leading meldet, ob die Replika gerade führt.
+func (s *Status) Leading() bool {
+	return s.leading.Load()
+}
+
+// Lead umschließt lead so, dass der Status während der Führung gesetzt ist.
+func (s *Status) Lead(lead func(ctx context.Context)) func(ctx context.Context) {
+	return func(ctx context.Context) {
+		s.leading.Store(true)
+		s.transitions.Add(1)
+		s.logger.Info("leader: started leading", "identity", s.identity)
+		defer func() {
+			s.leading.Store(false)
+			s.logger.Info("leader: stopped leading", "identity", s.identity)
+		}()
+		lead(ctx)
+	}
+}
+
+// ServeHTTP liefert die Metriken im Prometheus-Textformat. Über alle Replikas hinweg
+// zeigt maxcloud_reconciler_leader == 1, welche Replika gerade führt.
+func (s *Status) ServeHTTP(w http.ResponseWriter, _ *http.Request) {
+	leading := 0
+	if s.Leading() {
+		leading = 1
+	}
+
+	w.Header().Set("Content-Type", "text/plain; version=0.0.4; charset=utf-8")
+	fmt.Fprintln(w, "# HELP maxcloud_reconciler_leader Whether this replica currently runs the reconciler.")
+	fmt.Fprintln(w, "# TYPE maxcloud_reconciler_leader gauge")
+	fmt.Fprintf(w, "maxcloud_reconciler_leader{identity=%q} %d\n", s.identity, leading)
+	fmt.Fprintln(w, "# HELP maxcloud_reconciler_leader_transitions_total How often this replica became leader.")
+	fmt.Fprintln(w, "# TYPE maxcloud_reconciler_leader_transitions_total counter")
+	fmt.Fprintf(w, "maxcloud_reconciler_leader_transitions_total{identity=%q} %d\n", s.identity, s.transitions.Load())
+}
@@ -0,0 +1,37 @@
+package leader
+
+import (
+	"context"
+	"log/slog"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestStatusLead(t *testing.T) {
+	status := NewStatus(slog.Default(), "api-0")
+
+	var leadingInside bool
+	Always{}.Run(context.Background(), status.Lead(func(ctx context.Context) {
+		leadingInside = status.Leading()
+	}))
+
+	if !leadingInside {
+		t.Fatal("expected replica to be leading inside lead")
+	}
+	if status.Leading() {
+		t.Fatal("expected replica to stop leading after lead returns")
+	}
+
+	w := httptest.NewRecorder()
+	status.ServeHTTP(w, httptest.NewRequest("GET", "/metrics", nil))
+	body := w.Body.String()
+	for _, want := range []string{
+		`maxcloud_reconciler_leader{identity="api-0"} 0`,
+		`maxcloud_reconciler_leader_transitions_total{identity="api-0"} 1`,
+	} {
+		if !strings.Contains(body, want) {
+			t.Fatalf("expected %q in metrics, got:\n%s", want, body)
+		}
+	}
+}
@@ -0,0 +1,152 @@
+package orchestrator
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/max-cloud/shared/pkg/models"
+
+	k8serrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+)
+
+var domainMappingGVR = schema.GroupVersionResource{
+	Group:    "serving.knative.dev",
+	Version:  "v1beta1",
+	Resource: "domainmappings",
+}
+
+// domainIDLabel verknüpft ein DomainMapping mit der Domain im Store.
+const domainIDLabel = "max-cloud.dev/domain-id"
+
+// ApplyDomain erstellt ein Knative DomainMapping, das den Hostnamen auf den Knative Service zeigt.
+// Das Zertifikat stellt Knative über Auto-TLS aus, sofern im Cluster aktiviert.
+func (k *KnativeOrchestrator) ApplyDomain(ctx context.Context, domain models.Domain, svc models.Service) error {
+	ns := k.namespaceForOrg(domain.OrgID)
+	obj := buildDomainMapping(domain, svc, ns)
+	mappings := k.client.Resource(domainMappingGVR).Namespace(ns)
+
+	_, err := mappings.Create(ctx, obj, metav1.CreateOptions{})
+	if err == nil {
+		k.logger.Info("knative: domain mapping created", "hostname", domain.Hostname, "service", svc.Name, "namespace", ns)
+		return nil
+	}
+	if !k8serrors.IsAlreadyExists(err) {
+		return fmt.Errorf("creating domain mapping: %w", err)
+	}
+
+	existing, err := mappings.Get(ctx, domain.Hostname, metav1.GetOptions{})
+	if err != nil {
+		return fmt.Errorf("getting existing domain mapping: %w", err)
+	}
+	obj.SetResourceVersion(existing.GetResourceVersion())
+	if _, err := mappings.Update(ctx, obj, metav1.UpdateOptions{}); err != nil {
+		return fmt.Errorf("updating domain mapping: %w", err)
+	}
+	k.logger.Info("knative: domain mapping updated", "hostname", domain.Hostname, "service", svc.Name, "namespace", ns)
+	return nil
+}
+
+// RemoveDomain löscht das DomainMapping eines Hostnamens.
+func (k *KnativeOrchestrator) RemoveDomain(ctx context.Context, domain models.Domain) error {
+	ns := k.namespaceForOrg(domain.OrgID)
+	err := k.client.Resource(domainMappingGVR).Namespace(ns).Delete(ctx, domain.Hostname, metav1.DeleteOptions{})
+	if err != nil {
+		if k8serrors.IsNotFound(err) {
+			return nil
+		}
+		return fmt.Errorf("deleting domain mapping: %w", err)
+	}
+	k.logger.Info("knative: domain mapping removed", "hostname", domain.Hostname, "namespace", ns)
+	return nil
+}
+
+// DomainStatus liest die Conditions Ready und CertificateProvisioned des DomainMappings.
+func (k *KnativeOrchestrator) DomainStatus(ctx context.Context, domain models.Domain) (*DomainResult, error) {
+	ns := k.namespaceForOrg(domain.OrgID)
+	obj, err := k.client.Resource(domainMappingGVR).Namespace(ns).Get(ctx, domain.Hostname, metav1.GetOptions{})
+	if err != nil {
+		if k8serrors.IsNotFound(err) {
+			return nil, ErrNotFound
+		}
+		return nil, fmt.Errorf("getting domain mapping: %w", err)
+	}
+	return parseDomainStatus(obj), nil
+}
+
+func buildDomainMapping(domain models.Domain, svc models.Service, ns string) *unstructured.Unstructured {
+	return &unstructured.Unstructured{
+		Object: map[string]interface{}{
+			"apiVersion": "serving.knative.dev/v1beta1",
+			"kind":       "DomainMapping",
+			"metadata": map[string]interface{}{
+				"name":      domain.Hostname,
+				"namespace": ns,
+				"labels": map[string]interface{}{
+					managedByLabel: "max-cloud",
+					domainIDLabel:  domain.ID,
+				},
+			},
+			"spec": map[string]interface{}{
+				"ref": map[string]interface{}{
+					"apiVersion": "serving.knative.dev/v1",
+					"kind":       "Service",
+					"name":       svc.Name,
+				},
+			},
+		},
+	}
+}
+
+func parseDomainStatus(obj *unstructured.Unstructured) *DomainResult {
+	result := &DomainResult{Status: models.DomainStatusProvisioning}
+
+	observed, found, err := unstructured.NestedInt64(obj.Object, "status", "observedGeneration")
+	if err == nil && found && observed < obj.GetGeneration() {
+		return result
+	}
+
+	conditions, found, err := unstructured.NestedSlice(obj.Object, "status", "conditions")
+	if err != nil || !found {
+		return result
+	}
+
+	for _, c := range conditions {
+		cond, ok := c.(map[string]interface{})
+		if !ok {
+			continue
+		}
+		condType, _ := cond["type"].(string)
+		condStatus, _ := cond["status"].(string)
+		reason, _ := cond["reason"].(string)
+		message, _ := cond["message"].(string)
+
+		switch condType {
+		case "Ready":
+			switch condStatus {
+			case "True":
+				result.Status = models.DomainStatusReady
+			case "False":
+				result.Status = models.DomainStatusFailed
+				result.Message = message
+			default:
+				if result.Message == "" {
+					result.Message = message
+				}
+			}
+		case "CertificateProvisioned":
+			// Ohne Auto-TLS meldet Knative die Condition als erfüllt, obwohl kein Zertifikat existiert
+			result.CertificateReady = condStatus == "True" && reason != "TLSNotEnabled"
+			if condStatus != "True" && result.Message == "" {
+				result.Message = message
+			}
+		}
+	}
+	if result.Status == models.DomainStatusReady {
+		result.Message = ""
+	}
+
+	return result
+}
@@ -0,0 +1,100 @@
+package orchestrator
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"reflect"
+	"strconv"
+
+	"github.com/max-cloud/shared/pkg/models"
+
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+)
+
+// specHashAnnotation speichert den Hash der Spec, die max-cloud zuletzt ausgerollt hat.
+const specHashAnnotation = "max-cloud.dev/spec-hash"
+
+// specHash berechnet einen stabilen Hash über eine Spec. encoding/json sortiert Map-Keys,
+// daher ergibt dieselbe Spec immer denselben Hash.
+func specHash(spec interface{}) string {
+	data, err := json.Marshal(spec)
+	if err != nil {
+		return ""
+	}
+	sum := sha256.Sum256(data)
+	return hex.EncodeToString(sum[:])
+}
+
+// detectDrift vergleicht einen Knative Service im Cluster mit der Soll-Spec von svc.
+// Erkannt werden ein fehlender oder veränderter Spec-Hash sowie direkt im Cluster geänderte
+// Felder der Spec. Von Knative ergänzte Defaults gelten nicht als Drift.
+// Für eine andere Generation als svc.Generation wird nichts gemeldet.
+func (k *KnativeOrchestrator) detectDrift(live *unstructured.Unstructured, svc models.Service) string {
+	if live.GetAnnotations()[generationAnnotation] != strconv.FormatInt(svc.Generation, 10) {
+		return ""
+	}
+
+	desired := k.buildKnativeService(svc, live.GetNamespace())
+	if live.GetAnnotations()[specHashAnnotation] != desired.GetAnnotations()[specHashAnnotation] {
+		return "spec hash annotation does not match the desired spec"
+	}
+	if !containsSpec(normalizeJSON(live.Object["spec"]), normalizeJSON(desired.Object["spec"])) {
+		return "live spec was modified in the cluster"
+	}
+	return ""
+}
+
+// normalizeJSON bringt einen Wert in die Form, die encoding/json beim Dekodieren erzeugt
+// (float64, []interface{}, map[string]interface{}), damit Typen vergleichbar sind.
+func normalizeJSON(v interface{}) interface{} {
+	data, err := json.Marshal(v)
+	if err != nil {
+		return nil
+	}
+	var out interface{}
+	if err := json.Unmarshal(data, &out); err != nil {
+		return nil
+	}
+	return out
+}
+
+// containsSpec prüft, ob live alle Felder aus desired mit gleichem Wert enthält.
+// Zusätzliche Felder in live sind erlaubt, Listen müssen gleich lang sein.
+func containsSpec(live, desired interface{}) bool {
+	switch d := desired.(type) {
+	case nil:
+		return true
+	case map[string]interface{}:
+		l, ok := live.(map[string]interface{})
+		if !ok {
+			return false
+		}
+		for key, dv := range d {
+			lv, found := l[key]
+			if !found {
+				if dv == nil {
+					continue
+				}
+				return false
+			}
+			if !containsSpec(lv, dv) {
+				return false
+			}
+		}
+		return true
+	case []interface{}:
+		l, ok := live.([]interface{})
+		if !ok || len(l) != len(d) {
+			return false
+		}
+		for i := range d {
+			if !containsSpec(l[i], d[i]) {
+				return false
+			}
+		}
+		return true
+	default:
+		return reflect.DeepEqual(live, desired)
+	}
+}
@@ -0,0 +1,66 @@
+package orchestrator
+
+import (
+	"cmp"
+	"context"
+	"fmt"
+	"slices"
+	"time"
+
+	"github.com/max-cloud/shared/pkg/models"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// Events liest die Kubernetes-Events der aktuellen Pods eines Services (z.B. Image-Pull-Fehler,
+// OOM-Kills, fehlgeschlagene Probes). Kubernetes bewahrt Events nur begrenzt auf.
+func (k *KnativeOrchestrator) Events(ctx context.Context, svc models.Service) ([]models.ServiceEvent, error) {
+	ns := k.namespaceForService(svc)
+	pods, err := k.clientset.CoreV1().Pods(ns).List(ctx, metav1.ListOptions{
+		LabelSelector: fmt.Sprintf("serving.knative.dev/service=%s", svc.Name),
+	})
+	if err != nil {
+		return nil, fmt.Errorf("listing pods: %w", err)
+	}
+	if len(pods.Items) == 0 {
+		return nil, nil
+	}
+
+	podNames := make(map[string]bool, len(pods.Items))
+	for _, pod := range pods.Items {
+		podNames[pod.Name] = true
+	}
+
+	list, err := k.clientset.CoreV1().Events(ns).List(ctx, metav1.ListOptions{
+		FieldSelector: "involvedObject.kind=Pod",
+	})
+	if err != nil {
+		return nil, fmt.Errorf("listing events: %w", err)
+	}
+
+	var events []models.ServiceEvent
+	for _, ev := range list.Items {
+		if ev.InvolvedObject.Kind != "Pod" || !podNames[ev.InvolvedObject.Name] {
+			continue
+		}
+		events = append(events, models.ServiceEvent{
+			ServiceID: svc.ID,
+			OrgID:     svc.OrgID,
+			Type:      models.ServiceEventKubernetes,
+			Reason:    ev.Reason,
+			Message:   fmt.Sprintf("pod %s: %s", ev.InvolvedObject.Name, ev.Message),
+			CreatedAt: eventTime(ev),
+		})
+	}
+	slices.SortFunc(events, func(a, b models.ServiceEvent) int {
+		return b.CreatedAt.Compare(a.CreatedAt)
+	})
+	return events, nil
+}
+
+// eventTime gibt den Zeitpunkt des letzten Auftretens eines Events zurück.
+// Je nach Quelle setzt Kubernetes nur einen der Zeitstempel.
+func eventTime(ev corev1.Event) time.Time {
+	return cmp.Or(ev.LastTimestamp.Time, ev.EventTime.Time, ev.CreationTimestamp.Time)
+}
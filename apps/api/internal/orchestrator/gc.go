@@ -0,0 +1,46 @@
+package orchestrator
+
+import (
+	"context"
+	"fmt"
+
+	k8serrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// ListManaged listet alle Knative Services mit dem managed-by-Label über alle Namespaces.
+func (k *KnativeOrchestrator) ListManaged(ctx context.Context) ([]ManagedResource, error) {
+	list, err := k.client.Resource(knativeServiceGVR).Namespace(metav1.NamespaceAll).List(ctx, metav1.ListOptions{
+		LabelSelector: managedByLabel + "=max-cloud",
+	})
+	if err != nil {
+		return nil, fmt.Errorf("listing knative services: %w", err)
+	}
+
+	resources := make([]ManagedResource, 0, len(list.Items))
+	for _, item := range list.Items {
+		resources = append(resources, ManagedResource{
+			Name:      item.GetName(),
+			Namespace: item.GetNamespace(),
+			ServiceID: item.GetLabels()[serviceIDLabel],
+		})
+	}
+	return resources, nil
+}
+
+// RemoveManaged löscht einen Knative Service und das Secret seiner SecretEnv-Werte.
+func (k *KnativeOrchestrator) RemoveManaged(ctx context.Context, res ManagedResource) error {
+	err := k.client.Resource(knativeServiceGVR).Namespace(res.Namespace).Delete(ctx, res.Name, metav1.DeleteOptions{})
+	if err != nil && !k8serrors.IsNotFound(err) {
+		return fmt.Errorf("deleting knative service: %w", err)
+	}
+
+	secret := res.Name + secretNameSuffix
+	err = k.clientset.CoreV1().Secrets(res.Namespace).Delete(ctx, secret, metav1.DeleteOptions{})
+	if err != nil && !k8serrors.IsNotFound(err) {
+		return fmt.Errorf("deleting secret %s: %w", secret, err)
+	}
+
+	k.logger.Info("knative: managed service removed", "name", res.Name, "namespace", res.Namespace)
+	return nil
+}
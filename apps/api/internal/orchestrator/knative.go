@@ -1,10 +1,15 @@
 package orchestrator
 
 import (
+	"cmp"
 	"context"
 	"fmt"
 	"io"
 	"log/slog"
+	"maps"
+	"slices"
+	"strconv"
+	"time"
 
 	"github.com/max-cloud/shared/pkg/models"
 
@@ -13,6 +18,7 @@ import (
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
 	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/types"
 	"k8s.io/client-go/dynamic"
 	"k8s.io/client-go/kubernetes"
 	"k8s.io/client-go/tools/clientcmd"
@@ -24,6 +30,22 @@ var knativeServiceGVR = schema.GroupVersionResource{
 	Resource: "services",
 }
 
+// generationAnnotation speichert die Service-Generation aus dem Store am Knative Service.
+const generationAnnotation = "max-cloud.dev/generation"
+
+// serviceIDLabel verknüpft einen Knative Service mit dem Service im Store.
+const serviceIDLabel = "max-cloud.dev/service-id"
+
+// managedByLabel kennzeichnet alle von max-cloud angelegten Ressourcen.
+const managedByLabel = "app.kubernetes.io/managed-by"
+
+// restartedAtAnnotation am Pod-Template eines Revision-Deployments erzwingt ein Rolling Update.
+const restartedAtAnnotation = "max-cloud.dev/restarted-at"
+
+// secretNameSuffix wird an den Service-Namen angehängt, um das Kubernetes-Secret
+// mit den Werten seiner SecretEnv-Variablen zu benennen.
+const secretNameSuffix = "-env"
+
 // OrgNamespacePrefix is the prefix for organization namespaces.
 const OrgNamespacePrefix = "mc-org-"
 
@@ -35,10 +57,11 @@ type KnativeOrchestrator struct {
 	logger            *slog.Logger
 	registryURL       string
 	registryJWTSecret string
+	pullTokenExpiry   time.Duration
 }
 
 // NewKnative erstellt einen KnativeOrchestrator mit kubeconfig.
-func NewKnative(logger *slog.Logger, kubeconfigPath string, defaultNamespace string, registryURL string, registryJWTSecret string) (*KnativeOrchestrator, error) {
+func NewKnative(logger *slog.Logger, kubeconfigPath string, defaultNamespace string, registryURL string, registryJWTSecret string, pullTokenExpiry time.Duration) (*KnativeOrchestrator, error) {
 	config, err := clientcmd.BuildConfigFromFlags("", kubeconfigPath)
 	if err != nil {
 		return nil, fmt.Errorf("building kubeconfig: %w", err)
@@ -61,6 +84,7 @@ func NewKnative(logger *slog.Logger, kubeconfigPath string, defaultNamespace str
 		logger:            logger,
 		registryURL:       registryURL,
 		registryJWTSecret: registryJWTSecret,
+		pullTokenExpiry:   cmp.Or(pullTokenExpiry, DefaultPullTokenExpiry),
 	}, nil
 }
 
@@ -73,13 +97,19 @@ func newKnativeFromClient(logger *slog.Logger, client dynamic.Interface, cs kube
 		logger:            logger,
 		registryURL:       registryURL,
 		registryJWTSecret: registryJWTSecret,
+		pullTokenExpiry:   DefaultPullTokenExpiry,
 	}
 }
 
 // namespaceForService returns the namespace for a service, using orgID if available.
 func (k *KnativeOrchestrator) namespaceForService(svc models.Service) string {
-	if svc.OrgID != "" {
-		return OrgNamespacePrefix + svc.OrgID
+	return k.namespaceForOrg(svc.OrgID)
+}
+
+// namespaceForOrg returns the namespace of an organization or the default namespace.
+func (k *KnativeOrchestrator) namespaceForOrg(orgID string) string {
+	if orgID != "" {
+		return OrgNamespacePrefix + orgID
 	}
 	return k.defaultNS
 }
@@ -89,7 +119,8 @@ func NamespaceFromOrgID(orgID string) string {
 	return OrgNamespacePrefix + orgID
 }
 
-// CreateNamespace erstellt einen Kubernetes Namespace für eine Organisation.
+// CreateNamespace erstellt den Kubernetes Namespace einer Organisation samt Pull-Secret
+// für die eigene Registry (idempotent). Ein bald ablaufendes Pull-Token wird erneuert.
 func (k *KnativeOrchestrator) CreateNamespace(ctx context.Context, orgID string) error {
 	nsName := NamespaceFromOrgID(orgID)
 
@@ -97,21 +128,38 @@ func (k *KnativeOrchestrator) CreateNamespace(ctx context.Context, orgID string)
 		ObjectMeta: metav1.ObjectMeta{
 			Name: nsName,
 			Labels: map[string]string{
-				"app.kubernetes.io/managed-by": "max-cloud",
-				"max-cloud.dev/org-id":         orgID,
+				managedByLabel:         "max-cloud",
+				"max-cloud.dev/org-id": orgID,
 			},
 		},
 	}, metav1.CreateOptions{})
 
 	if err != nil {
-		if k8serrors.IsAlreadyExists(err) {
-			k.logger.Info("namespace already exists", "namespace", nsName)
+		if !k8serrors.IsAlreadyExists(err) {
+			return fmt.Errorf("creating namespace %s: %w", nsName, err)
+		}
+		k.logger.Debug("namespace already exists", "namespace", nsName)
+	} else {
+		k.logger.Info("namespace created", "namespace", nsName, "org_id", orgID)
+	}
+
+	return k.ensurePullSecret(ctx, orgID)
+}
+
+// DeleteNamespace löscht den Namespace einer Organisation. Kubernetes entfernt dabei
+// alle darin enthaltenen Services, Secrets und Domain-Mappings.
+func (k *KnativeOrchestrator) DeleteNamespace(ctx context.Context, orgID string) error {
+	nsName := NamespaceFromOrgID(orgID)
+
+	err := k.clientset.CoreV1().Namespaces().Delete(ctx, nsName, metav1.DeleteOptions{})
+	if err != nil {
+		if k8serrors.IsNotFound(err) {
 			return nil
 		}
-		return fmt.Errorf("creating namespace %s: %w", nsName, err)
+		return fmt.Errorf("deleting namespace %s: %w", nsName, err)
 	}
 
-	k.logger.Info("namespace created", "namespace", nsName, "org_id", orgID)
+	k.logger.Info("namespace deleted", "namespace", nsName, "org_id", orgID)
 	return nil
 }
 
@@ -169,7 +217,7 @@ func (k *KnativeOrchestrator) Deploy(ctx context.Context, svc models.Service) (*
 		k.logger.Info("knative: service created", "name", svc.Name, "namespace", ns)
 	}
 
-	return &DeployResult{Status: models.ServiceStatusPending}, nil
+	return &DeployResult{Status: models.ServiceStatusPending, Generation: svc.Generation}, nil
 }
 
 func (k *KnativeOrchestrator) Remove(ctx context.Context, svc models.Service) error {
@@ -177,11 +225,74 @@ func (k *KnativeOrchestrator) Remove(ctx context.Context, svc models.Service) er
 	err := k.client.Resource(knativeServiceGVR).Namespace(ns).Delete(ctx, svc.Name, metav1.DeleteOptions{})
 	if err != nil {
 		if k8serrors.IsNotFound(err) {
-			return nil
+			return k.ApplySecrets(ctx, svc, nil)
 		}
 		return fmt.Errorf("deleting knative service: %w", err)
 	}
 	k.logger.Info("knative: service removed", "name", svc.Name, "namespace", ns)
+	return k.ApplySecrets(ctx, svc, nil)
+}
+
+// ApplySecrets schreibt die Secret-Werte eines Services in ein eigenes Kubernetes-Secret.
+// Jeder Service erhält nur die Secrets, die er referenziert. Ohne Werte wird das Secret gelöscht.
+func (k *KnativeOrchestrator) ApplySecrets(ctx context.Context, svc models.Service, values map[string]string) error {
+	ns := k.namespaceForService(svc)
+	name := svc.Name + secretNameSuffix
+	secrets := k.clientset.CoreV1().Secrets(ns)
+
+	if len(values) == 0 {
+		err := secrets.Delete(ctx, name, metav1.DeleteOptions{})
+		if err != nil && !k8serrors.IsNotFound(err) {
+			return fmt.Errorf("deleting secret %s: %w", name, err)
+		}
+		return nil
+	}
+
+	data := make(map[string][]byte, len(values))
+	for key, value := range values {
+		data[key] = []byte(value)
+	}
+	secret := &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      name,
+			Namespace: ns,
+			Labels: map[string]string{
+				managedByLabel: "max-cloud",
+			},
+		},
+		Type: corev1.SecretTypeOpaque,
+		Data: data,
+	}
+
+	_, err := secrets.Create(ctx, secret, metav1.CreateOptions{})
+	if k8serrors.IsAlreadyExists(err) {
+		_, err = secrets.Update(ctx, secret, metav1.UpdateOptions{})
+	}
+	if err != nil {
+		return fmt.Errorf("applying secret %s: %w", name, err)
+	}
+	k.logger.Info("knative: secrets applied", "name", name, "namespace", ns, "keys", len(values))
+	return nil
+}
+
+// RestartRevisions setzt eine Annotation am Pod-Template der Deployments, die Knative für die
+// Revisionen anlegt. Kubernetes ersetzt die Pods dann schrittweise, der Traffic bleibt unverändert.
+func (k *KnativeOrchestrator) RestartRevisions(ctx context.Context, svc models.Service, revisions []string) error {
+	ns := k.namespaceForService(svc)
+	patch := fmt.Sprintf(`{"spec":{"template":{"metadata":{"annotations":{%q:%q}}}}}`,
+		restartedAtAnnotation, time.Now().UTC().Format(time.RFC3339))
+
+	for _, rev := range revisions {
+		name := rev + "-deployment"
+		_, err := k.clientset.AppsV1().Deployments(ns).Patch(ctx, name, types.StrategicMergePatchType, []byte(patch), metav1.PatchOptions{})
+		if err != nil {
+			if k8serrors.IsNotFound(err) {
+				continue
+			}
+			return fmt.Errorf("restarting revision %s: %w", rev, err)
+		}
+		k.logger.Info("knative: revision restarted", "revision", rev, "namespace", ns)
+	}
 	return nil
 }
 
@@ -195,7 +306,9 @@ func (k *KnativeOrchestrator) Status(ctx context.Context, svc models.Service) (*
 		return nil, fmt.Errorf("getting knative service: %w", err)
 	}
 
-	return k.parseStatus(obj), nil
+	result := k.parseStatus(obj)
+	result.Drift = k.detectDrift(obj, svc)
+	return result, nil
 }
 
 func (k *KnativeOrchestrator) Logs(ctx context.Context, svc models.Service, opts LogsOptions) (io.ReadCloser, error) {
@@ -236,7 +349,7 @@ func (k *KnativeOrchestrator) Logs(ctx context.Context, svc models.Service, opts
 func (k *KnativeOrchestrator) buildKnativeService(svc models.Service, ns string) *unstructured.Unstructured {
 	container := map[string]interface{}{
 		"image": svc.Image,
-		"env":   buildEnvVars(svc.EnvVars),
+		"env":   buildEnvVars(svc.EnvVars, svc.SecretEnv, svc.Name+secretNameSuffix),
 	}
 
 	if svc.Port > 0 {
@@ -255,12 +368,33 @@ func (k *KnativeOrchestrator) buildKnativeService(svc models.Service, ns string)
 		container["args"] = svc.Args
 	}
 
+	if resources := buildResources(svc.CPU, svc.Memory); resources != nil {
+		container["resources"] = resources
+	}
+
+	if svc.Probes != nil {
+		if probe := buildProbe(svc.Probes.Liveness); probe != nil {
+			container["livenessProbe"] = probe
+		}
+		if probe := buildProbe(svc.Probes.Readiness); probe != nil {
+			container["readinessProbe"] = probe
+		}
+		if probe := buildProbe(svc.Probes.Startup); probe != nil {
+			container["startupProbe"] = probe
+		}
+	}
+
 	containers := []interface{}{container}
 
 	minScale := svc.MinScale
 	maxScale := svc.MaxScale
 	if maxScale == 0 {
-		maxScale = 10
+		maxScale = models.DefaultMaxScale
+	}
+
+	revisionName := svc.LatestRevision
+	if revisionName == "" {
+		revisionName = models.RevisionName(svc.Name, svc.Generation)
 	}
 
 	obj := &unstructured.Unstructured{
@@ -271,35 +405,117 @@ func (k *KnativeOrchestrator) buildKnativeService(svc models.Service, ns string)
 				"name":      svc.Name,
 				"namespace": ns,
 				"labels": map[string]interface{}{
-					"app.kubernetes.io/managed-by": "max-cloud",
+					managedByLabel: "max-cloud",
+					serviceIDLabel: svc.ID,
+				},
+				"annotations": map[string]interface{}{
+					generationAnnotation: strconv.FormatInt(svc.Generation, 10),
 				},
 			},
 			"spec": map[string]interface{}{
 				"template": map[string]interface{}{
 					"metadata": map[string]interface{}{
-						"annotations": map[string]interface{}{
-							"autoscaling.knative.dev/minScale": fmt.Sprintf("%d", minScale),
-							"autoscaling.knative.dev/maxScale": fmt.Sprintf("%d", maxScale),
-						},
+						"name":        revisionName,
+						"annotations": buildAutoscalingAnnotations(svc, minScale, maxScale),
 					},
-					"spec": k.buildPodSpec(containers, svc.Image),
+					"spec": k.buildPodSpec(containers, svc),
 				},
 			},
 		},
 	}
 
+	if traffic := buildTraffic(svc.Traffic); traffic != nil {
+		_ = unstructured.SetNestedSlice(obj.Object, traffic, "spec", "traffic")
+	}
+
+	_ = unstructured.SetNestedField(obj.Object, specHash(obj.Object["spec"]), "metadata", "annotations", specHashAnnotation)
+
 	return obj
 }
 
-func (k *KnativeOrchestrator) buildPodSpec(containers []interface{}, image string) map[string]interface{} {
+// buildTraffic übersetzt die Traffic-Aufteilung in Knative-Traffic-Targets.
+// Ohne Aufteilung gibt es nil zurück, Knative leitet dann alles auf die neueste Revision.
+func buildTraffic(targets []models.TrafficTarget) []interface{} {
+	if len(targets) == 0 {
+		return nil
+	}
+	result := make([]interface{}, 0, len(targets))
+	for _, t := range targets {
+		target := map[string]interface{}{
+			"revisionName":   t.RevisionName,
+			"percent":        int64(t.Percent),
+			"latestRevision": false,
+		}
+		if t.Tag != "" {
+			target["tag"] = t.Tag
+		}
+		result = append(result, target)
+	}
+	return result
+}
+
+// buildProbe rendert eine Probe als Kubernetes-Probe. Ohne Port prüft Knative den Container-Port.
+func buildProbe(p *models.Probe) map[string]interface{} {
+	if p == nil {
+		return nil
+	}
+
+	handler := map[string]interface{}{}
+	if p.Port > 0 {
+		handler["port"] = int64(p.Port)
+	}
+
+	probe := map[string]interface{}{}
+	switch p.Type {
+	case models.ProbeTypeTCP:
+		probe["tcpSocket"] = handler
+	case models.ProbeTypeGRPC:
+		probe["grpc"] = handler
+	default:
+		handler["path"] = p.Path
+		probe["httpGet"] = handler
+	}
+
+	if p.InitialDelaySeconds > 0 {
+		probe["initialDelaySeconds"] = int64(p.InitialDelaySeconds)
+	}
+	if p.PeriodSeconds > 0 {
+		probe["periodSeconds"] = int64(p.PeriodSeconds)
+	}
+	if p.TimeoutSeconds > 0 {
+		probe["timeoutSeconds"] = int64(p.TimeoutSeconds)
+	}
+	if p.FailureThreshold > 0 {
+		probe["failureThreshold"] = int64(p.FailureThreshold)
+	}
+	if p.SuccessThreshold > 0 {
+		probe["successThreshold"] = int64(p.SuccessThreshold)
+	}
+	return probe
+}
+
+func (k *KnativeOrchestrator) buildPodSpec(containers []interface{}, svc models.Service) map[string]interface{} {
 	podSpec := map[string]interface{}{
 		"containers": containers,
 	}
 
-	if k.usesPrivateRegistry(image) {
+	if svc.ContainerConcurrency > 0 {
+		podSpec["containerConcurrency"] = int64(svc.ContainerConcurrency)
+	}
+	if svc.TimeoutSeconds > 0 {
+		podSpec["timeoutSeconds"] = int64(svc.TimeoutSeconds)
+	}
+	if svc.ResponseStartTimeoutSeconds > 0 {
+		podSpec["responseStartTimeoutSeconds"] = int64(svc.ResponseStartTimeoutSeconds)
+	}
+	if svc.IdleTimeoutSeconds > 0 {
+		podSpec["idleTimeoutSeconds"] = int64(svc.IdleTimeoutSeconds)
+	}
+
+	if k.usesPrivateRegistry(svc.Image) {
 		podSpec["imagePullSecrets"] = []interface{}{
 			map[string]interface{}{
-				"name": "registry-pull-secret",
+				"name": pullSecretName,
 			},
 		}
 	}
@@ -314,29 +530,125 @@ func (k *KnativeOrchestrator) usesPrivateRegistry(image string) bool {
 	return len(image) > len(k.registryURL) && image[:len(k.registryURL)] == k.registryURL
 }
 
-func buildEnvVars(envVars map[string]string) []interface{} {
-	if len(envVars) == 0 {
+// buildAutoscalingAnnotations übersetzt die Autoscaling-Einstellungen in Knative-Annotationen.
+// Nicht gesetzte Werte werden weggelassen, dann gelten die Cluster-Defaults.
+func buildAutoscalingAnnotations(svc models.Service, minScale, maxScale int) map[string]interface{} {
+	annotations := map[string]interface{}{
+		"autoscaling.knative.dev/minScale": fmt.Sprintf("%d", minScale),
+		"autoscaling.knative.dev/maxScale": fmt.Sprintf("%d", maxScale),
+	}
+	if svc.AutoscalingTarget > 0 {
+		annotations["autoscaling.knative.dev/target"] = fmt.Sprintf("%d", svc.AutoscalingTarget)
+	}
+	if svc.ScaleDownDelay > 0 {
+		annotations["autoscaling.knative.dev/scale-down-delay"] = fmt.Sprintf("%ds", svc.ScaleDownDelay)
+	}
+	if svc.ScaleToZeroRetention > 0 {
+		annotations["autoscaling.knative.dev/scale-to-zero-pod-retention-period"] = fmt.Sprintf("%ds", svc.ScaleToZeroRetention)
+	}
+	return annotations
+}
+
+// buildResources setzt Requests und Limits gleich, damit jede Instanz genau ihren Tier erhält.
+func buildResources(cpu, memory string) map[string]interface{} {
+	values := map[string]interface{}{}
+	if cpu != "" {
+		values["cpu"] = cpu
+	}
+	if memory != "" {
+		values["memory"] = memory
+	}
+	if len(values) == 0 {
+		return nil
+	}
+	limits := make(map[string]interface{}, len(values))
+	for k, v := range values {
+		limits[k] = v
+	}
+	return map[string]interface{}{
+		"requests": values,
+		"limits":   limits,
+	}
+}
+
+// buildEnvVars rendert die Env-Variablen des Containers. SecretEnv-Variablen werden per
+// secretKeyRef aus dem Kubernetes-Secret secretName gelesen, nie als Klartext.
+func buildEnvVars(envVars, secretEnv map[string]string, secretName string) []interface{} {
+	if len(envVars) == 0 && len(secretEnv) == 0 {
 		return nil
 	}
-	result := make([]interface{}, 0, len(envVars))
-	for k, v := range envVars {
+	// Sortiert, damit dieselbe Spec immer dasselbe Objekt (und denselben Spec-Hash) ergibt
+	result := make([]interface{}, 0, len(envVars)+len(secretEnv))
+	for _, k := range slices.Sorted(maps.Keys(envVars)) {
 		result = append(result, map[string]interface{}{
 			"name":  k,
-			"value": v,
+			"value": envVars[k],
+		})
+	}
+	for _, k := range slices.Sorted(maps.Keys(secretEnv)) {
+		result = append(result, map[string]interface{}{
+			"name": k,
+			"valueFrom": map[string]interface{}{
+				"secretKeyRef": map[string]interface{}{
+					"name": secretName,
+					"key":  secretEnv[k],
+				},
+			},
 		})
 	}
 	return result
 }
 
+// parseTagURLs liest die URLs getaggter Traffic-Targets aus status.traffic.
+func parseTagURLs(obj *unstructured.Unstructured) map[string]string {
+	traffic, found, err := unstructured.NestedSlice(obj.Object, "status", "traffic")
+	if err != nil || !found {
+		return nil
+	}
+
+	var urls map[string]string
+	for _, t := range traffic {
+		target, ok := t.(map[string]interface{})
+		if !ok {
+			continue
+		}
+		tag, _ := target["tag"].(string)
+		url, _ := target["url"].(string)
+		if tag == "" || url == "" {
+			continue
+		}
+		if urls == nil {
+			urls = make(map[string]string)
+		}
+		urls[tag] = url
+	}
+	return urls
+}
+
 func (k *KnativeOrchestrator) parseStatus(obj *unstructured.Unstructured) *DeployResult {
 	result := &DeployResult{Status: models.ServiceStatusPending}
 
+	if v, ok := obj.GetAnnotations()[generationAnnotation]; ok {
+		if gen, err := strconv.ParseInt(v, 10, 64); err == nil {
+			result.Generation = gen
+		}
+	}
+
 	// URL aus status.url lesen
 	url, found, err := unstructured.NestedString(obj.Object, "status", "url")
 	if err == nil && found {
 		result.URL = url
 	}
 
+	result.TagURLs = parseTagURLs(obj)
+
+	// Solange Knative die aktuelle Spec noch nicht verarbeitet hat, beschreibt der
+	// Ready-Status die vorherige Revision.
+	observed, found, err := unstructured.NestedInt64(obj.Object, "status", "observedGeneration")
+	if err == nil && found && observed < obj.GetGeneration() {
+		return result
+	}
+
 	// status.conditions nach type=Ready suchen
 	conditions, found, err := unstructured.NestedSlice(obj.Object, "status", "conditions")
 	if err != nil || !found {
@@ -361,6 +673,10 @@ func (k *KnativeOrchestrator) parseStatus(obj *unstructured.Unstructured) *Deplo
 		default:
 			result.Status = models.ServiceStatusPending
 		}
+		if result.Status != models.ServiceStatusReady {
+			result.Reason, _ = cond["reason"].(string)
+			result.Message, _ = cond["message"].(string)
+		}
 		break
 	}
 
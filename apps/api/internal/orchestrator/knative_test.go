@@ -2,12 +2,17 @@ package orchestrator
 
 import (
 	"context"
+	"encoding/json"
 	"errors"
 	"log/slog"
+	"strings"
 	"testing"
+	"time"
 
 	"github.com/max-cloud/shared/pkg/models"
 
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
 	"k8s.io/apimachinery/pkg/runtime"
@@ -21,6 +26,7 @@ func newTestKnative() (*KnativeOrchestrator, *dynamicfake.FakeDynamicClient, *ku
 	client := dynamicfake.NewSimpleDynamicClientWithCustomListKinds(scheme,
 		map[schema.GroupVersionResource]string{
 			knativeServiceGVR: "ServiceList",
+			domainMappingGVR:  "DomainMappingList",
 		},
 	)
 	cs := kubefake.NewSimpleClientset()
@@ -73,6 +79,443 @@ func TestKnativeDeployIdempotent(t *testing.T) {
 	}
 }
 
+func TestKnativeDeployRecordsGeneration(t *testing.T) {
+	orch, _, _ := newTestKnative()
+	ctx := context.Background()
+
+	svc := models.Service{Name: "myapp", Image: "nginx:latest", MaxScale: 10, Generation: 3}
+	if _, err := orch.Deploy(ctx, svc); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	result, err := orch.Status(ctx, svc)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if result.Generation != 3 {
+		t.Fatalf("expected generation 3, got %d", result.Generation)
+	}
+}
+
+func TestKnativeDeployResources(t *testing.T) {
+	orch, client, _ := newTestKnative()
+	ctx := context.Background()
+
+	svc := models.Service{Name: "myapp", Image: "nginx:latest", MaxScale: 10, CPU: "500m", Memory: "1Gi"}
+	if _, err := orch.Deploy(ctx, svc); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	obj, err := client.Resource(knativeServiceGVR).Namespace("default").Get(ctx, "myapp", metav1.GetOptions{})
+	if err != nil {
+		t.Fatalf("expected knative service to exist: %v", err)
+	}
+	containers, _, _ := unstructured.NestedSlice(obj.Object, "spec", "template", "spec", "containers")
+	if len(containers) != 1 {
+		t.Fatalf("expected 1 container, got %d", len(containers))
+	}
+	container := containers[0].(map[string]interface{})
+	for _, kind := range []string{"requests", "limits"} {
+		cpu, _, _ := unstructured.NestedString(container, "resources", kind, "cpu")
+		memory, _, _ := unstructured.NestedString(container, "resources", kind, "memory")
+		if cpu != "500m" || memory != "1Gi" {
+			t.Fatalf("expected %s cpu=500m memory=1Gi, got cpu=%q memory=%q", kind, cpu, memory)
+		}
+	}
+}
+
+func TestKnativeDeployAutoscaling(t *testing.T) {
+	orch, client, _ := newTestKnative()
+	ctx := context.Background()
+
+	svc := models.Service{
+		Name:                 "myapp",
+		Image:                "nginx:latest",
+		MinScale:             1,
+		MaxScale:             8,
+		ContainerConcurrency: 50,
+		AutoscalingTarget:    40,
+		ScaleDownDelay:       120,
+		ScaleToZeroRetention: 300,
+	}
+	if _, err := orch.Deploy(ctx, svc); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	obj, err := client.Resource(knativeServiceGVR).Namespace("default").Get(ctx, "myapp", metav1.GetOptions{})
+	if err != nil {
+		t.Fatalf("expected knative service to exist: %v", err)
+	}
+
+	annotations, _, _ := unstructured.NestedStringMap(obj.Object, "spec", "template", "metadata", "annotations")
+	want := map[string]string{
+		"autoscaling.knative.dev/minScale":                           "1",
+		"autoscaling.knative.dev/maxScale":                           "8",
+		"autoscaling.knative.dev/target":                             "40",
+		"autoscaling.knative.dev/scale-down-delay":                   "120s",
+		"autoscaling.knative.dev/scale-to-zero-pod-retention-period": "300s",
+	}
+	for k, v := range want {
+		if annotations[k] != v {
+			t.Fatalf("expected annotation %s=%s, got %q", k, v, annotations[k])
+		}
+	}
+
+	concurrency, _, _ := unstructured.NestedInt64(obj.Object, "spec", "template", "spec", "containerConcurrency")
+	if concurrency != 50 {
+		t.Fatalf("expected containerConcurrency 50, got %d", concurrency)
+	}
+}
+
+func TestKnativeDeployTimeouts(t *testing.T) {
+	orch, client, _ := newTestKnative()
+	ctx := context.Background()
+
+	svc := models.Service{
+		Name:                        "reports",
+		Image:                       "nginx:latest",
+		TimeoutSeconds:              900,
+		ResponseStartTimeoutSeconds: 600,
+		IdleTimeoutSeconds:          120,
+	}
+	if _, err := orch.Deploy(ctx, svc); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	obj, err := client.Resource(knativeServiceGVR).Namespace("default").Get(ctx, "reports", metav1.GetOptions{})
+	if err != nil {
+		t.Fatalf("expected knative service to exist: %v", err)
+	}
+
+	want := map[string]int64{
+		"timeoutSeconds":              900,
+		"responseStartTimeoutSeconds": 600,
+		"idleTimeoutSeconds":          120,
+	}
+	for field, v := range want {
+		got, _, _ := unstructured.NestedInt64(obj.Object, "spec", "template", "spec", field)
+		if got != v {
+			t.Fatalf("expected %s=%d, got %d", field, v, got)
+		}
+	}
+
+	// Ohne Timeouts bleiben die Knative-Defaults aktiv
+	svc = models.Service{Name: "plain", Image: "nginx:latest"}
+	if _, err := orch.Deploy(ctx, svc); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	obj, _ = client.Resource(knativeServiceGVR).Namespace("default").Get(ctx, "plain", metav1.GetOptions{})
+	if _, found, _ := unstructured.NestedFieldNoCopy(obj.Object, "spec", "template", "spec", "timeoutSeconds"); found {
+		t.Fatal("expected no timeoutSeconds without explicit timeout")
+	}
+}
+
+func TestKnativeDeployProbes(t *testing.T) {
+	orch, client, _ := newTestKnative()
+	ctx := context.Background()
+
+	svc := models.Service{
+		Name:  "slowboot",
+		Image: "nginx:latest",
+		Probes: &models.Probes{
+			Readiness: &models.Probe{Path: "/healthz", PeriodSeconds: 5},
+			Startup:   &models.Probe{Path: "/healthz", PeriodSeconds: 5, FailureThreshold: 24},
+			Liveness:  &models.Probe{Type: models.ProbeTypeTCP, Port: 8081},
+		},
+	}
+	if _, err := orch.Deploy(ctx, svc); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	obj, err := client.Resource(knativeServiceGVR).Namespace("default").Get(ctx, "slowboot", metav1.GetOptions{})
+	if err != nil {
+		t.Fatalf("expected knative service to exist: %v", err)
+	}
+
+	containers, _, _ := unstructured.NestedSlice(obj.Object, "spec", "template", "spec", "containers")
+	container := containers[0].(map[string]interface{})
+
+	path, _, _ := unstructured.NestedString(container, "readinessProbe", "httpGet", "path")
+	if path != "/healthz" {
+		t.Fatalf("expected readiness path /healthz, got %q", path)
+	}
+	threshold, _, _ := unstructured.NestedInt64(container, "startupProbe", "failureThreshold")
+	if threshold != 24 {
+		t.Fatalf("expected startup failureThreshold 24, got %d", threshold)
+	}
+	port, _, _ := unstructured.NestedInt64(container, "livenessProbe", "tcpSocket", "port")
+	if port != 8081 {
+		t.Fatalf("expected liveness tcp port 8081, got %d", port)
+	}
+	if _, found, _ := unstructured.NestedFieldNoCopy(container, "readinessProbe", "httpGet", "port"); found {
+		t.Fatal("expected no readiness port when probing the container port")
+	}
+}
+
+func TestKnativeApplySecrets(t *testing.T) {
+	orch, client, cs := newTestKnative()
+	ctx := context.Background()
+
+	svc := models.Service{
+		Name:      "myapp",
+		Image:     "nginx:latest",
+		EnvVars:   map[string]string{"LOG_LEVEL": "debug"},
+		SecretEnv: map[string]string{"DB_PASSWORD": "db-password"},
+	}
+	if err := orch.ApplySecrets(ctx, svc, map[string]string{"db-password": "hunter2"}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if _, err := orch.Deploy(ctx, svc); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	secret, err := cs.CoreV1().Secrets("default").Get(ctx, "myapp-env", metav1.GetOptions{})
+	if err != nil {
+		t.Fatalf("expected kubernetes secret to exist: %v", err)
+	}
+	if string(secret.Data["db-password"]) != "hunter2" {
+		t.Fatalf("expected secret data, got %v", secret.Data)
+	}
+
+	obj, _ := client.Resource(knativeServiceGVR).Namespace("default").Get(ctx, "myapp", metav1.GetOptions{})
+	containers, _, _ := unstructured.NestedSlice(obj.Object, "spec", "template", "spec", "containers")
+	env := containers[0].(map[string]interface{})["env"].([]interface{})
+	for _, e := range env {
+		entry := e.(map[string]interface{})
+		if entry["name"] != "DB_PASSWORD" {
+			continue
+		}
+		if _, ok := entry["value"]; ok {
+			t.Fatal("expected secret env var without literal value")
+		}
+		key, _, _ := unstructured.NestedString(entry, "valueFrom", "secretKeyRef", "key")
+		name, _, _ := unstructured.NestedString(entry, "valueFrom", "secretKeyRef", "name")
+		if key != "db-password" || name != "myapp-env" {
+			t.Fatalf("expected secretKeyRef myapp-env/db-password, got %s/%s", name, key)
+		}
+	}
+
+	// Aktualisieren überschreibt die Werte
+	if err := orch.ApplySecrets(ctx, svc, map[string]string{"db-password": "correct-horse"}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	secret, _ = cs.CoreV1().Secrets("default").Get(ctx, "myapp-env", metav1.GetOptions{})
+	if string(secret.Data["db-password"]) != "correct-horse" {
+		t.Fatalf("expected updated secret data, got %v", secret.Data)
+	}
+
+	// Remove räumt das Secret mit auf
+	if err := orch.Remove(ctx, svc); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if _, err := cs.CoreV1().Secrets("default").Get(ctx, "myapp-env", metav1.GetOptions{}); err == nil {
+		t.Fatal("expected kubernetes secret to be deleted")
+	}
+}
+
+func TestKnativeRestartRevisions(t *testing.T) {
+	orch, _, cs := newTestKnative()
+	ctx := context.Background()
+
+	deployment := &appsv1.Deployment{ObjectMeta: metav1.ObjectMeta{Name: "myapp-00001-deployment", Namespace: "default"}}
+	if _, err := cs.AppsV1().Deployments("default").Create(ctx, deployment, metav1.CreateOptions{}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	// Revisionen ohne Deployment werden übersprungen
+	svc := models.Service{Name: "myapp"}
+	if err := orch.RestartRevisions(ctx, svc, []string{"myapp-00001", "myapp-00002"}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	got, _ := cs.AppsV1().Deployments("default").Get(ctx, "myapp-00001-deployment", metav1.GetOptions{})
+	if got.Spec.Template.Annotations[restartedAtAnnotation] == "" {
+		t.Fatalf("expected restart annotation on pod template, got %v", got.Spec.Template.Annotations)
+	}
+}
+
+func TestKnativeDeployTrafficSplit(t *testing.T) {
+	orch, client, _ := newTestKnative()
+	ctx := context.Background()
+
+	svc := models.Service{
+		Name:           "myapp",
+		Image:          "nginx:latest",
+		MaxScale:       10,
+		Generation:     3,
+		LatestRevision: "myapp-00003",
+		Traffic: []models.TrafficTarget{
+			{RevisionName: "myapp-00002", Percent: 90},
+			{RevisionName: "myapp-00003", Percent: 10},
+		},
+	}
+	if _, err := orch.Deploy(ctx, svc); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	obj, err := client.Resource(knativeServiceGVR).Namespace("default").Get(ctx, "myapp", metav1.GetOptions{})
+	if err != nil {
+		t.Fatalf("expected knative service to exist: %v", err)
+	}
+
+	name, _, _ := unstructured.NestedString(obj.Object, "spec", "template", "metadata", "name")
+	if name != "myapp-00003" {
+		t.Fatalf("expected template name myapp-00003, got %q", name)
+	}
+
+	traffic, found, err := unstructured.NestedSlice(obj.Object, "spec", "traffic")
+	if err != nil || !found {
+		t.Fatalf("expected spec.traffic, found=%v err=%v", found, err)
+	}
+	if len(traffic) != 2 {
+		t.Fatalf("expected 2 traffic targets, got %d", len(traffic))
+	}
+	first := traffic[0].(map[string]interface{})
+	if first["revisionName"] != "myapp-00002" || first["percent"] != int64(90) {
+		t.Fatalf("unexpected first traffic target: %v", first)
+	}
+}
+
+func TestKnativeDeployTrafficTag(t *testing.T) {
+	orch, client, _ := newTestKnative()
+	ctx := context.Background()
+
+	svc := models.Service{
+		Name:           "myapp",
+		Image:          "nginx:latest",
+		MaxScale:       10,
+		Generation:     2,
+		LatestRevision: "myapp-00002",
+		Traffic: []models.TrafficTarget{
+			{RevisionName: "myapp-00001", Percent: 100},
+			{RevisionName: "myapp-00002", Percent: 0, Tag: "candidate"},
+		},
+	}
+	if _, err := orch.Deploy(ctx, svc); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	obj, err := client.Resource(knativeServiceGVR).Namespace("default").Get(ctx, "myapp", metav1.GetOptions{})
+	if err != nil {
+		t.Fatalf("expected knative service to exist: %v", err)
+	}
+	traffic, _, _ := unstructured.NestedSlice(obj.Object, "spec", "traffic")
+	if len(traffic) != 2 {
+		t.Fatalf("expected 2 traffic targets, got %d", len(traffic))
+	}
+	if _, ok := traffic[0].(map[string]interface{})["tag"]; ok {
+		t.Fatal("expected untagged first target")
+	}
+	if tag := traffic[1].(map[string]interface{})["tag"]; tag != "candidate" {
+		t.Fatalf("expected tag candidate, got %v", tag)
+	}
+}
+
+func TestKnativeStatusTagURLs(t *testing.T) {
+	orch, client, _ := newTestKnative()
+	ctx := context.Background()
+
+	obj := &unstructured.Unstructured{
+		Object: map[string]interface{}{
+			"apiVersion": "serving.knative.dev/v1",
+			"kind":       "Service",
+			"metadata": map[string]interface{}{
+				"name":      "myapp",
+				"namespace": "default",
+			},
+			"status": map[string]interface{}{
+				"url": "https://myapp.default.example.com",
+				"traffic": []interface{}{
+					map[string]interface{}{
+						"revisionName": "myapp-00001",
+						"percent":      int64(100),
+					},
+					map[string]interface{}{
+						"revisionName": "myapp-00002",
+						"percent":      int64(0),
+						"tag":          "candidate",
+						"url":          "https://candidate-myapp.default.example.com",
+					},
+				},
+			},
+		},
+	}
+	if _, err := client.Resource(knativeServiceGVR).Namespace("default").Create(ctx, obj, metav1.CreateOptions{}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	result, err := orch.Status(ctx, models.Service{Name: "myapp"})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(result.TagURLs) != 1 {
+		t.Fatalf("expected 1 tag URL, got %v", result.TagURLs)
+	}
+	if got := result.TagURLs["candidate"]; got != "https://candidate-myapp.default.example.com" {
+		t.Fatalf("unexpected candidate URL %q", got)
+	}
+}
+
+func TestKnativeDeployWithoutTrafficSplit(t *testing.T) {
+	orch, client, _ := newTestKnative()
+	ctx := context.Background()
+
+	svc := models.Service{Name: "myapp", Image: "nginx:latest", MaxScale: 10, Generation: 2}
+	if _, err := orch.Deploy(ctx, svc); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	obj, err := client.Resource(knativeServiceGVR).Namespace("default").Get(ctx, "myapp", metav1.GetOptions{})
+	if err != nil {
+		t.Fatalf("expected knative service to exist: %v", err)
+	}
+
+	name, _, _ := unstructured.NestedString(obj.Object, "spec", "template", "metadata", "name")
+	if name != "myapp-00002" {
+		t.Fatalf("expected template name myapp-00002, got %q", name)
+	}
+	if _, found, _ := unstructured.NestedSlice(obj.Object, "spec", "traffic"); found {
+		t.Fatal("expected no spec.traffic without a split")
+	}
+}
+
+func TestKnativeStatusStaleGeneration(t *testing.T) {
+	orch, client, _ := newTestKnative()
+	ctx := context.Background()
+
+	obj := &unstructured.Unstructured{
+		Object: map[string]interface{}{
+			"apiVersion": "serving.knative.dev/v1",
+			"kind":       "Service",
+			"metadata": map[string]interface{}{
+				"name":       "myapp",
+				"namespace":  "default",
+				"generation": int64(2),
+			},
+			"status": map[string]interface{}{
+				"observedGeneration": int64(1),
+				"conditions": []interface{}{
+					map[string]interface{}{
+						"type":   "Ready",
+						"status": "True",
+					},
+				},
+			},
+		},
+	}
+	if _, err := client.Resource(knativeServiceGVR).Namespace("default").Create(ctx, obj, metav1.CreateOptions{}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	result, err := orch.Status(ctx, models.Service{Name: "myapp"})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if result.Status != models.ServiceStatusPending {
+		t.Fatalf("expected pending while generation is not observed, got %s", result.Status)
+	}
+}
+
 func TestKnativeRemove(t *testing.T) {
 	orch, _, _ := newTestKnative()
 	ctx := context.Background()
@@ -162,8 +605,10 @@ func TestKnativeStatusFailed(t *testing.T) {
 			"status": map[string]interface{}{
 				"conditions": []interface{}{
 					map[string]interface{}{
-						"type":   "Ready",
-						"status": "False",
+						"type":    "Ready",
+						"status":  "False",
+						"reason":  "RevisionFailed",
+						"message": `Revision "failapp-00001" failed with message: Back-off pulling image "nginx:missing".`,
 					},
 				},
 			},
@@ -180,6 +625,12 @@ func TestKnativeStatusFailed(t *testing.T) {
 	if result.Status != models.ServiceStatusFailed {
 		t.Fatalf("expected failed, got %s", result.Status)
 	}
+	if result.Reason != "RevisionFailed" {
+		t.Fatalf("expected reason RevisionFailed, got %q", result.Reason)
+	}
+	if !strings.Contains(result.Message, "Back-off pulling image") {
+		t.Fatalf("expected condition message, got %q", result.Message)
+	}
 }
 
 func TestKnativeLogsNoPods(t *testing.T) {
@@ -324,3 +775,432 @@ func TestKnativeDeployToOrgNamespace(t *testing.T) {
 		t.Fatalf("expected knative service in org namespace %s: %v", expectedNS, err)
 	}
 }
+
+func TestKnativeApplyDomain(t *testing.T) {
+	orch, client, _ := newTestKnative()
+	ctx := context.Background()
+
+	domain := models.Domain{ID: "dom-1", OrgID: "org-1", Hostname: "www.example.com"}
+	svc := models.Service{Name: "myapp", OrgID: "org-1"}
+
+	if _, err := orch.DomainStatus(ctx, domain); !errors.Is(err, ErrNotFound) {
+		t.Fatalf("expected ErrNotFound before apply, got %v", err)
+	}
+
+	if err := orch.ApplyDomain(ctx, domain, svc); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	// Idempotent: erneutes Anwenden aktualisiert das bestehende Mapping
+	if err := orch.ApplyDomain(ctx, domain, svc); err != nil {
+		t.Fatalf("unexpected error on reapply: %v", err)
+	}
+
+	obj, err := client.Resource(domainMappingGVR).Namespace("mc-org-org-1").Get(ctx, "www.example.com", metav1.GetOptions{})
+	if err != nil {
+		t.Fatalf("expected domain mapping to exist: %v", err)
+	}
+	ref, _, _ := unstructured.NestedString(obj.Object, "spec", "ref", "name")
+	if ref != "myapp" {
+		t.Fatalf("expected ref to myapp, got %q", ref)
+	}
+	if obj.GetLabels()[domainIDLabel] != "dom-1" {
+		t.Fatalf("expected domain id label, got %v", obj.GetLabels())
+	}
+
+	result, err := orch.DomainStatus(ctx, domain)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if result.Status != models.DomainStatusProvisioning || result.CertificateReady {
+		t.Fatalf("expected provisioning without certificate, got %+v", result)
+	}
+
+	if err := orch.RemoveDomain(ctx, domain); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if err := orch.RemoveDomain(ctx, domain); err != nil {
+		t.Fatalf("expected idempotent remove, got %v", err)
+	}
+}
+
+func TestParseDomainStatus(t *testing.T) {
+	tests := []struct {
+		name       string
+		conditions []interface{}
+		status     models.DomainStatus
+		cert       bool
+		message    string
+	}{
+		{
+			name: "ready with certificate",
+			conditions: []interface{}{
+				map[string]interface{}{"type": "Ready", "status": "True"},
+				map[string]interface{}{"type": "CertificateProvisioned", "status": "True"},
+			},
+			status: models.DomainStatusReady,
+			cert:   true,
+		},
+		{
+			name: "ready without auto tls",
+			conditions: []interface{}{
+				map[string]interface{}{"type": "Ready", "status": "True"},
+				map[string]interface{}{"type": "CertificateProvisioned", "status": "True", "reason": "TLSNotEnabled"},
+			},
+			status: models.DomainStatusReady,
+		},
+		{
+			name: "certificate pending",
+			conditions: []interface{}{
+				map[string]interface{}{"type": "Ready", "status": "Unknown"},
+				map[string]interface{}{"type": "CertificateProvisioned", "status": "Unknown", "message": "Certificate is not ready"},
+			},
+			status:  models.DomainStatusProvisioning,
+			message: "Certificate is not ready",
+		},
+		{
+			name: "domain claimed elsewhere",
+			conditions: []interface{}{
+				map[string]interface{}{"type": "Ready", "status": "False", "message": "domain already claimed"},
+			},
+			status:  models.DomainStatusFailed,
+			message: "domain already claimed",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			obj := &unstructured.Unstructured{Object: map[string]interface{}{
+				"status": map[string]interface{}{"conditions": tt.conditions},
+			}}
+			result := parseDomainStatus(obj)
+			if result.Status != tt.status || result.CertificateReady != tt.cert || result.Message != tt.message {
+				t.Fatalf("unexpected result: %+v", result)
+			}
+		})
+	}
+}
+
+func TestKnativeWatch(t *testing.T) {
+	orch, _, _ := newTestKnative()
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	notified := make(chan string, 10)
+	done := make(chan error, 1)
+	go func() {
+		done <- orch.Watch(ctx, func(id string) { notified <- id })
+	}()
+
+	if _, err := orch.Deploy(ctx, models.Service{ID: "svc-1", Name: "myapp", Image: "nginx:latest"}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	select {
+	case id := <-notified:
+		if id != "svc-1" {
+			t.Fatalf("expected svc-1, got %q", id)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("expected watch notification for deployed service")
+	}
+
+	cancel()
+	select {
+	case err := <-done:
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("expected watch to stop after cancel")
+	}
+}
+
+func TestKnativeEvents(t *testing.T) {
+	orch, _, cs := newTestKnative()
+	ctx := context.Background()
+
+	pod := &corev1.Pod{ObjectMeta: metav1.ObjectMeta{
+		Name:      "myapp-00001-deployment-abc",
+		Namespace: "default",
+		Labels:    map[string]string{"serving.knative.dev/service": "myapp"},
+	}}
+	if _, err := cs.CoreV1().Pods("default").Create(ctx, pod, metav1.CreateOptions{}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	now := time.Now()
+	events := []corev1.Event{
+		{
+			ObjectMeta:     metav1.ObjectMeta{Name: "e1", Namespace: "default"},
+			InvolvedObject: corev1.ObjectReference{Kind: "Pod", Name: pod.Name},
+			Reason:         "Pulling",
+			Message:        "Pulling image",
+			LastTimestamp:  metav1.NewTime(now.Add(-time.Minute)),
+		},
+		{
+			ObjectMeta:     metav1.ObjectMeta{Name: "e2", Namespace: "default"},
+			InvolvedObject: corev1.ObjectReference{Kind: "Pod", Name: pod.Name},
+			Reason:         "BackOff",
+			Message:        "Back-off restarting failed container",
+			LastTimestamp:  metav1.NewTime(now),
+		},
+		{
+			ObjectMeta:     metav1.ObjectMeta{Name: "e3", Namespace: "default"},
+			InvolvedObject: corev1.ObjectReference{Kind: "Pod", Name: "other-00001-deployment-xyz"},
+			Reason:         "Killing",
+			LastTimestamp:  metav1.NewTime(now),
+		},
+	}
+	for i := range events {
+		if _, err := cs.CoreV1().Events("default").Create(ctx, &events[i], metav1.CreateOptions{}); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+	}
+
+	got, err := orch.Events(ctx, models.Service{ID: "svc-1", Name: "myapp"})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(got) != 2 {
+		t.Fatalf("expected 2 events of the service's pods, got %+v", got)
+	}
+	if got[0].Reason != "BackOff" || got[0].Type != models.ServiceEventKubernetes || got[0].ServiceID != "svc-1" {
+		t.Fatalf("expected newest event first, got %+v", got[0])
+	}
+	if !strings.Contains(got[0].Message, pod.Name) {
+		t.Fatalf("expected pod name in message, got %q", got[0].Message)
+	}
+}
+
+func TestKnativeStatusDetectsDrift(t *testing.T) {
+	orch, client, _ := newTestKnative()
+	ctx := context.Background()
+	svc := models.Service{
+		ID:         "svc-1",
+		Name:       "myapp",
+		Image:      "nginx:1.25",
+		Generation: 1,
+		MaxScale:   3,
+		EnvVars:    map[string]string{"B": "2", "A": "1", "C": "3"},
+	}
+
+	if _, err := orch.Deploy(ctx, svc); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	services := client.Resource(knativeServiceGVR).Namespace("default")
+	live, err := services.Get(ctx, "myapp", metav1.GetOptions{})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	// Von Knative ergänzte Defaults sind kein Drift
+	containers, _, _ := unstructured.NestedSlice(live.Object, "spec", "template", "spec", "containers")
+	containers[0].(map[string]interface{})["name"] = "user-container"
+	_ = unstructured.SetNestedSlice(live.Object, containers, "spec", "template", "spec", "containers")
+	_ = unstructured.SetNestedField(live.Object, int64(300), "spec", "template", "spec", "timeoutSeconds")
+	live, err = services.Update(ctx, live, metav1.UpdateOptions{})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	result, err := orch.Status(ctx, svc)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if result.Drift != "" {
+		t.Fatalf("expected no drift for defaulted fields, got %q", result.Drift)
+	}
+
+	// Ein von Hand geändertes Image ist Drift
+	containers[0].(map[string]interface{})["image"] = "nginx:evil"
+	_ = unstructured.SetNestedSlice(live.Object, containers, "spec", "template", "spec", "containers")
+	live, err = services.Update(ctx, live, metav1.UpdateOptions{})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	result, _ = orch.Status(ctx, svc)
+	if result.Drift == "" {
+		t.Fatal("expected drift for modified image")
+	}
+
+	// Ein fehlender Spec-Hash ist Drift
+	annotations := live.GetAnnotations()
+	delete(annotations, specHashAnnotation)
+	live.SetAnnotations(annotations)
+	if _, err := services.Update(ctx, live, metav1.UpdateOptions{}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	result, _ = orch.Status(ctx, svc)
+	if result.Drift != "spec hash annotation does not match the desired spec" {
+		t.Fatalf("expected spec hash drift, got %q", result.Drift)
+	}
+}
+
+func TestBuildKnativeServiceSpecHashStable(t *testing.T) {
+	orch, _, _ := newTestKnative()
+	svc := models.Service{
+		Name:      "myapp",
+		Image:     "nginx:latest",
+		EnvVars:   map[string]string{"A": "1", "B": "2", "C": "3", "D": "4"},
+		SecretEnv: map[string]string{"E": "db", "F": "api"},
+	}
+
+	hash := orch.buildKnativeService(svc, "default").GetAnnotations()[specHashAnnotation]
+	if hash == "" {
+		t.Fatal("expected spec hash annotation")
+	}
+	for range 10 {
+		if got := orch.buildKnativeService(svc, "default").GetAnnotations()[specHashAnnotation]; got != hash {
+			t.Fatalf("expected stable spec hash, got %s and %s", hash, got)
+		}
+	}
+
+	svc.Image = "nginx:1.27"
+	if got := orch.buildKnativeService(svc, "default").GetAnnotations()[specHashAnnotation]; got == hash {
+		t.Fatal("expected spec hash to change with the image")
+	}
+}
+
+func TestKnativeListAndRemoveManaged(t *testing.T) {
+	orch, client, cs := newTestKnative()
+	ctx := context.Background()
+
+	svc := models.Service{ID: "svc-1", Name: "myapp", Image: "nginx:latest", OrgID: "org-1"}
+	if err := orch.ApplySecrets(ctx, svc, map[string]string{"db-password": "hunter2"}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if _, err := orch.Deploy(ctx, svc); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	// Fremde Knative Services ohne managed-by-Label werden nicht gelistet
+	foreign := &unstructured.Unstructured{Object: map[string]interface{}{
+		"apiVersion": "serving.knative.dev/v1",
+		"kind":       "Service",
+		"metadata":   map[string]interface{}{"name": "other", "namespace": "default"},
+	}}
+	if _, err := client.Resource(knativeServiceGVR).Namespace("default").Create(ctx, foreign, metav1.CreateOptions{}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	resources, err := orch.ListManaged(ctx)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	want := ManagedResource{Name: "myapp", Namespace: OrgNamespacePrefix + "org-1", ServiceID: "svc-1"}
+	if len(resources) != 1 || resources[0] != want {
+		t.Fatalf("expected %+v, got %+v", want, resources)
+	}
+
+	if err := orch.RemoveManaged(ctx, resources[0]); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if _, err := orch.Status(ctx, svc); !errors.Is(err, ErrNotFound) {
+		t.Fatalf("expected knative service to be removed, got %v", err)
+	}
+	if _, err := cs.CoreV1().Secrets(want.Namespace).Get(ctx, "myapp-env", metav1.GetOptions{}); err == nil {
+		t.Fatal("expected kubernetes secret to be deleted")
+	}
+
+	// Erneutes Entfernen ist kein Fehler
+	if err := orch.RemoveManaged(ctx, resources[0]); err != nil {
+		t.Fatalf("expected idempotent remove, got %v", err)
+	}
+}
+
+func TestKnativeLeaderElector(t *testing.T) {
+	orch, _, cs := newTestKnative()
+	ctx, cancel := context.WithCancel(context.Background())
+
+	leading := make(chan struct{})
+	stopped := make(chan struct{})
+	go func() {
+		defer close(stopped)
+		orch.LeaderElector("api-0").Run(ctx, func(ctx context.Context) {
+			close(leading)
+			<-ctx.Done()
+		})
+	}()
+
+	select {
+	case <-leading:
+	case <-time.After(5 * time.Second):
+		t.Fatal("expected elector to acquire the lease")
+	}
+	lease, err := cs.CoordinationV1().Leases("default").Get(context.Background(), leaseName, metav1.GetOptions{})
+	if err != nil {
+		t.Fatalf("expected lease to exist: %v", err)
+	}
+	if lease.Spec.HolderIdentity == nil || *lease.Spec.HolderIdentity != "api-0" {
+		t.Fatalf("expected api-0 to hold the lease, got %v", lease.Spec.HolderIdentity)
+	}
+
+	// Beim Herunterfahren wird die Lease für eine schnelle Übernahme freigegeben
+	cancel()
+	<-stopped
+	lease, _ = cs.CoordinationV1().Leases("default").Get(context.Background(), leaseName, metav1.GetOptions{})
+	if lease.Spec.HolderIdentity != nil && *lease.Spec.HolderIdentity != "" {
+		t.Fatalf("expected lease to be released, got holder %q", *lease.Spec.HolderIdentity)
+	}
+}
+
+func TestKnativeCreateNamespaceProvisionsPullSecret(t *testing.T) {
+	orch, _, cs := newTestKnative()
+	ctx := context.Background()
+
+	if err := orch.CreateNamespace(ctx, "org-1"); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	ns := NamespaceFromOrgID("org-1")
+	secret, err := cs.CoreV1().Secrets(ns).Get(ctx, pullSecretName, metav1.GetOptions{})
+	if err != nil {
+		t.Fatalf("expected pull secret to exist: %v", err)
+	}
+	if secret.Type != corev1.SecretTypeDockerConfigJson {
+		t.Fatalf("expected dockerconfigjson secret, got %s", secret.Type)
+	}
+	var dockerConfig struct {
+		Auths map[string]struct {
+			Username string `json:"username"`
+			Password string `json:"password"`
+		} `json:"auths"`
+	}
+	if err := json.Unmarshal(secret.Data[corev1.DockerConfigJsonKey], &dockerConfig); err != nil {
+		t.Fatalf("invalid docker config: %v", err)
+	}
+	creds, ok := dockerConfig.Auths["registry.maxcloud.dev"]
+	if !ok || creds.Username != "org-1" || creds.Password == "" {
+		t.Fatalf("expected registry credentials for org-1, got %+v", dockerConfig.Auths)
+	}
+
+	// Ein frisches Token wird nicht erneuert, ein bald ablaufendes schon
+	if err := orch.CreateNamespace(ctx, "org-1"); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	unchanged, _ := cs.CoreV1().Secrets(ns).Get(ctx, pullSecretName, metav1.GetOptions{})
+	if unchanged.Annotations[pullSecretExpiresAnnotation] != secret.Annotations[pullSecretExpiresAnnotation] {
+		t.Fatal("expected fresh pull secret to be kept")
+	}
+
+	unchanged.Annotations[pullSecretExpiresAnnotation] = time.Now().Add(time.Hour).UTC().Format(time.RFC3339)
+	if _, err := cs.CoreV1().Secrets(ns).Update(ctx, unchanged, metav1.UpdateOptions{}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if err := orch.CreateNamespace(ctx, "org-1"); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	rotated, _ := cs.CoreV1().Secrets(ns).Get(ctx, pullSecretName, metav1.GetOptions{})
+	expiresAt, _ := time.Parse(time.RFC3339, rotated.Annotations[pullSecretExpiresAnnotation])
+	if time.Until(expiresAt) < DefaultPullTokenExpiry-time.Minute {
+		t.Fatalf("expected pull secret to be rotated, expires at %s", expiresAt)
+	}
+
+	if err := orch.DeleteNamespace(ctx, "org-1"); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if exists, _ := orch.NamespaceExists(ctx, "org-1"); exists {
+		t.Fatal("expected namespace to be deleted")
+	}
+	if err := orch.DeleteNamespace(ctx, "org-1"); err != nil {
+		t.Fatalf("expected idempotent delete, got %v", err)
+	}
+}
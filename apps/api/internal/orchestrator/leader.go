@@ -0,0 +1,112 @@
+package orchestrator
+
+import (
+	"context"
+	"time"
+
+	"github.com/max-cloud/api/internal/leader"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/tools/leaderelection"
+	"k8s.io/client-go/tools/leaderelection/resourcelock"
+)
+
+// leaseName ist der Name der Lease, über die die führende Replika gewählt wird.
+const leaseName = "max-cloud-reconciler"
+
+// Zeiten der Lease-Wahl (Kubernetes-Standardwerte). Bricht die führende Replika ab,
+// übernimmt spätestens nach leaseDuration eine andere; beim geordneten Herunterfahren
+// wird die Lease sofort freigegeben.
+const (
+	leaseDuration      = 15 * time.Second
+	leaseRenewDeadline = 10 * time.Second
+	leaseRetryPeriod   = 2 * time.Second
+)
+
+// leaseElector wählt die führende Replika über eine Kubernetes Lease im Standard-Namespace.
+type leaseElector struct {
+	k    *KnativeOrchestrator
+	lock *resourcelock.LeaseLock
+}
+
+// LeaderElector gibt einen Elector zurück, der die Führung über eine Kubernetes Lease
+// vergibt. identity muss je Replika eindeutig sein, z.B. der Pod-Name.
+func (k *KnativeOrchestrator) LeaderElector(identity string) leader.Elector {
+	return &leaseElector{
+		k: k,
+		lock: &resourcelock.LeaseLock{
+			LeaseMeta:  metav1.ObjectMeta{Name: leaseName, Namespace: k.defaultNS},
+			Client:     k.clientset.CoordinationV1(),
+			LockConfig: resourcelock.ResourceLockConfig{Identity: identity},
+		},
+	}
+}
+
+func (e *leaseElector) Run(ctx context.Context, lead func(ctx context.Context)) {
+	for {
+		e.elect(ctx, lead)
+		select {
+		case <-ctx.Done():
+			return
+		case <-time.After(leaseRetryPeriod):
+		}
+	}
+}
+
+// elect durchläuft eine Wahl: warten auf die Lease, lead ausführen und die Lease
+// wieder freigeben, sobald lead zurückgekehrt ist. Kehrt zurück, wenn ctx abgebrochen
+// wird oder die Lease nicht erneuert werden konnte.
+func (e *leaseElector) elect(ctx context.Context, lead func(ctx context.Context)) {
+	// Die Wahl läuft bis nach dem Ende von lead weiter, damit die Lease nicht freigegeben
+	// wird, während der Reconciler noch arbeitet
+	electCtx, cancelElect := context.WithCancel(context.WithoutCancel(ctx))
+	defer cancelElect()
+
+	started := make(chan struct{})
+	finished := make(chan struct{})
+	elector, err := leaderelection.NewLeaderElector(leaderelection.LeaderElectionConfig{
+		Lock:            e.lock,
+		LeaseDuration:   leaseDuration,
+		RenewDeadline:   leaseRenewDeadline,
+		RetryPeriod:     leaseRetryPeriod,
+		ReleaseOnCancel: true,
+		Name:            leaseName,
+		Callbacks: leaderelection.LeaderCallbacks{
+			OnStartedLeading: func(leaseCtx context.Context) {
+				close(started)
+				defer close(finished)
+				leadCtx, cancel := context.WithCancel(leaseCtx)
+				defer cancel()
+				stop := context.AfterFunc(ctx, cancel)
+				defer stop()
+				lead(leadCtx)
+			},
+			OnStoppedLeading: func() {},
+			OnNewLeader: func(identity string) {
+				e.k.logger.Info("knative: observed reconciler leader", "leader", identity, "lease", leaseName)
+			},
+		},
+	})
+	if err != nil {
+		e.k.logger.Error("knative: invalid leader election config", "error", err)
+		return
+	}
+
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		elector.Run(electCtx)
+	}()
+
+	select {
+	case <-ctx.Done():
+	case <-done:
+	}
+	select {
+	case <-started:
+		<-finished
+	default:
+	}
+	cancelElect()
+	<-done
+}
@@ -5,6 +5,9 @@ import (
 	"fmt"
 	"io"
 	"log/slog"
+	"maps"
+	"slices"
+	"sync"
 	"time"
 
 	"github.com/max-cloud/shared/pkg/models"
@@ -13,33 +16,181 @@ import (
 // NoopOrchestrator gibt sofort ready zurück — für lokale Entwicklung ohne Cluster.
 type NoopOrchestrator struct {
 	logger *slog.Logger
+
+	mu       sync.Mutex
+	traffic  map[string][]models.TrafficTarget
+	secrets  map[string]map[string]string
+	restarts map[string][]string
+	domains  map[string]string // hostname → service name
+	managed  map[string]ManagedResource
 }
 
 // NewNoop erstellt einen neuen NoopOrchestrator.
 func NewNoop(logger *slog.Logger) *NoopOrchestrator {
-	return &NoopOrchestrator{logger: logger}
+	return &NoopOrchestrator{
+		logger:   logger,
+		traffic:  make(map[string][]models.TrafficTarget),
+		secrets:  make(map[string]map[string]string),
+		restarts: make(map[string][]string),
+		domains:  make(map[string]string),
+		managed:  make(map[string]ManagedResource),
+	}
 }
 
 func (n *NoopOrchestrator) Deploy(_ context.Context, svc models.Service) (*DeployResult, error) {
-	n.logger.Info("noop: deploy", "name", svc.Name, "image", svc.Image)
+	n.logger.Info("noop: deploy", "name", svc.Name, "image", svc.Image, "generation", svc.Generation)
+
+	n.mu.Lock()
+	n.traffic[svc.ID] = slices.Clone(svc.Traffic)
+	n.managed[svc.ID] = ManagedResource{Name: svc.Name, Namespace: "default", ServiceID: svc.ID}
+	n.mu.Unlock()
+
 	return &DeployResult{
-		Status: models.ServiceStatusReady,
-		URL:    fmt.Sprintf("https://%s.maxcloud.dev", svc.Name),
+		Status:     models.ServiceStatusReady,
+		URL:        fmt.Sprintf("https://%s.maxcloud.dev", svc.Name),
+		Generation: svc.Generation,
+		TagURLs:    noopTagURLs(svc),
 	}, nil
 }
 
 func (n *NoopOrchestrator) Remove(_ context.Context, svc models.Service) error {
 	n.logger.Info("noop: remove", "name", svc.Name)
+
+	n.mu.Lock()
+	delete(n.traffic, svc.ID)
+	delete(n.secrets, svc.ID)
+	delete(n.restarts, svc.ID)
+	delete(n.managed, svc.ID)
+	n.mu.Unlock()
+
+	return nil
+}
+
+// ListManaged gibt alle deployten und nicht wieder entfernten Services zurück.
+func (n *NoopOrchestrator) ListManaged(_ context.Context) ([]ManagedResource, error) {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+	return slices.Collect(maps.Values(n.managed)), nil
+}
+
+func (n *NoopOrchestrator) RemoveManaged(_ context.Context, res ManagedResource) error {
+	n.logger.Info("noop: remove managed", "name", res.Name)
+
+	n.mu.Lock()
+	delete(n.traffic, res.ServiceID)
+	delete(n.secrets, res.ServiceID)
+	delete(n.restarts, res.ServiceID)
+	delete(n.managed, res.ServiceID)
+	n.mu.Unlock()
+
 	return nil
 }
 
+// ApplySecrets merkt sich die Secret-Werte eines Services.
+func (n *NoopOrchestrator) ApplySecrets(_ context.Context, svc models.Service, values map[string]string) error {
+	n.logger.Info("noop: apply secrets", "name", svc.Name, "keys", len(values))
+
+	n.mu.Lock()
+	defer n.mu.Unlock()
+	if len(values) == 0 {
+		delete(n.secrets, svc.ID)
+		return nil
+	}
+	n.secrets[svc.ID] = maps.Clone(values)
+	return nil
+}
+
+// Secrets gibt die zuletzt hinterlegten Secret-Werte eines Services zurück.
+func (n *NoopOrchestrator) Secrets(serviceID string) map[string]string {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+	return maps.Clone(n.secrets[serviceID])
+}
+
+// RestartRevisions merkt sich die neu gestarteten Revisionen eines Services.
+func (n *NoopOrchestrator) RestartRevisions(_ context.Context, svc models.Service, revisions []string) error {
+	n.logger.Info("noop: restart revisions", "name", svc.Name, "revisions", revisions)
+
+	n.mu.Lock()
+	n.restarts[svc.ID] = append(n.restarts[svc.ID], revisions...)
+	n.mu.Unlock()
+	return nil
+}
+
+// Restarts gibt alle bisher neu gestarteten Revisionen eines Services zurück.
+func (n *NoopOrchestrator) Restarts(serviceID string) []string {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+	return slices.Clone(n.restarts[serviceID])
+}
+
+// ApplyDomain merkt sich das Mapping eines Hostnamens.
+func (n *NoopOrchestrator) ApplyDomain(_ context.Context, domain models.Domain, svc models.Service) error {
+	n.logger.Info("noop: apply domain", "hostname", domain.Hostname, "service", svc.Name)
+
+	n.mu.Lock()
+	n.domains[domain.Hostname] = svc.Name
+	n.mu.Unlock()
+	return nil
+}
+
+func (n *NoopOrchestrator) RemoveDomain(_ context.Context, domain models.Domain) error {
+	n.logger.Info("noop: remove domain", "hostname", domain.Hostname)
+
+	n.mu.Lock()
+	delete(n.domains, domain.Hostname)
+	n.mu.Unlock()
+	return nil
+}
+
+// DomainStatus meldet angewendete Mappings sofort als bereit inklusive Zertifikat.
+func (n *NoopOrchestrator) DomainStatus(_ context.Context, domain models.Domain) (*DomainResult, error) {
+	n.mu.Lock()
+	_, ok := n.domains[domain.Hostname]
+	n.mu.Unlock()
+	if !ok {
+		return nil, ErrNotFound
+	}
+	return &DomainResult{Status: models.DomainStatusReady, CertificateReady: true}, nil
+}
+
+// Traffic gibt die zuletzt deployte Traffic-Aufteilung eines Services zurück.
+// Nil bedeutet 100% auf die neueste Revision.
+func (n *NoopOrchestrator) Traffic(serviceID string) []models.TrafficTarget {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+	return slices.Clone(n.traffic[serviceID])
+}
+
 func (n *NoopOrchestrator) Status(_ context.Context, svc models.Service) (*DeployResult, error) {
 	return &DeployResult{
-		Status: models.ServiceStatusReady,
-		URL:    fmt.Sprintf("https://%s.maxcloud.dev", svc.Name),
+		Status:     models.ServiceStatusReady,
+		URL:        fmt.Sprintf("https://%s.maxcloud.dev", svc.Name),
+		Generation: svc.Generation,
+		TagURLs:    noopTagURLs(svc),
 	}, nil
 }
 
+// noopTagURLs baut die Tag-URLs nach dem Knative-Schema {tag}-{name}.
+func noopTagURLs(svc models.Service) map[string]string {
+	var urls map[string]string
+	for _, t := range svc.Traffic {
+		if t.Tag == "" {
+			continue
+		}
+		if urls == nil {
+			urls = make(map[string]string)
+		}
+		urls[t.Tag] = fmt.Sprintf("https://%s-%s.maxcloud.dev", t.Tag, svc.Name)
+	}
+	return urls
+}
+
+// Events gibt keine Events zurück, da ohne Cluster keine Pods existieren.
+func (n *NoopOrchestrator) Events(_ context.Context, _ models.Service) ([]models.ServiceEvent, error) {
+	return nil, nil
+}
+
 func (n *NoopOrchestrator) Logs(ctx context.Context, svc models.Service, opts LogsOptions) (io.ReadCloser, error) {
 	pr, pw := io.Pipe()
 
@@ -88,6 +239,11 @@ func (n *NoopOrchestrator) CreateNamespace(_ context.Context, orgID string) erro
 	return nil
 }
 
+func (n *NoopOrchestrator) DeleteNamespace(_ context.Context, orgID string) error {
+	n.logger.Info("noop: delete namespace", "org_id", orgID)
+	return nil
+}
+
 func (n *NoopOrchestrator) NamespaceExists(_ context.Context, orgID string) (bool, error) {
 	n.logger.Info("noop: namespace exists check", "org_id", orgID)
 	return true, nil
@@ -26,6 +26,30 @@ func TestNoopDeploy(t *testing.T) {
 	}
 }
 
+func TestNoopDeployRecordsTraffic(t *testing.T) {
+	orch := NewNoop(slog.Default())
+	split := []models.TrafficTarget{
+		{RevisionName: "myapp-00001", Percent: 90},
+		{RevisionName: "myapp-00002", Percent: 10},
+	}
+	_, err := orch.Deploy(context.Background(), models.Service{ID: "svc-1", Name: "myapp", Traffic: split})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	got := orch.Traffic("svc-1")
+	if len(got) != 2 || got[0] != split[0] || got[1] != split[1] {
+		t.Fatalf("expected recorded split %v, got %v", split, got)
+	}
+
+	if err := orch.Remove(context.Background(), models.Service{ID: "svc-1", Name: "myapp"}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if got := orch.Traffic("svc-1"); got != nil {
+		t.Fatalf("expected no split after remove, got %v", got)
+	}
+}
+
 func TestNoopRemove(t *testing.T) {
 	orch := NewNoop(slog.Default())
 	err := orch.Remove(context.Background(), models.Service{Name: "myapp"})
@@ -18,6 +18,33 @@ var ErrNoPods = errors.New("orchestrator: no running pods found")
 type DeployResult struct {
 	Status models.ServiceStatus
 	URL    string
+	// Generation ist die Service-Generation, die zuletzt an den Orchestrator übergeben wurde.
+	Generation int64
+	// TagURLs enthält die URLs der getaggten Traffic-Targets (Tag → URL).
+	TagURLs map[string]string
+	// Reason und Message erklären einen nicht bereiten Status (z.B. Image-Pull-Fehler).
+	Reason  string
+	Message string
+	// Drift beschreibt, wie das Live-Objekt von der Spec der aktuellen Generation abweicht; leer ohne Drift.
+	Drift string
+}
+
+// DomainResult enthält den Zustand des Mappings einer Custom Domain.
+type DomainResult struct {
+	Status models.DomainStatus
+	// CertificateReady meldet, ob für den Hostnamen ein TLS-Zertifikat ausgestellt wurde.
+	CertificateReady bool
+	// Message beschreibt, warum das Mapping noch nicht oder nicht mehr bereit ist.
+	Message string
+}
+
+// ManagedResource ist eine vom Orchestrator verwaltete Container-Ressource,
+// unabhängig davon, ob der zugehörige Service noch im Store existiert.
+type ManagedResource struct {
+	Name      string
+	Namespace string
+	// ServiceID stammt aus dem Label der Ressource; leer bei Ressourcen von vor dessen Einführung.
+	ServiceID string
 }
 
 // LogsOptions konfiguriert das Log-Streaming.
@@ -30,14 +57,43 @@ type LogsOptions struct {
 type Orchestrator interface {
 	// Deploy erstellt oder aktualisiert eine Container-Ressource (idempotent).
 	Deploy(ctx context.Context, svc models.Service) (*DeployResult, error)
+	// ApplySecrets hinterlegt die entschlüsselten Secret-Werte eines Services (Secret-Name → Wert),
+	// auf die SecretEnv beim nächsten Deploy verweist. Ohne Werte werden sie entfernt.
+	ApplySecrets(ctx context.Context, svc models.Service, values map[string]string) error
+	// RestartRevisions startet die Pods der angegebenen Revisionen neu, damit sie geänderte
+	// Secret-Werte laden, ohne eine neue Revision anzulegen. Fehlende Revisionen werden übersprungen.
+	RestartRevisions(ctx context.Context, svc models.Service, revisions []string) error
 	// Remove löscht eine Container-Ressource (idempotent, kein Fehler wenn nicht vorhanden).
 	Remove(ctx context.Context, svc models.Service) error
+	// ListManaged listet alle von max-cloud angelegten Container-Ressourcen aller Organisationen.
+	ListManaged(ctx context.Context) ([]ManagedResource, error)
+	// RemoveManaged löscht eine per ListManaged gefundene Ressource samt Secrets (idempotent).
+	RemoveManaged(ctx context.Context, res ManagedResource) error
+	// ApplyDomain erstellt oder aktualisiert das Mapping eines verifizierten Hostnamens auf svc (idempotent).
+	ApplyDomain(ctx context.Context, domain models.Domain, svc models.Service) error
+	// RemoveDomain löscht das Mapping eines Hostnamens (idempotent, kein Fehler wenn nicht vorhanden).
+	RemoveDomain(ctx context.Context, domain models.Domain) error
+	// DomainStatus liest den Zustand eines Mappings inklusive Zertifikat. ErrNotFound, wenn es nicht existiert.
+	DomainStatus(ctx context.Context, domain models.Domain) (*DomainResult, error)
 	// Status liest den aktuellen Zustand einer Container-Ressource.
 	Status(ctx context.Context, svc models.Service) (*DeployResult, error)
 	// Logs streamt Container-Logs als zeilenweisen Text.
 	Logs(ctx context.Context, svc models.Service, opts LogsOptions) (io.ReadCloser, error)
-	// CreateNamespace erstellt einen Kubernetes Namespace für eine Organisation.
+	// Events liest die Kubernetes-Events der Pods eines Services, neueste zuerst.
+	Events(ctx context.Context, svc models.Service) ([]models.ServiceEvent, error)
+	// CreateNamespace stellt den Namespace einer Organisation samt Registry-Pull-Secret bereit
+	// (idempotent) und erneuert das Pull-Secret vor Ablauf seines Tokens.
 	CreateNamespace(ctx context.Context, orgID string) error
+	// DeleteNamespace löscht den Namespace einer Organisation mit allen Ressourcen darin (idempotent).
+	DeleteNamespace(ctx context.Context, orgID string) error
 	// NamespaceExists prüft ob ein Namespace existiert.
 	NamespaceExists(ctx context.Context, orgID string) (bool, error)
 }
+
+// Watcher wird von Orchestratoren implementiert, die Zustandsänderungen ihrer
+// Container-Ressourcen aktiv melden, statt nur per Status abgefragt zu werden.
+type Watcher interface {
+	// Watch ruft notify mit der Service-ID auf, sobald sich eine Container-Ressource
+	// ändert oder gelöscht wird. Blockiert, bis ctx abgebrochen wird.
+	Watch(ctx context.Context, notify func(serviceID string)) error
+}
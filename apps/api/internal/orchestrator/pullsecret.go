@@ -0,0 +1,108 @@
+package orchestrator
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/max-cloud/api/internal/registry"
+
+	corev1 "k8s.io/api/core/v1"
+	k8serrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// pullSecretName ist das Image-Pull-Secret im Namespace jeder Organisation, auf das
+// Services mit Images aus der eigenen Registry verweisen.
+const pullSecretName = "registry-pull-secret"
+
+// pullSecretExpiresAnnotation speichert den Ablaufzeitpunkt des Tokens im Pull-Secret (RFC 3339).
+const pullSecretExpiresAnnotation = "max-cloud.dev/expires-at"
+
+// DefaultPullTokenExpiry ist die Gültigkeit des Registry-Tokens im Pull-Secret.
+const DefaultPullTokenExpiry = 30 * 24 * time.Hour
+
+// ensurePullSecret legt das Pull-Secret einer Organisation mit einem org-weiten
+// Pull-Token an und erneuert es, sobald weniger als ein Drittel der Gültigkeit übrig ist.
+func (k *KnativeOrchestrator) ensurePullSecret(ctx context.Context, orgID string) error {
+	if k.registryJWTSecret == "" || k.registryURL == "" {
+		return nil
+	}
+	ns := NamespaceFromOrgID(orgID)
+	secrets := k.clientset.CoreV1().Secrets(ns)
+
+	existing, err := secrets.Get(ctx, pullSecretName, metav1.GetOptions{})
+	found := err == nil
+	if err != nil && !k8serrors.IsNotFound(err) {
+		return fmt.Errorf("getting pull secret: %w", err)
+	}
+	if found && !k.pullSecretDue(existing, time.Now()) {
+		return nil
+	}
+
+	secret, err := k.buildPullSecret(orgID, ns, time.Now())
+	if err != nil {
+		return err
+	}
+	if !found {
+		if _, err := secrets.Create(ctx, secret, metav1.CreateOptions{}); err != nil {
+			return fmt.Errorf("creating pull secret: %w", err)
+		}
+		k.logger.Info("knative: pull secret created", "namespace", ns)
+		return nil
+	}
+	secret.ResourceVersion = existing.ResourceVersion
+	if _, err := secrets.Update(ctx, secret, metav1.UpdateOptions{}); err != nil {
+		return fmt.Errorf("rotating pull secret: %w", err)
+	}
+	k.logger.Info("knative: pull secret rotated", "namespace", ns)
+	return nil
+}
+
+// pullSecretDue meldet, ob das Token im Pull-Secret erneuert werden muss.
+func (k *KnativeOrchestrator) pullSecretDue(secret *corev1.Secret, now time.Time) bool {
+	expiresAt, err := time.Parse(time.RFC3339, secret.Annotations[pullSecretExpiresAnnotation])
+	if err != nil {
+		return true
+	}
+	return expiresAt.Sub(now) < k.pullTokenExpiry/3
+}
+
+// buildPullSecret erstellt ein Secret vom Typ dockerconfigjson mit einem Token, das
+// alle Repositories der Organisation pullen darf.
+func (k *KnativeOrchestrator) buildPullSecret(orgID, ns string, now time.Time) (*corev1.Secret, error) {
+	token, err := registry.SignToken(k.registryJWTSecret, orgID, k.registryURL, registry.OrgPullAccess(orgID), now, k.pullTokenExpiry)
+	if err != nil {
+		return nil, fmt.Errorf("signing pull token: %w", err)
+	}
+
+	dockerConfig, err := json.Marshal(map[string]interface{}{
+		"auths": map[string]interface{}{
+			k.registryURL: map[string]string{
+				"username": orgID,
+				"password": token,
+				"auth":     base64.StdEncoding.EncodeToString([]byte(orgID + ":" + token)),
+			},
+		},
+	})
+	if err != nil {
+		return nil, fmt.Errorf("encoding docker config: %w", err)
+	}
+
+	return &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      pullSecretName,
+			Namespace: ns,
+			Labels: map[string]string{
+				managedByLabel: "max-cloud",
+			},
+			Annotations: map[string]string{
+				pullSecretExpiresAnnotation: now.Add(k.pullTokenExpiry).UTC().Format(time.RFC3339),
+			},
+		},
+		Type: corev1.SecretTypeDockerConfigJson,
+		Data: map[string][]byte{corev1.DockerConfigJsonKey: dockerConfig},
+	}, nil
+}
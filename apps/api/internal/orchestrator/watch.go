@@ -0,0 +1,64 @@
+package orchestrator
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/client-go/dynamic/dynamicinformer"
+	"k8s.io/client-go/tools/cache"
+)
+
+// Watch beobachtet alle von max-cloud verwalteten Knative Services über einen Informer
+// und meldet Änderungen (z.B. Ready-Condition, URL) mit der Service-ID aus dem Label.
+// Knative Services ohne Service-ID-Label (vor dessen Einführung deployt) werden ignoriert
+// und weiter über den periodischen Resync des Reconcilers erfasst.
+func (k *KnativeOrchestrator) Watch(ctx context.Context, notify func(serviceID string)) error {
+	factory := dynamicinformer.NewFilteredDynamicSharedInformerFactory(k.client, 0, metav1.NamespaceAll,
+		func(opts *metav1.ListOptions) {
+			opts.LabelSelector = managedByLabel + "=max-cloud"
+		},
+	)
+	informer := factory.ForResource(knativeServiceGVR).Informer()
+
+	enqueue := func(obj interface{}) {
+		if tombstone, ok := obj.(cache.DeletedFinalStateUnknown); ok {
+			obj = tombstone.Obj
+		}
+		u, ok := obj.(*unstructured.Unstructured)
+		if !ok {
+			return
+		}
+		if id := u.GetLabels()[serviceIDLabel]; id != "" {
+			notify(id)
+		}
+	}
+	if _, err := informer.AddEventHandler(cache.ResourceEventHandlerFuncs{
+		AddFunc: enqueue,
+		UpdateFunc: func(oldObj, newObj interface{}) {
+			// Nur Änderungen am Objekt melden, nicht jede erneute Auslieferung desselben Stands
+			if oldObj.(*unstructured.Unstructured).GetResourceVersion() == newObj.(*unstructured.Unstructured).GetResourceVersion() {
+				return
+			}
+			enqueue(newObj)
+		},
+		DeleteFunc: enqueue,
+	}); err != nil {
+		return fmt.Errorf("registering watch handler: %w", err)
+	}
+
+	factory.Start(ctx.Done())
+	if !cache.WaitForCacheSync(ctx.Done(), informer.HasSynced) {
+		if ctx.Err() != nil {
+			return nil
+		}
+		return errors.New("knative service informer did not sync")
+	}
+	k.logger.Info("knative: watching services")
+
+	<-ctx.Done()
+	factory.Shutdown()
+	return nil
+}
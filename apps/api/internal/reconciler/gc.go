@@ -0,0 +1,64 @@
+package reconciler
+
+import (
+	"context"
+	"time"
+)
+
+// CollectGarbage löscht Container-Ressourcen, zu denen es keinen Service im Store mehr gibt,
+// z.B. nach einem Absturz zwischen Deploy und Store-Schreibvorgang oder einer von Hand
+// gelöschten Zeile. Eine Ressource wird erst entfernt, wenn sie über die GracePeriod hinweg
+// verwaist war; im DryRun-Modus wird sie nur gemeldet.
+// Ressourcen ohne Service-ID-Label lassen sich keinem Service zuordnen und bleiben unberührt.
+func (r *Reconciler) CollectGarbage(ctx context.Context) {
+	resources, err := r.orchestrator.ListManaged(ctx)
+	if err != nil {
+		r.logger.Error("reconciler: gc failed to list managed resources", "error", err)
+		return
+	}
+	// Erst nach den Ressourcen listen: ein dazwischen angelegter Service ist so bereits im Store
+	services, err := r.store.List(ctx)
+	if err != nil {
+		r.logger.Error("reconciler: gc failed to list services", "error", err)
+		return
+	}
+
+	known := make(map[string]bool, len(services))
+	for _, svc := range services {
+		known[svc.ID] = true
+	}
+
+	now := time.Now()
+	orphans := make(map[string]time.Time)
+	for _, res := range resources {
+		if res.ServiceID == "" || known[res.ServiceID] {
+			continue
+		}
+
+		key := res.Namespace + "/" + res.Name
+		since, seen := r.orphans[key]
+		if !seen {
+			since = now
+			r.logger.Info("reconciler: gc found orphaned resource", "name", res.Name, "namespace", res.Namespace, "service_id", res.ServiceID, "grace_period", r.gc.GracePeriod)
+		}
+		if now.Sub(since) < r.gc.GracePeriod {
+			orphans[key] = since
+			continue
+		}
+
+		if r.gc.DryRun {
+			r.logger.Warn("reconciler: gc dry-run, would remove orphaned resource", "name", res.Name, "namespace", res.Namespace, "service_id", res.ServiceID, "orphaned_since", since)
+			orphans[key] = since
+			continue
+		}
+		if err := r.orchestrator.RemoveManaged(ctx, res); err != nil {
+			r.logger.Error("reconciler: gc failed to remove orphaned resource", "error", err, "name", res.Name, "namespace", res.Namespace)
+			orphans[key] = since
+			continue
+		}
+		r.logger.Info("reconciler: gc removed orphaned resource", "name", res.Name, "namespace", res.Namespace, "service_id", res.ServiceID)
+	}
+
+	// Ressourcen, die wieder einen Service haben oder verschwunden sind, vergessen
+	r.orphans = orphans
+}
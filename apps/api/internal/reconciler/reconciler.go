@@ -2,50 +2,168 @@ package reconciler
 
 import (
 	"context"
+	"errors"
+	"fmt"
 	"log/slog"
+	"maps"
+	"slices"
+	"sync"
 	"time"
 
 	"github.com/max-cloud/api/internal/orchestrator"
 	"github.com/max-cloud/api/internal/store"
 	"github.com/max-cloud/shared/pkg/models"
+
+	"k8s.io/client-go/util/workqueue"
+)
+
+// Wartezeiten zwischen wiederholten Versuchen eines fehlschlagenden Services (exponentiell).
+const (
+	retryBaseDelay = 5 * time.Second
+	retryMaxDelay  = 5 * time.Minute
 )
 
+// namespaceResyncInterval ist der Abstand, in dem die Namespaces aller Organisationen mit
+// Services geprüft und ablaufende Registry-Pull-Secrets erneuert werden.
+const namespaceResyncInterval = time.Hour
+
+// driftResyncInterval ist der Abstand, in dem ausgerollte Services (ready und failed) auf
+// Drift im Cluster geprüft werden. Jede Prüfung fragt den Orchestrator live ab, daher
+// deutlich seltener als der Reconcile-Tick für offene Arbeit. Im Watch-Mode lösen
+// Änderungen im Cluster den Abgleich ohnehin sofort aus.
+const driftResyncInterval = 10 * time.Minute
+
+// GCOptions konfiguriert die Garbage Collection verwaister Container-Ressourcen.
+type GCOptions struct {
+	// Interval zwischen zwei GC-Durchläufen; 0 deaktiviert die Garbage Collection.
+	Interval time.Duration
+	// GracePeriod, die eine Ressource ohne Service im Store mindestens verwaist sein muss,
+	// bevor sie gelöscht wird.
+	GracePeriod time.Duration
+	// DryRun meldet verwaiste Ressourcen nur, statt sie zu löschen.
+	DryRun bool
+}
+
 // Reconciler gleicht den Soll-Zustand (Store) mit dem Ist-Zustand (Orchestrator) ab.
 type Reconciler struct {
 	logger       *slog.Logger
 	store        store.ServiceStore
+	secrets      store.SecretStore
+	domains      store.DomainStore
+	events       store.EventStore
 	orchestrator orchestrator.Orchestrator
 	interval     time.Duration
+	workers      int
+	gc           GCOptions
+
+	// orphans merkt sich, seit wann eine Ressource ohne Service im Store gesehen wird
+	// (Namespace/Name → Zeitpunkt). Nur von der Run-Schleife verwendet.
+	orphans map[string]time.Time
+
+	// queue enthält die IDs der Services, die abgeglichen werden sollen (dedupliziert).
+	// Fehlgeschlagene Services werden über limiter mit exponentiellem Backoff erneut eingereiht.
+	queue   workqueue.TypedRateLimitingInterface[string]
+	limiter workqueue.TypedRateLimiter[string]
 }
 
-// New erstellt einen neuen Reconciler.
-func New(logger *slog.Logger, st store.ServiceStore, orch orchestrator.Orchestrator, interval time.Duration) *Reconciler {
+// New erstellt einen neuen Reconciler, der Services mit bis zu workers Goroutinen parallel abgleicht.
+func New(logger *slog.Logger, st store.ServiceStore, secretSt store.SecretStore, domainSt store.DomainStore, eventSt store.EventStore, orch orchestrator.Orchestrator, interval time.Duration, workers int, gc GCOptions) *Reconciler {
+	if workers < 1 {
+		workers = 1
+	}
+	limiter := workqueue.NewTypedItemExponentialFailureRateLimiter[string](retryBaseDelay, retryMaxDelay)
 	return &Reconciler{
 		logger:       logger,
 		store:        st,
+		secrets:      secretSt,
+		domains:      domainSt,
+		events:       eventSt,
 		orchestrator: orch,
 		interval:     interval,
+		workers:      workers,
+		gc:           gc,
+		orphans:      make(map[string]time.Time),
+		queue:        workqueue.NewTypedRateLimitingQueue(limiter),
+		limiter:      limiter,
 	}
 }
 
 // Run startet die Reconcile-Schleife und blockiert bis ctx abgebrochen wird.
+// Ein Reconciler kann nur einmal laufen, da die Queue danach geschlossen ist.
+// Unterstützt der Orchestrator Watches, werden Statusänderungen sofort abgeglichen
+// und der Ticker dient nur noch als periodischer Resync. Ausgerollte Services werden
+// nur alle driftResyncInterval auf Drift geprüft.
 func (r *Reconciler) Run(ctx context.Context) {
+	// Erst zurückkehren, wenn kein Worker mehr arbeitet: nach dem Verlust der Führung
+	// darf eine andere Replika übernehmen, ohne dass sich Deploys überschneiden
+	var wg sync.WaitGroup
+	defer wg.Wait()
+	for range r.workers {
+		wg.Go(func() {
+			for r.processNextItem(ctx) {
+			}
+		})
+	}
+
+	if w, ok := r.orchestrator.(orchestrator.Watcher); ok {
+		go func() {
+			if err := w.Watch(ctx, r.queue.Add); err != nil {
+				r.logger.Error("reconciler: watch failed, falling back to polling", "error", err)
+			}
+		}()
+	}
+
 	ticker := time.NewTicker(r.interval)
 	defer ticker.Stop()
 
+	driftTicker := time.NewTicker(driftResyncInterval)
+	defer driftTicker.Stop()
+
+	namespaceTicker := time.NewTicker(namespaceResyncInterval)
+	defer namespaceTicker.Stop()
+
+	// Ein nil-Channel blockiert für immer und deaktiviert so die Garbage Collection
+	var gcTick <-chan time.Time
+	if r.gc.Interval > 0 {
+		gcTicker := time.NewTicker(r.gc.Interval)
+		defer gcTicker.Stop()
+		gcTick = gcTicker.C
+	}
+
 	for {
 		select {
 		case <-ctx.Done():
+			r.queue.ShutDown()
 			r.logger.Info("reconciler stopped")
 			return
 		case <-ticker.C:
-			r.RunOnce(ctx)
+			r.enqueueServices(ctx, false)
+			r.reconcileDomains(ctx)
+		case <-driftTicker.C:
+			r.enqueueServices(ctx, true)
+		case <-gcTick:
+			r.CollectGarbage(ctx)
+		case <-namespaceTicker.C:
+			r.reconcileNamespaces(ctx)
 		}
 	}
 }
 
-// RunOnce führt einen einzelnen Reconcile-Durchlauf aus.
+// RunOnce führt einen einzelnen Reconcile-Durchlauf inklusive Drift-Prüfung aus und arbeitet
+// dabei alle fälligen Services der Queue ab. Services im Backoff werden übersprungen.
 func (r *Reconciler) RunOnce(ctx context.Context) {
+	r.enqueueServices(ctx, true)
+	for r.queue.Len() > 0 {
+		r.processNextItem(ctx)
+	}
+
+	r.reconcileDomains(ctx)
+}
+
+// enqueueServices stellt alle Services mit offener Arbeit in die Queue. Mit resync werden
+// auch bereits ausgerollte Services eingereiht, um sie auf Drift im Cluster zu prüfen.
+// Services im Backoff stehen bereits verzögert in der Queue und werden nicht vorgezogen.
+func (r *Reconciler) enqueueServices(ctx context.Context, resync bool) {
 	services, err := r.store.List(ctx)
 	if err != nil {
 		r.logger.Error("reconciler: failed to list services", "error", err)
@@ -53,49 +171,295 @@ func (r *Reconciler) RunOnce(ctx context.Context) {
 	}
 
 	for _, svc := range services {
-		switch svc.Status {
-		case models.ServiceStatusPending:
-			r.reconcilePending(ctx, svc)
-		case models.ServiceStatusDeleting:
-			r.reconcileDeleting(ctx, svc)
+		if !resync && svc.Status != models.ServiceStatusPending && svc.Status != models.ServiceStatusDeleting {
+			continue
 		}
+		if r.queue.NumRequeues(svc.ID) > 0 {
+			continue
+		}
+		r.queue.Add(svc.ID)
 	}
 }
 
-func (r *Reconciler) reconcilePending(ctx context.Context, svc models.Service) {
-	result, err := r.orchestrator.Status(ctx, svc)
+// processNextItem gleicht den nächsten Service der Queue ab und gibt false zurück,
+// sobald die Queue geschlossen wurde. Die Queue gibt eine ID nie gleichzeitig an
+// zwei Worker heraus.
+func (r *Reconciler) processNextItem(ctx context.Context) bool {
+	id, shutdown := r.queue.Get()
+	if shutdown {
+		return false
+	}
+	defer r.queue.Done(id)
+
+	r.reconcileService(ctx, id)
+	return true
+}
+
+// reconcileService lädt einen Service aus dem Store und gleicht ihn je nach Status ab.
+// Schlägt der Abgleich fehl, werden Versuch und Fehler am Service gespeichert und der
+// Service nach exponentiell wachsender Wartezeit erneut eingereiht.
+func (r *Reconciler) reconcileService(ctx context.Context, id string) {
+	svc, err := r.store.Get(ctx, id)
 	if err != nil {
-		if err == orchestrator.ErrNotFound {
-			if _, err := r.orchestrator.Deploy(ctx, svc); err != nil {
-				r.logger.Error("reconciler: deploy failed", "error", err, "id", svc.ID)
-			} else {
-				r.logger.Info("reconciler: deployed to knative", "id", svc.ID)
-			}
-		} else {
-			r.logger.Error("reconciler: status check failed", "error", err, "id", svc.ID)
+		if errors.Is(err, store.ErrNotFound) {
+			r.queue.Forget(id)
+			return
 		}
+		r.logger.Error("reconciler: failed to get service", "error", err, "id", id)
+		r.queue.AddRateLimited(id)
 		return
 	}
 
+	switch svc.Status {
+	case models.ServiceStatusPending:
+		err = r.reconcilePending(ctx, svc)
+	case models.ServiceStatusDeleting:
+		err = r.reconcileDeleting(ctx, svc)
+	case models.ServiceStatusReady, models.ServiceStatusFailed:
+		err = r.reconcileDrift(ctx, svc)
+	}
+
+	if err != nil {
+		attempts := svc.ReconcileAttempts + 1
+		delay := r.limiter.When(id)
+		r.logger.Error("reconciler: reconcile failed", "error", err, "id", id, "attempt", attempts, "retry_in", delay)
+		if err := r.store.UpdateReconcileState(ctx, id, attempts, err.Error()); err != nil && !errors.Is(err, store.ErrNotFound) {
+			r.logger.Error("reconciler: update reconcile state failed", "error", err, "id", id)
+		}
+		// Nur den ersten Fehlschlag einer Serie protokollieren, nicht jeden Wiederholungsversuch
+		if attempts == 1 {
+			r.recordEvent(ctx, svc, models.ServiceEventFailed, "ReconcileError", err.Error())
+		}
+		r.queue.AddAfter(id, delay)
+		return
+	}
+
+	r.queue.Forget(id)
+	if svc.ReconcileAttempts > 0 || svc.LastError != "" {
+		if err := r.store.UpdateReconcileState(ctx, id, 0, ""); err != nil && !errors.Is(err, store.ErrNotFound) {
+			r.logger.Error("reconciler: reset reconcile state failed", "error", err, "id", id)
+		}
+	}
+}
+
+func (r *Reconciler) reconcilePending(ctx context.Context, svc models.Service) error {
+	result, err := r.orchestrator.Status(ctx, svc)
+	if errors.Is(err, orchestrator.ErrNotFound) {
+		if err := r.deploy(ctx, svc); err != nil {
+			return fmt.Errorf("deploy: %w", err)
+		}
+		r.logger.Info("reconciler: deployed to knative", "id", svc.ID)
+		r.recordEvent(ctx, svc, models.ServiceEventDeployed, "", fmt.Sprintf("generation %d deployed", svc.Generation))
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("status check: %w", err)
+	}
+
+	// Der Service wurde seit dem letzten Deploy geändert: neue Spec ausrollen
+	if result.Generation < svc.Generation {
+		if err := r.deploy(ctx, svc); err != nil {
+			return fmt.Errorf("redeploy generation %d: %w", svc.Generation, err)
+		}
+		r.logger.Info("reconciler: redeployed updated spec", "id", svc.ID, "generation", svc.Generation)
+		r.recordEvent(ctx, svc, models.ServiceEventDeployed, "", fmt.Sprintf("generation %d deployed", svc.Generation))
+		return nil
+	}
+
+	return r.syncStatus(ctx, svc, result)
+}
+
+// reconcileDrift prüft einen ausgerollten Service auf Abweichungen im Cluster, z.B. einen
+// gelöschten oder von Hand geänderten Knative Service, und rollt ihn dann erneut aus.
+// Ohne Drift werden nur Statusänderungen übernommen.
+func (r *Reconciler) reconcileDrift(ctx context.Context, svc models.Service) error {
+	result, err := r.orchestrator.Status(ctx, svc)
+	var drift string
+	switch {
+	case errors.Is(err, orchestrator.ErrNotFound):
+		drift = "service is missing in the cluster"
+	case err != nil:
+		return fmt.Errorf("status check: %w", err)
+	case result.Generation != svc.Generation:
+		drift = fmt.Sprintf("cluster runs generation %d instead of %d", result.Generation, svc.Generation)
+	default:
+		drift = result.Drift
+	}
+
+	if drift == "" {
+		return r.syncStatus(ctx, svc, result)
+	}
+
+	r.logger.Warn("reconciler: drift detected, redeploying", "id", svc.ID, "drift", drift)
+	r.recordEvent(ctx, svc, models.ServiceEventDrift, "", drift)
+	if err := r.deploy(ctx, svc); err != nil {
+		return fmt.Errorf("redeploy after drift: %w", err)
+	}
+	// Als pending markieren, damit der Reconciler die Bereitschaft des Redeploys verfolgt
+	if err := r.store.UpdateStatus(ctx, svc.ID, models.ServiceStatusPending, ""); err != nil {
+		return fmt.Errorf("update status: %w", err)
+	}
+	r.recordEvent(ctx, svc, models.ServiceEventDeployed, "", fmt.Sprintf("generation %d redeployed", svc.Generation))
+	return nil
+}
+
+// syncStatus übernimmt Status, URLs und Statusgrund aus dem Orchestrator in den Store.
+func (r *Reconciler) syncStatus(ctx context.Context, svc models.Service, result *orchestrator.DeployResult) error {
+	if !maps.Equal(result.TagURLs, svc.TagURLs) {
+		if err := r.store.UpdateTagURLs(ctx, svc.ID, result.TagURLs); err != nil {
+			return fmt.Errorf("update tag urls: %w", err)
+		}
+		r.logger.Info("reconciler: tag urls updated", "id", svc.ID, "tags", len(result.TagURLs))
+	}
+
+	// Grund vor dem Status schreiben: ein fehlgeschlagener Service wird danach nicht mehr abgeglichen
+	if result.Reason != svc.StatusReason || result.Message != svc.StatusMessage {
+		if err := r.store.UpdateStatusReason(ctx, svc.ID, result.Reason, result.Message); err != nil {
+			return fmt.Errorf("update status reason: %w", err)
+		}
+	}
+
 	if result.Status != svc.Status || result.URL != svc.URL {
 		if err := r.store.UpdateStatus(ctx, svc.ID, result.Status, result.URL); err != nil {
-			r.logger.Error("reconciler: update status failed", "error", err, "id", svc.ID)
-			return
+			return fmt.Errorf("update status: %w", err)
+		}
+		r.logger.Info("reconciler: status updated", "id", svc.ID, "status", result.Status, "url", result.URL, "reason", result.Reason)
+		if result.Status != svc.Status {
+			r.recordStatusEvent(ctx, svc, result)
 		}
-		r.logger.Info("reconciler: status updated", "id", svc.ID, "status", result.Status, "url", result.URL)
 	}
+	return nil
+}
+
+// deploy löst die Secrets eines Services auf, hinterlegt sie beim Orchestrator und rollt ihn aus.
+func (r *Reconciler) deploy(ctx context.Context, svc models.Service) error {
+	// Namespace und Pull-Secret der Organisation müssen vor dem ersten Deploy existieren
+	if svc.OrgID != "" {
+		if err := r.orchestrator.CreateNamespace(ctx, svc.OrgID); err != nil {
+			return fmt.Errorf("provisioning namespace: %w", err)
+		}
+	}
+
+	var values map[string]string
+	if len(svc.SecretEnv) > 0 {
+		names := slices.Compact(slices.Sorted(maps.Values(svc.SecretEnv)))
+		resolved, err := r.secrets.SecretValues(ctx, svc.OrgID, names)
+		if err != nil {
+			return fmt.Errorf("resolving secrets: %w", err)
+		}
+		values = resolved
+	}
+
+	if err := r.orchestrator.ApplySecrets(ctx, svc, values); err != nil {
+		return err
+	}
+	_, err := r.orchestrator.Deploy(ctx, svc)
+	return err
 }
 
-func (r *Reconciler) reconcileDeleting(ctx context.Context, svc models.Service) {
+func (r *Reconciler) reconcileDeleting(ctx context.Context, svc models.Service) error {
 	if err := r.orchestrator.Remove(ctx, svc); err != nil {
-		r.logger.Error("reconciler: remove failed", "error", err, "id", svc.ID)
-		return
+		return fmt.Errorf("remove: %w", err)
 	}
 
 	if err := r.store.Delete(ctx, svc.ID); err != nil {
-		r.logger.Error("reconciler: delete from store failed", "error", err, "id", svc.ID)
-		return
+		return fmt.Errorf("delete from store: %w", err)
 	}
 
 	r.logger.Info("reconciler: service deleted", "id", svc.ID)
+	r.recordEvent(ctx, svc, models.ServiceEventDeleted, "", "service removed from cluster")
+	return nil
+}
+
+// recordStatusEvent protokolliert einen Statuswechsel. Ein Fehlschlag wird mit dem
+// vom Orchestrator gemeldeten Grund als failed protokolliert.
+func (r *Reconciler) recordStatusEvent(ctx context.Context, svc models.Service, result *orchestrator.DeployResult) {
+	if result.Status == models.ServiceStatusFailed {
+		r.recordEvent(ctx, svc, models.ServiceEventFailed, result.Reason, result.Message)
+		return
+	}
+	r.recordEvent(ctx, svc, models.ServiceEventStatusChanged, result.Reason,
+		fmt.Sprintf("status changed from %s to %s", svc.Status, result.Status))
+}
+
+// recordEvent hängt ein Ereignis an die Historie von svc an. Fehler werden nur geloggt.
+func (r *Reconciler) recordEvent(ctx context.Context, svc models.Service, eventType models.ServiceEventType, reason, message string) {
+	err := r.events.RecordEvent(ctx, models.ServiceEvent{
+		ServiceID: svc.ID,
+		OrgID:     svc.OrgID,
+		Type:      eventType,
+		Reason:    reason,
+		Message:   message,
+	})
+	if err != nil {
+		r.logger.Error("reconciler: record event failed", "error", err, "id", svc.ID, "type", eventType)
+	}
+}
+
+// reconcileNamespaces stellt die Namespaces aller Organisationen mit Services sicher und
+// erneuert dabei Registry-Pull-Secrets, deren Token bald abläuft.
+func (r *Reconciler) reconcileNamespaces(ctx context.Context) {
+	services, err := r.store.List(ctx)
+	if err != nil {
+		r.logger.Error("reconciler: failed to list services", "error", err)
+		return
+	}
+
+	orgIDs := make(map[string]bool)
+	for _, svc := range services {
+		if svc.OrgID != "" {
+			orgIDs[svc.OrgID] = true
+		}
+	}
+	for _, orgID := range slices.Sorted(maps.Keys(orgIDs)) {
+		if err := r.orchestrator.CreateNamespace(ctx, orgID); err != nil {
+			r.logger.Error("reconciler: provisioning namespace failed", "error", err, "org_id", orgID)
+		}
+	}
+}
+
+// reconcileDomains legt für verifizierte Domains das Mapping beim Orchestrator an und
+// überträgt dessen Zustand inklusive Zertifikat in den Store.
+func (r *Reconciler) reconcileDomains(ctx context.Context) {
+	domains, err := r.domains.ListDomains(ctx)
+	if err != nil {
+		r.logger.Error("reconciler: failed to list domains", "error", err)
+		return
+	}
+
+	for _, d := range domains {
+		if d.VerifiedAt == nil {
+			continue
+		}
+		r.reconcileDomain(ctx, d)
+	}
+}
+
+func (r *Reconciler) reconcileDomain(ctx context.Context, d models.Domain) {
+	result, err := r.orchestrator.DomainStatus(ctx, d)
+	if errors.Is(err, orchestrator.ErrNotFound) {
+		svc, err := r.store.Get(ctx, d.ServiceID)
+		if err != nil {
+			r.logger.Error("reconciler: get service for domain failed", "error", err, "domain", d.Hostname, "service_id", d.ServiceID)
+			return
+		}
+		if err := r.orchestrator.ApplyDomain(ctx, d, svc); err != nil {
+			r.logger.Error("reconciler: apply domain failed", "error", err, "domain", d.Hostname)
+			return
+		}
+		r.logger.Info("reconciler: domain mapped", "domain", d.Hostname, "service", svc.Name)
+		return
+	}
+	if err != nil {
+		r.logger.Error("reconciler: domain status check failed", "error", err, "domain", d.Hostname)
+		return
+	}
+
+	if result.Status != d.Status || result.CertificateReady != d.CertificateReady || result.Message != d.Message {
+		if err := r.domains.UpdateDomainStatus(ctx, d.ID, result.Status, result.CertificateReady, result.Message); err != nil {
+			r.logger.Error("reconciler: update domain status failed", "error", err, "domain", d.Hostname)
+			return
+		}
+		r.logger.Info("reconciler: domain status updated", "domain", d.Hostname, "status", result.Status, "certificate_ready", result.CertificateReady)
+	}
 }
@@ -2,10 +2,15 @@ package reconciler
 
 import (
 	"context"
+	"errors"
 	"log/slog"
+	"slices"
+	"strings"
+	"sync"
 	"testing"
 	"time"
 
+	"github.com/max-cloud/api/internal/auth"
 	"github.com/max-cloud/api/internal/orchestrator"
 	"github.com/max-cloud/api/internal/store"
 	"github.com/max-cloud/shared/pkg/models"
@@ -14,7 +19,7 @@ import (
 func TestReconcilePendingToReady(t *testing.T) {
 	st := store.NewMemory()
 	orch := orchestrator.NewNoop(slog.Default())
-	rec := New(slog.Default(), st, orch, time.Second)
+	rec := New(slog.Default(), st, st, st, st, orch, time.Second, 1, GCOptions{})
 	ctx := context.Background()
 
 	svc, err := st.Create(ctx, models.DeployRequest{Name: "myapp", Image: "nginx:latest"})
@@ -39,10 +44,32 @@ func TestReconcilePendingToReady(t *testing.T) {
 	}
 }
 
+func TestReconcileRecordsTagURLs(t *testing.T) {
+	st := store.NewMemory()
+	orch := orchestrator.NewNoop(slog.Default())
+	rec := New(slog.Default(), st, st, st, st, orch, time.Second, 1, GCOptions{})
+	ctx := context.Background()
+
+	svc, err := st.Create(ctx, models.DeployRequest{Name: "myapp", Image: "nginx:latest", Tag: "candidate"})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	rec.RunOnce(ctx)
+
+	updated, err := st.Get(ctx, svc.ID)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if got := updated.TagURLs["candidate"]; got != "https://candidate-myapp.maxcloud.dev" {
+		t.Fatalf("expected candidate tag URL, got %q", got)
+	}
+}
+
 func TestReconcileDeleting(t *testing.T) {
 	st := store.NewMemory()
 	orch := orchestrator.NewNoop(slog.Default())
-	rec := New(slog.Default(), st, orch, time.Second)
+	rec := New(slog.Default(), st, st, st, st, orch, time.Second, 1, GCOptions{})
 	ctx := context.Background()
 
 	svc, err := st.Create(ctx, models.DeployRequest{Name: "myapp", Image: "nginx:latest"})
@@ -65,7 +92,7 @@ func TestReconcileDeleting(t *testing.T) {
 func TestReconcileSkipsReady(t *testing.T) {
 	st := store.NewMemory()
 	orch := orchestrator.NewNoop(slog.Default())
-	rec := New(slog.Default(), st, orch, time.Second)
+	rec := New(slog.Default(), st, st, st, st, orch, time.Second, 1, GCOptions{})
 	ctx := context.Background()
 
 	svc, err := st.Create(ctx, models.DeployRequest{Name: "myapp", Image: "nginx:latest"})
@@ -86,3 +113,514 @@ func TestReconcileSkipsReady(t *testing.T) {
 		t.Fatalf("expected ready, got %s", updated.Status)
 	}
 }
+
+func TestEnqueueServicesResyncsReadyOnlyOnDriftCheck(t *testing.T) {
+	st := store.NewMemory()
+	rec := New(slog.Default(), st, st, st, st, orchestrator.NewNoop(slog.Default()), time.Second, 1, GCOptions{})
+	ctx := context.Background()
+
+	if _, err := st.Create(ctx, models.DeployRequest{Name: "pending", Image: "nginx:latest"}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	for _, status := range []models.ServiceStatus{models.ServiceStatusReady, models.ServiceStatusFailed} {
+		svc, err := st.Create(ctx, models.DeployRequest{Name: string(status), Image: "nginx:latest"})
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if err := st.UpdateStatus(ctx, svc.ID, status, ""); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+	}
+
+	// Der normale Tick fragt ausgerollte Services nicht beim Orchestrator ab
+	rec.enqueueServices(ctx, false)
+	if rec.queue.Len() != 1 {
+		t.Fatalf("expected only the pending service queued, got %d", rec.queue.Len())
+	}
+
+	rec.enqueueServices(ctx, true)
+	if rec.queue.Len() != 3 {
+		t.Fatalf("expected all services queued on resync, got %d", rec.queue.Len())
+	}
+}
+
+// recordingOrchestrator zeichnet Deploy-Aufrufe auf und merkt sich die zuletzt ausgerollte Generation.
+type recordingOrchestrator struct {
+	*orchestrator.NoopOrchestrator
+	mu       sync.Mutex
+	deployed map[string]int64
+	deploys  int
+}
+
+func newRecordingOrchestrator() *recordingOrchestrator {
+	return &recordingOrchestrator{
+		NoopOrchestrator: orchestrator.NewNoop(slog.Default()),
+		deployed:         make(map[string]int64),
+	}
+}
+
+func (o *recordingOrchestrator) Deploy(ctx context.Context, svc models.Service) (*orchestrator.DeployResult, error) {
+	o.mu.Lock()
+	o.deployed[svc.ID] = svc.Generation
+	o.deploys++
+	o.mu.Unlock()
+	return o.NoopOrchestrator.Deploy(ctx, svc)
+}
+
+func (o *recordingOrchestrator) Status(ctx context.Context, svc models.Service) (*orchestrator.DeployResult, error) {
+	o.mu.Lock()
+	gen, ok := o.deployed[svc.ID]
+	o.mu.Unlock()
+	if !ok {
+		return nil, orchestrator.ErrNotFound
+	}
+	result, err := o.NoopOrchestrator.Status(ctx, svc)
+	if err != nil {
+		return nil, err
+	}
+	result.Generation = gen
+	return result, nil
+}
+
+func TestReconcileRedeploysUpdatedSpec(t *testing.T) {
+	st := store.NewMemory()
+	orch := newRecordingOrchestrator()
+	rec := New(slog.Default(), st, st, st, st, orch, time.Second, 1, GCOptions{})
+	ctx := context.Background()
+
+	svc, err := st.Create(ctx, models.DeployRequest{Name: "myapp", Image: "nginx:1.25"})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	rec.RunOnce(ctx) // Deploy
+	rec.RunOnce(ctx) // Status → ready
+
+	current, err := st.Get(ctx, svc.ID)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if current.Status != models.ServiceStatusReady {
+		t.Fatalf("expected ready, got %s", current.Status)
+	}
+
+	current.Image = "nginx:1.27"
+	updated, err := st.Update(ctx, current)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	rec.RunOnce(ctx) // Redeploy der neuen Generation
+
+	if orch.deploys != 2 {
+		t.Fatalf("expected 2 deploys, got %d", orch.deploys)
+	}
+	if orch.deployed[svc.ID] != updated.Generation {
+		t.Fatalf("expected generation %d deployed, got %d", updated.Generation, orch.deployed[svc.ID])
+	}
+
+	rec.RunOnce(ctx) // Status → ready
+
+	final, err := st.Get(ctx, svc.ID)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if final.Status != models.ServiceStatusReady {
+		t.Fatalf("expected ready after redeploy, got %s", final.Status)
+	}
+}
+
+func TestReconcileAppliesSecrets(t *testing.T) {
+	st := store.NewMemory()
+	orch := newRecordingOrchestrator()
+	rec := New(slog.Default(), st, st, st, st, orch, time.Second, 1, GCOptions{})
+	ctx := auth.WithTenant(context.Background(), "org-1", "user-1")
+
+	if _, err := st.SetSecret(ctx, "org-1", "db-password", "hunter2"); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	svc, err := st.Create(ctx, models.DeployRequest{
+		Name:      "myapp",
+		Image:     "nginx:latest",
+		SecretEnv: map[string]string{"DB_PASSWORD": "db-password", "PGPASSWORD": "db-password"},
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	rec.RunOnce(context.Background())
+
+	if orch.deploys != 1 {
+		t.Fatalf("expected 1 deploy, got %d", orch.deploys)
+	}
+	if got := orch.Secrets(svc.ID); len(got) != 1 || got["db-password"] != "hunter2" {
+		t.Fatalf("expected decrypted secret handed to orchestrator, got %v", got)
+	}
+
+	// Fehlt ein referenziertes Secret, wird nicht deployt
+	if err := st.DeleteSecret(ctx, "org-1", "db-password"); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	current, _ := st.Get(ctx, svc.ID)
+	current.Image = "nginx:1.27"
+	if _, err := st.Update(ctx, current); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	rec.RunOnce(context.Background())
+
+	if orch.deploys != 1 {
+		t.Fatalf("expected no deploy with missing secret, got %d deploys", orch.deploys)
+	}
+}
+
+func TestReconcileDomains(t *testing.T) {
+	st := store.NewMemory()
+	orch := orchestrator.NewNoop(slog.Default())
+	rec := New(slog.Default(), st, st, st, st, orch, time.Second, 1, GCOptions{})
+	ctx := auth.WithTenant(context.Background(), "org-1", "user-1")
+
+	svc, err := st.Create(ctx, models.DeployRequest{Name: "myapp", Image: "nginx:latest"})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	d, err := st.CreateDomain(ctx, models.CreateDomainRequest{Hostname: "www.example.com", ServiceID: svc.ID})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	// Unverifizierte Domains werden nicht gemappt
+	rec.RunOnce(context.Background())
+	if _, err := orch.DomainStatus(ctx, d); err != orchestrator.ErrNotFound {
+		t.Fatalf("expected no mapping for unverified domain, got %v", err)
+	}
+
+	if _, err := st.VerifyDomain(ctx, d.ID); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	// Erster Durchlauf legt das Mapping an, der zweite übernimmt den Zustand
+	rec.RunOnce(context.Background())
+	rec.RunOnce(context.Background())
+
+	updated, err := st.GetDomain(ctx, d.ID)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if updated.Status != models.DomainStatusReady || !updated.CertificateReady {
+		t.Fatalf("expected ready domain with certificate, got %+v", updated)
+	}
+}
+
+// watchingOrchestrator meldet Service-IDs aus einem Channel wie ein Watch auf den Cluster.
+type watchingOrchestrator struct {
+	*orchestrator.NoopOrchestrator
+	events chan string
+}
+
+func (o *watchingOrchestrator) Watch(ctx context.Context, notify func(serviceID string)) error {
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case id := <-o.events:
+			notify(id)
+		}
+	}
+}
+
+func TestRunReconcilesWatchEvents(t *testing.T) {
+	st := store.NewMemory()
+	orch := &watchingOrchestrator{NoopOrchestrator: orchestrator.NewNoop(slog.Default()), events: make(chan string)}
+	// Das Intervall ist so lang, dass nur das Watch-Ereignis den Abgleich auslösen kann
+	rec := New(slog.Default(), st, st, st, st, orch, time.Hour, 1, GCOptions{})
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	svc, err := st.Create(ctx, models.DeployRequest{Name: "myapp", Image: "nginx:latest"})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	go rec.Run(ctx)
+	orch.events <- svc.ID
+
+	deadline := time.Now().Add(5 * time.Second)
+	for {
+		updated, err := st.Get(ctx, svc.ID)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if updated.Status == models.ServiceStatusReady {
+			break
+		}
+		if time.Now().After(deadline) {
+			t.Fatalf("expected ready after watch event, got %s", updated.Status)
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+}
+
+// failingOrchestrator lässt Deploy fehlschlagen, solange fail gesetzt ist.
+type failingOrchestrator struct {
+	*recordingOrchestrator
+	fail bool
+}
+
+func (o *failingOrchestrator) Deploy(ctx context.Context, svc models.Service) (*orchestrator.DeployResult, error) {
+	if o.fail {
+		o.mu.Lock()
+		o.deploys++
+		o.mu.Unlock()
+		return nil, errors.New("image pull failed")
+	}
+	return o.recordingOrchestrator.Deploy(ctx, svc)
+}
+
+func TestReconcileBacksOffFailingDeploy(t *testing.T) {
+	st := store.NewMemory()
+	orch := &failingOrchestrator{recordingOrchestrator: newRecordingOrchestrator(), fail: true}
+	rec := New(slog.Default(), st, st, st, st, orch, time.Second, 1, GCOptions{})
+	ctx := context.Background()
+
+	svc, err := st.Create(ctx, models.DeployRequest{Name: "myapp", Image: "nginx:latest"})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	rec.RunOnce(ctx)
+	rec.RunOnce(ctx)
+
+	// Der zweite Durchlauf fällt in den Backoff und versucht keinen erneuten Deploy
+	if orch.deploys != 1 {
+		t.Fatalf("expected 1 deploy attempt, got %d", orch.deploys)
+	}
+	failed, _ := st.Get(ctx, svc.ID)
+	if failed.ReconcileAttempts != 1 {
+		t.Fatalf("expected 1 recorded attempt, got %d", failed.ReconcileAttempts)
+	}
+	if !strings.Contains(failed.LastError, "image pull failed") {
+		t.Fatalf("expected last error to be recorded, got %q", failed.LastError)
+	}
+	events, _ := st.ListEvents(ctx, svc.ID, 10)
+	if len(events) != 1 || events[0].Type != models.ServiceEventFailed {
+		t.Fatalf("expected a single failed event, got %+v", events)
+	}
+
+	// Nach Ablauf des Backoffs gelingt der Deploy und der Fehlerzustand wird zurückgesetzt
+	orch.fail = false
+	rec.reconcileService(ctx, svc.ID)
+
+	recovered, _ := st.Get(ctx, svc.ID)
+	if recovered.ReconcileAttempts != 0 || recovered.LastError != "" {
+		t.Fatalf("expected reconcile state to be reset, got %d / %q", recovered.ReconcileAttempts, recovered.LastError)
+	}
+	if rec.queue.NumRequeues(svc.ID) != 0 {
+		t.Fatalf("expected backoff to be reset, got %d requeues", rec.queue.NumRequeues(svc.ID))
+	}
+}
+
+// crashingOrchestrator meldet jeden Service als fehlgeschlagen, wie Knative bei einem Crash-Loop.
+type crashingOrchestrator struct {
+	*orchestrator.NoopOrchestrator
+}
+
+func (o *crashingOrchestrator) Status(ctx context.Context, svc models.Service) (*orchestrator.DeployResult, error) {
+	return &orchestrator.DeployResult{
+		Status:     models.ServiceStatusFailed,
+		Generation: svc.Generation,
+		Reason:     "RevisionFailed",
+		Message:    "Container failed with: exit code 1",
+	}, nil
+}
+
+func TestReconcileRecordsFailureReason(t *testing.T) {
+	st := store.NewMemory()
+	orch := &crashingOrchestrator{NoopOrchestrator: orchestrator.NewNoop(slog.Default())}
+	rec := New(slog.Default(), st, st, st, st, orch, time.Second, 1, GCOptions{})
+	ctx := context.Background()
+
+	svc, err := st.Create(ctx, models.DeployRequest{Name: "myapp", Image: "nginx:latest"})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	rec.RunOnce(ctx)
+
+	updated, err := st.Get(ctx, svc.ID)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if updated.Status != models.ServiceStatusFailed {
+		t.Fatalf("expected failed, got %s", updated.Status)
+	}
+	if updated.StatusReason != "RevisionFailed" || updated.StatusMessage != "Container failed with: exit code 1" {
+		t.Fatalf("expected failure reason to be recorded, got %q / %q", updated.StatusReason, updated.StatusMessage)
+	}
+}
+
+func TestReconcileRecordsEvents(t *testing.T) {
+	st := store.NewMemory()
+	orch := newRecordingOrchestrator()
+	rec := New(slog.Default(), st, st, st, st, orch, time.Second, 1, GCOptions{})
+	ctx := context.Background()
+
+	svc, err := st.Create(ctx, models.DeployRequest{Name: "myapp", Image: "nginx:latest"})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	// Erster Durchlauf rollt aus, der zweite übernimmt den Status
+	rec.RunOnce(ctx)
+	rec.RunOnce(ctx)
+
+	events, err := st.ListEvents(ctx, svc.ID, 10)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(events) != 2 {
+		t.Fatalf("expected 2 events, got %+v", events)
+	}
+	if events[1].Type != models.ServiceEventDeployed {
+		t.Fatalf("expected deployed event, got %s", events[1].Type)
+	}
+	if events[0].Type != models.ServiceEventStatusChanged || events[0].Message != "status changed from pending to ready" {
+		t.Fatalf("expected status change event, got %+v", events[0])
+	}
+}
+
+func TestReconcileRedeploysOnDrift(t *testing.T) {
+	st := store.NewMemory()
+	orch := newRecordingOrchestrator()
+	rec := New(slog.Default(), st, st, st, st, orch, time.Second, 1, GCOptions{})
+	ctx := context.Background()
+
+	svc, err := st.Create(ctx, models.DeployRequest{Name: "myapp", Image: "nginx:latest"})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	rec.RunOnce(ctx)
+	rec.RunOnce(ctx)
+	if ready, _ := st.Get(ctx, svc.ID); ready.Status != models.ServiceStatusReady {
+		t.Fatalf("expected ready, got %s", ready.Status)
+	}
+
+	// Ohne Drift wird ein bereiter Service nicht erneut ausgerollt
+	rec.RunOnce(ctx)
+	if orch.deploys != 1 {
+		t.Fatalf("expected 1 deploy without drift, got %d", orch.deploys)
+	}
+
+	// Jemand löscht den Knative Service im Cluster
+	orch.mu.Lock()
+	delete(orch.deployed, svc.ID)
+	orch.mu.Unlock()
+
+	rec.RunOnce(ctx)
+
+	if orch.deploys != 2 {
+		t.Fatalf("expected redeploy after drift, got %d deploys", orch.deploys)
+	}
+	healed, _ := st.Get(ctx, svc.ID)
+	if healed.Status != models.ServiceStatusPending {
+		t.Fatalf("expected pending after redeploy, got %s", healed.Status)
+	}
+	events, _ := st.ListEvents(ctx, svc.ID, 10)
+	if len(events) < 2 || events[1].Type != models.ServiceEventDrift {
+		t.Fatalf("expected drift event, got %+v", events)
+	}
+}
+
+func TestCollectGarbageRemovesOrphans(t *testing.T) {
+	st := store.NewMemory()
+	orch := orchestrator.NewNoop(slog.Default())
+	rec := New(slog.Default(), st, st, st, st, orch, time.Second, 1, GCOptions{GracePeriod: time.Hour, DryRun: true})
+	ctx := context.Background()
+
+	svc, err := st.Create(ctx, models.DeployRequest{Name: "myapp", Image: "nginx:latest"})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if _, err := orch.Deploy(ctx, svc); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	// Absturz nach dem Deploy: der Service wurde nie im Store gespeichert
+	if _, err := orch.Deploy(ctx, models.Service{ID: "ghost-id", Name: "ghost", Image: "nginx:latest"}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	managed := func() []string {
+		resources, _ := orch.ListManaged(ctx)
+		var names []string
+		for _, res := range resources {
+			names = append(names, res.Name)
+		}
+		slices.Sort(names)
+		return names
+	}
+
+	// Innerhalb der Grace Period bleibt die Ressource bestehen
+	rec.CollectGarbage(ctx)
+	if got := managed(); !slices.Equal(got, []string{"ghost", "myapp"}) {
+		t.Fatalf("expected orphan to survive grace period, got %v", got)
+	}
+
+	// Im Dry-Run wird die Ressource nach Ablauf der Grace Period nur gemeldet
+	rec.orphans["default/ghost"] = time.Now().Add(-2 * time.Hour)
+	rec.CollectGarbage(ctx)
+	if got := managed(); !slices.Equal(got, []string{"ghost", "myapp"}) {
+		t.Fatalf("expected dry-run to keep orphan, got %v", got)
+	}
+
+	rec.gc.DryRun = false
+	rec.CollectGarbage(ctx)
+	if got := managed(); !slices.Equal(got, []string{"myapp"}) {
+		t.Fatalf("expected orphan to be removed, got %v", got)
+	}
+	if len(rec.orphans) != 0 {
+		t.Fatalf("expected orphan tracking to be cleared, got %v", rec.orphans)
+	}
+	if _, err := st.Get(ctx, svc.ID); err != nil {
+		t.Fatalf("expected service to remain: %v", err)
+	}
+}
+
+// namespaceOrchestrator zeichnet auf, für welche Organisationen Namespaces bereitgestellt wurden.
+type namespaceOrchestrator struct {
+	*recordingOrchestrator
+	namespaces []string
+}
+
+func (o *namespaceOrchestrator) CreateNamespace(_ context.Context, orgID string) error {
+	o.mu.Lock()
+	o.namespaces = append(o.namespaces, orgID)
+	o.mu.Unlock()
+	return nil
+}
+
+func TestReconcileProvisionsNamespaceBeforeDeploy(t *testing.T) {
+	st := store.NewMemory()
+	orch := &namespaceOrchestrator{recordingOrchestrator: newRecordingOrchestrator()}
+	rec := New(slog.Default(), st, st, st, st, orch, time.Second, 1, GCOptions{})
+	ctx := context.Background()
+
+	_, org, _, err := st.Register(ctx, "test@example.com", "TestOrg")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if _, err := st.Create(auth.WithTenant(ctx, org.ID, ""), models.DeployRequest{Name: "myapp", Image: "nginx:latest"}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	rec.RunOnce(ctx)
+	if orch.deploys != 1 || !slices.Equal(orch.namespaces, []string{org.ID}) {
+		t.Fatalf("expected namespace before deploy, got namespaces %v and %d deploys", orch.namespaces, orch.deploys)
+	}
+
+	// Der periodische Abgleich erneuert die Namespaces aller Organisationen mit Services
+	rec.reconcileNamespaces(ctx)
+	if !slices.Equal(orch.namespaces, []string{org.ID, org.ID}) {
+		t.Fatalf("expected namespace resync, got %v", orch.namespaces)
+	}
+}
@@ -0,0 +1,37 @@
+package registry
+
+import (
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+)
+
+// Access beschreibt eine Berechtigung im Docker-Token-Format, z.B.
+// {"type": "repository", "name": "<org>/app", "actions": ["pull"]}.
+type Access = map[string]interface{}
+
+// SignToken stellt ein mit secret signiertes Registry-Token für orgID aus, das bis
+// now+expiry für audience (den Registry-Dienst) mit den angegebenen Berechtigungen gilt.
+func SignToken(secret, orgID, audience string, access []Access, now time.Time, expiry time.Duration) (string, error) {
+	token := jwt.NewWithClaims(jwt.SigningMethodHS256, jwt.MapClaims{
+		"iss":    "max-cloud",
+		"sub":    orgID,
+		"aud":    audience,
+		"exp":    now.Add(expiry).Unix(),
+		"nbf":    now.Unix(),
+		"iat":    now.Unix(),
+		"access": access,
+	})
+	return token.SignedString([]byte(secret))
+}
+
+// OrgPullAccess erlaubt das Pullen aller Repositories einer Organisation.
+func OrgPullAccess(orgID string) []Access {
+	return []Access{
+		{
+			"type":    "repository",
+			"name":    orgID + "/*",
+			"actions": []string{"pull"},
+		},
+	}
+}
@@ -13,6 +13,7 @@ import (
 	"github.com/max-cloud/api/internal/handler"
 	"github.com/max-cloud/api/internal/orchestrator"
 	"github.com/max-cloud/api/internal/store"
+	"github.com/max-cloud/shared/pkg/models"
 )
 
 // Server holds dependencies for the API server.
@@ -20,6 +21,9 @@ type Server struct {
 	logger              *slog.Logger
 	store               store.ServiceStore
 	authStore           store.AuthStore
+	secretStore         store.SecretStore
+	domainStore         store.DomainStore
+	eventStore          store.EventStore
 	orchestrator        orchestrator.Orchestrator
 	emailSender         email.Sender
 	inviteExpiry        time.Duration
@@ -28,14 +32,18 @@ type Server struct {
 	registryURL         string
 	registryJWTSecret   string
 	registryTokenExpiry time.Duration
+	metrics             http.Handler
 }
 
 // New creates a new Server.
-func New(logger *slog.Logger, st store.ServiceStore, authSt store.AuthStore, orch orchestrator.Orchestrator, emailSender email.Sender, inviteExpiry time.Duration, devMode bool, devOrgUID string, registryURL string, registryJWTSecret string, registryTokenExpiry time.Duration) *Server {
+func New(logger *slog.Logger, st store.ServiceStore, authSt store.AuthStore, secretSt store.SecretStore, domainSt store.DomainStore, eventSt store.EventStore, orch orchestrator.Orchestrator, emailSender email.Sender, inviteExpiry time.Duration, devMode bool, devOrgUID string, registryURL string, registryJWTSecret string, registryTokenExpiry time.Duration, metrics http.Handler) *Server {
 	return &Server{
 		logger:              logger,
 		store:               st,
 		authStore:           authSt,
+		secretStore:         secretSt,
+		domainStore:         domainSt,
+		eventStore:          eventSt,
 		orchestrator:        orch,
 		emailSender:         emailSender,
 		inviteExpiry:        inviteExpiry,
@@ -44,6 +52,7 @@ func New(logger *slog.Logger, st store.ServiceStore, authSt store.AuthStore, orc
 		registryURL:         registryURL,
 		registryJWTSecret:   registryJWTSecret,
 		registryTokenExpiry: registryTokenExpiry,
+		metrics:             metrics,
 	}
 }
 
@@ -55,9 +64,12 @@ func (s *Server) Router() http.Handler {
 	r.Use(middleware.RealIP)
 	r.Use(middleware.Recoverer)
 
-	h := handler.New(s.logger, s.store, s.authStore, s.orchestrator, s.emailSender, s.inviteExpiry, s.devMode, s.registryURL, s.registryJWTSecret, s.registryTokenExpiry)
+	h := handler.New(s.logger, s.store, s.authStore, s.secretStore, s.domainStore, s.eventStore, s.orchestrator, s.emailSender, s.inviteExpiry, s.devMode, s.registryURL, s.registryJWTSecret, s.registryTokenExpiry)
 
 	r.Get("/healthz", h.Health)
+	if s.metrics != nil {
+		r.Method(http.MethodGet, "/metrics", s.metrics)
+	}
 
 	r.Route("/api/v1", func(r chi.Router) {
 		// Öffentliche Routen
@@ -74,20 +86,55 @@ func (s *Server) Router() http.Handler {
 				r.Use(auth.Middleware(s.logger, s.authStore))
 			}
 
-			r.Get("/services", h.ListServices)
-			r.Post("/services", h.CreateService)
-			r.Get("/services/{id}", h.GetService)
-			r.Get("/services/{id}/logs", h.StreamLogs)
-			r.Delete("/services/{id}", h.DeleteService)
+			// Berechtigungen laut Rollenmatrix und Scopes des API-Keys
+			require := func(perm models.Permission) func(http.Handler) http.Handler {
+				return auth.Require(s.logger, s.authStore, perm)
+			}
+
+			r.With(require(models.PermissionRead)).Get("/services", h.ListServices)
+			r.With(require(models.PermissionDeploy)).Post("/services", h.CreateService)
+			r.With(require(models.PermissionRead)).Get("/services/{id}", h.GetService)
+			r.With(require(models.PermissionDeploy)).Patch("/services/{id}", h.UpdateService)
+			r.With(require(models.PermissionReadLogs)).Get("/services/{id}/logs", h.StreamLogs)
+			r.With(require(models.PermissionRead)).Get("/services/{id}/revisions", h.ListRevisions)
+			r.With(require(models.PermissionReadLogs)).Get("/services/{id}/events", h.ListServiceEvents)
+			r.With(require(models.PermissionDeploy)).Post("/services/{id}/rollback", h.RollbackService)
+			r.With(require(models.PermissionDeploy)).Put("/services/{id}/traffic", h.SetTraffic)
+			r.With(require(models.PermissionRead)).Get("/services/{id}/env", h.GetServiceEnv)
+			r.With(require(models.PermissionDeploy)).Patch("/services/{id}/env", h.UpdateServiceEnv)
+			r.With(require(models.PermissionDelete)).Delete("/services/{id}", h.DeleteService)
 
 			r.Post("/auth/api-keys", h.CreateAPIKey)
 			r.Get("/auth/api-keys", h.ListAPIKeys)
 			r.Delete("/auth/api-keys/{id}", h.DeleteAPIKey)
+			r.Post("/auth/api-keys/{id}/rotate", h.RotateAPIKey)
 			r.Get("/auth/status", h.AuthStatus)
+			r.With(require(models.PermissionManageOrg)).Delete("/auth/org", h.DeleteOrganization)
+
+			r.With(require(models.PermissionManageMembers)).Post("/auth/invites", h.CreateInvite)
+			r.With(require(models.PermissionManageMembers)).Get("/auth/invites", h.ListInvites)
+			r.With(require(models.PermissionManageMembers)).Delete("/auth/invites/{id}", h.RevokeInvite)
+
+			r.With(require(models.PermissionRead)).Get("/org/members", h.ListMembers)
+			r.With(require(models.PermissionManageMembers)).Patch("/org/members/{id}", h.UpdateMemberRole)
+			r.With(require(models.PermissionManageMembers)).Delete("/org/members/{id}", h.RemoveMember)
+
+			r.With(require(models.PermissionRead)).Get("/org/service-accounts", h.ListServiceAccounts)
+			r.With(require(models.PermissionManageMembers)).Post("/org/service-accounts", h.CreateServiceAccount)
+			r.With(require(models.PermissionRead)).Get("/org/service-accounts/{id}", h.GetServiceAccount)
+			r.With(require(models.PermissionManageMembers)).Patch("/org/service-accounts/{id}", h.UpdateServiceAccount)
+			r.With(require(models.PermissionManageMembers)).Delete("/org/service-accounts/{id}", h.DeleteServiceAccount)
+			r.With(require(models.PermissionManageMembers)).Post("/org/service-accounts/{id}/api-keys", h.CreateServiceAccountAPIKey)
+
+			r.With(require(models.PermissionRead)).Get("/secrets", h.ListSecrets)
+			r.With(require(models.PermissionManageSecrets)).Put("/secrets/{name}", h.SetSecret)
+			r.With(require(models.PermissionManageSecrets)).Delete("/secrets/{name}", h.DeleteSecret)
 
-			r.Post("/auth/invites", h.CreateInvite)
-			r.Get("/auth/invites", h.ListInvites)
-			r.Delete("/auth/invites/{id}", h.RevokeInvite)
+			r.With(require(models.PermissionRead)).Get("/domains", h.ListDomains)
+			r.With(require(models.PermissionDeploy)).Post("/domains", h.CreateDomain)
+			r.With(require(models.PermissionRead)).Get("/domains/{id}", h.GetDomain)
+			r.With(require(models.PermissionDeploy)).Post("/domains/{id}/verify", h.VerifyDomain)
+			r.With(require(models.PermissionDelete)).Delete("/domains/{id}", h.DeleteDomain)
 
 			r.Get("/registry/token", h.GetRegistryToken)
 		})
@@ -106,7 +153,8 @@ func (s *Server) devAuthMiddleware() func(http.Handler) http.Handler {
 				rawKey := strings.TrimPrefix(authHeader, "Bearer ")
 				info, err := s.authStore.ValidateAPIKey(r.Context(), rawKey)
 				if err == nil {
-					ctx := auth.WithTenant(r.Context(), info.OrgID, info.UserID)
+					ctx := auth.WithTenant(r.Context(), info.OrgID, info.OwnerID())
+					ctx = auth.WithAPIKey(ctx, info)
 					next.ServeHTTP(w, r.WithContext(ctx))
 					return
 				}
@@ -117,6 +165,7 @@ func (s *Server) devAuthMiddleware() func(http.Handler) http.Handler {
 				orgID = s.devOrgUID
 			}
 			ctx := auth.WithTenant(r.Context(), orgID, "dev-user")
+			ctx = auth.WithRole(ctx, models.OrgRoleOwner)
 			next.ServeHTTP(w, r.WithContext(ctx))
 		})
 	}
@@ -2,10 +2,13 @@ package store
 
 import (
 	"context"
+	"errors"
+	"slices"
 	"strings"
 	"testing"
 	"time"
 
+	"github.com/max-cloud/api/internal/auth"
 	"github.com/max-cloud/shared/pkg/models"
 )
 
@@ -35,6 +38,154 @@ func TestRegister(t *testing.T) {
 	}
 }
 
+func TestGetOrganization(t *testing.T) {
+	s := NewMemory()
+	ctx := context.Background()
+
+	_, org, _, err := s.Register(ctx, "test@example.com", "TestOrg")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	got, err := s.GetOrganization(ctx, org.ID)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if got.Name != "TestOrg" || got.MaxScaleLimit != models.DefaultMaxScaleLimit {
+		t.Fatalf("unexpected organization: %+v", got)
+	}
+
+	if _, err := s.GetOrganization(ctx, "nonexistent"); !errors.Is(err, ErrNotFound) {
+		t.Fatalf("expected ErrNotFound, got %v", err)
+	}
+}
+
+func TestDeleteOrganization(t *testing.T) {
+	s := NewMemory()
+	ctx := context.Background()
+
+	user, org, rawKey, err := s.Register(ctx, "test@example.com", "TestOrg")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	_, other, otherKey, err := s.Register(ctx, "other@example.com", "OtherOrg")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	tenant := auth.WithTenant(ctx, org.ID, user.ID)
+	svc, err := s.Create(tenant, models.DeployRequest{Name: "web", Image: "nginx:latest"})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if _, err := s.SetSecret(ctx, org.ID, "db-password", "hunter2"); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if _, err := s.CreateDomain(tenant, models.CreateDomainRequest{Hostname: "www.example.com", ServiceID: svc.ID}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if err := s.DeleteOrganization(ctx, org.ID); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if _, err := s.GetOrganization(ctx, org.ID); !errors.Is(err, ErrNotFound) {
+		t.Fatalf("expected organization to be deleted, got %v", err)
+	}
+	if _, err := s.Get(ctx, svc.ID); !errors.Is(err, ErrNotFound) {
+		t.Fatalf("expected service to be deleted, got %v", err)
+	}
+	if secrets, _ := s.ListSecrets(ctx, org.ID); len(secrets) != 0 {
+		t.Fatalf("expected secrets to be deleted, got %+v", secrets)
+	}
+	if domains, _ := s.ListDomains(ctx); len(domains) != 0 {
+		t.Fatalf("expected domains to be deleted, got %+v", domains)
+	}
+	if _, err := s.ValidateAPIKey(ctx, rawKey); err == nil {
+		t.Fatal("expected api key of deleted organization to be invalid")
+	}
+	if info, err := s.ValidateAPIKey(ctx, otherKey); err != nil || info.OrgID != other.ID {
+		t.Fatalf("expected api key of other organization to stay valid, got %v", err)
+	}
+
+	// Der Name ist wieder frei
+	if _, _, _, err := s.Register(ctx, "new@example.com", "TestOrg"); err != nil {
+		t.Fatalf("expected org name to be reusable, got %v", err)
+	}
+
+	if err := s.DeleteOrganization(ctx, org.ID); !errors.Is(err, ErrNotFound) {
+		t.Fatalf("expected ErrNotFound for deleted organization, got %v", err)
+	}
+}
+
+func TestMemberManagement(t *testing.T) {
+	s := NewMemory()
+	ctx := context.Background()
+
+	admin, org, adminKey, err := s.Register(ctx, "admin@example.com", "TestOrg")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	_, rawToken, err := s.CreateInvite(ctx, org.ID, "bob@example.com", models.OrgRoleDeveloper, admin.ID, time.Now().Add(time.Hour))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	bob, _, _, bobKey, err := s.AcceptInvite(ctx, rawToken)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	members, err := s.ListMembers(ctx, org.ID)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(members) != 2 || members[0].Email != "admin@example.com" || members[1].Email != "bob@example.com" {
+		t.Fatalf("unexpected members: %+v", members)
+	}
+	if members[1].Role != models.OrgRoleDeveloper {
+		t.Fatalf("expected role developer, got %s", members[1].Role)
+	}
+
+	// Der einzige Owner kann weder herabgestuft noch entfernt werden
+	if _, err := s.UpdateMemberRole(ctx, org.ID, admin.ID, models.OrgRoleDeveloper); !errors.Is(err, ErrLastOwner) {
+		t.Fatalf("expected ErrLastOwner, got %v", err)
+	}
+	if err := s.RemoveMember(ctx, org.ID, admin.ID); !errors.Is(err, ErrLastOwner) {
+		t.Fatalf("expected ErrLastOwner, got %v", err)
+	}
+
+	m, err := s.UpdateMemberRole(ctx, org.ID, bob.ID, models.OrgRoleOwner)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if m.Role != models.OrgRoleOwner || m.Email != "bob@example.com" {
+		t.Fatalf("unexpected member: %+v", m)
+	}
+	if _, err := s.UpdateMemberRole(ctx, org.ID, admin.ID, models.OrgRoleDeveloper); err != nil {
+		t.Fatalf("expected demotion with second owner to succeed, got %v", err)
+	}
+
+	if err := s.RemoveMember(ctx, org.ID, admin.ID); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if _, err := s.GetAuthInfo(ctx, org.ID, admin.ID); !errors.Is(err, ErrNotFound) {
+		t.Fatalf("expected removed member to have no access, got %v", err)
+	}
+	if _, err := s.ValidateAPIKey(ctx, adminKey); err == nil {
+		t.Fatal("expected api key of removed member to be revoked")
+	}
+	if _, err := s.ValidateAPIKey(ctx, bobKey); err != nil {
+		t.Fatalf("expected api key of remaining member to stay valid, got %v", err)
+	}
+
+	if err := s.RemoveMember(ctx, org.ID, admin.ID); !errors.Is(err, ErrMemberNotFound) {
+		t.Fatalf("expected ErrMemberNotFound, got %v", err)
+	}
+	if _, err := s.UpdateMemberRole(ctx, org.ID, admin.ID, models.OrgRoleAdmin); !errors.Is(err, ErrMemberNotFound) {
+		t.Fatalf("expected ErrMemberNotFound, got %v", err)
+	}
+}
+
 func TestRegisterDuplicateEmail(t *testing.T) {
 	s := NewMemory()
 	ctx := context.Background()
@@ -110,7 +261,7 @@ func TestCreateAPIKey(t *testing.T) {
 		t.Fatalf("unexpected error: %v", err)
 	}
 
-	rawKey, info, err := s.CreateAPIKey(ctx, org.ID, user.ID, "ci-key")
+	rawKey, info, err := s.CreateAPIKey(ctx, org.ID, user.ID, models.CreateAPIKeyRequest{Name: "ci-key"})
 	if err != nil {
 		t.Fatalf("unexpected error: %v", err)
 	}
@@ -131,6 +282,147 @@ func TestCreateAPIKey(t *testing.T) {
 	}
 }
 
+func TestCreateScopedAPIKey(t *testing.T) {
+	s := NewMemory()
+	ctx := context.Background()
+
+	user, org, _, err := s.Register(ctx, "test@example.com", "TestOrg")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	expires := time.Now().Add(time.Hour).Truncate(time.Second)
+	rawKey, _, err := s.CreateAPIKey(ctx, org.ID, user.ID, models.CreateAPIKeyRequest{
+		Name:      "ci-key",
+		Scopes:    []models.Permission{models.PermissionDeploy, models.PermissionPushImages},
+		ExpiresAt: &expires,
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	validated, err := s.ValidateAPIKey(ctx, rawKey)
+	if err != nil {
+		t.Fatalf("unexpected error validating new key: %v", err)
+	}
+	if !slices.Equal(validated.Scopes, []models.Permission{models.PermissionDeploy, models.PermissionPushImages}) {
+		t.Fatalf("unexpected scopes: %v", validated.Scopes)
+	}
+	if validated.ExpiresAt == nil || !validated.ExpiresAt.Equal(expires) {
+		t.Fatalf("expected expiry %v, got %v", expires, validated.ExpiresAt)
+	}
+
+	keys, err := s.ListAPIKeys(ctx, org.ID)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	for _, k := range keys {
+		if k.Name == "ci-key" && len(k.Scopes) != 2 {
+			t.Fatalf("expected scopes in list, got %+v", k)
+		}
+	}
+
+	// Abgelaufene Keys werden abgelehnt
+	past := time.Now().Add(-time.Minute)
+	expiredKey, _, err := s.CreateAPIKey(ctx, org.ID, user.ID, models.CreateAPIKeyRequest{Name: "old", ExpiresAt: &past})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if _, err := s.ValidateAPIKey(ctx, expiredKey); err == nil {
+		t.Fatal("expected expired key to be rejected")
+	}
+}
+
+func TestRotateAPIKey(t *testing.T) {
+	s := NewMemory()
+	ctx := context.Background()
+
+	user, org, _, err := s.Register(ctx, "test@example.com", "TestOrg")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	expires := time.Now().Add(time.Hour)
+	oldKey, oldInfo, err := s.CreateAPIKey(ctx, org.ID, user.ID, models.CreateAPIKeyRequest{
+		Name:      "ci-key",
+		Scopes:    []models.Permission{models.PermissionDeploy},
+		ExpiresAt: &expires,
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	newKey, newInfo, err := s.RotateAPIKey(ctx, org.ID, user.ID, oldInfo.ID, time.Minute)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if newInfo.ID == oldInfo.ID || newInfo.Name != "ci-key" || !slices.Equal(newInfo.Scopes, oldInfo.Scopes) {
+		t.Fatalf("unexpected replacement: %+v", newInfo)
+	}
+	if newInfo.ExpiresAt == nil || newInfo.ExpiresAt.Before(expires) {
+		t.Fatalf("expected replacement to keep the lifetime, got %v", newInfo.ExpiresAt)
+	}
+
+	// Beide Keys sind während der Übergangszeit gültig
+	if _, err := s.ValidateAPIKey(ctx, newKey); err != nil {
+		t.Fatalf("unexpected error validating new key: %v", err)
+	}
+	validated, err := s.ValidateAPIKey(ctx, oldKey)
+	if err != nil {
+		t.Fatalf("expected old key to stay valid: %v", err)
+	}
+	if validated.ExpiresAt == nil || validated.ExpiresAt.After(time.Now().Add(time.Minute)) {
+		t.Fatalf("expected old key to expire after the grace period, got %v", validated.ExpiresAt)
+	}
+	if validated.RotatedAt == nil {
+		t.Fatal("expected old key to be marked as rotated")
+	}
+	if _, _, err := s.RotateAPIKey(ctx, org.ID, user.ID, oldInfo.ID, time.Minute); !errors.Is(err, ErrKeyRotated) {
+		t.Fatalf("expected ErrKeyRotated for rotated key, got %v", err)
+	}
+
+	// Ohne Übergangszeit ist der alte Key sofort ungültig und kann nicht erneut rotiert werden
+	_, newest, err := s.RotateAPIKey(ctx, org.ID, user.ID, newInfo.ID, 0)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if _, err := s.ValidateAPIKey(ctx, newKey); err == nil {
+		t.Fatal("expected rotated key without grace period to be rejected")
+	}
+	if newest.ExpiresAt == nil || newest.ExpiresAt.Before(expires) {
+		t.Fatalf("expected successor of successor to keep the original lifetime, got %v", newest.ExpiresAt)
+	}
+	if _, _, err := s.RotateAPIKey(ctx, org.ID, user.ID, newInfo.ID, time.Minute); !errors.Is(err, ErrKeyNotFound) {
+		t.Fatalf("expected ErrKeyNotFound for expired key, got %v", err)
+	}
+	if _, _, err := s.RotateAPIKey(ctx, "other-org", user.ID, newest.ID, time.Minute); !errors.Is(err, ErrKeyNotFound) {
+		t.Fatalf("expected ErrKeyNotFound for foreign org, got %v", err)
+	}
+	if _, _, err := s.RotateAPIKey(ctx, org.ID, "other-user", newest.ID, time.Minute); !errors.Is(err, ErrKeyNotFound) {
+		t.Fatalf("expected ErrKeyNotFound for other owner, got %v", err)
+	}
+
+	// Keys ohne Ablauf bleiben über mehrere Rotationen ohne Ablauf
+	_, permanent, err := s.CreateAPIKey(ctx, org.ID, user.ID, models.CreateAPIKeyRequest{Name: "permanent"})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	_, successor, err := s.RotateAPIKey(ctx, org.ID, user.ID, permanent.ID, time.Minute)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	_, next, err := s.RotateAPIKey(ctx, org.ID, user.ID, successor.ID, time.Minute)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if successor.ExpiresAt != nil || next.ExpiresAt != nil {
+		t.Fatalf("expected successors without expiry, got %v and %v", successor.ExpiresAt, next.ExpiresAt)
+	}
+	if _, _, err := s.RotateAPIKey(ctx, org.ID, user.ID, permanent.ID, time.Minute); !errors.Is(err, ErrKeyRotated) {
+		t.Fatalf("expected ErrKeyRotated for rotated key, got %v", err)
+	}
+}
+
 func TestListAPIKeys(t *testing.T) {
 	s := NewMemory()
 	ctx := context.Background()
@@ -150,7 +442,7 @@ func TestListAPIKeys(t *testing.T) {
 	}
 
 	// Zweiten Key erstellen
-	if _, _, err := s.CreateAPIKey(ctx, org.ID, user.ID, "second"); err != nil {
+	if _, _, err := s.CreateAPIKey(ctx, org.ID, user.ID, models.CreateAPIKeyRequest{Name: "second"}); err != nil {
 		t.Fatalf("unexpected error: %v", err)
 	}
 
@@ -172,17 +464,30 @@ func TestDeleteAPIKey(t *testing.T) {
 		t.Fatalf("unexpected error: %v", err)
 	}
 
-	_, info, err := s.CreateAPIKey(ctx, org.ID, user.ID, "to-delete")
+	_, info, err := s.CreateAPIKey(ctx, org.ID, user.ID, models.CreateAPIKeyRequest{Name: "to-delete"})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	got, err := s.GetAPIKey(ctx, org.ID, info.ID)
 	if err != nil {
 		t.Fatalf("unexpected error: %v", err)
 	}
+	if got.Name != "to-delete" || got.UserID != user.ID {
+		t.Fatalf("unexpected api key: %+v", got)
+	}
+
+	// Ein fremder Besitzer darf den Key nicht löschen
+	if err := s.DeleteAPIKey(ctx, org.ID, "other-user", info.ID); err != ErrKeyNotFound {
+		t.Fatalf("expected ErrKeyNotFound for other owner, got %v", err)
+	}
 
-	if err := s.DeleteAPIKey(ctx, org.ID, info.ID); err != nil {
+	if err := s.DeleteAPIKey(ctx, org.ID, user.ID, info.ID); err != nil {
 		t.Fatalf("unexpected error: %v", err)
 	}
 
 	// Erneutes Löschen sollte ErrKeyNotFound geben
-	if err := s.DeleteAPIKey(ctx, org.ID, info.ID); err != ErrKeyNotFound {
+	if err := s.DeleteAPIKey(ctx, org.ID, user.ID, info.ID); err != ErrKeyNotFound {
 		t.Fatalf("expected ErrKeyNotFound, got %v", err)
 	}
 }
@@ -206,8 +511,8 @@ func TestGetAuthInfo(t *testing.T) {
 	if info.Organization.Name != "TestOrg" {
 		t.Fatalf("expected org TestOrg, got %s", info.Organization.Name)
 	}
-	if info.Role != "admin" {
-		t.Fatalf("expected role admin, got %s", info.Role)
+	if info.Role != models.OrgRoleOwner {
+		t.Fatalf("expected role owner, got %s", info.Role)
 	}
 }
 
@@ -221,7 +526,7 @@ func TestCreateInvite(t *testing.T) {
 	}
 
 	expires := time.Now().Add(7 * 24 * time.Hour)
-	invite, rawToken, err := s.CreateInvite(ctx, org.ID, "new@example.com", models.OrgRoleMember, user.ID, expires)
+	invite, rawToken, err := s.CreateInvite(ctx, org.ID, "new@example.com", models.OrgRoleDeveloper, user.ID, expires)
 	if err != nil {
 		t.Fatalf("unexpected error: %v", err)
 	}
@@ -232,8 +537,8 @@ func TestCreateInvite(t *testing.T) {
 	if invite.Email != "new@example.com" {
 		t.Fatalf("expected email new@example.com, got %s", invite.Email)
 	}
-	if invite.Role != models.OrgRoleMember {
-		t.Fatalf("expected role member, got %s", invite.Role)
+	if invite.Role != models.OrgRoleDeveloper {
+		t.Fatalf("expected role developer, got %s", invite.Role)
 	}
 	if invite.Status != models.InviteStatusPending {
 		t.Fatalf("expected status pending, got %s", invite.Status)
@@ -253,7 +558,7 @@ func TestCreateInviteAlreadyMember(t *testing.T) {
 	}
 
 	expires := time.Now().Add(7 * 24 * time.Hour)
-	_, _, err = s.CreateInvite(ctx, org.ID, "admin@example.com", models.OrgRoleMember, user.ID, expires)
+	_, _, err = s.CreateInvite(ctx, org.ID, "admin@example.com", models.OrgRoleDeveloper, user.ID, expires)
 	if err != ErrAlreadyMember {
 		t.Fatalf("expected ErrAlreadyMember, got %v", err)
 	}
@@ -269,7 +574,7 @@ func TestAcceptInvite(t *testing.T) {
 	}
 
 	expires := time.Now().Add(7 * 24 * time.Hour)
-	_, rawToken, err := s.CreateInvite(ctx, org.ID, "new@example.com", models.OrgRoleMember, admin.ID, expires)
+	_, rawToken, err := s.CreateInvite(ctx, org.ID, "new@example.com", models.OrgRoleDeveloper, admin.ID, expires)
 	if err != nil {
 		t.Fatalf("unexpected error: %v", err)
 	}
@@ -285,8 +590,8 @@ func TestAcceptInvite(t *testing.T) {
 	if retOrg.ID != org.ID {
 		t.Fatalf("expected org ID %s, got %s", org.ID, retOrg.ID)
 	}
-	if role != models.OrgRoleMember {
-		t.Fatalf("expected role member, got %s", role)
+	if role != models.OrgRoleDeveloper {
+		t.Fatalf("expected role developer, got %s", role)
 	}
 	if !strings.HasPrefix(apiKey, "mc_") {
 		t.Fatalf("expected api key to start with mc_, got %s", apiKey)
@@ -309,7 +614,7 @@ func TestAcceptInviteExistingUser(t *testing.T) {
 
 	// existing@example.com zu Org1 einladen
 	expires := time.Now().Add(7 * 24 * time.Hour)
-	_, rawToken, err := s.CreateInvite(ctx, org1.ID, "existing@example.com", models.OrgRoleMember, admin1.ID, expires)
+	_, rawToken, err := s.CreateInvite(ctx, org1.ID, "existing@example.com", models.OrgRoleDeveloper, admin1.ID, expires)
 	if err != nil {
 		t.Fatalf("unexpected error: %v", err)
 	}
@@ -325,8 +630,8 @@ func TestAcceptInviteExistingUser(t *testing.T) {
 	if retOrg.ID != org1.ID {
 		t.Fatalf("expected org ID %s, got %s", org1.ID, retOrg.ID)
 	}
-	if role != models.OrgRoleMember {
-		t.Fatalf("expected role member, got %s", role)
+	if role != models.OrgRoleDeveloper {
+		t.Fatalf("expected role developer, got %s", role)
 	}
 }
 
@@ -341,7 +646,7 @@ func TestAcceptInviteExpired(t *testing.T) {
 
 	// Einladung die bereits abgelaufen ist
 	expires := time.Now().Add(-1 * time.Hour)
-	_, rawToken, err := s.CreateInvite(ctx, org.ID, "new@example.com", models.OrgRoleMember, admin.ID, expires)
+	_, rawToken, err := s.CreateInvite(ctx, org.ID, "new@example.com", models.OrgRoleDeveloper, admin.ID, expires)
 	if err != nil {
 		t.Fatalf("unexpected error: %v", err)
 	}
@@ -372,7 +677,7 @@ func TestAcceptInviteAlreadyUsed(t *testing.T) {
 	}
 
 	expires := time.Now().Add(7 * 24 * time.Hour)
-	_, rawToken, err := s.CreateInvite(ctx, org.ID, "new@example.com", models.OrgRoleMember, admin.ID, expires)
+	_, rawToken, err := s.CreateInvite(ctx, org.ID, "new@example.com", models.OrgRoleDeveloper, admin.ID, expires)
 	if err != nil {
 		t.Fatalf("unexpected error: %v", err)
 	}
@@ -409,7 +714,7 @@ func TestListInvites(t *testing.T) {
 
 	// Einladung erstellen
 	expires := time.Now().Add(7 * 24 * time.Hour)
-	if _, _, err := s.CreateInvite(ctx, org.ID, "a@example.com", models.OrgRoleMember, admin.ID, expires); err != nil {
+	if _, _, err := s.CreateInvite(ctx, org.ID, "a@example.com", models.OrgRoleDeveloper, admin.ID, expires); err != nil {
 		t.Fatalf("unexpected error: %v", err)
 	}
 	if _, _, err := s.CreateInvite(ctx, org.ID, "b@example.com", models.OrgRoleAdmin, admin.ID, expires); err != nil {
@@ -435,7 +740,7 @@ func TestRevokeInvite(t *testing.T) {
 	}
 
 	expires := time.Now().Add(7 * 24 * time.Hour)
-	invite, _, err := s.CreateInvite(ctx, org.ID, "new@example.com", models.OrgRoleMember, admin.ID, expires)
+	invite, _, err := s.CreateInvite(ctx, org.ID, "new@example.com", models.OrgRoleDeveloper, admin.ID, expires)
 	if err != nil {
 		t.Fatalf("unexpected error: %v", err)
 	}
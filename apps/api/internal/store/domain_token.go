@@ -0,0 +1,21 @@
+package store
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"fmt"
+)
+
+const (
+	domainTokenPrefix    = "maxcloud-verify="
+	domainTokenRandBytes = 16
+)
+
+// generateDomainToken erzeugt den Wert für den TXT-Challenge-Record einer Domain.
+func generateDomainToken() (string, error) {
+	b := make([]byte, domainTokenRandBytes)
+	if _, err := rand.Read(b); err != nil {
+		return "", fmt.Errorf("generating random bytes: %w", err)
+	}
+	return domainTokenPrefix + hex.EncodeToString(b), nil
+}
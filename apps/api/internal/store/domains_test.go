@@ -0,0 +1,84 @@
+package store
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/max-cloud/api/internal/auth"
+	"github.com/max-cloud/shared/pkg/models"
+)
+
+func TestDomains(t *testing.T) {
+	s := NewMemory()
+	org1 := auth.WithTenant(context.Background(), "org-1", "user-1")
+	org2 := auth.WithTenant(context.Background(), "org-2", "user-2")
+
+	d, err := s.CreateDomain(org1, models.CreateDomainRequest{Hostname: "www.example.com", ServiceID: "svc-1"})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if d.Status != models.DomainStatusPendingVerification || d.VerifiedAt != nil {
+		t.Fatalf("expected unverified domain, got %+v", d)
+	}
+	if !strings.HasPrefix(d.VerificationToken, domainTokenPrefix) {
+		t.Fatalf("unexpected verification token %q", d.VerificationToken)
+	}
+
+	if _, err := s.CreateDomain(org1, models.CreateDomainRequest{Hostname: "www.example.com", ServiceID: "svc-2"}); !errors.Is(err, ErrDuplicateDomain) {
+		t.Fatalf("expected ErrDuplicateDomain within org, got %v", err)
+	}
+
+	// Eine andere Organisation darf den Hostnamen beanspruchen, aber nicht zusätzlich verifizieren
+	other, err := s.CreateDomain(org2, models.CreateDomainRequest{Hostname: "www.example.com", ServiceID: "svc-3"})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if other.VerificationToken == d.VerificationToken {
+		t.Fatal("expected distinct verification tokens")
+	}
+
+	if _, err := s.GetDomain(org2, d.ID); !errors.Is(err, ErrDomainNotFound) {
+		t.Fatalf("expected ErrDomainNotFound across tenants, got %v", err)
+	}
+
+	verified, err := s.VerifyDomain(org1, d.ID)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if verified.Status != models.DomainStatusProvisioning || verified.VerifiedAt == nil {
+		t.Fatalf("expected verified domain in provisioning, got %+v", verified)
+	}
+	if _, err := s.VerifyDomain(org2, other.ID); !errors.Is(err, ErrDuplicateDomain) {
+		t.Fatalf("expected ErrDuplicateDomain for second verification, got %v", err)
+	}
+
+	if err := s.UpdateDomainStatus(context.Background(), d.ID, models.DomainStatusReady, true, ""); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	// Erneutes Verifizieren setzt den Status nicht zurück
+	again, _ := s.VerifyDomain(org1, d.ID)
+	if again.Status != models.DomainStatusReady || !again.CertificateReady {
+		t.Fatalf("expected ready domain to stay ready, got %+v", again)
+	}
+
+	all, _ := s.ListDomains(context.Background())
+	if len(all) != 2 {
+		t.Fatalf("expected 2 domains without tenant, got %d", len(all))
+	}
+	own, _ := s.ListDomains(org1)
+	if len(own) != 1 || own[0].ID != d.ID {
+		t.Fatalf("expected only own domain, got %+v", own)
+	}
+
+	if err := s.DeleteDomain(org2, d.ID); !errors.Is(err, ErrDomainNotFound) {
+		t.Fatalf("expected ErrDomainNotFound across tenants, got %v", err)
+	}
+	if err := s.DeleteDomain(org1, d.ID); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if _, err := s.GetDomain(org1, d.ID); !errors.Is(err, ErrDomainNotFound) {
+		t.Fatalf("expected ErrDomainNotFound after delete, got %v", err)
+	}
+}
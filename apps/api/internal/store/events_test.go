@@ -0,0 +1,46 @@
+package store
+
+import (
+	"context"
+	"testing"
+
+	"github.com/max-cloud/shared/pkg/models"
+)
+
+func TestEvents(t *testing.T) {
+	s := NewMemory()
+	ctx := context.Background()
+
+	for _, typ := range []models.ServiceEventType{models.ServiceEventCreated, models.ServiceEventDeployed, models.ServiceEventStatusChanged} {
+		if err := s.RecordEvent(ctx, models.ServiceEvent{ServiceID: "svc-1", Type: typ, Message: string(typ)}); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+	}
+	if err := s.RecordEvent(ctx, models.ServiceEvent{ServiceID: "svc-2", Type: models.ServiceEventCreated}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	events, err := s.ListEvents(ctx, "svc-1", 10)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(events) != 3 {
+		t.Fatalf("expected 3 events, got %d", len(events))
+	}
+	if events[0].Type != models.ServiceEventStatusChanged || events[2].Type != models.ServiceEventCreated {
+		t.Fatalf("expected newest first, got %+v", events)
+	}
+	if events[0].ID == "" || events[0].CreatedAt.IsZero() {
+		t.Fatalf("expected id and timestamp to be set, got %+v", events[0])
+	}
+
+	limited, _ := s.ListEvents(ctx, "svc-1", 2)
+	if len(limited) != 2 || limited[0].Type != models.ServiceEventStatusChanged {
+		t.Fatalf("expected the 2 newest events, got %+v", limited)
+	}
+
+	none, _ := s.ListEvents(ctx, "unknown", 10)
+	if none == nil || len(none) != 0 {
+		t.Fatalf("expected empty list, got %+v", none)
+	}
+}
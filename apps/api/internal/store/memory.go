@@ -1,7 +1,10 @@
 package store
 
 import (
+	"cmp"
 	"context"
+	"maps"
+	"slices"
 	"sync"
 	"time"
 
@@ -12,8 +15,9 @@ import (
 
 // MemoryStore provides thread-safe in-memory storage for services.
 type MemoryStore struct {
-	mu       sync.RWMutex
-	services map[string]models.Service
+	mu        sync.RWMutex
+	services  map[string]models.Service
+	revisions map[string][]models.Revision // serviceID → revisions (älteste zuerst)
 
 	// Auth-Daten
 	orgs         map[string]models.Organization       // id → org
@@ -24,9 +28,20 @@ type MemoryStore struct {
 	emailIndex   map[string]string                    // email → userID
 	orgNameIndex map[string]bool                      // orgName → exists
 
+	// Service-Account-Daten
+	serviceAccounts map[string]models.ServiceAccount // id → service account
+
 	// Invite-Daten
 	invitations  map[string]models.Invitation  // inviteID → invitation
 	inviteTokens map[string][]inviteTokenEntry // tokenPrefix → entries
+
+	// Secret-Daten, verschlüsselt mit einem pro Prozess erzeugten Schlüssel
+	secrets      map[string]map[string]secretEntry // orgID → name → entry
+	secretCipher *SecretCipher
+
+	domains map[string]models.Domain // domainID → domain
+
+	events map[string][]models.ServiceEvent // serviceID → events (älteste zuerst)
 }
 
 type inviteTokenEntry struct {
@@ -39,19 +54,35 @@ type apiKeyEntry struct {
 	hash string
 }
 
-// NewMemory creates a new MemoryStore.
+// NewMemory creates a new MemoryStore. Secrets are encrypted with a random
+// key that lives as long as the store itself.
 func NewMemory() *MemoryStore {
+	key, err := GenerateSecretKey()
+	if err != nil {
+		panic(err)
+	}
+	secretCipher, err := NewSecretCipher(key)
+	if err != nil {
+		panic(err)
+	}
+
 	return &MemoryStore{
-		services:     make(map[string]models.Service),
-		orgs:         make(map[string]models.Organization),
-		users:        make(map[string]models.User),
-		orgMembers:   make(map[string]map[string]models.OrgRole),
-		apiKeys:      make(map[string][]apiKeyEntry),
-		apiKeysByID:  make(map[string]*apiKeyEntry),
-		emailIndex:   make(map[string]string),
-		orgNameIndex: make(map[string]bool),
-		invitations:  make(map[string]models.Invitation),
-		inviteTokens: make(map[string][]inviteTokenEntry),
+		services:        make(map[string]models.Service),
+		revisions:       make(map[string][]models.Revision),
+		orgs:            make(map[string]models.Organization),
+		users:           make(map[string]models.User),
+		orgMembers:      make(map[string]map[string]models.OrgRole),
+		apiKeys:         make(map[string][]apiKeyEntry),
+		apiKeysByID:     make(map[string]*apiKeyEntry),
+		emailIndex:      make(map[string]string),
+		orgNameIndex:    make(map[string]bool),
+		serviceAccounts: make(map[string]models.ServiceAccount),
+		invitations:     make(map[string]models.Invitation),
+		inviteTokens:    make(map[string][]inviteTokenEntry),
+		secrets:         make(map[string]map[string]secretEntry),
+		secretCipher:    secretCipher,
+		domains:         make(map[string]models.Domain),
+		events:          make(map[string][]models.ServiceEvent),
 	}
 }
 
@@ -75,18 +106,32 @@ func (s *MemoryStore) Create(ctx context.Context, req models.DeployRequest) (mod
 
 	now := time.Now()
 	svc := models.Service{
-		ID:        uuid.New().String(),
-		Name:      req.Name,
-		Image:     req.Image,
-		Status:    models.ServiceStatusPending,
-		Port:      req.Port,
-		Command:   req.Command,
-		Args:      req.Args,
-		EnvVars:   req.EnvVars,
-		MinScale:  0,
-		MaxScale:  10,
-		CreatedAt: now,
-		UpdatedAt: now,
+		ID:                          uuid.New().String(),
+		Name:                        req.Name,
+		Image:                       req.Image,
+		Status:                      models.ServiceStatusPending,
+		Port:                        req.Port,
+		Command:                     req.Command,
+		Args:                        req.Args,
+		EnvVars:                     req.EnvVars,
+		MinScale:                    req.MinScale,
+		MaxScale:                    cmp.Or(req.MaxScale, models.DefaultMaxScale),
+		CPU:                         cmp.Or(req.CPU, models.DefaultCPU),
+		Memory:                      cmp.Or(req.Memory, models.DefaultMemory),
+		Generation:                  1,
+		ContainerConcurrency:        req.ContainerConcurrency,
+		AutoscalingTarget:           req.AutoscalingTarget,
+		ScaleDownDelay:              req.ScaleDownDelay,
+		ScaleToZeroRetention:        req.ScaleToZeroRetention,
+		TimeoutSeconds:              req.TimeoutSeconds,
+		ResponseStartTimeoutSeconds: req.ResponseStartTimeoutSeconds,
+		IdleTimeoutSeconds:          req.IdleTimeoutSeconds,
+		Probes:                      req.Probes.Clone(),
+		SecretEnv:                   maps.Clone(req.SecretEnv),
+		LatestRevision:              models.RevisionName(req.Name, 1),
+		Traffic:                     initialTraffic(req),
+		CreatedAt:                   now,
+		UpdatedAt:                   now,
 	}
 
 	if hasOrgID {
@@ -94,6 +139,7 @@ func (s *MemoryStore) Create(ctx context.Context, req models.DeployRequest) (mod
 	}
 
 	s.services[svc.ID] = svc
+	s.appendRevision(svc)
 	return svc, nil
 }
 
@@ -169,6 +215,7 @@ func (s *MemoryStore) Delete(ctx context.Context, id string) error {
 	}
 
 	delete(s.services, id)
+	delete(s.revisions, id)
 	return nil
 }
 
@@ -189,3 +236,179 @@ func (s *MemoryStore) UpdateStatus(_ context.Context, id string, status models.S
 	s.services[id] = svc
 	return nil
 }
+
+// UpdateTagURLs speichert die Tag-URLs eines Services.
+func (s *MemoryStore) UpdateTagURLs(_ context.Context, id string, tagURLs map[string]string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	svc, ok := s.services[id]
+	if !ok {
+		return ErrNotFound
+	}
+	svc.TagURLs = maps.Clone(tagURLs)
+	svc.UpdatedAt = time.Now()
+	s.services[id] = svc
+	return nil
+}
+
+// UpdateStatusReason speichert Grund und Meldung des Status eines Services.
+func (s *MemoryStore) UpdateStatusReason(_ context.Context, id string, reason, message string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	svc, ok := s.services[id]
+	if !ok {
+		return ErrNotFound
+	}
+	svc.StatusReason = reason
+	svc.StatusMessage = message
+	svc.UpdatedAt = time.Now()
+	s.services[id] = svc
+	return nil
+}
+
+// UpdateReconcileState speichert Fehlversuche und letzten Fehler eines Services.
+func (s *MemoryStore) UpdateReconcileState(_ context.Context, id string, attempts int, lastError string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	svc, ok := s.services[id]
+	if !ok {
+		return ErrNotFound
+	}
+	svc.ReconcileAttempts = attempts
+	svc.LastError = lastError
+	s.services[id] = svc
+	return nil
+}
+
+// Update überschreibt die Spec eines Services, sofern die Generation übereinstimmt.
+func (s *MemoryStore) Update(ctx context.Context, svc models.Service) (models.Service, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	existing, ok := s.services[svc.ID]
+	if !ok {
+		return models.Service{}, ErrNotFound
+	}
+
+	if orgID, ok := auth.OrgIDFromContext(ctx); ok {
+		if existing.OrgID != orgID {
+			return models.Service{}, ErrNotFound
+		}
+	}
+
+	if existing.Generation != svc.Generation {
+		return models.Service{}, ErrConflict
+	}
+
+	existing.Image = svc.Image
+	existing.Port = svc.Port
+	existing.Command = svc.Command
+	existing.Args = svc.Args
+	existing.EnvVars = svc.EnvVars
+	existing.MinScale = svc.MinScale
+	existing.MaxScale = svc.MaxScale
+	existing.CPU = svc.CPU
+	existing.Memory = svc.Memory
+	existing.ContainerConcurrency = svc.ContainerConcurrency
+	existing.AutoscalingTarget = svc.AutoscalingTarget
+	existing.ScaleDownDelay = svc.ScaleDownDelay
+	existing.ScaleToZeroRetention = svc.ScaleToZeroRetention
+	existing.TimeoutSeconds = svc.TimeoutSeconds
+	existing.ResponseStartTimeoutSeconds = svc.ResponseStartTimeoutSeconds
+	existing.IdleTimeoutSeconds = svc.IdleTimeoutSeconds
+	existing.Probes = svc.Probes.Clone()
+	existing.SecretEnv = maps.Clone(svc.SecretEnv)
+	existing.Traffic = svc.Traffic
+	existing.Status = models.ServiceStatusPending
+	existing.Generation++
+	existing.LatestRevision = models.RevisionName(existing.Name, existing.Generation)
+	existing.UpdatedAt = time.Now()
+	s.services[svc.ID] = existing
+	s.appendRevision(existing)
+	return existing, nil
+}
+
+// SetTraffic setzt die Traffic-Aufteilung eines Services, sofern die Generation übereinstimmt.
+func (s *MemoryStore) SetTraffic(ctx context.Context, id string, generation int64, traffic []models.TrafficTarget) (models.Service, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	existing, ok := s.services[id]
+	if !ok {
+		return models.Service{}, ErrNotFound
+	}
+
+	if orgID, ok := auth.OrgIDFromContext(ctx); ok {
+		if existing.OrgID != orgID {
+			return models.Service{}, ErrNotFound
+		}
+	}
+
+	if existing.Generation != generation {
+		return models.Service{}, ErrConflict
+	}
+
+	existing.Traffic = slices.Clone(traffic)
+	existing.Status = models.ServiceStatusPending
+	existing.Generation++
+	existing.UpdatedAt = time.Now()
+	s.services[id] = existing
+	return existing, nil
+}
+
+// ListRevisions gibt die Revisionen eines Services zurück, neueste zuerst.
+func (s *MemoryStore) ListRevisions(ctx context.Context, serviceID string) ([]models.Revision, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	svc, ok := s.services[serviceID]
+	if !ok {
+		return nil, ErrNotFound
+	}
+
+	if orgID, ok := auth.OrgIDFromContext(ctx); ok {
+		if svc.OrgID != orgID {
+			return nil, ErrNotFound
+		}
+	}
+
+	revs := s.revisions[serviceID]
+	result := make([]models.Revision, 0, len(revs))
+	for i := len(revs) - 1; i >= 0; i-- {
+		result = append(result, revs[i])
+	}
+	return result, nil
+}
+
+// appendRevision legt einen Snapshot der aktuellen Spec an. Aufrufer muss s.mu halten.
+func (s *MemoryStore) appendRevision(svc models.Service) {
+	rev := models.Revision{
+		ID:                          uuid.New().String(),
+		ServiceID:                   svc.ID,
+		Name:                        models.RevisionName(svc.Name, svc.Generation),
+		Generation:                  svc.Generation,
+		Image:                       svc.Image,
+		Port:                        svc.Port,
+		Command:                     slices.Clone(svc.Command),
+		Args:                        slices.Clone(svc.Args),
+		EnvVars:                     maps.Clone(svc.EnvVars),
+		MinScale:                    svc.MinScale,
+		MaxScale:                    svc.MaxScale,
+		CPU:                         svc.CPU,
+		Memory:                      svc.Memory,
+		ContainerConcurrency:        svc.ContainerConcurrency,
+		AutoscalingTarget:           svc.AutoscalingTarget,
+		ScaleDownDelay:              svc.ScaleDownDelay,
+		ScaleToZeroRetention:        svc.ScaleToZeroRetention,
+		TimeoutSeconds:              svc.TimeoutSeconds,
+		ResponseStartTimeoutSeconds: svc.ResponseStartTimeoutSeconds,
+		IdleTimeoutSeconds:          svc.IdleTimeoutSeconds,
+		Probes:                      svc.Probes.Clone(),
+		SecretEnv:                   maps.Clone(svc.SecretEnv),
+		CreatedAt:                   svc.UpdatedAt,
+	}
+	s.revisions[svc.ID] = append(s.revisions[svc.ID], rev)
+}
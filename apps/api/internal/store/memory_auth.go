@@ -3,6 +3,8 @@ package store
 import (
 	"context"
 	"crypto/subtle"
+	"slices"
+	"strings"
 	"time"
 
 	"github.com/google/uuid"
@@ -24,9 +26,10 @@ func (s *MemoryStore) Register(_ context.Context, email, orgName string) (models
 	now := time.Now()
 
 	org := models.Organization{
-		ID:        uuid.New().String(),
-		Name:      orgName,
-		CreatedAt: now,
+		ID:            uuid.New().String(),
+		Name:          orgName,
+		MaxScaleLimit: models.DefaultMaxScaleLimit,
+		CreatedAt:     now,
 	}
 
 	user := models.User{
@@ -54,7 +57,7 @@ func (s *MemoryStore) Register(_ context.Context, email, orgName string) (models
 	// Alles speichern
 	s.orgs[org.ID] = org
 	s.users[user.ID] = user
-	s.orgMembers[org.ID] = map[string]models.OrgRole{user.ID: models.OrgRoleAdmin}
+	s.orgMembers[org.ID] = map[string]models.OrgRole{user.ID: models.OrgRoleOwner}
 	s.apiKeys[prefix] = append(s.apiKeys[prefix], entry)
 	s.apiKeysByID[keyInfo.ID] = &s.apiKeys[prefix][len(s.apiKeys[prefix])-1]
 	s.emailIndex[email] = user.ID
@@ -94,7 +97,7 @@ func (s *MemoryStore) ValidateAPIKey(_ context.Context, rawKey string) (*models.
 }
 
 // CreateAPIKey erstellt einen neuen API-Key für eine Organisation/User.
-func (s *MemoryStore) CreateAPIKey(_ context.Context, orgID, userID, name string) (string, *models.APIKeyInfo, error) {
+func (s *MemoryStore) CreateAPIKey(_ context.Context, orgID, userID string, req models.CreateAPIKeyRequest) (string, *models.APIKeyInfo, error) {
 	rawKey, keyHash, prefix, err := generateAPIKey()
 	if err != nil {
 		return "", nil, err
@@ -103,13 +106,17 @@ func (s *MemoryStore) CreateAPIKey(_ context.Context, orgID, userID, name string
 	s.mu.Lock()
 	defer s.mu.Unlock()
 
+	now := time.Now()
 	keyInfo := models.APIKeyInfo{
-		ID:        uuid.New().String(),
-		Prefix:    prefix,
-		Name:      name,
-		OrgID:     orgID,
-		UserID:    userID,
-		CreatedAt: time.Now(),
+		ID:              uuid.New().String(),
+		Prefix:          prefix,
+		Name:            req.Name,
+		OrgID:           orgID,
+		UserID:          userID,
+		Scopes:          slices.Clone(req.Scopes),
+		CreatedAt:       now,
+		ExpiresAt:       req.ExpiresAt,
+		LifetimeSeconds: models.APIKeyLifetimeSeconds(now, req.ExpiresAt),
 	}
 
 	entry := apiKeyEntry{info: keyInfo, hash: keyHash}
@@ -138,13 +145,26 @@ func (s *MemoryStore) ListAPIKeys(_ context.Context, orgID string) ([]models.API
 	return result, nil
 }
 
-// DeleteAPIKey löscht einen API-Key anhand seiner ID.
-func (s *MemoryStore) DeleteAPIKey(_ context.Context, orgID, keyID string) error {
+// GetAPIKey gibt einen API-Key anhand seiner ID zurück.
+func (s *MemoryStore) GetAPIKey(_ context.Context, orgID, keyID string) (*models.APIKeyInfo, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	entry, ok := s.apiKeysByID[keyID]
+	if !ok || entry.info.OrgID != orgID {
+		return nil, ErrKeyNotFound
+	}
+	info := entry.info
+	return &info, nil
+}
+
+// DeleteAPIKey löscht einen API-Key anhand seiner ID, sofern er ownerID gehört.
+func (s *MemoryStore) DeleteAPIKey(_ context.Context, orgID, ownerID, keyID string) error {
 	s.mu.Lock()
 	defer s.mu.Unlock()
 
 	entry, ok := s.apiKeysByID[keyID]
-	if !ok || entry.info.OrgID != orgID {
+	if !ok || entry.info.OrgID != orgID || entry.info.OwnerID() != ownerID {
 		return ErrKeyNotFound
 	}
 
@@ -161,7 +181,53 @@ func (s *MemoryStore) DeleteAPIKey(_ context.Context, orgID, keyID string) error
 	return nil
 }
 
-// GetAuthInfo gibt Informationen über den authentifizierten Benutzer zurück.
+// RotateAPIKey stellt einen Nachfolger des Keys von ownerID aus und verkürzt die Gültigkeit
+// des alten Keys auf gracePeriod. Jeder Key kann nur einmal rotiert werden.
+func (s *MemoryStore) RotateAPIKey(_ context.Context, orgID, ownerID, keyID string, gracePeriod time.Duration) (string, *models.APIKeyInfo, error) {
+	rawKey, keyHash, prefix, err := generateAPIKey()
+	if err != nil {
+		return "", nil, err
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	now := time.Now()
+	old, ok := s.apiKeysByID[keyID]
+	if !ok || old.info.OrgID != orgID || old.info.OwnerID() != ownerID || (old.info.ExpiresAt != nil && old.info.ExpiresAt.Before(now)) {
+		return "", nil, ErrKeyNotFound
+	}
+	if old.info.RotatedAt != nil {
+		return "", nil, ErrKeyRotated
+	}
+
+	keyInfo := models.APIKeyInfo{
+		ID:               uuid.New().String(),
+		Prefix:           prefix,
+		Name:             old.info.Name,
+		OrgID:            orgID,
+		UserID:           old.info.UserID,
+		ServiceAccountID: old.info.ServiceAccountID,
+		Scopes:           slices.Clone(old.info.Scopes),
+		CreatedAt:        now,
+		ExpiresAt:        old.info.RotatedExpiry(now),
+		LifetimeSeconds:  old.info.LifetimeSeconds,
+	}
+
+	old.info.RotatedAt = &now
+	graceEnd := now.Add(gracePeriod)
+	if old.info.ExpiresAt == nil || graceEnd.Before(*old.info.ExpiresAt) {
+		old.info.ExpiresAt = &graceEnd
+	}
+
+	entry := apiKeyEntry{info: keyInfo, hash: keyHash}
+	s.apiKeys[prefix] = append(s.apiKeys[prefix], entry)
+	s.apiKeysByID[keyInfo.ID] = &s.apiKeys[prefix][len(s.apiKeys[prefix])-1]
+
+	return rawKey, &keyInfo, nil
+}
+
+// GetAuthInfo gibt Informationen über den authentifizierten Benutzer oder Service-Account zurück.
 func (s *MemoryStore) GetAuthInfo(_ context.Context, orgID, userID string) (*models.AuthInfo, error) {
 	s.mu.RLock()
 	defer s.mu.RUnlock()
@@ -171,6 +237,10 @@ func (s *MemoryStore) GetAuthInfo(_ context.Context, orgID, userID string) (*mod
 		return nil, ErrNotFound
 	}
 
+	if sa, ok := s.serviceAccounts[userID]; ok && sa.OrgID == orgID {
+		return &models.AuthInfo{ServiceAccount: &sa, Organization: org, Role: sa.Role}, nil
+	}
+
 	user, ok := s.users[userID]
 	if !ok {
 		return nil, ErrNotFound
@@ -193,6 +263,149 @@ func (s *MemoryStore) GetAuthInfo(_ context.Context, orgID, userID string) (*mod
 	}, nil
 }
 
+// GetOrganization gibt eine Organisation anhand ihrer ID zurück.
+func (s *MemoryStore) GetOrganization(_ context.Context, orgID string) (models.Organization, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	org, ok := s.orgs[orgID]
+	if !ok {
+		return models.Organization{}, ErrNotFound
+	}
+	return org, nil
+}
+
+// DeleteOrganization löscht eine Organisation und alle ihr gehörenden Daten.
+func (s *MemoryStore) DeleteOrganization(_ context.Context, orgID string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	org, ok := s.orgs[orgID]
+	if !ok {
+		return ErrNotFound
+	}
+
+	for id, svc := range s.services {
+		if svc.OrgID == orgID {
+			delete(s.services, id)
+			delete(s.revisions, id)
+			delete(s.events, id)
+		}
+	}
+	for id, d := range s.domains {
+		if d.OrgID == orgID {
+			delete(s.domains, id)
+		}
+	}
+	delete(s.secrets, orgID)
+
+	s.deleteAPIKeysLocked(func(info models.APIKeyInfo) bool {
+		return info.OrgID == orgID
+	})
+
+	for id, inv := range s.invitations {
+		if inv.OrgID == orgID {
+			delete(s.invitations, id)
+		}
+	}
+
+	for id, sa := range s.serviceAccounts {
+		if sa.OrgID == orgID {
+			delete(s.serviceAccounts, id)
+		}
+	}
+
+	delete(s.orgMembers, orgID)
+	delete(s.orgNameIndex, org.Name)
+	delete(s.orgs, orgID)
+	return nil
+}
+
+// deleteAPIKeysLocked löscht alle API-Keys, auf die match zutrifft. s.mu muss gehalten werden.
+func (s *MemoryStore) deleteAPIKeysLocked(match func(info models.APIKeyInfo) bool) {
+	for keyID, entry := range s.apiKeysByID {
+		if !match(entry.info) {
+			continue
+		}
+		prefix := entry.info.Prefix
+		s.apiKeys[prefix] = slices.DeleteFunc(s.apiKeys[prefix], func(e apiKeyEntry) bool {
+			return e.info.ID == keyID
+		})
+		delete(s.apiKeysByID, keyID)
+	}
+	// Die Zeiger in apiKeysByID zeigen nach dem Löschen evtl. auf verschobene Einträge
+	for prefix, entries := range s.apiKeys {
+		for i := range entries {
+			s.apiKeysByID[entries[i].info.ID] = &s.apiKeys[prefix][i]
+		}
+	}
+}
+
+// ListMembers gibt alle Mitglieder einer Organisation zurück.
+func (s *MemoryStore) ListMembers(_ context.Context, orgID string) ([]models.OrgMember, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	members := []models.OrgMember{}
+	for userID, role := range s.orgMembers[orgID] {
+		members = append(members, models.OrgMember{UserID: userID, Email: s.users[userID].Email, Role: role})
+	}
+	slices.SortFunc(members, func(a, b models.OrgMember) int {
+		return strings.Compare(a.Email, b.Email)
+	})
+	return members, nil
+}
+
+// UpdateMemberRole ändert die Rolle eines Mitglieds.
+func (s *MemoryStore) UpdateMemberRole(_ context.Context, orgID, userID string, role models.OrgRole) (models.OrgMember, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	members := s.orgMembers[orgID]
+	current, ok := members[userID]
+	if !ok {
+		return models.OrgMember{}, ErrMemberNotFound
+	}
+	if current == models.OrgRoleOwner && role != models.OrgRoleOwner && s.countOwnersLocked(orgID) == 1 {
+		return models.OrgMember{}, ErrLastOwner
+	}
+
+	members[userID] = role
+	return models.OrgMember{UserID: userID, Email: s.users[userID].Email, Role: role}, nil
+}
+
+// RemoveMember entfernt ein Mitglied und löscht seine API-Keys in der Organisation.
+func (s *MemoryStore) RemoveMember(_ context.Context, orgID, userID string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	members := s.orgMembers[orgID]
+	role, ok := members[userID]
+	if !ok {
+		return ErrMemberNotFound
+	}
+	if role == models.OrgRoleOwner && s.countOwnersLocked(orgID) == 1 {
+		return ErrLastOwner
+	}
+
+	delete(members, userID)
+	s.deleteAPIKeysLocked(func(info models.APIKeyInfo) bool {
+		return info.OrgID == orgID && info.UserID == userID
+	})
+	return nil
+}
+
+// countOwnersLocked zählt die Owner einer Organisation. s.mu muss gehalten werden.
+func (s *MemoryStore) countOwnersLocked(orgID string) int {
+	owners := 0
+	for _, role := range s.orgMembers[orgID] {
+		if role == models.OrgRoleOwner {
+			owners++
+		}
+	}
+	return owners
+}
+
 // UpdateAPIKeyLastUsed aktualisiert den Zeitstempel der letzten Nutzung eines API-Keys.
 func (s *MemoryStore) UpdateAPIKeyLastUsed(_ context.Context, keyID string) error {
 	s.mu.Lock()
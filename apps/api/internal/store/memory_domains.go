@@ -0,0 +1,144 @@
+package store
+
+import (
+	"cmp"
+	"context"
+	"slices"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/max-cloud/api/internal/auth"
+	"github.com/max-cloud/shared/pkg/models"
+)
+
+// CreateDomain legt eine unverifizierte Domain an.
+func (s *MemoryStore) CreateDomain(ctx context.Context, req models.CreateDomainRequest) (models.Domain, error) {
+	token, err := generateDomainToken()
+	if err != nil {
+		return models.Domain{}, err
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	orgID, _ := auth.OrgIDFromContext(ctx)
+	for _, d := range s.domains {
+		if d.Hostname == req.Hostname && d.OrgID == orgID {
+			return models.Domain{}, ErrDuplicateDomain
+		}
+	}
+
+	now := time.Now()
+	d := models.Domain{
+		ID:                uuid.New().String(),
+		OrgID:             orgID,
+		ServiceID:         req.ServiceID,
+		Hostname:          req.Hostname,
+		VerificationToken: token,
+		Status:            models.DomainStatusPendingVerification,
+		CreatedAt:         now,
+		UpdatedAt:         now,
+	}
+	s.domains[d.ID] = d
+	return d, nil
+}
+
+// GetDomain gibt eine Domain anhand ihrer ID zurück.
+func (s *MemoryStore) GetDomain(ctx context.Context, id string) (models.Domain, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	d, ok := s.domainForTenant(ctx, id)
+	if !ok {
+		return models.Domain{}, ErrDomainNotFound
+	}
+	return d, nil
+}
+
+// ListDomains gibt alle Domains sortiert nach Hostname zurück.
+func (s *MemoryStore) ListDomains(ctx context.Context) ([]models.Domain, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	orgID, hasTenant := auth.OrgIDFromContext(ctx)
+
+	result := make([]models.Domain, 0, len(s.domains))
+	for _, d := range s.domains {
+		if hasTenant && d.OrgID != orgID {
+			continue
+		}
+		result = append(result, d)
+	}
+	slices.SortFunc(result, func(a, b models.Domain) int {
+		return cmp.Compare(a.Hostname, b.Hostname)
+	})
+	return result, nil
+}
+
+// VerifyDomain markiert eine Domain als verifiziert.
+func (s *MemoryStore) VerifyDomain(ctx context.Context, id string) (models.Domain, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	d, ok := s.domainForTenant(ctx, id)
+	if !ok {
+		return models.Domain{}, ErrDomainNotFound
+	}
+	for _, other := range s.domains {
+		if other.ID != d.ID && other.Hostname == d.Hostname && other.VerifiedAt != nil {
+			return models.Domain{}, ErrDuplicateDomain
+		}
+	}
+
+	now := time.Now()
+	if d.VerifiedAt == nil {
+		d.VerifiedAt = &now
+		d.Status = models.DomainStatusProvisioning
+		d.Message = ""
+	}
+	d.UpdatedAt = now
+	s.domains[id] = d
+	return d, nil
+}
+
+// UpdateDomainStatus setzt Status, Zertifikatszustand und Meldung einer Domain.
+func (s *MemoryStore) UpdateDomainStatus(_ context.Context, id string, status models.DomainStatus, certificateReady bool, message string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	d, ok := s.domains[id]
+	if !ok {
+		return ErrDomainNotFound
+	}
+	d.Status = status
+	d.CertificateReady = certificateReady
+	d.Message = message
+	d.UpdatedAt = time.Now()
+	s.domains[id] = d
+	return nil
+}
+
+// DeleteDomain entfernt eine Domain.
+func (s *MemoryStore) DeleteDomain(ctx context.Context, id string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if _, ok := s.domainForTenant(ctx, id); !ok {
+		return ErrDomainNotFound
+	}
+	delete(s.domains, id)
+	return nil
+}
+
+// domainForTenant liest eine Domain und prüft sie gegen den Tenant im Kontext.
+// Der Aufrufer muss s.mu halten.
+func (s *MemoryStore) domainForTenant(ctx context.Context, id string) (models.Domain, bool) {
+	d, ok := s.domains[id]
+	if !ok {
+		return models.Domain{}, false
+	}
+	if orgID, ok := auth.OrgIDFromContext(ctx); ok && d.OrgID != orgID {
+		return models.Domain{}, false
+	}
+	return d, true
+}
@@ -0,0 +1,33 @@
+package store
+
+import (
+	"context"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/max-cloud/shared/pkg/models"
+)
+
+// RecordEvent hängt ein Ereignis an die Historie eines Services an.
+func (s *MemoryStore) RecordEvent(_ context.Context, event models.ServiceEvent) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	event.ID = uuid.New().String()
+	event.CreatedAt = time.Now()
+	s.events[event.ServiceID] = append(s.events[event.ServiceID], event)
+	return nil
+}
+
+// ListEvents gibt die letzten limit Ereignisse eines Services zurück, neueste zuerst.
+func (s *MemoryStore) ListEvents(_ context.Context, serviceID string, limit int) ([]models.ServiceEvent, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	events := s.events[serviceID]
+	result := make([]models.ServiceEvent, 0, min(len(events), limit))
+	for i := len(events) - 1; i >= 0 && len(result) < limit; i-- {
+		result = append(result, events[i])
+	}
+	return result, nil
+}
@@ -0,0 +1,97 @@
+package store
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/max-cloud/shared/pkg/models"
+)
+
+type secretEntry struct {
+	secret     models.Secret
+	ciphertext []byte
+}
+
+// SetSecret legt ein Secret an oder überschreibt seinen Wert.
+func (s *MemoryStore) SetSecret(_ context.Context, orgID, name, value string) (models.Secret, error) {
+	ciphertext, err := s.secretCipher.encrypt(value, secretAAD(orgID, name))
+	if err != nil {
+		return models.Secret{}, err
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	now := time.Now()
+	entries := s.secrets[orgID]
+	if entries == nil {
+		entries = make(map[string]secretEntry)
+		s.secrets[orgID] = entries
+	}
+
+	entry, ok := entries[name]
+	if !ok {
+		entry.secret = models.Secret{
+			ID:        uuid.New().String(),
+			OrgID:     orgID,
+			Name:      name,
+			CreatedAt: now,
+		}
+	}
+	entry.secret.UpdatedAt = now
+	entry.ciphertext = ciphertext
+	entries[name] = entry
+
+	return entry.secret, nil
+}
+
+// ListSecrets gibt die Secrets einer Organisation sortiert nach Namen zurück, ohne Werte.
+func (s *MemoryStore) ListSecrets(_ context.Context, orgID string) ([]models.Secret, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	result := make([]models.Secret, 0, len(s.secrets[orgID]))
+	for _, entry := range s.secrets[orgID] {
+		result = append(result, entry.secret)
+	}
+	slices.SortFunc(result, func(a, b models.Secret) int {
+		return strings.Compare(a.Name, b.Name)
+	})
+	return result, nil
+}
+
+// DeleteSecret löscht ein Secret.
+func (s *MemoryStore) DeleteSecret(_ context.Context, orgID, name string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if _, ok := s.secrets[orgID][name]; !ok {
+		return ErrSecretNotFound
+	}
+	delete(s.secrets[orgID], name)
+	return nil
+}
+
+// SecretValues gibt die entschlüsselten Werte der genannten Secrets zurück.
+func (s *MemoryStore) SecretValues(_ context.Context, orgID string, names []string) (map[string]string, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	values := make(map[string]string, len(names))
+	for _, name := range names {
+		entry, ok := s.secrets[orgID][name]
+		if !ok {
+			return nil, fmt.Errorf("%w: %s", ErrSecretNotFound, name)
+		}
+		value, err := s.secretCipher.decrypt(entry.ciphertext, secretAAD(orgID, name))
+		if err != nil {
+			return nil, err
+		}
+		values[name] = value
+	}
+	return values, nil
+}
@@ -0,0 +1,128 @@
+package store
+
+import (
+	"context"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/max-cloud/shared/pkg/models"
+)
+
+// CreateServiceAccount legt einen Service-Account an.
+func (s *MemoryStore) CreateServiceAccount(_ context.Context, orgID, name string, role models.OrgRole) (models.ServiceAccount, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for _, sa := range s.serviceAccounts {
+		if sa.OrgID == orgID && sa.Name == name {
+			return models.ServiceAccount{}, ErrDuplicateServiceAccount
+		}
+	}
+
+	sa := models.ServiceAccount{
+		ID:        uuid.New().String(),
+		OrgID:     orgID,
+		Name:      name,
+		Role:      role,
+		CreatedAt: time.Now(),
+	}
+	s.serviceAccounts[sa.ID] = sa
+	return sa, nil
+}
+
+// ListServiceAccounts gibt alle Service-Accounts einer Organisation sortiert nach Namen zurück.
+func (s *MemoryStore) ListServiceAccounts(_ context.Context, orgID string) ([]models.ServiceAccount, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	result := []models.ServiceAccount{}
+	for _, sa := range s.serviceAccounts {
+		if sa.OrgID == orgID {
+			result = append(result, sa)
+		}
+	}
+	slices.SortFunc(result, func(a, b models.ServiceAccount) int {
+		return strings.Compare(a.Name, b.Name)
+	})
+	return result, nil
+}
+
+// GetServiceAccount gibt einen Service-Account der Organisation zurück.
+func (s *MemoryStore) GetServiceAccount(_ context.Context, orgID, id string) (models.ServiceAccount, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	sa, ok := s.serviceAccounts[id]
+	if !ok || sa.OrgID != orgID {
+		return models.ServiceAccount{}, ErrServiceAccountNotFound
+	}
+	return sa, nil
+}
+
+// UpdateServiceAccountRole ändert die Rolle eines Service-Accounts.
+func (s *MemoryStore) UpdateServiceAccountRole(_ context.Context, orgID, id string, role models.OrgRole) (models.ServiceAccount, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	sa, ok := s.serviceAccounts[id]
+	if !ok || sa.OrgID != orgID {
+		return models.ServiceAccount{}, ErrServiceAccountNotFound
+	}
+	sa.Role = role
+	s.serviceAccounts[id] = sa
+	return sa, nil
+}
+
+// DeleteServiceAccount löscht einen Service-Account samt seiner API-Keys.
+func (s *MemoryStore) DeleteServiceAccount(_ context.Context, orgID, id string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	sa, ok := s.serviceAccounts[id]
+	if !ok || sa.OrgID != orgID {
+		return ErrServiceAccountNotFound
+	}
+
+	s.deleteAPIKeysLocked(func(info models.APIKeyInfo) bool {
+		return info.ServiceAccountID == id
+	})
+	delete(s.serviceAccounts, id)
+	return nil
+}
+
+// CreateServiceAccountAPIKey erstellt einen API-Key, der dem Service-Account gehört.
+func (s *MemoryStore) CreateServiceAccountAPIKey(_ context.Context, orgID, id string, req models.CreateAPIKeyRequest) (string, *models.APIKeyInfo, error) {
+	rawKey, keyHash, prefix, err := generateAPIKey()
+	if err != nil {
+		return "", nil, err
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	sa, ok := s.serviceAccounts[id]
+	if !ok || sa.OrgID != orgID {
+		return "", nil, ErrServiceAccountNotFound
+	}
+
+	now := time.Now()
+	keyInfo := models.APIKeyInfo{
+		ID:               uuid.New().String(),
+		Prefix:           prefix,
+		Name:             req.Name,
+		OrgID:            orgID,
+		ServiceAccountID: sa.ID,
+		Scopes:           slices.Clone(req.Scopes),
+		CreatedAt:        now,
+		ExpiresAt:        req.ExpiresAt,
+		LifetimeSeconds:  models.APIKeyLifetimeSeconds(now, req.ExpiresAt),
+	}
+
+	entry := apiKeyEntry{info: keyInfo, hash: keyHash}
+	s.apiKeys[prefix] = append(s.apiKeys[prefix], entry)
+	s.apiKeysByID[keyInfo.ID] = &s.apiKeys[prefix][len(s.apiKeys[prefix])-1]
+
+	return rawKey, &keyInfo, nil
+}
@@ -159,6 +159,298 @@ func TestUpdateStatusNotFound(t *testing.T) {
 	}
 }
 
+func TestUpdate(t *testing.T) {
+	s := NewMemory()
+	ctx := context.Background()
+	created, err := s.Create(ctx, models.DeployRequest{Name: "app", Image: "img:1"})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if err := s.UpdateStatus(ctx, created.ID, models.ServiceStatusReady, "https://app.maxcloud.dev"); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	created.Image = "img:2"
+	updated, err := s.Update(ctx, created)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if updated.Image != "img:2" {
+		t.Fatalf("expected image img:2, got %s", updated.Image)
+	}
+	if updated.Status != models.ServiceStatusPending {
+		t.Fatalf("expected pending, got %s", updated.Status)
+	}
+	if updated.Generation != created.Generation+1 {
+		t.Fatalf("expected generation %d, got %d", created.Generation+1, updated.Generation)
+	}
+	if updated.URL != "https://app.maxcloud.dev" {
+		t.Fatalf("expected URL to be kept, got %s", updated.URL)
+	}
+}
+
+func TestUpdateConflict(t *testing.T) {
+	s := NewMemory()
+	ctx := context.Background()
+	created, err := s.Create(ctx, models.DeployRequest{Name: "app", Image: "img:1"})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	first := created
+	first.Image = "img:2"
+	if _, err := s.Update(ctx, first); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	// Zweites Update basiert auf veralteter Generation
+	second := created
+	second.Image = "img:3"
+	_, err = s.Update(ctx, second)
+	if !errors.Is(err, ErrConflict) {
+		t.Fatalf("expected ErrConflict, got %v", err)
+	}
+}
+
+func TestUpdateTenantIsolation(t *testing.T) {
+	s := NewMemory()
+
+	ctxOrg1 := auth.WithTenant(context.Background(), "org-1", "user-1")
+	ctxOrg2 := auth.WithTenant(context.Background(), "org-2", "user-2")
+
+	svc, err := s.Create(ctxOrg1, models.DeployRequest{Name: "app1", Image: "img"})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	svc.Image = "evil"
+	_, err = s.Update(ctxOrg2, svc)
+	if !errors.Is(err, ErrNotFound) {
+		t.Fatalf("expected ErrNotFound for cross-tenant update, got %v", err)
+	}
+}
+
+func TestRevisionsRecordedOnCreateAndUpdate(t *testing.T) {
+	s := NewMemory()
+	ctx := context.Background()
+	created, err := s.Create(ctx, models.DeployRequest{
+		Name:    "app",
+		Image:   "img:1",
+		EnvVars: map[string]string{"A": "1"},
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	next := created
+	next.Image = "img:2"
+	next.EnvVars = map[string]string{"A": "2"}
+	if _, err := s.Update(ctx, next); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	revs, err := s.ListRevisions(ctx, created.ID)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(revs) != 2 {
+		t.Fatalf("expected 2 revisions, got %d", len(revs))
+	}
+	if revs[0].Generation != 2 || revs[0].Image != "img:2" || revs[0].EnvVars["A"] != "2" {
+		t.Fatalf("unexpected newest revision: %+v", revs[0])
+	}
+	if revs[1].Generation != 1 || revs[1].Image != "img:1" || revs[1].EnvVars["A"] != "1" {
+		t.Fatalf("unexpected oldest revision: %+v", revs[1])
+	}
+}
+
+func TestListRevisionsTenantIsolation(t *testing.T) {
+	s := NewMemory()
+
+	ctxOrg1 := auth.WithTenant(context.Background(), "org-1", "user-1")
+	ctxOrg2 := auth.WithTenant(context.Background(), "org-2", "user-2")
+
+	svc, err := s.Create(ctxOrg1, models.DeployRequest{Name: "app1", Image: "img"})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	_, err = s.ListRevisions(ctxOrg2, svc.ID)
+	if !errors.Is(err, ErrNotFound) {
+		t.Fatalf("expected ErrNotFound for cross-tenant revisions, got %v", err)
+	}
+}
+
+func TestSetTraffic(t *testing.T) {
+	s := NewMemory()
+	ctx := context.Background()
+	created, err := s.Create(ctx, models.DeployRequest{Name: "app", Image: "img:1"})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	next := created
+	next.Image = "img:2"
+	updated, err := s.Update(ctx, next)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if updated.LatestRevision != "app-00002" {
+		t.Fatalf("expected latest revision app-00002, got %s", updated.LatestRevision)
+	}
+
+	split := []models.TrafficTarget{
+		{RevisionName: "app-00001", Percent: 90},
+		{RevisionName: "app-00002", Percent: 10},
+	}
+	routed, err := s.SetTraffic(ctx, created.ID, updated.Generation, split)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(routed.Traffic) != 2 || routed.Traffic[0] != split[0] {
+		t.Fatalf("unexpected traffic: %+v", routed.Traffic)
+	}
+	if routed.Generation != updated.Generation+1 || routed.Status != models.ServiceStatusPending {
+		t.Fatalf("expected pending generation %d, got %s generation %d", updated.Generation+1, routed.Status, routed.Generation)
+	}
+	if routed.LatestRevision != "app-00002" {
+		t.Fatalf("expected latest revision to be kept, got %s", routed.LatestRevision)
+	}
+
+	// Traffic-Änderungen erzeugen keine neue Revision
+	revs, _ := s.ListRevisions(ctx, created.ID)
+	if len(revs) != 2 {
+		t.Fatalf("expected 2 revisions, got %d", len(revs))
+	}
+
+	_, err = s.SetTraffic(ctx, created.ID, updated.Generation, nil)
+	if !errors.Is(err, ErrConflict) {
+		t.Fatalf("expected ErrConflict for stale generation, got %v", err)
+	}
+}
+
+func TestCreateWithTag(t *testing.T) {
+	s := NewMemory()
+	ctx := context.Background()
+
+	svc, err := s.Create(ctx, models.DeployRequest{Name: "app", Image: "img:1", Tag: "preview"})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	want := models.TrafficTarget{RevisionName: "app-00001", Percent: 100, Tag: "preview"}
+	if len(svc.Traffic) != 1 || svc.Traffic[0] != want {
+		t.Fatalf("expected traffic %+v, got %+v", want, svc.Traffic)
+	}
+
+	if err := s.UpdateTagURLs(ctx, svc.ID, map[string]string{"preview": "https://preview-app.maxcloud.dev"}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	got, _ := s.Get(ctx, svc.ID)
+	if got.TagURLs["preview"] != "https://preview-app.maxcloud.dev" {
+		t.Fatalf("expected tag URL to be stored, got %v", got.TagURLs)
+	}
+
+	if err := s.UpdateTagURLs(ctx, "nonexistent", nil); !errors.Is(err, ErrNotFound) {
+		t.Fatalf("expected ErrNotFound, got %v", err)
+	}
+}
+
+func TestUpdateStatusReason(t *testing.T) {
+	s := NewMemory()
+	ctx := context.Background()
+
+	svc, err := s.Create(ctx, models.DeployRequest{Name: "app", Image: "img:1"})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if err := s.UpdateStatusReason(ctx, svc.ID, "RevisionFailed", "image pull failed"); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	got, _ := s.Get(ctx, svc.ID)
+	if got.StatusReason != "RevisionFailed" || got.StatusMessage != "image pull failed" {
+		t.Fatalf("expected status reason to be stored, got %q / %q", got.StatusReason, got.StatusMessage)
+	}
+
+	if err := s.UpdateStatusReason(ctx, "nonexistent", "", ""); !errors.Is(err, ErrNotFound) {
+		t.Fatalf("expected ErrNotFound, got %v", err)
+	}
+}
+
+func TestUpdateReconcileState(t *testing.T) {
+	s := NewMemory()
+	ctx := context.Background()
+
+	svc, err := s.Create(ctx, models.DeployRequest{Name: "app", Image: "img:1"})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if err := s.UpdateReconcileState(ctx, svc.ID, 3, "deploy: image pull failed"); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	got, _ := s.Get(ctx, svc.ID)
+	if got.ReconcileAttempts != 3 || got.LastError != "deploy: image pull failed" {
+		t.Fatalf("expected reconcile state to be stored, got %d / %q", got.ReconcileAttempts, got.LastError)
+	}
+
+	if err := s.UpdateReconcileState(ctx, svc.ID, 0, ""); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	got, _ = s.Get(ctx, svc.ID)
+	if got.ReconcileAttempts != 0 || got.LastError != "" {
+		t.Fatalf("expected reconcile state to be reset, got %d / %q", got.ReconcileAttempts, got.LastError)
+	}
+
+	if err := s.UpdateReconcileState(ctx, "nonexistent", 1, "x"); !errors.Is(err, ErrNotFound) {
+		t.Fatalf("expected ErrNotFound, got %v", err)
+	}
+}
+
+func TestResourcesRecordedInRevisions(t *testing.T) {
+	s := NewMemory()
+	ctx := context.Background()
+
+	created, err := s.Create(ctx, models.DeployRequest{Name: "app", Image: "img:1"})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if created.CPU != models.DefaultCPU || created.Memory != models.DefaultMemory {
+		t.Fatalf("expected default resources, got cpu=%s memory=%s", created.CPU, created.Memory)
+	}
+
+	next := created
+	next.CPU = "2"
+	next.Memory = "4Gi"
+	if _, err := s.Update(ctx, next); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	revs, _ := s.ListRevisions(ctx, created.ID)
+	if revs[0].CPU != "2" || revs[0].Memory != "4Gi" {
+		t.Fatalf("unexpected newest revision resources: cpu=%s memory=%s", revs[0].CPU, revs[0].Memory)
+	}
+	if revs[1].CPU != models.DefaultCPU || revs[1].Memory != models.DefaultMemory {
+		t.Fatalf("unexpected oldest revision resources: cpu=%s memory=%s", revs[1].CPU, revs[1].Memory)
+	}
+}
+
+func TestSetTrafficTenantIsolation(t *testing.T) {
+	s := NewMemory()
+
+	ctxOrg1 := auth.WithTenant(context.Background(), "org-1", "user-1")
+	ctxOrg2 := auth.WithTenant(context.Background(), "org-2", "user-2")
+
+	svc, err := s.Create(ctxOrg1, models.DeployRequest{Name: "app1", Image: "img"})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	_, err = s.SetTraffic(ctxOrg2, svc.ID, svc.Generation, nil)
+	if !errors.Is(err, ErrNotFound) {
+		t.Fatalf("expected ErrNotFound for cross-tenant traffic, got %v", err)
+	}
+}
+
 func TestListTenantIsolation(t *testing.T) {
 	s := NewMemory()
 
@@ -0,0 +1 @@
+ALTER TABLE services ADD COLUMN IF NOT EXISTS generation BIGINT NOT NULL DEFAULT 1;
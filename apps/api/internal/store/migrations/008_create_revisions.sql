@@ -0,0 +1,23 @@
+CREATE TABLE IF NOT EXISTS revisions (
+    id         UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    service_id UUID NOT NULL REFERENCES services(id) ON DELETE CASCADE,
+    name       TEXT NOT NULL,
+    generation BIGINT NOT NULL,
+    image      TEXT NOT NULL,
+    port       INTEGER NOT NULL DEFAULT 0,
+    command    JSONB NOT NULL DEFAULT '[]'::jsonb,
+    args       JSONB NOT NULL DEFAULT '[]'::jsonb,
+    env_vars   JSONB NOT NULL DEFAULT '{}'::jsonb,
+    min_scale  INTEGER NOT NULL DEFAULT 0,
+    max_scale  INTEGER NOT NULL DEFAULT 10,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    UNIQUE (service_id, generation)
+);
+
+-- Bestehende Services erhalten eine Revision für ihren aktuellen Stand
+INSERT INTO revisions (service_id, name, generation, image, port, command, args, env_vars, min_scale, max_scale, created_at)
+SELECT id, name || '-' || LPAD(generation::text, 5, '0'), generation, image,
+       COALESCE(port, 0), COALESCE(command, '[]'::jsonb), COALESCE(args, '[]'::jsonb), env_vars,
+       min_scale, max_scale, updated_at
+FROM services
+ON CONFLICT (service_id, generation) DO NOTHING;
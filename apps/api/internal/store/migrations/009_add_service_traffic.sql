@@ -0,0 +1,7 @@
+ALTER TABLE services ADD COLUMN IF NOT EXISTS latest_revision TEXT NOT NULL DEFAULT '';
+ALTER TABLE services ADD COLUMN IF NOT EXISTS traffic JSONB NOT NULL DEFAULT '[]'::jsonb;
+
+UPDATE services s
+SET latest_revision = r.name
+FROM revisions r
+WHERE r.service_id = s.id AND r.generation = s.generation AND s.latest_revision = '';
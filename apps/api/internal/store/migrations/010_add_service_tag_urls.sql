@@ -0,0 +1 @@
+ALTER TABLE services ADD COLUMN IF NOT EXISTS tag_urls JSONB NOT NULL DEFAULT '{}'::jsonb;
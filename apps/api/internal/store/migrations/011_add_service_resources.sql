@@ -0,0 +1,5 @@
+ALTER TABLE services ADD COLUMN IF NOT EXISTS cpu TEXT NOT NULL DEFAULT '1';
+ALTER TABLE services ADD COLUMN IF NOT EXISTS memory TEXT NOT NULL DEFAULT '512Mi';
+
+ALTER TABLE revisions ADD COLUMN IF NOT EXISTS cpu TEXT NOT NULL DEFAULT '1';
+ALTER TABLE revisions ADD COLUMN IF NOT EXISTS memory TEXT NOT NULL DEFAULT '512Mi';
@@ -0,0 +1,12 @@
+ALTER TABLE services ADD COLUMN IF NOT EXISTS container_concurrency INTEGER NOT NULL DEFAULT 0;
+ALTER TABLE services ADD COLUMN IF NOT EXISTS autoscaling_target INTEGER NOT NULL DEFAULT 0;
+ALTER TABLE services ADD COLUMN IF NOT EXISTS scale_down_delay INTEGER NOT NULL DEFAULT 0;
+ALTER TABLE services ADD COLUMN IF NOT EXISTS scale_to_zero_retention INTEGER NOT NULL DEFAULT 0;
+
+ALTER TABLE revisions ADD COLUMN IF NOT EXISTS container_concurrency INTEGER NOT NULL DEFAULT 0;
+ALTER TABLE revisions ADD COLUMN IF NOT EXISTS autoscaling_target INTEGER NOT NULL DEFAULT 0;
+ALTER TABLE revisions ADD COLUMN IF NOT EXISTS scale_down_delay INTEGER NOT NULL DEFAULT 0;
+ALTER TABLE revisions ADD COLUMN IF NOT EXISTS scale_to_zero_retention INTEGER NOT NULL DEFAULT 0;
+
+-- Obergrenze für max_scale pro Organisation
+ALTER TABLE organizations ADD COLUMN IF NOT EXISTS max_scale_limit INTEGER NOT NULL DEFAULT 20;
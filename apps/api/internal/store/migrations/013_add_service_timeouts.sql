@@ -0,0 +1,7 @@
+ALTER TABLE services ADD COLUMN IF NOT EXISTS timeout_seconds INTEGER NOT NULL DEFAULT 0;
+ALTER TABLE services ADD COLUMN IF NOT EXISTS response_start_timeout_seconds INTEGER NOT NULL DEFAULT 0;
+ALTER TABLE services ADD COLUMN IF NOT EXISTS idle_timeout_seconds INTEGER NOT NULL DEFAULT 0;
+
+ALTER TABLE revisions ADD COLUMN IF NOT EXISTS timeout_seconds INTEGER NOT NULL DEFAULT 0;
+ALTER TABLE revisions ADD COLUMN IF NOT EXISTS response_start_timeout_seconds INTEGER NOT NULL DEFAULT 0;
+ALTER TABLE revisions ADD COLUMN IF NOT EXISTS idle_timeout_seconds INTEGER NOT NULL DEFAULT 0;
@@ -0,0 +1,3 @@
+-- Health checks as JSON ({"liveness":{...},"readiness":{...},"startup":{...}}); NULL means no probes
+ALTER TABLE services ADD COLUMN IF NOT EXISTS probes JSONB;
+ALTER TABLE revisions ADD COLUMN IF NOT EXISTS probes JSONB;
@@ -0,0 +1,14 @@
+-- Org-scoped secrets; value holds the AES-GCM ciphertext, never the plaintext
+CREATE TABLE IF NOT EXISTS secrets (
+    id         UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    org_id     UUID NOT NULL REFERENCES organizations(id) ON DELETE CASCADE,
+    name       TEXT NOT NULL,
+    value      BYTEA NOT NULL,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    updated_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    UNIQUE (org_id, name)
+);
+
+-- Environment variables backed by secrets (env name -> secret name)
+ALTER TABLE services ADD COLUMN IF NOT EXISTS secret_env JSONB NOT NULL DEFAULT '{}'::jsonb;
+ALTER TABLE revisions ADD COLUMN IF NOT EXISTS secret_env JSONB NOT NULL DEFAULT '{}'::jsonb;
@@ -0,0 +1,19 @@
+-- Custom domains mapped to a service. Several organizations may claim the same
+-- hostname, but only the one that passes the TXT challenge can verify it.
+CREATE TABLE IF NOT EXISTS domains (
+    id                 UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    org_id             UUID REFERENCES organizations(id) ON DELETE CASCADE,
+    service_id         UUID NOT NULL REFERENCES services(id),
+    hostname           TEXT NOT NULL,
+    verification_token TEXT NOT NULL,
+    status             TEXT NOT NULL DEFAULT 'pending_verification',
+    certificate_ready  BOOLEAN NOT NULL DEFAULT FALSE,
+    message            TEXT NOT NULL DEFAULT '',
+    verified_at        TIMESTAMPTZ,
+    created_at         TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    updated_at         TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+CREATE UNIQUE INDEX IF NOT EXISTS idx_domains_org_hostname ON domains (org_id, hostname);
+CREATE UNIQUE INDEX IF NOT EXISTS idx_domains_verified_hostname ON domains (hostname) WHERE verified_at IS NOT NULL;
+CREATE INDEX IF NOT EXISTS idx_domains_service_id ON domains (service_id);
@@ -0,0 +1,2 @@
+ALTER TABLE services ADD COLUMN IF NOT EXISTS reconcile_attempts INTEGER NOT NULL DEFAULT 0;
+ALTER TABLE services ADD COLUMN IF NOT EXISTS last_error TEXT NOT NULL DEFAULT '';
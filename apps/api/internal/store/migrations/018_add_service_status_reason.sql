@@ -0,0 +1,2 @@
+ALTER TABLE services ADD COLUMN IF NOT EXISTS status_reason TEXT NOT NULL DEFAULT '';
+ALTER TABLE services ADD COLUMN IF NOT EXISTS status_message TEXT NOT NULL DEFAULT '';
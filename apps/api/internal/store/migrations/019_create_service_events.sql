@@ -0,0 +1,13 @@
+-- Append-only history of a service. Rows are kept after the service is deleted,
+-- so service_id deliberately has no foreign key.
+CREATE TABLE IF NOT EXISTS service_events (
+    id         UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    service_id UUID NOT NULL,
+    org_id     UUID REFERENCES organizations(id) ON DELETE CASCADE,
+    type       TEXT NOT NULL,
+    reason     TEXT NOT NULL DEFAULT '',
+    message    TEXT NOT NULL DEFAULT '',
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX IF NOT EXISTS idx_service_events_service_id ON service_events (service_id, created_at DESC);
@@ -0,0 +1,14 @@
+-- Replaces the admin/member roles with owner/admin/developer/viewer.
+-- Existing admins become owners so they keep the right to delete their organization,
+-- members become developers and keep the right to deploy.
+ALTER TABLE org_members DROP CONSTRAINT IF EXISTS org_members_role_check;
+UPDATE org_members SET role = 'owner' WHERE role = 'admin';
+UPDATE org_members SET role = 'developer' WHERE role = 'member';
+ALTER TABLE org_members ADD CONSTRAINT org_members_role_check
+    CHECK (role IN ('owner', 'admin', 'developer', 'viewer'));
+
+-- Pending admin invitations stay admin: ownership is only granted explicitly.
+ALTER TABLE invitations DROP CONSTRAINT IF EXISTS invitations_role_check;
+UPDATE invitations SET role = 'developer' WHERE role = 'member';
+ALTER TABLE invitations ADD CONSTRAINT invitations_role_check
+    CHECK (role IN ('owner', 'admin', 'developer', 'viewer'));
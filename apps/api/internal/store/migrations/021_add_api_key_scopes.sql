@@ -0,0 +1,2 @@
+-- An empty scope list grants every permission of the user's role.
+ALTER TABLE api_keys ADD COLUMN IF NOT EXISTS scopes TEXT[] NOT NULL DEFAULT '{}';
@@ -0,0 +1,20 @@
+-- Service accounts belong to an organization instead of a user, so their API keys
+-- survive when the member who created them leaves. They never hold the owner role.
+CREATE TABLE IF NOT EXISTS service_accounts (
+    id         UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    org_id     UUID NOT NULL REFERENCES organizations(id) ON DELETE CASCADE,
+    name       TEXT NOT NULL,
+    role       TEXT NOT NULL CHECK (role IN ('admin', 'developer', 'viewer')),
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    UNIQUE (org_id, name)
+);
+
+-- An API key is owned by exactly one user or service account.
+ALTER TABLE api_keys ALTER COLUMN user_id DROP NOT NULL;
+ALTER TABLE api_keys ADD COLUMN IF NOT EXISTS service_account_id UUID REFERENCES service_accounts(id) ON DELETE CASCADE;
+ALTER TABLE api_keys DROP CONSTRAINT IF EXISTS api_keys_owner_check;
+ALTER TABLE api_keys ADD CONSTRAINT api_keys_owner_check
+    CHECK ((user_id IS NULL) <> (service_account_id IS NULL));
+
+-- Who triggered an event: "user:<id>" or "service-account:<id>", empty for the reconciler.
+ALTER TABLE service_events ADD COLUMN IF NOT EXISTS actor TEXT NOT NULL DEFAULT '';
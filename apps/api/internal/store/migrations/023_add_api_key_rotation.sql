@@ -0,0 +1,8 @@
+-- A rotated key keeps only its grace period, so the lifetime a successor inherits
+-- is recorded explicitly (0 = no expiry). rotated_at prevents rotating a key twice.
+ALTER TABLE api_keys ADD COLUMN IF NOT EXISTS lifetime_seconds BIGINT NOT NULL DEFAULT 0;
+ALTER TABLE api_keys ADD COLUMN IF NOT EXISTS rotated_at TIMESTAMPTZ;
+
+UPDATE api_keys
+SET lifetime_seconds = GREATEST(1, ROUND(EXTRACT(EPOCH FROM expires_at - created_at)))::BIGINT
+WHERE expires_at IS NOT NULL AND lifetime_seconds = 0;
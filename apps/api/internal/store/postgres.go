@@ -1,6 +1,7 @@
 package store
 
 import (
+	"cmp"
 	"context"
 	"embed"
 	"encoding/json"
@@ -31,16 +32,71 @@ func (s *PostgresStore) UpdateStatus(ctx context.Context, id string, status mode
 	return nil
 }
 
+// UpdateTagURLs speichert die Tag-URLs eines Services.
+func (s *PostgresStore) UpdateTagURLs(ctx context.Context, id string, tagURLs map[string]string) error {
+	if tagURLs == nil {
+		tagURLs = map[string]string{}
+	}
+	data, err := json.Marshal(tagURLs)
+	if err != nil {
+		return fmt.Errorf("marshaling tag urls: %w", err)
+	}
+
+	result, err := s.pool.Exec(ctx,
+		`UPDATE services SET tag_urls = $1, updated_at = NOW() WHERE id = $2`,
+		data, id,
+	)
+	if err != nil {
+		return fmt.Errorf("updating tag urls: %w", err)
+	}
+	if result.RowsAffected() == 0 {
+		return ErrNotFound
+	}
+	return nil
+}
+
+// UpdateStatusReason speichert Grund und Meldung des Status eines Services.
+func (s *PostgresStore) UpdateStatusReason(ctx context.Context, id string, reason, message string) error {
+	result, err := s.pool.Exec(ctx,
+		`UPDATE services SET status_reason = $1, status_message = $2, updated_at = NOW() WHERE id = $3`,
+		reason, message, id,
+	)
+	if err != nil {
+		return fmt.Errorf("updating status reason: %w", err)
+	}
+	if result.RowsAffected() == 0 {
+		return ErrNotFound
+	}
+	return nil
+}
+
+// UpdateReconcileState speichert Fehlversuche und letzten Fehler eines Services.
+func (s *PostgresStore) UpdateReconcileState(ctx context.Context, id string, attempts int, lastError string) error {
+	result, err := s.pool.Exec(ctx,
+		`UPDATE services SET reconcile_attempts = $1, last_error = $2 WHERE id = $3`,
+		attempts, lastError, id,
+	)
+	if err != nil {
+		return fmt.Errorf("updating reconcile state: %w", err)
+	}
+	if result.RowsAffected() == 0 {
+		return ErrNotFound
+	}
+	return nil
+}
+
 //go:embed migrations/*.sql
 var migrationsFS embed.FS
 
 // PostgresStore implementiert ServiceStore mit PostgreSQL als Backend.
 type PostgresStore struct {
-	pool *pgxpool.Pool
+	pool         *pgxpool.Pool
+	secretCipher *SecretCipher
 }
 
 // NewPostgres erstellt einen neuen PostgresStore, verbindet sich mit der DB und führt Migrationen aus.
-func NewPostgres(ctx context.Context, databaseURL string) (*PostgresStore, error) {
+// secretCipher verschlüsselt die Werte von Secrets.
+func NewPostgres(ctx context.Context, databaseURL string, secretCipher *SecretCipher) (*PostgresStore, error) {
 	pool, err := pgxpool.New(ctx, databaseURL)
 	if err != nil {
 		return nil, fmt.Errorf("connecting to database: %w", err)
@@ -56,7 +112,7 @@ func NewPostgres(ctx context.Context, databaseURL string) (*PostgresStore, error
 		return nil, err
 	}
 
-	return &PostgresStore{pool: pool}, nil
+	return &PostgresStore{pool: pool, secretCipher: secretCipher}, nil
 }
 
 // Close schliesst den Connection-Pool.
@@ -127,50 +183,198 @@ func runMigrations(ctx context.Context, pool *pgxpool.Pool) error {
 	return nil
 }
 
-// Create fügt einen neuen Service ein. UUID und Timestamps werden von PostgreSQL generiert.
-func (s *PostgresStore) Create(ctx context.Context, req models.DeployRequest) (models.Service, error) {
-	envJSON, err := json.Marshal(req.EnvVars)
+// serviceColumns ist die Spaltenliste, die scanService erwartet.
+const serviceColumns = `id, name, image, status, url, env_vars, min_scale, max_scale, created_at, updated_at, org_id, port, command, args, generation, latest_revision, traffic, tag_urls, cpu, memory,
+	container_concurrency, autoscaling_target, scale_down_delay, scale_to_zero_retention,
+	timeout_seconds, response_start_timeout_seconds, idle_timeout_seconds, probes, secret_env,
+	reconcile_attempts, last_error, status_reason, status_message`
+
+// scanService liest eine Service-Zeile (Spalten wie serviceColumns) ein.
+func scanService(row pgx.Row) (models.Service, error) {
+	var svc models.Service
+	var envBytes, commandBytes, argsBytes, trafficBytes, tagURLBytes, probesBytes, secretEnvBytes []byte
+	var orgID *string
+	if err := row.Scan(
+		&svc.ID, &svc.Name, &svc.Image, &svc.Status, &svc.URL,
+		&envBytes, &svc.MinScale, &svc.MaxScale, &svc.CreatedAt, &svc.UpdatedAt, &orgID,
+		&svc.Port, &commandBytes, &argsBytes, &svc.Generation, &svc.LatestRevision, &trafficBytes, &tagURLBytes,
+		&svc.CPU, &svc.Memory,
+		&svc.ContainerConcurrency, &svc.AutoscalingTarget, &svc.ScaleDownDelay, &svc.ScaleToZeroRetention,
+		&svc.TimeoutSeconds, &svc.ResponseStartTimeoutSeconds, &svc.IdleTimeoutSeconds, &probesBytes, &secretEnvBytes,
+		&svc.ReconcileAttempts, &svc.LastError, &svc.StatusReason, &svc.StatusMessage,
+	); err != nil {
+		return models.Service{}, err
+	}
+
+	if orgID != nil {
+		svc.OrgID = *orgID
+	}
+
+	if err := json.Unmarshal(envBytes, &svc.EnvVars); err != nil {
+		return models.Service{}, fmt.Errorf("unmarshaling env_vars: %w", err)
+	}
+
+	if err := json.Unmarshal(commandBytes, &svc.Command); err != nil {
+		return models.Service{}, fmt.Errorf("unmarshaling command: %w", err)
+	}
+
+	if err := json.Unmarshal(argsBytes, &svc.Args); err != nil {
+		return models.Service{}, fmt.Errorf("unmarshaling args: %w", err)
+	}
+
+	if err := json.Unmarshal(trafficBytes, &svc.Traffic); err != nil {
+		return models.Service{}, fmt.Errorf("unmarshaling traffic: %w", err)
+	}
+	if len(svc.Traffic) == 0 {
+		svc.Traffic = nil
+	}
+
+	if err := json.Unmarshal(tagURLBytes, &svc.TagURLs); err != nil {
+		return models.Service{}, fmt.Errorf("unmarshaling tag urls: %w", err)
+	}
+	if len(svc.TagURLs) == 0 {
+		svc.TagURLs = nil
+	}
+
+	probes, err := unmarshalProbes(probesBytes)
 	if err != nil {
-		return models.Service{}, fmt.Errorf("marshaling env_vars: %w", err)
+		return models.Service{}, err
 	}
-	if req.EnvVars == nil {
+	svc.Probes = probes
+
+	if err := json.Unmarshal(secretEnvBytes, &svc.SecretEnv); err != nil {
+		return models.Service{}, fmt.Errorf("unmarshaling secret_env: %w", err)
+	}
+	if len(svc.SecretEnv) == 0 {
+		svc.SecretEnv = nil
+	}
+
+	return svc, nil
+}
+
+// marshalTraffic serialisiert die Traffic-Aufteilung für die JSONB-Spalte.
+func marshalTraffic(traffic []models.TrafficTarget) ([]byte, error) {
+	if len(traffic) == 0 {
+		return []byte("[]"), nil
+	}
+	data, err := json.Marshal(traffic)
+	if err != nil {
+		return nil, fmt.Errorf("marshaling traffic: %w", err)
+	}
+	return data, nil
+}
+
+// marshalProbes serialisiert die Probes für die JSONB-Spalte. Ohne Probes wird NULL gespeichert.
+func marshalProbes(probes *models.Probes) ([]byte, error) {
+	if probes.IsZero() {
+		return nil, nil
+	}
+	data, err := json.Marshal(probes)
+	if err != nil {
+		return nil, fmt.Errorf("marshaling probes: %w", err)
+	}
+	return data, nil
+}
+
+// unmarshalProbes liest die JSONB-Spalte probes; NULL ergibt nil.
+func unmarshalProbes(data []byte) (*models.Probes, error) {
+	if len(data) == 0 {
+		return nil, nil
+	}
+	var probes *models.Probes
+	if err := json.Unmarshal(data, &probes); err != nil {
+		return nil, fmt.Errorf("unmarshaling probes: %w", err)
+	}
+	if probes.IsZero() {
+		return nil, nil
+	}
+	return probes, nil
+}
+
+// marshalSecretEnv serialisiert die Secret-Referenzen für die JSONB-Spalte.
+func marshalSecretEnv(secretEnv map[string]string) ([]byte, error) {
+	if len(secretEnv) == 0 {
+		return []byte("{}"), nil
+	}
+	data, err := json.Marshal(secretEnv)
+	if err != nil {
+		return nil, fmt.Errorf("marshaling secret_env: %w", err)
+	}
+	return data, nil
+}
+
+// marshalServiceSpec serialisiert die JSONB-Spalten eines Services.
+func marshalServiceSpec(envVars map[string]string, command, args []string) (envJSON, commandJSON, argsJSON []byte, err error) {
+	envJSON, err = json.Marshal(envVars)
+	if err != nil {
+		return nil, nil, nil, fmt.Errorf("marshaling env_vars: %w", err)
+	}
+	if envVars == nil {
 		envJSON = []byte("{}")
 	}
 
-	commandJSON, err := json.Marshal(req.Command)
+	commandJSON, err = json.Marshal(command)
 	if err != nil {
-		return models.Service{}, fmt.Errorf("marshaling command: %w", err)
+		return nil, nil, nil, fmt.Errorf("marshaling command: %w", err)
 	}
-	if len(req.Command) == 0 {
+	if len(command) == 0 {
 		commandJSON = []byte("[]")
 	}
 
-	argsJSON, err := json.Marshal(req.Args)
+	argsJSON, err = json.Marshal(args)
 	if err != nil {
-		return models.Service{}, fmt.Errorf("marshaling args: %w", err)
+		return nil, nil, nil, fmt.Errorf("marshaling args: %w", err)
 	}
-	if len(req.Args) == 0 {
+	if len(args) == 0 {
 		argsJSON = []byte("[]")
 	}
 
+	return envJSON, commandJSON, argsJSON, nil
+}
+
+// Create fügt einen neuen Service ein. UUID und Timestamps werden von PostgreSQL generiert.
+func (s *PostgresStore) Create(ctx context.Context, req models.DeployRequest) (models.Service, error) {
+	envJSON, commandJSON, argsJSON, err := marshalServiceSpec(req.EnvVars, req.Command, req.Args)
+	if err != nil {
+		return models.Service{}, err
+	}
+
 	var orgIDParam any
 	if orgID, ok := auth.OrgIDFromContext(ctx); ok {
 		orgIDParam = orgID
 	}
 
-	var svc models.Service
-	var envBytes, commandBytes, argsBytes []byte
-	var orgID *string
-	err = s.pool.QueryRow(ctx,
-		`INSERT INTO services (name, image, status, url, env_vars, org_id, port, command, args)
-		 VALUES ($1, $2, 'pending', '', $3, $4, $5, $6, $7)
-		 RETURNING id, name, image, status, url, env_vars, min_scale, max_scale, created_at, updated_at, org_id, port, command, args`,
-		req.Name, req.Image, envJSON, orgIDParam, req.Port, commandJSON, argsJSON,
-	).Scan(
-		&svc.ID, &svc.Name, &svc.Image, &svc.Status, &svc.URL,
-		&envBytes, &svc.MinScale, &svc.MaxScale, &svc.CreatedAt, &svc.UpdatedAt, &orgID,
-		&svc.Port, &commandBytes, &argsBytes,
-	)
+	trafficJSON, err := marshalTraffic(initialTraffic(req))
+	if err != nil {
+		return models.Service{}, err
+	}
+	probesJSON, err := marshalProbes(req.Probes)
+	if err != nil {
+		return models.Service{}, err
+	}
+	secretEnvJSON, err := marshalSecretEnv(req.SecretEnv)
+	if err != nil {
+		return models.Service{}, err
+	}
+
+	tx, err := s.pool.Begin(ctx)
+	if err != nil {
+		return models.Service{}, fmt.Errorf("begin tx: %w", err)
+	}
+	defer tx.Rollback(ctx)
+
+	svc, err := scanService(tx.QueryRow(ctx,
+		`INSERT INTO services (name, image, status, url, env_vars, org_id, port, command, args, latest_revision, traffic, cpu, memory,
+		                       min_scale, max_scale, container_concurrency, autoscaling_target, scale_down_delay, scale_to_zero_retention,
+		                       timeout_seconds, response_start_timeout_seconds, idle_timeout_seconds, probes, secret_env)
+		 VALUES ($1, $2, 'pending', '', $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14, $15, $16, $17, $18, $19, $20, $21, $22)
+		 RETURNING `+serviceColumns,
+		req.Name, req.Image, envJSON, orgIDParam, req.Port, commandJSON, argsJSON, models.RevisionName(req.Name, 1), trafficJSON,
+		cmp.Or(req.CPU, models.DefaultCPU), cmp.Or(req.Memory, models.DefaultMemory),
+		req.MinScale, cmp.Or(req.MaxScale, models.DefaultMaxScale),
+		req.ContainerConcurrency, req.AutoscalingTarget, req.ScaleDownDelay, req.ScaleToZeroRetention,
+		req.TimeoutSeconds, req.ResponseStartTimeoutSeconds, req.IdleTimeoutSeconds, probesJSON, secretEnvJSON,
+	))
 	if err != nil {
 		if strings.Contains(err.Error(), "duplicate key value violates unique constraint") {
 			return models.Service{}, ErrDuplicateService
@@ -178,20 +382,12 @@ func (s *PostgresStore) Create(ctx context.Context, req models.DeployRequest) (m
 		return models.Service{}, fmt.Errorf("inserting service: %w", err)
 	}
 
-	if orgID != nil {
-		svc.OrgID = *orgID
-	}
-
-	if err := json.Unmarshal(envBytes, &svc.EnvVars); err != nil {
-		return models.Service{}, fmt.Errorf("unmarshaling env_vars: %w", err)
-	}
-
-	if err := json.Unmarshal(commandBytes, &svc.Command); err != nil {
-		return models.Service{}, fmt.Errorf("unmarshaling command: %w", err)
+	if err := insertRevision(ctx, tx, svc); err != nil {
+		return models.Service{}, err
 	}
 
-	if err := json.Unmarshal(argsBytes, &svc.Args); err != nil {
-		return models.Service{}, fmt.Errorf("unmarshaling args: %w", err)
+	if err := tx.Commit(ctx); err != nil {
+		return models.Service{}, fmt.Errorf("commit: %w", err)
 	}
 
 	return svc, nil
@@ -199,24 +395,15 @@ func (s *PostgresStore) Create(ctx context.Context, req models.DeployRequest) (m
 
 // Get gibt einen Service anhand seiner ID zurück. Gibt ErrNotFound zurück, wenn nicht vorhanden.
 func (s *PostgresStore) Get(ctx context.Context, id string) (models.Service, error) {
-	query := `SELECT id, name, image, status, url, env_vars, min_scale, max_scale, created_at, updated_at, org_id, port, command, args
-		 FROM services WHERE id = $1`
+	query := `SELECT ` + serviceColumns + ` FROM services WHERE id = $1`
 	args := []any{id}
 
 	if orgID, ok := auth.OrgIDFromContext(ctx); ok {
-		query = `SELECT id, name, image, status, url, env_vars, min_scale, max_scale, created_at, updated_at, org_id, port, command, args
-			 FROM services WHERE id = $1 AND org_id = $2`
+		query += ` AND org_id = $2`
 		args = append(args, orgID)
 	}
 
-	var svc models.Service
-	var envBytes, commandBytes, argsBytes []byte
-	var orgID *string
-	err := s.pool.QueryRow(ctx, query, args...).Scan(
-		&svc.ID, &svc.Name, &svc.Image, &svc.Status, &svc.URL,
-		&envBytes, &svc.MinScale, &svc.MaxScale, &svc.CreatedAt, &svc.UpdatedAt, &orgID,
-		&svc.Port, &commandBytes, &argsBytes,
-	)
+	svc, err := scanService(s.pool.QueryRow(ctx, query, args...))
 	if err != nil {
 		if errors.Is(err, pgx.ErrNoRows) {
 			return models.Service{}, ErrNotFound
@@ -224,45 +411,20 @@ func (s *PostgresStore) Get(ctx context.Context, id string) (models.Service, err
 		return models.Service{}, fmt.Errorf("querying service: %w", err)
 	}
 
-	if orgID != nil {
-		svc.OrgID = *orgID
-	}
-
-	if err := json.Unmarshal(envBytes, &svc.EnvVars); err != nil {
-		return models.Service{}, fmt.Errorf("unmarshaling env_vars: %w", err)
-	}
-
-	if err := json.Unmarshal(commandBytes, &svc.Command); err != nil {
-		return models.Service{}, fmt.Errorf("unmarshaling command: %w", err)
-	}
-
-	if err := json.Unmarshal(argsBytes, &svc.Args); err != nil {
-		return models.Service{}, fmt.Errorf("unmarshaling args: %w", err)
-	}
-
 	return svc, nil
 }
 
 // GetByName gibt einen Service anhand seines Namens zurück.
 func (s *PostgresStore) GetByName(ctx context.Context, name string) (models.Service, error) {
-	query := `SELECT id, name, image, status, url, env_vars, min_scale, max_scale, created_at, updated_at, org_id, port, command, args
-		 FROM services WHERE name = $1`
+	query := `SELECT ` + serviceColumns + ` FROM services WHERE name = $1`
 	args := []any{name}
 
 	if orgID, ok := auth.OrgIDFromContext(ctx); ok {
-		query = `SELECT id, name, image, status, url, env_vars, min_scale, max_scale, created_at, updated_at, org_id
-			 FROM services WHERE name = $1 AND org_id = $2`
+		query += ` AND org_id = $2`
 		args = append(args, orgID)
 	}
 
-	var svc models.Service
-	var envBytes, commandBytes, argsBytes []byte
-	var orgID *string
-	err := s.pool.QueryRow(ctx, query, args...).Scan(
-		&svc.ID, &svc.Name, &svc.Image, &svc.Status, &svc.URL,
-		&envBytes, &svc.MinScale, &svc.MaxScale, &svc.CreatedAt, &svc.UpdatedAt, &orgID,
-		&svc.Port, &commandBytes, &argsBytes,
-	)
+	svc, err := scanService(s.pool.QueryRow(ctx, query, args...))
 	if err != nil {
 		if errors.Is(err, pgx.ErrNoRows) {
 			return models.Service{}, ErrNotFound
@@ -270,29 +432,12 @@ func (s *PostgresStore) GetByName(ctx context.Context, name string) (models.Serv
 		return models.Service{}, fmt.Errorf("querying service by name: %w", err)
 	}
 
-	if orgID != nil {
-		svc.OrgID = *orgID
-	}
-
-	if err := json.Unmarshal(envBytes, &svc.EnvVars); err != nil {
-		return models.Service{}, fmt.Errorf("unmarshaling env_vars: %w", err)
-	}
-
-	if err := json.Unmarshal(commandBytes, &svc.Command); err != nil {
-		return models.Service{}, fmt.Errorf("unmarshaling command: %w", err)
-	}
-
-	if err := json.Unmarshal(argsBytes, &svc.Args); err != nil {
-		return models.Service{}, fmt.Errorf("unmarshaling args: %w", err)
-	}
-
 	return svc, nil
 }
 
 // List gibt alle Services zurück.
 func (s *PostgresStore) List(ctx context.Context) ([]models.Service, error) {
-	query := `SELECT id, name, image, status, url, env_vars, min_scale, max_scale, created_at, updated_at, org_id, port, command, args
-		 FROM services`
+	query := `SELECT ` + serviceColumns + ` FROM services`
 	var args []any
 
 	if orgID, ok := auth.OrgIDFromContext(ctx); ok {
@@ -309,28 +454,10 @@ func (s *PostgresStore) List(ctx context.Context) ([]models.Service, error) {
 
 	var services []models.Service
 	for rows.Next() {
-		var svc models.Service
-		var envBytes, commandBytes, argsBytes []byte
-		var orgID *string
-		if err := rows.Scan(
-			&svc.ID, &svc.Name, &svc.Image, &svc.Status, &svc.URL,
-			&envBytes, &svc.MinScale, &svc.MaxScale, &svc.CreatedAt, &svc.UpdatedAt, &orgID,
-			&svc.Port, &commandBytes, &argsBytes,
-		); err != nil {
+		svc, err := scanService(rows)
+		if err != nil {
 			return nil, fmt.Errorf("scanning service: %w", err)
 		}
-		if orgID != nil {
-			svc.OrgID = *orgID
-		}
-		if err := json.Unmarshal(envBytes, &svc.EnvVars); err != nil {
-			return nil, fmt.Errorf("unmarshaling env_vars: %w", err)
-		}
-		if err := json.Unmarshal(commandBytes, &svc.Command); err != nil {
-			return nil, fmt.Errorf("unmarshaling command: %w", err)
-		}
-		if err := json.Unmarshal(argsBytes, &svc.Args); err != nil {
-			return nil, fmt.Errorf("unmarshaling args: %w", err)
-		}
 		services = append(services, svc)
 	}
 	if err := rows.Err(); err != nil {
@@ -344,6 +471,214 @@ func (s *PostgresStore) List(ctx context.Context) ([]models.Service, error) {
 	return services, nil
 }
 
+// Update überschreibt die Spec eines Services, sofern die Generation übereinstimmt.
+func (s *PostgresStore) Update(ctx context.Context, svc models.Service) (models.Service, error) {
+	envJSON, commandJSON, argsJSON, err := marshalServiceSpec(svc.EnvVars, svc.Command, svc.Args)
+	if err != nil {
+		return models.Service{}, err
+	}
+	trafficJSON, err := marshalTraffic(svc.Traffic)
+	if err != nil {
+		return models.Service{}, err
+	}
+	probesJSON, err := marshalProbes(svc.Probes)
+	if err != nil {
+		return models.Service{}, err
+	}
+	secretEnvJSON, err := marshalSecretEnv(svc.SecretEnv)
+	if err != nil {
+		return models.Service{}, err
+	}
+
+	query := `UPDATE services
+		 SET image = $1, port = $2, command = $3, args = $4, env_vars = $5, min_scale = $6, max_scale = $7,
+		     traffic = $8, latest_revision = $9, cpu = $10, memory = $11,
+		     container_concurrency = $12, autoscaling_target = $13, scale_down_delay = $14, scale_to_zero_retention = $15,
+		     timeout_seconds = $16, response_start_timeout_seconds = $17, idle_timeout_seconds = $18,
+		     probes = $19, secret_env = $20, status = 'pending', generation = generation + 1, updated_at = NOW()
+		 WHERE id = $21 AND generation = $22`
+	args := []any{
+		svc.Image, svc.Port, commandJSON, argsJSON, envJSON, svc.MinScale, svc.MaxScale,
+		trafficJSON, models.RevisionName(svc.Name, svc.Generation+1), svc.CPU, svc.Memory,
+		svc.ContainerConcurrency, svc.AutoscalingTarget, svc.ScaleDownDelay, svc.ScaleToZeroRetention,
+		svc.TimeoutSeconds, svc.ResponseStartTimeoutSeconds, svc.IdleTimeoutSeconds,
+		probesJSON, secretEnvJSON, svc.ID, svc.Generation,
+	}
+
+	if orgID, ok := auth.OrgIDFromContext(ctx); ok {
+		query += ` AND org_id = $23`
+		args = append(args, orgID)
+	}
+	query += ` RETURNING ` + serviceColumns
+
+	tx, err := s.pool.Begin(ctx)
+	if err != nil {
+		return models.Service{}, fmt.Errorf("begin tx: %w", err)
+	}
+	defer tx.Rollback(ctx)
+
+	updated, err := scanService(tx.QueryRow(ctx, query, args...))
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			// Unterscheiden zwischen nicht vorhanden und veralteter Generation
+			if _, getErr := s.Get(ctx, svc.ID); getErr != nil {
+				return models.Service{}, getErr
+			}
+			return models.Service{}, ErrConflict
+		}
+		return models.Service{}, fmt.Errorf("updating service: %w", err)
+	}
+
+	if err := insertRevision(ctx, tx, updated); err != nil {
+		return models.Service{}, err
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return models.Service{}, fmt.Errorf("commit: %w", err)
+	}
+
+	return updated, nil
+}
+
+// SetTraffic setzt die Traffic-Aufteilung eines Services, sofern die Generation übereinstimmt.
+func (s *PostgresStore) SetTraffic(ctx context.Context, id string, generation int64, traffic []models.TrafficTarget) (models.Service, error) {
+	trafficJSON, err := marshalTraffic(traffic)
+	if err != nil {
+		return models.Service{}, err
+	}
+
+	query := `UPDATE services
+		 SET traffic = $1, status = 'pending', generation = generation + 1, updated_at = NOW()
+		 WHERE id = $2 AND generation = $3`
+	args := []any{trafficJSON, id, generation}
+
+	if orgID, ok := auth.OrgIDFromContext(ctx); ok {
+		query += ` AND org_id = $4`
+		args = append(args, orgID)
+	}
+	query += ` RETURNING ` + serviceColumns
+
+	updated, err := scanService(s.pool.QueryRow(ctx, query, args...))
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			if _, getErr := s.Get(ctx, id); getErr != nil {
+				return models.Service{}, getErr
+			}
+			return models.Service{}, ErrConflict
+		}
+		return models.Service{}, fmt.Errorf("updating traffic: %w", err)
+	}
+
+	return updated, nil
+}
+
+// insertRevision legt innerhalb von tx einen Snapshot der aktuellen Service-Spec an.
+func insertRevision(ctx context.Context, tx pgx.Tx, svc models.Service) error {
+	envJSON, commandJSON, argsJSON, err := marshalServiceSpec(svc.EnvVars, svc.Command, svc.Args)
+	if err != nil {
+		return err
+	}
+	probesJSON, err := marshalProbes(svc.Probes)
+	if err != nil {
+		return err
+	}
+	secretEnvJSON, err := marshalSecretEnv(svc.SecretEnv)
+	if err != nil {
+		return err
+	}
+
+	if _, err := tx.Exec(ctx,
+		`INSERT INTO revisions (service_id, name, generation, image, port, command, args, env_vars, min_scale, max_scale, cpu, memory,
+		                        container_concurrency, autoscaling_target, scale_down_delay, scale_to_zero_retention,
+		                        timeout_seconds, response_start_timeout_seconds, idle_timeout_seconds, probes, secret_env)
+		 VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14, $15, $16, $17, $18, $19, $20, $21)`,
+		svc.ID, models.RevisionName(svc.Name, svc.Generation), svc.Generation, svc.Image, svc.Port,
+		commandJSON, argsJSON, envJSON, svc.MinScale, svc.MaxScale, svc.CPU, svc.Memory,
+		svc.ContainerConcurrency, svc.AutoscalingTarget, svc.ScaleDownDelay, svc.ScaleToZeroRetention,
+		svc.TimeoutSeconds, svc.ResponseStartTimeoutSeconds, svc.IdleTimeoutSeconds, probesJSON, secretEnvJSON,
+	); err != nil {
+		return fmt.Errorf("inserting revision: %w", err)
+	}
+	return nil
+}
+
+// revisionColumns ist die Spaltenliste, die scanRevision erwartet.
+const revisionColumns = `id, service_id, name, generation, image, port, command, args, env_vars, min_scale, max_scale, created_at, cpu, memory,
+	container_concurrency, autoscaling_target, scale_down_delay, scale_to_zero_retention,
+	timeout_seconds, response_start_timeout_seconds, idle_timeout_seconds, probes, secret_env`
+
+// scanRevision liest eine Revisions-Zeile (Spalten wie revisionColumns) ein.
+func scanRevision(row pgx.Row) (models.Revision, error) {
+	var rev models.Revision
+	var envBytes, commandBytes, argsBytes, probesBytes, secretEnvBytes []byte
+	if err := row.Scan(
+		&rev.ID, &rev.ServiceID, &rev.Name, &rev.Generation, &rev.Image, &rev.Port,
+		&commandBytes, &argsBytes, &envBytes, &rev.MinScale, &rev.MaxScale, &rev.CreatedAt,
+		&rev.CPU, &rev.Memory,
+		&rev.ContainerConcurrency, &rev.AutoscalingTarget, &rev.ScaleDownDelay, &rev.ScaleToZeroRetention,
+		&rev.TimeoutSeconds, &rev.ResponseStartTimeoutSeconds, &rev.IdleTimeoutSeconds, &probesBytes, &secretEnvBytes,
+	); err != nil {
+		return models.Revision{}, err
+	}
+
+	if err := json.Unmarshal(envBytes, &rev.EnvVars); err != nil {
+		return models.Revision{}, fmt.Errorf("unmarshaling env_vars: %w", err)
+	}
+	if err := json.Unmarshal(commandBytes, &rev.Command); err != nil {
+		return models.Revision{}, fmt.Errorf("unmarshaling command: %w", err)
+	}
+	if err := json.Unmarshal(argsBytes, &rev.Args); err != nil {
+		return models.Revision{}, fmt.Errorf("unmarshaling args: %w", err)
+	}
+	probes, err := unmarshalProbes(probesBytes)
+	if err != nil {
+		return models.Revision{}, err
+	}
+	rev.Probes = probes
+	if err := json.Unmarshal(secretEnvBytes, &rev.SecretEnv); err != nil {
+		return models.Revision{}, fmt.Errorf("unmarshaling secret_env: %w", err)
+	}
+	if len(rev.SecretEnv) == 0 {
+		rev.SecretEnv = nil
+	}
+
+	return rev, nil
+}
+
+// ListRevisions gibt die Revisionen eines Services zurück, neueste zuerst.
+func (s *PostgresStore) ListRevisions(ctx context.Context, serviceID string) ([]models.Revision, error) {
+	// Tenant-Prüfung über den Service
+	if _, err := s.Get(ctx, serviceID); err != nil {
+		return nil, err
+	}
+
+	rows, err := s.pool.Query(ctx,
+		`SELECT `+revisionColumns+` FROM revisions WHERE service_id = $1 ORDER BY generation DESC`,
+		serviceID,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("querying revisions: %w", err)
+	}
+	defer rows.Close()
+
+	var revisions []models.Revision
+	for rows.Next() {
+		rev, err := scanRevision(rows)
+		if err != nil {
+			return nil, fmt.Errorf("scanning revision: %w", err)
+		}
+		revisions = append(revisions, rev)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("iterating revisions: %w", err)
+	}
+
+	if revisions == nil {
+		revisions = []models.Revision{}
+	}
+	return revisions, nil
+}
+
 // Delete entfernt einen Service anhand seiner ID. Gibt ErrNotFound zurück, wenn nicht vorhanden.
 func (s *PostgresStore) Delete(ctx context.Context, id string) error {
 	query := `DELETE FROM services WHERE id = $1`
@@ -22,9 +22,9 @@ func (s *PostgresStore) Register(ctx context.Context, email, orgName string) (mo
 	// Organisation anlegen
 	var org models.Organization
 	err = tx.QueryRow(ctx,
-		`INSERT INTO organizations (name) VALUES ($1) RETURNING id, name, created_at`,
+		`INSERT INTO organizations (name) VALUES ($1) RETURNING id, name, max_scale_limit, created_at`,
 		orgName,
-	).Scan(&org.ID, &org.Name, &org.CreatedAt)
+	).Scan(&org.ID, &org.Name, &org.MaxScaleLimit, &org.CreatedAt)
 	if err != nil {
 		if isDuplicateError(err) {
 			return models.User{}, models.Organization{}, "", ErrDuplicateOrg
@@ -47,7 +47,7 @@ func (s *PostgresStore) Register(ctx context.Context, email, orgName string) (mo
 
 	// Membership
 	if _, err := tx.Exec(ctx,
-		`INSERT INTO org_members (org_id, user_id, role) VALUES ($1, $2, 'admin')`,
+		`INSERT INTO org_members (org_id, user_id, role) VALUES ($1, $2, 'owner')`,
 		org.ID, user.ID,
 	); err != nil {
 		return models.User{}, models.Organization{}, "", fmt.Errorf("insert membership: %w", err)
@@ -83,7 +83,8 @@ func (s *PostgresStore) ValidateAPIKey(ctx context.Context, rawKey string) (*mod
 	hash := hashAPIKey(rawKey)
 
 	rows, err := s.pool.Query(ctx,
-		`SELECT id, key_hash, prefix, name, org_id, user_id, created_at, expires_at, last_used_at
+		`SELECT id, key_hash, prefix, name, org_id, COALESCE(user_id::text, ''), COALESCE(service_account_id::text, ''),
+		        scopes, created_at, expires_at, lifetime_seconds, rotated_at, last_used_at
 		 FROM api_keys WHERE prefix = $1`,
 		prefix,
 	)
@@ -95,12 +96,15 @@ func (s *PostgresStore) ValidateAPIKey(ctx context.Context, rawKey string) (*mod
 	for rows.Next() {
 		var info models.APIKeyInfo
 		var dbHash string
+		var scopes []string
 		if err := rows.Scan(
 			&info.ID, &dbHash, &info.Prefix, &info.Name,
-			&info.OrgID, &info.UserID, &info.CreatedAt, &info.ExpiresAt, &info.LastUsedAt,
+			&info.OrgID, &info.UserID, &info.ServiceAccountID, &scopes, &info.CreatedAt, &info.ExpiresAt,
+			&info.LifetimeSeconds, &info.RotatedAt, &info.LastUsedAt,
 		); err != nil {
 			return nil, fmt.Errorf("scanning api key: %w", err)
 		}
+		info.Scopes = toPermissions(scopes)
 
 		if subtle.ConstantTimeCompare([]byte(dbHash), []byte(hash)) == 1 {
 			if info.ExpiresAt != nil && info.ExpiresAt.Before(time.Now()) {
@@ -114,23 +118,42 @@ func (s *PostgresStore) ValidateAPIKey(ctx context.Context, rawKey string) (*mod
 }
 
 // CreateAPIKey erstellt einen neuen API-Key.
-func (s *PostgresStore) CreateAPIKey(ctx context.Context, orgID, userID, name string) (string, *models.APIKeyInfo, error) {
+func (s *PostgresStore) CreateAPIKey(ctx context.Context, orgID, userID string, req models.CreateAPIKeyRequest) (string, *models.APIKeyInfo, error) {
+	return insertAPIKey(ctx, s.pool, orgID, userID, "", req)
+}
+
+// queryRower wird von pgxpool.Pool und pgx.Tx erfüllt.
+type queryRower interface {
+	QueryRow(ctx context.Context, sql string, args ...any) pgx.Row
+}
+
+// insertAPIKey legt einen API-Key für einen User oder einen Service-Account an,
+// der jeweils andere Besitzer bleibt leer.
+func insertAPIKey(ctx context.Context, q queryRower, orgID, userID, serviceAccountID string, req models.CreateAPIKeyRequest) (string, *models.APIKeyInfo, error) {
 	rawKey, keyHash, prefix, err := generateAPIKey()
 	if err != nil {
 		return "", nil, err
 	}
 
+	scopes := make([]string, len(req.Scopes))
+	for i, p := range req.Scopes {
+		scopes[i] = string(p)
+	}
+
 	var info models.APIKeyInfo
-	err = s.pool.QueryRow(ctx,
-		`INSERT INTO api_keys (key_hash, prefix, name, org_id, user_id)
-		 VALUES ($1, $2, $3, $4, $5)
-		 RETURNING id, prefix, name, org_id, user_id, created_at, expires_at, last_used_at`,
-		keyHash, prefix, name, orgID, userID,
-	).Scan(&info.ID, &info.Prefix, &info.Name, &info.OrgID, &info.UserID,
-		&info.CreatedAt, &info.ExpiresAt, &info.LastUsedAt)
+	err = q.QueryRow(ctx,
+		`INSERT INTO api_keys (key_hash, prefix, name, org_id, user_id, service_account_id, scopes, expires_at, lifetime_seconds)
+		 VALUES ($1, $2, $3, $4, NULLIF($5, '')::uuid, NULLIF($6, '')::uuid, $7, $8, $9)
+		 RETURNING id, prefix, name, org_id, COALESCE(user_id::text, ''), COALESCE(service_account_id::text, ''),
+		           created_at, expires_at, lifetime_seconds, rotated_at, last_used_at`,
+		keyHash, prefix, req.Name, orgID, userID, serviceAccountID, scopes, req.ExpiresAt,
+		models.APIKeyLifetimeSeconds(time.Now(), req.ExpiresAt),
+	).Scan(&info.ID, &info.Prefix, &info.Name, &info.OrgID, &info.UserID, &info.ServiceAccountID,
+		&info.CreatedAt, &info.ExpiresAt, &info.LifetimeSeconds, &info.RotatedAt, &info.LastUsedAt)
 	if err != nil {
 		return "", nil, fmt.Errorf("inserting api key: %w", err)
 	}
+	info.Scopes = toPermissions(scopes)
 
 	return rawKey, &info, nil
 }
@@ -138,7 +161,8 @@ func (s *PostgresStore) CreateAPIKey(ctx context.Context, orgID, userID, name st
 // ListAPIKeys gibt alle API-Keys einer Organisation zurück.
 func (s *PostgresStore) ListAPIKeys(ctx context.Context, orgID string) ([]models.APIKeyInfo, error) {
 	rows, err := s.pool.Query(ctx,
-		`SELECT id, prefix, name, org_id, user_id, created_at, expires_at, last_used_at
+		`SELECT id, prefix, name, org_id, COALESCE(user_id::text, ''), COALESCE(service_account_id::text, ''),
+		        scopes, created_at, expires_at, lifetime_seconds, rotated_at, last_used_at
 		 FROM api_keys WHERE org_id = $1 ORDER BY created_at`,
 		orgID,
 	)
@@ -150,12 +174,14 @@ func (s *PostgresStore) ListAPIKeys(ctx context.Context, orgID string) ([]models
 	var keys []models.APIKeyInfo
 	for rows.Next() {
 		var info models.APIKeyInfo
+		var scopes []string
 		if err := rows.Scan(
-			&info.ID, &info.Prefix, &info.Name, &info.OrgID, &info.UserID,
-			&info.CreatedAt, &info.ExpiresAt, &info.LastUsedAt,
+			&info.ID, &info.Prefix, &info.Name, &info.OrgID, &info.UserID, &info.ServiceAccountID, &scopes,
+			&info.CreatedAt, &info.ExpiresAt, &info.LifetimeSeconds, &info.RotatedAt, &info.LastUsedAt,
 		); err != nil {
 			return nil, fmt.Errorf("scanning api key: %w", err)
 		}
+		info.Scopes = toPermissions(scopes)
 		keys = append(keys, info)
 	}
 	if keys == nil {
@@ -164,11 +190,35 @@ func (s *PostgresStore) ListAPIKeys(ctx context.Context, orgID string) ([]models
 	return keys, nil
 }
 
-// DeleteAPIKey löscht einen API-Key.
-func (s *PostgresStore) DeleteAPIKey(ctx context.Context, orgID, keyID string) error {
-	result, err := s.pool.Exec(ctx,
-		`DELETE FROM api_keys WHERE id = $1 AND org_id = $2`,
+// GetAPIKey gibt einen API-Key anhand seiner ID zurück.
+func (s *PostgresStore) GetAPIKey(ctx context.Context, orgID, keyID string) (*models.APIKeyInfo, error) {
+	var info models.APIKeyInfo
+	var scopes []string
+	err := s.pool.QueryRow(ctx,
+		`SELECT id, prefix, name, org_id, COALESCE(user_id::text, ''), COALESCE(service_account_id::text, ''),
+		        scopes, created_at, expires_at, lifetime_seconds, rotated_at, last_used_at
+		 FROM api_keys WHERE id = $1 AND org_id = $2`,
 		keyID, orgID,
+	).Scan(
+		&info.ID, &info.Prefix, &info.Name, &info.OrgID, &info.UserID, &info.ServiceAccountID, &scopes,
+		&info.CreatedAt, &info.ExpiresAt, &info.LifetimeSeconds, &info.RotatedAt, &info.LastUsedAt,
+	)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, ErrKeyNotFound
+		}
+		return nil, fmt.Errorf("querying api key: %w", err)
+	}
+	info.Scopes = toPermissions(scopes)
+	return &info, nil
+}
+
+// DeleteAPIKey löscht einen API-Key, sofern er ownerID gehört.
+func (s *PostgresStore) DeleteAPIKey(ctx context.Context, orgID, ownerID, keyID string) error {
+	result, err := s.pool.Exec(ctx,
+		`DELETE FROM api_keys
+		 WHERE id = $1 AND org_id = $2 AND COALESCE(user_id, service_account_id)::text = $3`,
+		keyID, orgID, ownerID,
 	)
 	if err != nil {
 		return fmt.Errorf("deleting api key: %w", err)
@@ -179,13 +229,68 @@ func (s *PostgresStore) DeleteAPIKey(ctx context.Context, orgID, keyID string) e
 	return nil
 }
 
-// GetAuthInfo gibt Informationen über User, Organisation und Rolle zurück.
+// RotateAPIKey stellt einen Nachfolger des Keys von ownerID aus und verkürzt die Gültigkeit
+// des alten Keys auf gracePeriod. Beides geschieht in einer Transaktion, jeder Key kann nur
+// einmal rotiert werden.
+func (s *PostgresStore) RotateAPIKey(ctx context.Context, orgID, ownerID, keyID string, gracePeriod time.Duration) (string, *models.APIKeyInfo, error) {
+	tx, err := s.pool.Begin(ctx)
+	if err != nil {
+		return "", nil, fmt.Errorf("begin tx: %w", err)
+	}
+	defer tx.Rollback(ctx)
+
+	var old models.APIKeyInfo
+	var scopes []string
+	err = tx.QueryRow(ctx,
+		`SELECT name, COALESCE(user_id::text, ''), COALESCE(service_account_id::text, ''), scopes,
+		        created_at, expires_at, lifetime_seconds, rotated_at
+		 FROM api_keys
+		 WHERE id = $1 AND org_id = $2 AND COALESCE(user_id, service_account_id)::text = $3
+		   AND (expires_at IS NULL OR expires_at > now())
+		 FOR UPDATE`,
+		keyID, orgID, ownerID,
+	).Scan(&old.Name, &old.UserID, &old.ServiceAccountID, &scopes, &old.CreatedAt, &old.ExpiresAt, &old.LifetimeSeconds, &old.RotatedAt)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return "", nil, ErrKeyNotFound
+	}
+	if err != nil {
+		return "", nil, fmt.Errorf("querying api key: %w", err)
+	}
+	if old.RotatedAt != nil {
+		return "", nil, ErrKeyRotated
+	}
+
+	now := time.Now()
+	rawKey, info, err := insertAPIKey(ctx, tx, orgID, old.UserID, old.ServiceAccountID, models.CreateAPIKeyRequest{
+		Name:      old.Name,
+		Scopes:    toPermissions(scopes),
+		ExpiresAt: old.RotatedExpiry(now),
+	})
+	if err != nil {
+		return "", nil, err
+	}
+
+	// LEAST ignoriert NULL, ein Key ohne Ablauf erhält damit das Ende der Übergangszeit
+	if _, err := tx.Exec(ctx,
+		`UPDATE api_keys SET expires_at = LEAST(expires_at, $2), rotated_at = $3 WHERE id = $1`,
+		keyID, now.Add(gracePeriod), now,
+	); err != nil {
+		return "", nil, fmt.Errorf("expiring api key: %w", err)
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return "", nil, fmt.Errorf("commit: %w", err)
+	}
+	return rawKey, info, nil
+}
+
+// GetAuthInfo gibt Informationen über User bzw. Service-Account, Organisation und Rolle zurück.
 func (s *PostgresStore) GetAuthInfo(ctx context.Context, orgID, userID string) (*models.AuthInfo, error) {
 	var info models.AuthInfo
 	var role string
 	err := s.pool.QueryRow(ctx,
 		`SELECT u.id, u.email, u.created_at,
-		        o.id, o.name, o.created_at,
+		        o.id, o.name, o.max_scale_limit, o.created_at,
 		        m.role
 		 FROM users u
 		 JOIN org_members m ON m.user_id = u.id
@@ -194,12 +299,12 @@ func (s *PostgresStore) GetAuthInfo(ctx context.Context, orgID, userID string) (
 		orgID, userID,
 	).Scan(
 		&info.User.ID, &info.User.Email, &info.User.CreatedAt,
-		&info.Organization.ID, &info.Organization.Name, &info.Organization.CreatedAt,
+		&info.Organization.ID, &info.Organization.Name, &info.Organization.MaxScaleLimit, &info.Organization.CreatedAt,
 		&role,
 	)
 	if err != nil {
 		if errors.Is(err, pgx.ErrNoRows) {
-			return nil, ErrNotFound
+			return s.serviceAccountAuthInfo(ctx, orgID, userID)
 		}
 		return nil, fmt.Errorf("querying auth info: %w", err)
 	}
@@ -207,6 +312,181 @@ func (s *PostgresStore) GetAuthInfo(ctx context.Context, orgID, userID string) (
 	return &info, nil
 }
 
+// GetOrganization gibt eine Organisation anhand ihrer ID zurück.
+func (s *PostgresStore) GetOrganization(ctx context.Context, orgID string) (models.Organization, error) {
+	var org models.Organization
+	err := s.pool.QueryRow(ctx,
+		`SELECT id, name, max_scale_limit, created_at FROM organizations WHERE id = $1`, orgID,
+	).Scan(&org.ID, &org.Name, &org.MaxScaleLimit, &org.CreatedAt)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return models.Organization{}, ErrNotFound
+		}
+		return models.Organization{}, fmt.Errorf("querying organization: %w", err)
+	}
+	return org, nil
+}
+
+// DeleteOrganization löscht eine Organisation. Domains und Services verweisen ohne
+// ON DELETE CASCADE auf ihre Organisation und werden deshalb vorher gelöscht;
+// alle übrigen Daten entfernt die Datenbank per Kaskade.
+func (s *PostgresStore) DeleteOrganization(ctx context.Context, orgID string) error {
+	tx, err := s.pool.Begin(ctx)
+	if err != nil {
+		return fmt.Errorf("begin tx: %w", err)
+	}
+	defer tx.Rollback(ctx)
+
+	if _, err := tx.Exec(ctx, `DELETE FROM domains WHERE org_id = $1`, orgID); err != nil {
+		return fmt.Errorf("deleting domains: %w", err)
+	}
+	if _, err := tx.Exec(ctx, `DELETE FROM services WHERE org_id = $1`, orgID); err != nil {
+		return fmt.Errorf("deleting services: %w", err)
+	}
+	result, err := tx.Exec(ctx, `DELETE FROM organizations WHERE id = $1`, orgID)
+	if err != nil {
+		return fmt.Errorf("deleting organization: %w", err)
+	}
+	if result.RowsAffected() == 0 {
+		return ErrNotFound
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return fmt.Errorf("commit: %w", err)
+	}
+	return nil
+}
+
+// ListMembers gibt alle Mitglieder einer Organisation sortiert nach E-Mail zurück.
+func (s *PostgresStore) ListMembers(ctx context.Context, orgID string) ([]models.OrgMember, error) {
+	rows, err := s.pool.Query(ctx,
+		`SELECT u.id, u.email, m.role
+		 FROM org_members m
+		 JOIN users u ON u.id = m.user_id
+		 WHERE m.org_id = $1
+		 ORDER BY u.email`,
+		orgID,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("querying members: %w", err)
+	}
+	defer rows.Close()
+
+	members := []models.OrgMember{}
+	for rows.Next() {
+		var m models.OrgMember
+		var role string
+		if err := rows.Scan(&m.UserID, &m.Email, &role); err != nil {
+			return nil, fmt.Errorf("scanning member: %w", err)
+		}
+		m.Role = models.OrgRole(role)
+		members = append(members, m)
+	}
+	return members, rows.Err()
+}
+
+// UpdateMemberRole ändert die Rolle eines Mitglieds. Die Mitglieder der Organisation
+// werden dabei gesperrt, damit zwei parallele Herabstufungen nicht den letzten Owner entfernen.
+func (s *PostgresStore) UpdateMemberRole(ctx context.Context, orgID, userID string, role models.OrgRole) (models.OrgMember, error) {
+	tx, err := s.pool.Begin(ctx)
+	if err != nil {
+		return models.OrgMember{}, fmt.Errorf("begin tx: %w", err)
+	}
+	defer tx.Rollback(ctx)
+
+	if err := checkLastOwner(ctx, tx, orgID, userID, role); err != nil {
+		return models.OrgMember{}, err
+	}
+
+	m := models.OrgMember{UserID: userID, Role: role}
+	err = tx.QueryRow(ctx,
+		`UPDATE org_members m SET role = $3
+		 FROM users u
+		 WHERE u.id = m.user_id AND m.org_id = $1 AND m.user_id = $2
+		 RETURNING u.email`,
+		orgID, userID, string(role),
+	).Scan(&m.Email)
+	if err != nil {
+		return models.OrgMember{}, fmt.Errorf("updating member role: %w", err)
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return models.OrgMember{}, fmt.Errorf("commit: %w", err)
+	}
+	return m, nil
+}
+
+// RemoveMember entfernt ein Mitglied und löscht seine API-Keys in der Organisation.
+func (s *PostgresStore) RemoveMember(ctx context.Context, orgID, userID string) error {
+	tx, err := s.pool.Begin(ctx)
+	if err != nil {
+		return fmt.Errorf("begin tx: %w", err)
+	}
+	defer tx.Rollback(ctx)
+
+	if err := checkLastOwner(ctx, tx, orgID, userID, ""); err != nil {
+		return err
+	}
+
+	if _, err := tx.Exec(ctx,
+		`DELETE FROM api_keys WHERE org_id = $1 AND user_id = $2`,
+		orgID, userID,
+	); err != nil {
+		return fmt.Errorf("deleting api keys: %w", err)
+	}
+	if _, err := tx.Exec(ctx,
+		`DELETE FROM org_members WHERE org_id = $1 AND user_id = $2`,
+		orgID, userID,
+	); err != nil {
+		return fmt.Errorf("deleting member: %w", err)
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return fmt.Errorf("commit: %w", err)
+	}
+	return nil
+}
+
+// checkLastOwner sperrt die Mitglieder einer Organisation und prüft, ob nach dem Wechsel
+// von userID auf newRole (leer = entfernt) noch ein Owner übrig bleibt.
+func checkLastOwner(ctx context.Context, tx pgx.Tx, orgID, userID string, newRole models.OrgRole) error {
+	rows, err := tx.Query(ctx,
+		`SELECT user_id, role FROM org_members WHERE org_id = $1 FOR UPDATE`,
+		orgID,
+	)
+	if err != nil {
+		return fmt.Errorf("locking members: %w", err)
+	}
+	defer rows.Close()
+
+	var current models.OrgRole
+	owners := 0
+	for rows.Next() {
+		var id, role string
+		if err := rows.Scan(&id, &role); err != nil {
+			return fmt.Errorf("scanning member: %w", err)
+		}
+		if id == userID {
+			current = models.OrgRole(role)
+			continue
+		}
+		if models.OrgRole(role) == models.OrgRoleOwner {
+			owners++
+		}
+	}
+	if err := rows.Err(); err != nil {
+		return fmt.Errorf("reading members: %w", err)
+	}
+
+	if current == "" {
+		return ErrMemberNotFound
+	}
+	if current == models.OrgRoleOwner && newRole != models.OrgRoleOwner && owners == 0 {
+		return ErrLastOwner
+	}
+	return nil
+}
+
 // UpdateAPIKeyLastUsed aktualisiert den last_used_at Timestamp.
 func (s *PostgresStore) UpdateAPIKeyLastUsed(ctx context.Context, keyID string) error {
 	_, err := s.pool.Exec(ctx,
@@ -219,6 +499,18 @@ func (s *PostgresStore) UpdateAPIKeyLastUsed(ctx context.Context, keyID string)
 	return nil
 }
 
+// toPermissions wandelt die Scopes aus der Datenbank um; ein leeres Array ergibt nil.
+func toPermissions(scopes []string) []models.Permission {
+	if len(scopes) == 0 {
+		return nil
+	}
+	perms := make([]models.Permission, len(scopes))
+	for i, s := range scopes {
+		perms[i] = models.Permission(s)
+	}
+	return perms
+}
+
 // isDuplicateError prüft auf PostgreSQL unique constraint violation.
 func isDuplicateError(err error) bool {
 	return err != nil && (errors.Is(err, pgx.ErrNoRows) == false) &&
@@ -412,8 +704,8 @@ func (s *PostgresStore) AcceptInvite(ctx context.Context, rawToken string) (mode
 	// Organisation laden
 	var org models.Organization
 	err = s.pool.QueryRow(ctx,
-		`SELECT id, name, created_at FROM organizations WHERE id = $1`, orgID,
-	).Scan(&org.ID, &org.Name, &org.CreatedAt)
+		`SELECT id, name, max_scale_limit, created_at FROM organizations WHERE id = $1`, orgID,
+	).Scan(&org.ID, &org.Name, &org.MaxScaleLimit, &org.CreatedAt)
 	if err != nil {
 		return models.User{}, models.Organization{}, "", "", fmt.Errorf("query org: %w", err)
 	}
@@ -0,0 +1,171 @@
+package store
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/max-cloud/api/internal/auth"
+	"github.com/max-cloud/shared/pkg/models"
+)
+
+const domainColumns = `id, org_id, service_id, hostname, verification_token, status, certificate_ready, message, verified_at, created_at, updated_at`
+
+func scanDomain(row pgx.Row) (models.Domain, error) {
+	var d models.Domain
+	var orgID *string
+	if err := row.Scan(
+		&d.ID, &orgID, &d.ServiceID, &d.Hostname, &d.VerificationToken, &d.Status,
+		&d.CertificateReady, &d.Message, &d.VerifiedAt, &d.CreatedAt, &d.UpdatedAt,
+	); err != nil {
+		return models.Domain{}, err
+	}
+	if orgID != nil {
+		d.OrgID = *orgID
+	}
+	return d, nil
+}
+
+// CreateDomain legt eine unverifizierte Domain an.
+func (s *PostgresStore) CreateDomain(ctx context.Context, req models.CreateDomainRequest) (models.Domain, error) {
+	token, err := generateDomainToken()
+	if err != nil {
+		return models.Domain{}, err
+	}
+
+	var orgIDParam any
+	if orgID, ok := auth.OrgIDFromContext(ctx); ok {
+		orgIDParam = orgID
+	}
+
+	d, err := scanDomain(s.pool.QueryRow(ctx,
+		`INSERT INTO domains (org_id, service_id, hostname, verification_token, status)
+		 VALUES ($1, $2, $3, $4, $5)
+		 RETURNING `+domainColumns,
+		orgIDParam, req.ServiceID, req.Hostname, token, string(models.DomainStatusPendingVerification),
+	))
+	if err != nil {
+		if isDuplicateError(err) {
+			return models.Domain{}, ErrDuplicateDomain
+		}
+		return models.Domain{}, fmt.Errorf("inserting domain: %w", err)
+	}
+	return d, nil
+}
+
+// GetDomain gibt eine Domain anhand ihrer ID zurück.
+func (s *PostgresStore) GetDomain(ctx context.Context, id string) (models.Domain, error) {
+	query := `SELECT ` + domainColumns + ` FROM domains WHERE id = $1`
+	args := []any{id}
+
+	if orgID, ok := auth.OrgIDFromContext(ctx); ok {
+		query += ` AND org_id = $2`
+		args = append(args, orgID)
+	}
+
+	d, err := scanDomain(s.pool.QueryRow(ctx, query, args...))
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return models.Domain{}, ErrDomainNotFound
+		}
+		return models.Domain{}, fmt.Errorf("querying domain: %w", err)
+	}
+	return d, nil
+}
+
+// ListDomains gibt alle Domains sortiert nach Hostname zurück.
+func (s *PostgresStore) ListDomains(ctx context.Context) ([]models.Domain, error) {
+	query := `SELECT ` + domainColumns + ` FROM domains`
+	var args []any
+
+	if orgID, ok := auth.OrgIDFromContext(ctx); ok {
+		query += ` WHERE org_id = $1`
+		args = append(args, orgID)
+	}
+	query += ` ORDER BY hostname`
+
+	rows, err := s.pool.Query(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("querying domains: %w", err)
+	}
+	defer rows.Close()
+
+	domains := []models.Domain{}
+	for rows.Next() {
+		d, err := scanDomain(rows)
+		if err != nil {
+			return nil, fmt.Errorf("scanning domain: %w", err)
+		}
+		domains = append(domains, d)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("iterating domains: %w", err)
+	}
+	return domains, nil
+}
+
+// VerifyDomain markiert eine Domain als verifiziert. Der partielle Unique-Index auf
+// verifizierte Hostnamen verhindert, dass zwei Organisationen denselben Hostnamen verifizieren.
+func (s *PostgresStore) VerifyDomain(ctx context.Context, id string) (models.Domain, error) {
+	query := `UPDATE domains
+		SET verified_at = COALESCE(verified_at, NOW()),
+		    status = CASE WHEN verified_at IS NULL THEN $2 ELSE status END,
+		    message = CASE WHEN verified_at IS NULL THEN '' ELSE message END,
+		    updated_at = NOW()
+		WHERE id = $1`
+	args := []any{id, string(models.DomainStatusProvisioning)}
+
+	if orgID, ok := auth.OrgIDFromContext(ctx); ok {
+		query += ` AND org_id = $3`
+		args = append(args, orgID)
+	}
+	query += ` RETURNING ` + domainColumns
+
+	d, err := scanDomain(s.pool.QueryRow(ctx, query, args...))
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return models.Domain{}, ErrDomainNotFound
+		}
+		if isDuplicateError(err) {
+			return models.Domain{}, ErrDuplicateDomain
+		}
+		return models.Domain{}, fmt.Errorf("verifying domain: %w", err)
+	}
+	return d, nil
+}
+
+// UpdateDomainStatus setzt Status, Zertifikatszustand und Meldung einer Domain.
+func (s *PostgresStore) UpdateDomainStatus(ctx context.Context, id string, status models.DomainStatus, certificateReady bool, message string) error {
+	result, err := s.pool.Exec(ctx,
+		`UPDATE domains SET status = $1, certificate_ready = $2, message = $3, updated_at = NOW() WHERE id = $4`,
+		string(status), certificateReady, message, id,
+	)
+	if err != nil {
+		return fmt.Errorf("updating domain status: %w", err)
+	}
+	if result.RowsAffected() == 0 {
+		return ErrDomainNotFound
+	}
+	return nil
+}
+
+// DeleteDomain entfernt eine Domain.
+func (s *PostgresStore) DeleteDomain(ctx context.Context, id string) error {
+	query := `DELETE FROM domains WHERE id = $1`
+	args := []any{id}
+
+	if orgID, ok := auth.OrgIDFromContext(ctx); ok {
+		query += ` AND org_id = $2`
+		args = append(args, orgID)
+	}
+
+	result, err := s.pool.Exec(ctx, query, args...)
+	if err != nil {
+		return fmt.Errorf("deleting domain: %w", err)
+	}
+	if result.RowsAffected() == 0 {
+		return ErrDomainNotFound
+	}
+	return nil
+}
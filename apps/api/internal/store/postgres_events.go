@@ -0,0 +1,53 @@
+package store
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/max-cloud/shared/pkg/models"
+)
+
+// RecordEvent hängt ein Ereignis an die Historie eines Services an.
+func (s *PostgresStore) RecordEvent(ctx context.Context, event models.ServiceEvent) error {
+	var orgIDParam any
+	if event.OrgID != "" {
+		orgIDParam = event.OrgID
+	}
+
+	_, err := s.pool.Exec(ctx,
+		`INSERT INTO service_events (service_id, org_id, type, reason, message, actor) VALUES ($1, $2, $3, $4, $5, $6)`,
+		event.ServiceID, orgIDParam, string(event.Type), event.Reason, event.Message, event.Actor,
+	)
+	if err != nil {
+		return fmt.Errorf("inserting service event: %w", err)
+	}
+	return nil
+}
+
+// ListEvents gibt die letzten limit Ereignisse eines Services zurück, neueste zuerst.
+func (s *PostgresStore) ListEvents(ctx context.Context, serviceID string, limit int) ([]models.ServiceEvent, error) {
+	rows, err := s.pool.Query(ctx,
+		`SELECT id, service_id, org_id, type, reason, message, actor, created_at
+		 FROM service_events WHERE service_id = $1
+		 ORDER BY created_at DESC, id DESC LIMIT $2`,
+		serviceID, limit,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("querying service events: %w", err)
+	}
+	defer rows.Close()
+
+	events := []models.ServiceEvent{}
+	for rows.Next() {
+		var e models.ServiceEvent
+		var orgID *string
+		if err := rows.Scan(&e.ID, &e.ServiceID, &orgID, &e.Type, &e.Reason, &e.Message, &e.Actor, &e.CreatedAt); err != nil {
+			return nil, fmt.Errorf("scanning service event: %w", err)
+		}
+		if orgID != nil {
+			e.OrgID = *orgID
+		}
+		events = append(events, e)
+	}
+	return events, rows.Err()
+}
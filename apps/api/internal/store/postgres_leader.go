@@ -0,0 +1,111 @@
+package store
+
+import (
+	"context"
+	"log/slog"
+	"time"
+
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/max-cloud/api/internal/leader"
+)
+
+// reconcilerLockKey ist der Schlüssel des Advisory Locks, den die führende Replika hält.
+const reconcilerLockKey int64 = 0x6d61786300000001
+
+// leaderRetryPeriod ist der Abstand, in dem sich Replikas um den Lock bewerben und
+// die führende Replika ihre Verbindung prüft.
+const leaderRetryPeriod = 2 * time.Second
+
+// advisoryLockElector wählt die führende Replika über einen Postgres Advisory Lock.
+// Der Lock hängt an der Datenbank-Session: stürzt die führende Replika ab, gibt Postgres
+// ihn mit dem Ende der Verbindung frei und eine andere Replika übernimmt beim nächsten Versuch.
+type advisoryLockElector struct {
+	pool   *pgxpool.Pool
+	logger *slog.Logger
+}
+
+// LeaderElector gibt einen Elector zurück, der die Führung über einen Advisory Lock vergibt.
+func (s *PostgresStore) LeaderElector(logger *slog.Logger) leader.Elector {
+	return &advisoryLockElector{pool: s.pool, logger: logger}
+}
+
+func (e *advisoryLockElector) Run(ctx context.Context, lead func(ctx context.Context)) {
+	ticker := time.NewTicker(leaderRetryPeriod)
+	defer ticker.Stop()
+
+	for {
+		if conn := e.tryAcquire(ctx); conn != nil {
+			e.hold(ctx, conn, lead)
+		}
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// tryAcquire versucht den Lock auf einer eigenen Verbindung zu erlangen und gibt diese
+// zurück, solange der Lock gehalten wird. Nil, wenn eine andere Replika führt.
+func (e *advisoryLockElector) tryAcquire(ctx context.Context) *pgxpool.Conn {
+	conn, err := e.pool.Acquire(ctx)
+	if err != nil {
+		if ctx.Err() == nil {
+			e.logger.Error("leader: acquiring connection failed", "error", err)
+		}
+		return nil
+	}
+
+	var locked bool
+	if err := conn.QueryRow(ctx, `SELECT pg_try_advisory_lock($1)`, reconcilerLockKey).Scan(&locked); err != nil {
+		if ctx.Err() == nil {
+			e.logger.Error("leader: trying advisory lock failed", "error", err)
+		}
+		conn.Release()
+		return nil
+	}
+	if !locked {
+		conn.Release()
+		return nil
+	}
+	return conn
+}
+
+// hold führt lead aus, solange die Verbindung mit dem Lock besteht, und gibt den Lock danach frei.
+func (e *advisoryLockElector) hold(ctx context.Context, conn *pgxpool.Conn, lead func(ctx context.Context)) {
+	leadCtx, cancel := context.WithCancel(ctx)
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		lead(leadCtx)
+	}()
+
+	ticker := time.NewTicker(leaderRetryPeriod)
+	defer ticker.Stop()
+
+loop:
+	for {
+		select {
+		case <-leadCtx.Done():
+			break loop
+		case <-done:
+			break loop
+		case <-ticker.C:
+			if err := conn.Ping(leadCtx); err != nil {
+				// Ohne Verbindung ist nicht sicher, ob der Lock noch gehalten wird
+				e.logger.Warn("leader: lost database connection, giving up leadership", "error", err)
+				break loop
+			}
+		}
+	}
+	cancel()
+	<-done
+
+	releaseCtx, releaseCancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer releaseCancel()
+	if _, err := conn.Exec(releaseCtx, `SELECT pg_advisory_unlock($1)`, reconcilerLockKey); err != nil {
+		// Verbindung schließen, damit die Session den Lock nicht im Pool weiter hält
+		conn.Conn().Close(releaseCtx)
+	}
+	conn.Release()
+}
@@ -0,0 +1,108 @@
+package store
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/max-cloud/shared/pkg/models"
+)
+
+// SetSecret legt ein Secret an oder überschreibt seinen Wert.
+func (s *PostgresStore) SetSecret(ctx context.Context, orgID, name, value string) (models.Secret, error) {
+	ciphertext, err := s.secretCipher.encrypt(value, secretAAD(orgID, name))
+	if err != nil {
+		return models.Secret{}, err
+	}
+
+	var secret models.Secret
+	err = s.pool.QueryRow(ctx,
+		`INSERT INTO secrets (org_id, name, value) VALUES ($1, $2, $3)
+		 ON CONFLICT (org_id, name) DO UPDATE SET value = EXCLUDED.value, updated_at = NOW()
+		 RETURNING id, org_id, name, created_at, updated_at`,
+		orgID, name, ciphertext,
+	).Scan(&secret.ID, &secret.OrgID, &secret.Name, &secret.CreatedAt, &secret.UpdatedAt)
+	if err != nil {
+		return models.Secret{}, fmt.Errorf("upserting secret: %w", err)
+	}
+	return secret, nil
+}
+
+// ListSecrets gibt die Secrets einer Organisation sortiert nach Namen zurück, ohne Werte.
+func (s *PostgresStore) ListSecrets(ctx context.Context, orgID string) ([]models.Secret, error) {
+	rows, err := s.pool.Query(ctx,
+		`SELECT id, org_id, name, created_at, updated_at FROM secrets WHERE org_id = $1 ORDER BY name`,
+		orgID,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("querying secrets: %w", err)
+	}
+	defer rows.Close()
+
+	secrets := []models.Secret{}
+	for rows.Next() {
+		var secret models.Secret
+		if err := rows.Scan(&secret.ID, &secret.OrgID, &secret.Name, &secret.CreatedAt, &secret.UpdatedAt); err != nil {
+			return nil, fmt.Errorf("scanning secret: %w", err)
+		}
+		secrets = append(secrets, secret)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("iterating secrets: %w", err)
+	}
+	return secrets, nil
+}
+
+// DeleteSecret löscht ein Secret.
+func (s *PostgresStore) DeleteSecret(ctx context.Context, orgID, name string) error {
+	result, err := s.pool.Exec(ctx,
+		`DELETE FROM secrets WHERE org_id = $1 AND name = $2`,
+		orgID, name,
+	)
+	if err != nil {
+		return fmt.Errorf("deleting secret: %w", err)
+	}
+	if result.RowsAffected() == 0 {
+		return ErrSecretNotFound
+	}
+	return nil
+}
+
+// SecretValues gibt die entschlüsselten Werte der genannten Secrets zurück.
+func (s *PostgresStore) SecretValues(ctx context.Context, orgID string, names []string) (map[string]string, error) {
+	values := make(map[string]string, len(names))
+	if len(names) == 0 {
+		return values, nil
+	}
+
+	rows, err := s.pool.Query(ctx,
+		`SELECT name, value FROM secrets WHERE org_id = $1 AND name = ANY($2)`,
+		orgID, names,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("querying secret values: %w", err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var name string
+		var ciphertext []byte
+		if err := rows.Scan(&name, &ciphertext); err != nil {
+			return nil, fmt.Errorf("scanning secret value: %w", err)
+		}
+		value, err := s.secretCipher.decrypt(ciphertext, secretAAD(orgID, name))
+		if err != nil {
+			return nil, err
+		}
+		values[name] = value
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("iterating secret values: %w", err)
+	}
+
+	for _, name := range names {
+		if _, ok := values[name]; !ok {
+			return nil, fmt.Errorf("%w: %s", ErrSecretNotFound, name)
+		}
+	}
+	return values, nil
+}
@@ -0,0 +1,124 @@
+package store
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/max-cloud/shared/pkg/models"
+)
+
+// CreateServiceAccount legt einen Service-Account an.
+func (s *PostgresStore) CreateServiceAccount(ctx context.Context, orgID, name string, role models.OrgRole) (models.ServiceAccount, error) {
+	var sa models.ServiceAccount
+	err := s.pool.QueryRow(ctx,
+		`INSERT INTO service_accounts (org_id, name, role) VALUES ($1, $2, $3)
+		 RETURNING id, org_id, name, role, created_at`,
+		orgID, name, string(role),
+	).Scan(&sa.ID, &sa.OrgID, &sa.Name, &sa.Role, &sa.CreatedAt)
+	if err != nil {
+		if isDuplicateError(err) {
+			return models.ServiceAccount{}, ErrDuplicateServiceAccount
+		}
+		return models.ServiceAccount{}, fmt.Errorf("inserting service account: %w", err)
+	}
+	return sa, nil
+}
+
+// ListServiceAccounts gibt alle Service-Accounts einer Organisation sortiert nach Namen zurück.
+func (s *PostgresStore) ListServiceAccounts(ctx context.Context, orgID string) ([]models.ServiceAccount, error) {
+	rows, err := s.pool.Query(ctx,
+		`SELECT id, org_id, name, role, created_at FROM service_accounts
+		 WHERE org_id = $1 ORDER BY name`,
+		orgID,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("querying service accounts: %w", err)
+	}
+	defer rows.Close()
+
+	accounts := []models.ServiceAccount{}
+	for rows.Next() {
+		var sa models.ServiceAccount
+		if err := rows.Scan(&sa.ID, &sa.OrgID, &sa.Name, &sa.Role, &sa.CreatedAt); err != nil {
+			return nil, fmt.Errorf("scanning service account: %w", err)
+		}
+		accounts = append(accounts, sa)
+	}
+	return accounts, rows.Err()
+}
+
+// GetServiceAccount gibt einen Service-Account der Organisation zurück.
+func (s *PostgresStore) GetServiceAccount(ctx context.Context, orgID, id string) (models.ServiceAccount, error) {
+	var sa models.ServiceAccount
+	err := s.pool.QueryRow(ctx,
+		`SELECT id, org_id, name, role, created_at FROM service_accounts
+		 WHERE id = $1 AND org_id = $2`,
+		id, orgID,
+	).Scan(&sa.ID, &sa.OrgID, &sa.Name, &sa.Role, &sa.CreatedAt)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return models.ServiceAccount{}, ErrServiceAccountNotFound
+		}
+		return models.ServiceAccount{}, fmt.Errorf("querying service account: %w", err)
+	}
+	return sa, nil
+}
+
+// UpdateServiceAccountRole ändert die Rolle eines Service-Accounts.
+func (s *PostgresStore) UpdateServiceAccountRole(ctx context.Context, orgID, id string, role models.OrgRole) (models.ServiceAccount, error) {
+	var sa models.ServiceAccount
+	err := s.pool.QueryRow(ctx,
+		`UPDATE service_accounts SET role = $3 WHERE id = $1 AND org_id = $2
+		 RETURNING id, org_id, name, role, created_at`,
+		id, orgID, string(role),
+	).Scan(&sa.ID, &sa.OrgID, &sa.Name, &sa.Role, &sa.CreatedAt)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return models.ServiceAccount{}, ErrServiceAccountNotFound
+		}
+		return models.ServiceAccount{}, fmt.Errorf("updating service account: %w", err)
+	}
+	return sa, nil
+}
+
+// DeleteServiceAccount löscht einen Service-Account, seine API-Keys entfernt die Datenbank per Kaskade.
+func (s *PostgresStore) DeleteServiceAccount(ctx context.Context, orgID, id string) error {
+	result, err := s.pool.Exec(ctx,
+		`DELETE FROM service_accounts WHERE id = $1 AND org_id = $2`,
+		id, orgID,
+	)
+	if err != nil {
+		return fmt.Errorf("deleting service account: %w", err)
+	}
+	if result.RowsAffected() == 0 {
+		return ErrServiceAccountNotFound
+	}
+	return nil
+}
+
+// CreateServiceAccountAPIKey erstellt einen API-Key, der dem Service-Account gehört.
+func (s *PostgresStore) CreateServiceAccountAPIKey(ctx context.Context, orgID, id string, req models.CreateAPIKeyRequest) (string, *models.APIKeyInfo, error) {
+	sa, err := s.GetServiceAccount(ctx, orgID, id)
+	if err != nil {
+		return "", nil, err
+	}
+	return insertAPIKey(ctx, s.pool, orgID, "", sa.ID, req)
+}
+
+// serviceAccountAuthInfo gibt die AuthInfo eines Service-Accounts zurück.
+func (s *PostgresStore) serviceAccountAuthInfo(ctx context.Context, orgID, id string) (*models.AuthInfo, error) {
+	sa, err := s.GetServiceAccount(ctx, orgID, id)
+	if err != nil {
+		if errors.Is(err, ErrServiceAccountNotFound) {
+			return nil, ErrNotFound
+		}
+		return nil, err
+	}
+	org, err := s.GetOrganization(ctx, orgID)
+	if err != nil {
+		return nil, err
+	}
+	return &models.AuthInfo{ServiceAccount: &sa, Organization: org, Role: sa.Role}, nil
+}
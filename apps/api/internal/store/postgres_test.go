@@ -3,8 +3,11 @@ package store
 import (
 	"context"
 	"errors"
+	"log/slog"
 	"os"
+	"strings"
 	"testing"
+	"time"
 
 	"github.com/max-cloud/api/internal/auth"
 	"github.com/max-cloud/shared/pkg/models"
@@ -19,13 +22,22 @@ func newPostgresStore(t *testing.T) *PostgresStore {
 	}
 
 	ctx := context.Background()
-	s, err := NewPostgres(ctx, dbURL)
+	key, err := GenerateSecretKey()
+	if err != nil {
+		t.Fatalf("failed to generate secret key: %v", err)
+	}
+	secretCipher, err := NewSecretCipher(key)
+	if err != nil {
+		t.Fatalf("failed to create secret cipher: %v", err)
+	}
+
+	s, err := NewPostgres(ctx, dbURL, secretCipher)
 	if err != nil {
 		t.Fatalf("failed to create PostgresStore: %v", err)
 	}
 
 	// Tabellen vor jedem Test leeren (Reihenfolge wegen FK-Constraints)
-	for _, table := range []string{"invitations", "api_keys", "org_members", "services", "users", "organizations"} {
+	for _, table := range []string{"service_events", "domains", "secrets", "invitations", "api_keys", "org_members", "revisions", "services", "users", "organizations"} {
 		if _, err := s.pool.Exec(ctx, "DELETE FROM "+table); err != nil {
 			t.Fatalf("failed to clean %s table: %v", table, err)
 		}
@@ -202,6 +214,155 @@ func TestPostgresUpdateStatusNotFound(t *testing.T) {
 	}
 }
 
+func TestPostgresUpdate(t *testing.T) {
+	s := newPostgresStore(t)
+	ctx := context.Background()
+
+	created, err := s.Create(ctx, models.DeployRequest{Name: "app", Image: "img:1"})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	created.Image = "img:2"
+	created.EnvVars = map[string]string{"FOO": "bar"}
+	updated, err := s.Update(ctx, created)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if updated.Image != "img:2" {
+		t.Fatalf("expected image img:2, got %s", updated.Image)
+	}
+	if updated.EnvVars["FOO"] != "bar" {
+		t.Fatalf("expected env FOO=bar, got %v", updated.EnvVars)
+	}
+	if updated.Generation != created.Generation+1 {
+		t.Fatalf("expected generation %d, got %d", created.Generation+1, updated.Generation)
+	}
+
+	// Erneutes Update mit veralteter Generation
+	_, err = s.Update(ctx, created)
+	if !errors.Is(err, ErrConflict) {
+		t.Fatalf("expected ErrConflict, got %v", err)
+	}
+}
+
+func TestPostgresRevisions(t *testing.T) {
+	s := newPostgresStore(t)
+	ctx := context.Background()
+
+	created, err := s.Create(ctx, models.DeployRequest{Name: "app", Image: "img:1"})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	created.Image = "img:2"
+	if _, err := s.Update(ctx, created); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	revs, err := s.ListRevisions(ctx, created.ID)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(revs) != 2 {
+		t.Fatalf("expected 2 revisions, got %d", len(revs))
+	}
+	if revs[0].Name != "app-00002" || revs[0].Image != "img:2" {
+		t.Fatalf("unexpected newest revision: %+v", revs[0])
+	}
+	if revs[1].Name != "app-00001" || revs[1].Image != "img:1" {
+		t.Fatalf("unexpected oldest revision: %+v", revs[1])
+	}
+}
+
+func TestPostgresSetTraffic(t *testing.T) {
+	s := newPostgresStore(t)
+	ctx := context.Background()
+
+	created, err := s.Create(ctx, models.DeployRequest{Name: "app", Image: "img:1"})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	created.Image = "img:2"
+	updated, err := s.Update(ctx, created)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if updated.LatestRevision != "app-00002" {
+		t.Fatalf("expected latest revision app-00002, got %s", updated.LatestRevision)
+	}
+
+	split := []models.TrafficTarget{
+		{RevisionName: "app-00001", Percent: 50},
+		{RevisionName: "app-00002", Percent: 50},
+	}
+	routed, err := s.SetTraffic(ctx, updated.ID, updated.Generation, split)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(routed.Traffic) != 2 || routed.Generation != updated.Generation+1 {
+		t.Fatalf("unexpected result: %+v", routed)
+	}
+
+	got, err := s.Get(ctx, updated.ID)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(got.Traffic) != 2 || got.Traffic[1] != split[1] {
+		t.Fatalf("expected traffic to be persisted, got %+v", got.Traffic)
+	}
+
+	_, err = s.SetTraffic(ctx, updated.ID, updated.Generation, nil)
+	if !errors.Is(err, ErrConflict) {
+		t.Fatalf("expected ErrConflict, got %v", err)
+	}
+}
+
+func TestPostgresTagURLs(t *testing.T) {
+	s := newPostgresStore(t)
+	ctx := context.Background()
+
+	svc, err := s.Create(ctx, models.DeployRequest{Name: "app", Image: "img:1", Tag: "preview"})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(svc.Traffic) != 1 || svc.Traffic[0].Tag != "preview" {
+		t.Fatalf("expected tagged traffic target, got %+v", svc.Traffic)
+	}
+
+	if err := s.UpdateTagURLs(ctx, svc.ID, map[string]string{"preview": "https://preview-app.maxcloud.dev"}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	got, err := s.Get(ctx, svc.ID)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if got.TagURLs["preview"] != "https://preview-app.maxcloud.dev" {
+		t.Fatalf("expected tag URL to be stored, got %v", got.TagURLs)
+	}
+}
+
+func TestPostgresUpdateReconcileState(t *testing.T) {
+	s := newPostgresStore(t)
+	ctx := context.Background()
+
+	svc, err := s.Create(ctx, models.DeployRequest{Name: "app", Image: "img:1"})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if err := s.UpdateReconcileState(ctx, svc.ID, 2, "deploy: quota exceeded"); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	got, err := s.Get(ctx, svc.ID)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if got.ReconcileAttempts != 2 || got.LastError != "deploy: quota exceeded" {
+		t.Fatalf("expected reconcile state to be stored, got %d / %q", got.ReconcileAttempts, got.LastError)
+	}
+}
+
 func TestPostgresListTenantIsolation(t *testing.T) {
 	s := newPostgresStore(t)
 	ctx := context.Background()
@@ -301,3 +462,201 @@ func TestPostgresReconcilerSeesAll(t *testing.T) {
 		t.Fatalf("expected 2 services for reconciler, got %d", len(svcs))
 	}
 }
+
+func TestPostgresSecrets(t *testing.T) {
+	s := newPostgresStore(t)
+	ctx := context.Background()
+
+	_, org, _, err := s.Register(ctx, "a@example.com", "Org1")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	first, err := s.SetSecret(ctx, org.ID, "DB_PASSWORD", "hunter2")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	second, err := s.SetSecret(ctx, org.ID, "DB_PASSWORD", "correct-horse")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if first.ID != second.ID {
+		t.Fatalf("expected overwrite to keep id %s, got %s", first.ID, second.ID)
+	}
+
+	// Der Wert liegt nur verschlüsselt in der Datenbank
+	var stored []byte
+	if err := s.pool.QueryRow(ctx, `SELECT value FROM secrets WHERE id = $1`, first.ID).Scan(&stored); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if strings.Contains(string(stored), "correct-horse") {
+		t.Fatal("expected secret value to be encrypted at rest")
+	}
+
+	values, err := s.SecretValues(ctx, org.ID, []string{"DB_PASSWORD"})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if values["DB_PASSWORD"] != "correct-horse" {
+		t.Fatalf("expected decrypted value, got %q", values["DB_PASSWORD"])
+	}
+
+	if _, err := s.SecretValues(ctx, org.ID, []string{"MISSING"}); !errors.Is(err, ErrSecretNotFound) {
+		t.Fatalf("expected ErrSecretNotFound, got %v", err)
+	}
+
+	if err := s.DeleteSecret(ctx, org.ID, "DB_PASSWORD"); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	secrets, err := s.ListSecrets(ctx, org.ID)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(secrets) != 0 {
+		t.Fatalf("expected no secrets after delete, got %d", len(secrets))
+	}
+}
+
+func TestPostgresEvents(t *testing.T) {
+	s := newPostgresStore(t)
+	ctx := context.Background()
+
+	svc, err := s.Create(ctx, models.DeployRequest{Name: "app", Image: "img:1"})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	for _, typ := range []models.ServiceEventType{models.ServiceEventCreated, models.ServiceEventDeployed} {
+		if err := s.RecordEvent(ctx, models.ServiceEvent{ServiceID: svc.ID, Type: typ, Message: string(typ), Actor: "user:u-1"}); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+	}
+
+	// Die Historie bleibt nach dem Löschen des Services erhalten
+	if err := s.Delete(ctx, svc.ID); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	events, err := s.ListEvents(ctx, svc.ID, 10)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(events) != 2 || events[0].Type != models.ServiceEventDeployed {
+		t.Fatalf("expected 2 events newest first, got %+v", events)
+	}
+	if events[0].Actor != "user:u-1" {
+		t.Fatalf("expected actor to be stored, got %q", events[0].Actor)
+	}
+}
+
+func TestPostgresDomains(t *testing.T) {
+	s := newPostgresStore(t)
+	ctx := context.Background()
+
+	_, org1, _, err := s.Register(ctx, "a@example.com", "Org1")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	_, org2, _, err := s.Register(ctx, "b@example.com", "Org2")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	ctx1 := auth.WithTenant(ctx, org1.ID, "")
+	ctx2 := auth.WithTenant(ctx, org2.ID, "")
+
+	svc1, err := s.Create(ctx1, models.DeployRequest{Name: "web", Image: "nginx:latest"})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	svc2, err := s.Create(ctx2, models.DeployRequest{Name: "web", Image: "nginx:latest"})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	d, err := s.CreateDomain(ctx1, models.CreateDomainRequest{Hostname: "www.example.com", ServiceID: svc1.ID})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if d.OrgID != org1.ID || d.Status != models.DomainStatusPendingVerification {
+		t.Fatalf("unexpected domain: %+v", d)
+	}
+	if _, err := s.CreateDomain(ctx1, models.CreateDomainRequest{Hostname: "www.example.com", ServiceID: svc1.ID}); !errors.Is(err, ErrDuplicateDomain) {
+		t.Fatalf("expected ErrDuplicateDomain, got %v", err)
+	}
+	other, err := s.CreateDomain(ctx2, models.CreateDomainRequest{Hostname: "www.example.com", ServiceID: svc2.ID})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	verified, err := s.VerifyDomain(ctx1, d.ID)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if verified.Status != models.DomainStatusProvisioning || verified.VerifiedAt == nil {
+		t.Fatalf("expected verified domain, got %+v", verified)
+	}
+	if _, err := s.VerifyDomain(ctx2, other.ID); !errors.Is(err, ErrDuplicateDomain) {
+		t.Fatalf("expected ErrDuplicateDomain, got %v", err)
+	}
+
+	if err := s.UpdateDomainStatus(ctx, d.ID, models.DomainStatusReady, true, ""); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	got, err := s.GetDomain(ctx1, d.ID)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if got.Status != models.DomainStatusReady || !got.CertificateReady {
+		t.Fatalf("expected ready domain, got %+v", got)
+	}
+
+	all, _ := s.ListDomains(ctx)
+	if len(all) != 2 {
+		t.Fatalf("expected 2 domains, got %d", len(all))
+	}
+
+	if err := s.DeleteDomain(ctx2, d.ID); !errors.Is(err, ErrDomainNotFound) {
+		t.Fatalf("expected ErrDomainNotFound across tenants, got %v", err)
+	}
+	if err := s.DeleteDomain(ctx1, d.ID); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+}
+
+func TestPostgresLeaderElector(t *testing.T) {
+	s := newPostgresStore(t)
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	leading := make(chan struct{})
+	released := make(chan struct{})
+	go func() {
+		s.LeaderElector(slog.Default()).Run(ctx, func(ctx context.Context) {
+			close(leading)
+			<-ctx.Done()
+		})
+		close(released)
+	}()
+
+	select {
+	case <-leading:
+	case <-time.After(5 * time.Second):
+		t.Fatal("expected first elector to lead")
+	}
+
+	// Eine zweite Replika erhält den Lock nicht, solange die erste führt
+	second := s.LeaderElector(slog.Default()).(*advisoryLockElector)
+	if conn := second.tryAcquire(context.Background()); conn != nil {
+		conn.Release()
+		t.Fatal("expected second elector not to acquire the lock")
+	}
+
+	cancel()
+	<-released
+
+	conn := second.tryAcquire(context.Background())
+	if conn == nil {
+		t.Fatal("expected lock to be released after cancel")
+	}
+	second.hold(context.Background(), conn, func(context.Context) {})
+}
@@ -0,0 +1,81 @@
+package store
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"encoding/base64"
+	"errors"
+	"fmt"
+)
+
+// SecretKeySize ist die Länge des Schlüssels für die Secret-Verschlüsselung (AES-256).
+const SecretKeySize = 32
+
+// SecretCipher verschlüsselt Secret-Werte mit AES-256-GCM.
+// Der Nonce wird dem Ciphertext vorangestellt.
+type SecretCipher struct {
+	aead cipher.AEAD
+}
+
+// NewSecretCipher erstellt einen SecretCipher mit einem 32-Byte-Schlüssel.
+func NewSecretCipher(key []byte) (*SecretCipher, error) {
+	if len(key) != SecretKeySize {
+		return nil, fmt.Errorf("secret key must be %d bytes, got %d", SecretKeySize, len(key))
+	}
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, fmt.Errorf("creating cipher: %w", err)
+	}
+	aead, err := cipher.NewGCM(block)
+	if err != nil {
+		return nil, fmt.Errorf("creating gcm: %w", err)
+	}
+	return &SecretCipher{aead: aead}, nil
+}
+
+// ParseSecretKey dekodiert einen base64-kodierten Schlüssel.
+func ParseSecretKey(encoded string) ([]byte, error) {
+	key, err := base64.StdEncoding.DecodeString(encoded)
+	if err != nil {
+		return nil, fmt.Errorf("decoding secret key: %w", err)
+	}
+	return key, nil
+}
+
+// GenerateSecretKey erzeugt einen zufälligen Schlüssel für NewSecretCipher.
+func GenerateSecretKey() ([]byte, error) {
+	key := make([]byte, SecretKeySize)
+	if _, err := rand.Read(key); err != nil {
+		return nil, fmt.Errorf("generating random bytes: %w", err)
+	}
+	return key, nil
+}
+
+// encrypt verschlüsselt plaintext. aad bindet den Ciphertext an Organisation und Name,
+// damit er nicht unbemerkt in eine andere Zeile kopiert werden kann.
+func (c *SecretCipher) encrypt(plaintext, aad string) ([]byte, error) {
+	nonce := make([]byte, c.aead.NonceSize())
+	if _, err := rand.Read(nonce); err != nil {
+		return nil, fmt.Errorf("generating nonce: %w", err)
+	}
+	return c.aead.Seal(nonce, nonce, []byte(plaintext), []byte(aad)), nil
+}
+
+// decrypt entschlüsselt einen mit encrypt erzeugten Ciphertext.
+func (c *SecretCipher) decrypt(ciphertext []byte, aad string) (string, error) {
+	size := c.aead.NonceSize()
+	if len(ciphertext) < size {
+		return "", errors.New("secret ciphertext too short")
+	}
+	plaintext, err := c.aead.Open(nil, ciphertext[:size], ciphertext[size:], []byte(aad))
+	if err != nil {
+		return "", fmt.Errorf("decrypting secret: %w", err)
+	}
+	return string(plaintext), nil
+}
+
+// secretAAD gibt die Zusatzdaten für die Verschlüsselung eines Secrets zurück.
+func secretAAD(orgID, name string) string {
+	return orgID + "/" + name
+}
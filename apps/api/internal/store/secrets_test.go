@@ -0,0 +1,84 @@
+package store
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"testing"
+)
+
+func TestSecretCipher(t *testing.T) {
+	key, err := GenerateSecretKey()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	c, err := NewSecretCipher(key)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	ciphertext, err := c.encrypt("s3cret", secretAAD("org-1", "TOKEN"))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if bytes.Contains(ciphertext, []byte("s3cret")) {
+		t.Fatal("expected ciphertext not to contain the plaintext")
+	}
+
+	plaintext, err := c.decrypt(ciphertext, secretAAD("org-1", "TOKEN"))
+	if err != nil || plaintext != "s3cret" {
+		t.Fatalf("expected s3cret, got %q (err %v)", plaintext, err)
+	}
+
+	// Ciphertext einer anderen Organisation lässt sich nicht entschlüsseln
+	if _, err := c.decrypt(ciphertext, secretAAD("org-2", "TOKEN")); err == nil {
+		t.Fatal("expected decrypt with foreign org to fail")
+	}
+
+	if _, err := NewSecretCipher([]byte("short")); err == nil {
+		t.Fatal("expected error for short key")
+	}
+}
+
+func TestSecrets(t *testing.T) {
+	s := NewMemory()
+	ctx := context.Background()
+
+	first, err := s.SetSecret(ctx, "org-1", "DB_PASSWORD", "hunter2")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	second, err := s.SetSecret(ctx, "org-1", "DB_PASSWORD", "correct-horse")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if first.ID != second.ID {
+		t.Fatalf("expected overwrite to keep id %s, got %s", first.ID, second.ID)
+	}
+	s.SetSecret(ctx, "org-1", "API_TOKEN", "abc")
+	s.SetSecret(ctx, "org-2", "DB_PASSWORD", "other")
+
+	secrets, _ := s.ListSecrets(ctx, "org-1")
+	if len(secrets) != 2 || secrets[0].Name != "API_TOKEN" || secrets[1].Name != "DB_PASSWORD" {
+		t.Fatalf("expected sorted secrets of org-1, got %+v", secrets)
+	}
+
+	values, err := s.SecretValues(ctx, "org-1", []string{"DB_PASSWORD", "API_TOKEN"})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if values["DB_PASSWORD"] != "correct-horse" || values["API_TOKEN"] != "abc" {
+		t.Fatalf("unexpected values: %v", values)
+	}
+
+	if _, err := s.SecretValues(ctx, "org-2", []string{"API_TOKEN"}); !errors.Is(err, ErrSecretNotFound) {
+		t.Fatalf("expected ErrSecretNotFound across tenants, got %v", err)
+	}
+
+	if err := s.DeleteSecret(ctx, "org-1", "DB_PASSWORD"); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if err := s.DeleteSecret(ctx, "org-1", "DB_PASSWORD"); !errors.Is(err, ErrSecretNotFound) {
+		t.Fatalf("expected ErrSecretNotFound, got %v", err)
+	}
+}
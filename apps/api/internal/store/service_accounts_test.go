@@ -0,0 +1,105 @@
+package store
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/max-cloud/shared/pkg/models"
+)
+
+func TestServiceAccounts(t *testing.T) {
+	s := NewMemory()
+	ctx := context.Background()
+
+	_, org, _, err := s.Register(ctx, "test@example.com", "TestOrg")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	sa, err := s.CreateServiceAccount(ctx, org.ID, "ci", models.OrgRoleDeveloper)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if _, err := s.CreateServiceAccount(ctx, org.ID, "ci", models.OrgRoleViewer); !errors.Is(err, ErrDuplicateServiceAccount) {
+		t.Fatalf("expected ErrDuplicateServiceAccount, got %v", err)
+	}
+	if _, err := s.CreateServiceAccount(ctx, org.ID, "backup", models.OrgRoleViewer); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	accounts, err := s.ListServiceAccounts(ctx, org.ID)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(accounts) != 2 || accounts[0].Name != "backup" || accounts[1].Name != "ci" {
+		t.Fatalf("expected sorted service accounts, got %+v", accounts)
+	}
+
+	updated, err := s.UpdateServiceAccountRole(ctx, org.ID, sa.ID, models.OrgRoleAdmin)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if updated.Role != models.OrgRoleAdmin {
+		t.Fatalf("expected role admin, got %s", updated.Role)
+	}
+
+	rawKey, info, err := s.CreateServiceAccountAPIKey(ctx, org.ID, sa.ID, models.CreateAPIKeyRequest{Name: "deploy"})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if info.ServiceAccountID != sa.ID || info.UserID != "" || info.OwnerID() != sa.ID {
+		t.Fatalf("expected key owned by service account, got %+v", info)
+	}
+	validated, err := s.ValidateAPIKey(ctx, rawKey)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if validated.ServiceAccountID != sa.ID {
+		t.Fatalf("expected validated key owned by service account, got %+v", validated)
+	}
+
+	// Die Rolle des Service-Accounts gilt für seine Keys
+	authInfo, err := s.GetAuthInfo(ctx, org.ID, sa.ID)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if authInfo.ServiceAccount == nil || authInfo.ServiceAccount.Name != "ci" || authInfo.Role != models.OrgRoleAdmin {
+		t.Fatalf("unexpected auth info: %+v", authInfo)
+	}
+
+	// Rotierte Keys gehören weiterhin dem Service-Account
+	_, rotated, err := s.RotateAPIKey(ctx, org.ID, sa.ID, info.ID, time.Minute)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if rotated.ServiceAccountID != sa.ID {
+		t.Fatalf("expected rotated key owned by service account, got %+v", rotated)
+	}
+
+	// Fremde Organisationen sehen den Service-Account nicht
+	_, other, _, err := s.Register(ctx, "other@example.com", "OtherOrg")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if _, err := s.GetServiceAccount(ctx, other.ID, sa.ID); !errors.Is(err, ErrServiceAccountNotFound) {
+		t.Fatalf("expected ErrServiceAccountNotFound across tenants, got %v", err)
+	}
+	if _, err := s.GetAuthInfo(ctx, other.ID, sa.ID); !errors.Is(err, ErrNotFound) {
+		t.Fatalf("expected ErrNotFound across tenants, got %v", err)
+	}
+	if _, _, err := s.CreateServiceAccountAPIKey(ctx, other.ID, sa.ID, models.CreateAPIKeyRequest{Name: "x"}); !errors.Is(err, ErrServiceAccountNotFound) {
+		t.Fatalf("expected ErrServiceAccountNotFound, got %v", err)
+	}
+
+	if err := s.DeleteServiceAccount(ctx, org.ID, sa.ID); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if _, err := s.ValidateAPIKey(ctx, rawKey); err == nil {
+		t.Fatal("expected api key of deleted service account to be revoked")
+	}
+	if err := s.DeleteServiceAccount(ctx, org.ID, sa.ID); !errors.Is(err, ErrServiceAccountNotFound) {
+		t.Fatalf("expected ErrServiceAccountNotFound, got %v", err)
+	}
+}
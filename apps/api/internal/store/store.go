@@ -14,6 +14,9 @@ var ErrNotFound = errors.New("service not found")
 // ErrDuplicateService wird zurückgegeben, wenn ein Service mit diesem Namen bereits existiert.
 var ErrDuplicateService = errors.New("service name already exists in this organization")
 
+// ErrConflict wird zurückgegeben, wenn ein Service zwischenzeitlich von einem anderen Request geändert wurde.
+var ErrConflict = errors.New("service was modified concurrently")
+
 // ErrDuplicateEmail wird zurückgegeben, wenn die E-Mail bereits registriert ist.
 var ErrDuplicateEmail = errors.New("email already registered")
 
@@ -23,6 +26,9 @@ var ErrDuplicateOrg = errors.New("organization name already taken")
 // ErrKeyNotFound wird zurückgegeben, wenn ein API-Key nicht existiert.
 var ErrKeyNotFound = errors.New("api key not found")
 
+// ErrKeyRotated wird zurückgegeben, wenn ein API-Key bereits rotiert wurde.
+var ErrKeyRotated = errors.New("api key already rotated")
+
 // ErrInviteNotFound wird zurückgegeben, wenn eine Einladung nicht existiert.
 var ErrInviteNotFound = errors.New("invite not found")
 
@@ -32,6 +38,30 @@ var ErrInviteExpired = errors.New("invite expired")
 // ErrAlreadyMember wird zurückgegeben, wenn der User bereits Mitglied der Org ist.
 var ErrAlreadyMember = errors.New("user is already a member of this organization")
 
+// ErrSecretNotFound wird zurückgegeben, wenn ein Secret nicht existiert.
+var ErrSecretNotFound = errors.New("secret not found")
+
+// ErrDomainNotFound wird zurückgegeben, wenn eine Domain nicht existiert.
+var ErrDomainNotFound = errors.New("domain not found")
+
+// ErrMemberNotFound wird zurückgegeben, wenn der Benutzer kein Mitglied der Organisation ist.
+var ErrMemberNotFound = errors.New("member not found")
+
+// ErrLastOwner wird zurückgegeben, wenn der letzte Owner einer Organisation entfernt
+// oder herabgestuft werden soll.
+var ErrLastOwner = errors.New("organization must keep at least one owner")
+
+// ErrDuplicateDomain wird zurückgegeben, wenn der Hostname bereits angelegt oder
+// von einer anderen Organisation verifiziert ist.
+var ErrDuplicateDomain = errors.New("domain already in use")
+
+// ErrServiceAccountNotFound wird zurückgegeben, wenn ein Service-Account nicht existiert.
+var ErrServiceAccountNotFound = errors.New("service account not found")
+
+// ErrDuplicateServiceAccount wird zurückgegeben, wenn der Name eines Service-Accounts
+// in der Organisation bereits vergeben ist.
+var ErrDuplicateServiceAccount = errors.New("service account name already exists in this organization")
+
 // ServiceStore definiert die Schnittstelle für Service-Persistenz.
 type ServiceStore interface {
 	Create(ctx context.Context, req models.DeployRequest) (models.Service, error)
@@ -40,16 +70,57 @@ type ServiceStore interface {
 	List(ctx context.Context) ([]models.Service, error)
 	Delete(ctx context.Context, id string) error
 	UpdateStatus(ctx context.Context, id string, status models.ServiceStatus, url string) error
+	// UpdateTagURLs speichert die vom Orchestrator gemeldeten Tag-URLs eines Services.
+	UpdateTagURLs(ctx context.Context, id string, tagURLs map[string]string) error
+	// UpdateStatusReason speichert Grund und Meldung des vom Orchestrator gemeldeten Status.
+	UpdateStatusReason(ctx context.Context, id string, reason, message string) error
+	// UpdateReconcileState speichert die Anzahl fehlgeschlagener Reconcile-Versuche und den letzten Fehler.
+	// attempts 0 mit leerem Fehler setzt den Zustand nach einem erfolgreichen Abgleich zurück.
+	UpdateReconcileState(ctx context.Context, id string, attempts int, lastError string) error
+	// Update schreibt die Spec eines Services, erhöht die Generation und setzt den Status auf pending.
+	// svc.Generation muss der aktuell gespeicherten Generation entsprechen, sonst ErrConflict.
+	Update(ctx context.Context, svc models.Service) (models.Service, error)
+	// SetTraffic setzt die Traffic-Aufteilung, erhöht die Generation und setzt den Status auf pending,
+	// ohne eine neue Revision anzulegen. generation muss der gespeicherten Generation entsprechen.
+	SetTraffic(ctx context.Context, id string, generation int64, traffic []models.TrafficTarget) (models.Service, error)
+	// ListRevisions gibt die Revisionen eines Services zurück, neueste zuerst.
+	// Create und Update legen automatisch eine Revision an.
+	ListRevisions(ctx context.Context, serviceID string) ([]models.Revision, error)
+}
+
+// initialTraffic gibt die Traffic-Aufteilung für einen neuen Service zurück.
+// Mit Tag erhält die erste Revision 100% und zusätzlich die Tag-URL.
+func initialTraffic(req models.DeployRequest) []models.TrafficTarget {
+	if req.Tag == "" {
+		return nil
+	}
+	return []models.TrafficTarget{
+		{RevisionName: models.RevisionName(req.Name, 1), Percent: 100, Tag: req.Tag},
+	}
 }
 
 // AuthStore definiert die Schnittstelle für Authentifizierung und Benutzerverwaltung.
 type AuthStore interface {
 	Register(ctx context.Context, email, orgName string) (models.User, models.Organization, string, error)
 	ValidateAPIKey(ctx context.Context, rawKey string) (*models.APIKeyInfo, error)
-	CreateAPIKey(ctx context.Context, orgID, userID, name string) (string, *models.APIKeyInfo, error)
+	CreateAPIKey(ctx context.Context, orgID, userID string, req models.CreateAPIKeyRequest) (string, *models.APIKeyInfo, error)
 	ListAPIKeys(ctx context.Context, orgID string) ([]models.APIKeyInfo, error)
-	DeleteAPIKey(ctx context.Context, orgID, keyID string) error
+	// GetAPIKey gibt einen API-Key der Organisation zurück, auch wenn er abgelaufen ist.
+	GetAPIKey(ctx context.Context, orgID, keyID string) (*models.APIKeyInfo, error)
+	// DeleteAPIKey löscht einen API-Key, sofern er ownerID (User oder Service-Account) gehört.
+	DeleteAPIKey(ctx context.Context, orgID, ownerID, keyID string) error
+	// RotateAPIKey stellt einen Nachfolger mit Name, Scopes und Laufzeit des Keys aus und
+	// lässt den alten Key nach gracePeriod ablaufen. Der Key muss ownerID gehören.
+	// Ein bereits rotierter Key liefert ErrKeyRotated.
+	RotateAPIKey(ctx context.Context, orgID, ownerID, keyID string, gracePeriod time.Duration) (string, *models.APIKeyInfo, error)
+	// GetAuthInfo gibt User bzw. Service-Account, Organisation und Rolle zurück.
+	// userID darf auch die ID eines Service-Accounts der Organisation sein.
 	GetAuthInfo(ctx context.Context, orgID, userID string) (*models.AuthInfo, error)
+	// GetOrganization gibt eine Organisation inklusive ihrer Limits zurück.
+	GetOrganization(ctx context.Context, orgID string) (models.Organization, error)
+	// DeleteOrganization löscht eine Organisation mit ihren Services, Domains, Secrets,
+	// API-Keys und Einladungen. Benutzer bleiben bestehen.
+	DeleteOrganization(ctx context.Context, orgID string) error
 	UpdateAPIKeyLastUsed(ctx context.Context, keyID string) error
 	CreateInvite(ctx context.Context, orgID, email string, role models.OrgRole, invitedBy string, expiresAt time.Time) (models.Invitation, string, error)
 	AcceptInvite(ctx context.Context, rawToken string) (models.User, models.Organization, models.OrgRole, string, error)
@@ -57,4 +128,61 @@ type AuthStore interface {
 	RevokeInvite(ctx context.Context, orgID, inviteID string) error
 	GetUserByEmail(ctx context.Context, email string) (*models.User, error)
 	EnsureDevOrg(ctx context.Context, devOrgID string) error
+	// ListMembers gibt alle Mitglieder einer Organisation sortiert nach E-Mail zurück.
+	ListMembers(ctx context.Context, orgID string) ([]models.OrgMember, error)
+	// UpdateMemberRole ändert die Rolle eines Mitglieds. ErrLastOwner, wenn dadurch kein Owner bliebe.
+	UpdateMemberRole(ctx context.Context, orgID, userID string, role models.OrgRole) (models.OrgMember, error)
+	// RemoveMember entfernt ein Mitglied und widerruft seine API-Keys in der Organisation.
+	// ErrLastOwner, wenn dadurch kein Owner bliebe.
+	RemoveMember(ctx context.Context, orgID, userID string) error
+	// CreateServiceAccount legt einen Service-Account an. ErrDuplicateServiceAccount,
+	// wenn der Name in der Organisation bereits vergeben ist.
+	CreateServiceAccount(ctx context.Context, orgID, name string, role models.OrgRole) (models.ServiceAccount, error)
+	// ListServiceAccounts gibt alle Service-Accounts einer Organisation sortiert nach Namen zurück.
+	ListServiceAccounts(ctx context.Context, orgID string) ([]models.ServiceAccount, error)
+	GetServiceAccount(ctx context.Context, orgID, id string) (models.ServiceAccount, error)
+	UpdateServiceAccountRole(ctx context.Context, orgID, id string, role models.OrgRole) (models.ServiceAccount, error)
+	// DeleteServiceAccount löscht einen Service-Account samt seiner API-Keys.
+	DeleteServiceAccount(ctx context.Context, orgID, id string) error
+	// CreateServiceAccountAPIKey erstellt einen API-Key, der dem Service-Account gehört.
+	CreateServiceAccountAPIKey(ctx context.Context, orgID, id string, req models.CreateAPIKeyRequest) (string, *models.APIKeyInfo, error)
+}
+
+// SecretStore definiert die Schnittstelle für verschlüsselte Secrets einer Organisation.
+// Die Werte werden nur verschlüsselt gespeichert; Lesezugriffe liefern außer
+// SecretValues keine Klartexte.
+type SecretStore interface {
+	// SetSecret legt ein Secret an oder überschreibt seinen Wert.
+	SetSecret(ctx context.Context, orgID, name, value string) (models.Secret, error)
+	ListSecrets(ctx context.Context, orgID string) ([]models.Secret, error)
+	DeleteSecret(ctx context.Context, orgID, name string) error
+	// SecretValues gibt die entschlüsselten Werte der genannten Secrets zurück (Name → Wert).
+	// Fehlt eines davon, wird ErrSecretNotFound zurückgegeben.
+	SecretValues(ctx context.Context, orgID string, names []string) (map[string]string, error)
+}
+
+// DomainStore definiert die Schnittstelle für Custom Domains.
+// Wie beim ServiceStore wird nach dem Tenant im Kontext gefiltert; ohne Tenant
+// (z.B. im Reconciler) sind alle Domains sichtbar.
+type DomainStore interface {
+	// CreateDomain legt eine unverifizierte Domain mit neuem Verifizierungs-Token an.
+	// Jeder Hostname kann pro Organisation nur einmal angelegt werden.
+	CreateDomain(ctx context.Context, req models.CreateDomainRequest) (models.Domain, error)
+	GetDomain(ctx context.Context, id string) (models.Domain, error)
+	ListDomains(ctx context.Context) ([]models.Domain, error)
+	// VerifyDomain markiert eine Domain als verifiziert und setzt den Status auf provisioning.
+	// Ist der Hostname bereits für eine andere Domain verifiziert, wird ErrDuplicateDomain zurückgegeben.
+	VerifyDomain(ctx context.Context, id string) (models.Domain, error)
+	// UpdateDomainStatus speichert den vom Orchestrator gemeldeten Zustand einer Domain.
+	UpdateDomainStatus(ctx context.Context, id string, status models.DomainStatus, certificateReady bool, message string) error
+	DeleteDomain(ctx context.Context, id string) error
+}
+
+// EventStore speichert die Ereignishistorie von Services. Ereignisse werden nur angehängt
+// und bleiben auch nach dem Löschen eines Services erhalten.
+type EventStore interface {
+	// RecordEvent hängt ein Ereignis an; ID und CreatedAt werden vom Store gesetzt.
+	RecordEvent(ctx context.Context, event models.ServiceEvent) error
+	// ListEvents gibt die letzten limit Ereignisse eines Services zurück, neueste zuerst.
+	ListEvents(ctx context.Context, serviceID string, limit int) ([]models.ServiceEvent, error)
 }
@@ -2,6 +2,7 @@ package main
 
 import (
 	"context"
+	"errors"
 	"log/slog"
 	"net/http"
 	"os"
@@ -11,6 +12,7 @@ import (
 
 	"github.com/max-cloud/api/internal/config"
 	"github.com/max-cloud/api/internal/email"
+	"github.com/max-cloud/api/internal/leader"
 	"github.com/max-cloud/api/internal/orchestrator"
 	"github.com/max-cloud/api/internal/reconciler"
 	"github.com/max-cloud/api/internal/server"
@@ -27,12 +29,23 @@ func main() {
 
 	var st store.ServiceStore
 	var authSt store.AuthStore
+	var secretSt store.SecretStore
+	var domainSt store.DomainStore
+	var eventSt store.EventStore
+	// Ohne gemeinsamen Zustand läuft nur eine Replika, die immer führt
+	var elector leader.Elector = leader.Always{}
 
 	if cfg.DatabaseURL != "" {
 		ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
 		defer cancel()
 
-		pg, err := store.NewPostgres(ctx, cfg.DatabaseURL)
+		secretCipher, err := loadSecretCipher(cfg, logger)
+		if err != nil {
+			logger.Error("failed to load secrets key", "error", err)
+			os.Exit(1)
+		}
+
+		pg, err := store.NewPostgres(ctx, cfg.DatabaseURL, secretCipher)
 		if err != nil {
 			logger.Error("failed to connect to database", "error", err)
 			os.Exit(1)
@@ -40,6 +53,10 @@ func main() {
 		defer pg.Close()
 		st = pg
 		authSt = pg
+		secretSt = pg
+		domainSt = pg
+		eventSt = pg
+		elector = pg.LeaderElector(logger)
 		logger.Info("using PostgreSQL store")
 
 		if cfg.DevMode && cfg.DevOrgUID != "" {
@@ -53,17 +70,22 @@ func main() {
 		mem := store.NewMemory()
 		st = mem
 		authSt = mem
+		secretSt = mem
+		domainSt = mem
+		eventSt = mem
 		logger.Info("using in-memory store (no DATABASE_URL set)")
 	}
 
 	var orch orchestrator.Orchestrator
 	if cfg.KubeconfigPath != "" {
-		k, err := orchestrator.NewKnative(logger, cfg.KubeconfigPath, cfg.KnativeNamespace, cfg.RegistryURL, cfg.RegistryJWTSecret)
+		k, err := orchestrator.NewKnative(logger, cfg.KubeconfigPath, cfg.KnativeNamespace, cfg.RegistryURL, cfg.RegistryJWTSecret, cfg.PullTokenExpiry)
 		if err != nil {
 			logger.Error("failed to create knative orchestrator", "error", err)
 			os.Exit(1)
 		}
 		orch = k
+		// Mit Cluster hat die Lease Vorrang vor dem Advisory Lock, da sie den Cluster schützt
+		elector = k.LeaderElector(cfg.LeaderID)
 		logger.Info("using Knative orchestrator", "namespace", cfg.KnativeNamespace)
 	} else {
 		orch = orchestrator.NewNoop(logger)
@@ -77,13 +99,32 @@ func main() {
 	emailSender := email.NewResend(cfg.ResendAPIKey, cfg.EmailFrom)
 	logger.Info("using Resend email sender", "from", cfg.EmailFrom)
 
-	srv := server.New(logger, st, authSt, orch, emailSender, cfg.InviteExpiration, cfg.DevMode, cfg.DevOrgUID, cfg.RegistryURL, cfg.RegistryJWTSecret, cfg.RegistryTokenExpiry)
+	leaderStatus := leader.NewStatus(logger, cfg.LeaderID)
+	srv := server.New(logger, st, authSt, secretSt, domainSt, eventSt, orch, emailSender, cfg.InviteExpiration, cfg.DevMode, cfg.DevOrgUID, cfg.RegistryURL, cfg.RegistryJWTSecret, cfg.RegistryTokenExpiry, leaderStatus)
 
-	rec := reconciler.New(logger, st, orch, cfg.ReconcileInterval)
+	gcOpts := reconciler.GCOptions{
+		Interval:    cfg.GCInterval,
+		GracePeriod: cfg.GCGracePeriod,
+		DryRun:      cfg.GCDryRun,
+	}
+	// Der In-Memory-Store ist nach einem Neustart leer, jeder Knative Service im Cluster
+	// gälte dann als verwaist. Ohne persistenten Store meldet die GC daher nur.
+	if cfg.DatabaseURL == "" && gcOpts.Interval > 0 && !gcOpts.DryRun {
+		gcOpts.DryRun = true
+		logger.Warn("garbage collection forced to dry-run: no persistent store configured")
+	}
 	reconcilerCtx, reconcilerCancel := context.WithCancel(context.Background())
 	defer reconcilerCancel()
-	go rec.Run(reconcilerCtx)
-	logger.Info("reconciler started", "interval", cfg.ReconcileInterval)
+	reconcilerDone := make(chan struct{})
+	go func() {
+		defer close(reconcilerDone)
+		// Nur die führende Replika gleicht ab; je Amtszeit ein neuer Reconciler, da dessen
+		// Queue beim Verlust der Führung geschlossen wird
+		elector.Run(reconcilerCtx, leaderStatus.Lead(func(ctx context.Context) {
+			reconciler.New(logger, st, secretSt, domainSt, eventSt, orch, cfg.ReconcileInterval, cfg.ReconcileWorkers, gcOpts).Run(ctx)
+		}))
+	}()
+	logger.Info("reconciler leader election started", "interval", cfg.ReconcileInterval, "gc_interval", gcOpts.Interval, "gc_dry_run", gcOpts.DryRun, "leader_id", cfg.LeaderID)
 
 	httpServer := &http.Server{
 		Addr:         ":" + cfg.Port,
@@ -114,5 +155,29 @@ func main() {
 	if err := httpServer.Shutdown(ctx); err != nil {
 		logger.Error("forced shutdown", "error", err)
 	}
+	// Auf das Freigeben der Führung warten, damit eine andere Replika sofort übernehmen kann
+	<-reconcilerDone
 	logger.Info("server stopped")
 }
+
+// loadSecretCipher erstellt den Cipher für Secrets aus SECRETS_KEY. Im Dev-Mode wird ohne
+// Schlüssel ein flüchtiger erzeugt; gespeicherte Secrets sind nach einem Neustart unlesbar.
+func loadSecretCipher(cfg *config.Config, logger *slog.Logger) (*store.SecretCipher, error) {
+	if cfg.SecretsKey == "" {
+		if !cfg.DevMode {
+			return nil, errors.New("SECRETS_KEY is required")
+		}
+		logger.Warn("SECRETS_KEY not set, using an ephemeral key for secrets")
+		key, err := store.GenerateSecretKey()
+		if err != nil {
+			return nil, err
+		}
+		return store.NewSecretCipher(key)
+	}
+
+	key, err := store.ParseSecretKey(cfg.SecretsKey)
+	if err != nil {
+		return nil, err
+	}
+	return store.NewSecretCipher(key)
+}
@@ -3,6 +3,7 @@ package cmd
 import (
 	"fmt"
 	"os"
+	"strings"
 	"text/tabwriter"
 	"time"
 
@@ -11,9 +12,13 @@ import (
 )
 
 var (
-	registerEmail   string
-	registerOrgName string
-	apiKeyName      string
+	registerEmail     string
+	registerOrgName   string
+	apiKeyName        string
+	apiKeyScopes      []string
+	apiKeyExpires     time.Duration
+	apiKeyGracePeriod time.Duration
+	deleteOrgName     string
 )
 
 var authCmd = &cobra.Command{
@@ -64,7 +69,11 @@ var authStatusCmd = &cobra.Command{
 		}
 
 		fmt.Printf("Authenticated as:\n")
-		fmt.Printf("  Email:        %s\n", info.User.Email)
+		if info.ServiceAccount != nil {
+			fmt.Printf("  Account:      %s (service account)\n", info.ServiceAccount.Name)
+		} else {
+			fmt.Printf("  Email:        %s\n", info.User.Email)
+		}
 		fmt.Printf("  Organization: %s\n", info.Organization.Name)
 		fmt.Printf("  Role:         %s\n", info.Role)
 
@@ -72,6 +81,31 @@ var authStatusCmd = &cobra.Command{
 	},
 }
 
+var authDeleteOrgCmd = &cobra.Command{
+	Use:   "delete-org",
+	Short: "Delete your organization with all services and data",
+	Long: `Delete your organization, all of its services, domains, secrets and API keys.
+This cannot be undone. Requires the owner role.
+
+Example:
+  maxcloud auth delete-org --confirm myorg`,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		info, err := client.AuthStatus()
+		if err != nil {
+			return formatError(err)
+		}
+		if deleteOrgName != info.Organization.Name {
+			return fmt.Errorf("confirm the deletion with --confirm %s", info.Organization.Name)
+		}
+
+		if err := client.DeleteOrganization(); err != nil {
+			return formatError(err)
+		}
+		fmt.Printf("Organization %s deleted.\n", info.Organization.Name)
+		return nil
+	},
+}
+
 var apiKeyCmd = &cobra.Command{
 	Use:   "api-key",
 	Short: "Manage API keys",
@@ -80,17 +114,42 @@ var apiKeyCmd = &cobra.Command{
 var apiKeyCreateCmd = &cobra.Command{
 	Use:   "create",
 	Short: "Create a new API key",
+	Long: `Create a new API key.
+
+Without --scope the key has all permissions of your role. Restrict keys for
+CI pipelines to what they need:
+
+  services:read     list and inspect services, domains, secrets and members
+  services:deploy   create, update and roll back services and domains
+  services:delete   delete services and domains
+  logs:read         read logs and events
+  registry:push     push images
+  secrets:write     set and delete secrets
+  members:manage    manage invitations and members
+  org:manage        delete the organization
+
+Example:
+  maxcloud auth api-key create --name ci --scope services:read --scope services:deploy --scope registry:push --expires 720h`,
 	RunE: func(cmd *cobra.Command, args []string) error {
-		resp, err := client.CreateAPIKey(models.CreateAPIKeyRequest{
-			Name: apiKeyName,
-		})
+		req := models.CreateAPIKeyRequest{Name: apiKeyName}
+		for _, scope := range apiKeyScopes {
+			req.Scopes = append(req.Scopes, models.Permission(scope))
+		}
+		if apiKeyExpires > 0 {
+			expiresAt := time.Now().Add(apiKeyExpires)
+			req.ExpiresAt = &expiresAt
+		}
+
+		resp, err := client.CreateAPIKey(req)
 		if err != nil {
 			return formatError(err)
 		}
 
 		fmt.Printf("API key created:\n")
-		fmt.Printf("  Name:   %s\n", resp.Info.Name)
-		fmt.Printf("  Key:    %s\n", resp.APIKey)
+		fmt.Printf("  Name:    %s\n", resp.Info.Name)
+		fmt.Printf("  Scopes:  %s\n", formatScopes(resp.Info.Scopes))
+		fmt.Printf("  Expires: %s\n", formatExpiry(resp.Info.ExpiresAt))
+		fmt.Printf("  Key:     %s\n", resp.APIKey)
 		fmt.Printf("\nSave this key — it won't be shown again.\n")
 
 		return nil
@@ -107,15 +166,17 @@ var apiKeyListCmd = &cobra.Command{
 		}
 
 		w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
-		fmt.Fprintln(w, "ID\tNAME\tPREFIX\tCREATED\tLAST USED")
+		fmt.Fprintln(w, "ID\tNAME\tPREFIX\tSCOPES\tCREATED\tEXPIRES\tLAST USED")
 		for _, k := range keys {
 			lastUsed := "-"
 			if k.LastUsedAt != nil {
 				lastUsed = k.LastUsedAt.Format(time.DateTime)
 			}
-			fmt.Fprintf(w, "%s\t%s\tmc_%s...\t%s\t%s\n",
+			fmt.Fprintf(w, "%s\t%s\tmc_%s...\t%s\t%s\t%s\t%s\n",
 				k.ID, k.Name, k.Prefix,
+				formatScopes(k.Scopes),
 				k.CreatedAt.Format(time.DateTime),
+				formatExpiry(k.ExpiresAt),
 				lastUsed,
 			)
 		}
@@ -138,6 +199,61 @@ var apiKeyDeleteCmd = &cobra.Command{
 	},
 }
 
+var apiKeyRotateCmd = &cobra.Command{
+	Use:   "rotate [key-id]",
+	Short: "Replace an API key, keeping the old one valid for a grace period",
+	Long: `Replace an API key with a new one that has the same name, scopes and
+lifetime.
+
+The old key stays valid for the grace period (default 24h, at most 720h) so
+every consumer can switch to the new key before the old one expires. Each key
+can only be rotated once; rotate its successor afterwards.
+
+You can always rotate your own keys. Rotating keys of other members or of
+service accounts requires the members:manage permission.`,
+	Args: cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		req := models.RotateAPIKeyRequest{GracePeriodSeconds: int(apiKeyGracePeriod.Seconds())}
+		resp, err := client.RotateAPIKey(args[0], req)
+		if err != nil {
+			return formatError(err)
+		}
+
+		fmt.Printf("API key rotated:\n")
+		fmt.Printf("  Name:    %s\n", resp.Info.Name)
+		fmt.Printf("  Scopes:  %s\n", formatScopes(resp.Info.Scopes))
+		fmt.Printf("  Expires: %s\n", formatExpiry(resp.Info.ExpiresAt))
+		fmt.Printf("  Key:     %s\n", resp.APIKey)
+		fmt.Printf("\nThe old key stays valid until %s.\n", time.Now().Add(apiKeyGracePeriod).Format(time.DateTime))
+		fmt.Printf("Save this key — it won't be shown again.\n")
+
+		return nil
+	},
+}
+
+// formatScopes gibt die Scopes eines Keys kommagetrennt aus, "all" für uneingeschränkte Keys.
+func formatScopes(scopes []models.Permission) string {
+	if len(scopes) == 0 {
+		return "all"
+	}
+	parts := make([]string, len(scopes))
+	for i, s := range scopes {
+		parts[i] = string(s)
+	}
+	return strings.Join(parts, ",")
+}
+
+// formatExpiry gibt das Ablaufdatum eines Keys aus, "never" für Keys ohne Ablauf.
+func formatExpiry(expiresAt *time.Time) string {
+	if expiresAt == nil {
+		return "never"
+	}
+	if expiresAt.Before(time.Now()) {
+		return "expired"
+	}
+	return expiresAt.Format(time.DateTime)
+}
+
 func init() {
 	authRegisterCmd.Flags().StringVar(&registerEmail, "email", "", "Email address")
 	authRegisterCmd.Flags().StringVar(&registerOrgName, "org", "", "Organization name")
@@ -146,12 +262,20 @@ func init() {
 
 	apiKeyCreateCmd.Flags().StringVar(&apiKeyName, "name", "", "Name for the API key")
 	apiKeyCreateCmd.MarkFlagRequired("name")
+	apiKeyCreateCmd.Flags().StringArrayVar(&apiKeyScopes, "scope", nil, "Restrict the key to a scope (repeatable)")
+	apiKeyCreateCmd.Flags().DurationVar(&apiKeyExpires, "expires", 0, "Lifetime of the key, e.g. 720h (default: never expires)")
+
+	apiKeyRotateCmd.Flags().DurationVar(&apiKeyGracePeriod, "grace", models.DefaultAPIKeyGracePeriodSeconds*time.Second, "How long the old key stays valid")
+
+	authDeleteOrgCmd.Flags().StringVar(&deleteOrgName, "confirm", "", "Name of the organization to delete")
 
 	apiKeyCmd.AddCommand(apiKeyCreateCmd)
 	apiKeyCmd.AddCommand(apiKeyListCmd)
 	apiKeyCmd.AddCommand(apiKeyDeleteCmd)
+	apiKeyCmd.AddCommand(apiKeyRotateCmd)
 
 	authCmd.AddCommand(authRegisterCmd)
 	authCmd.AddCommand(authStatusCmd)
+	authCmd.AddCommand(authDeleteOrgCmd)
 	authCmd.AddCommand(apiKeyCmd)
 }
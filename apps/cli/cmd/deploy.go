@@ -3,6 +3,7 @@ package cmd
 import (
 	"fmt"
 	"strings"
+	"time"
 
 	"github.com/max-cloud/shared/pkg/models"
 	"github.com/spf13/cobra"
@@ -14,6 +15,24 @@ var (
 	deployPort    int
 	deployCommand string
 	deployArgs    string
+	deployTag     string
+	deployCPU     string
+	deployMemory  string
+
+	deployMinScale             int
+	deployMaxScale             int
+	deployConcurrency          int
+	deployTarget               int
+	deployScaleDownDelay       time.Duration
+	deployScaleToZeroRetention time.Duration
+
+	deployTimeout              time.Duration
+	deployResponseStartTimeout time.Duration
+	deployIdleTimeout          time.Duration
+
+	deployHealthPath string
+	deploySecrets    []string
+	deployEnvFiles   []string
 )
 
 var deployCmd = &cobra.Command{
@@ -23,22 +42,40 @@ var deployCmd = &cobra.Command{
 	RunE: func(cmd *cobra.Command, args []string) error {
 		image := args[0]
 
-		envVars := make(map[string]string)
-		for _, e := range deployEnv {
-			parts := strings.SplitN(e, "=", 2)
-			if len(parts) != 2 {
-				return fmt.Errorf("invalid env format %q, expected KEY=VALUE", e)
-			}
-			envVars[parts[0]] = parts[1]
+		envVars, err := loadEnv(deployEnvFiles, deployEnv)
+		if err != nil {
+			return err
+		}
+		secretEnv, err := parseSecretPairs(deploySecrets)
+		if err != nil {
+			return err
 		}
 
 		req := models.DeployRequest{
-			Name:    deployName,
-			Image:   image,
-			Port:    deployPort,
-			Command: parseCSV(deployCommand),
-			Args:    parseCSV(deployArgs),
-			EnvVars: envVars,
+			Name:      deployName,
+			Image:     image,
+			Port:      deployPort,
+			Command:   parseCSV(deployCommand),
+			Args:      parseCSV(deployArgs),
+			EnvVars:   envVars,
+			SecretEnv: secretEnv,
+			CPU:       deployCPU,
+			Memory:    deployMemory,
+			Tag:       deployTag,
+
+			MinScale:             deployMinScale,
+			MaxScale:             deployMaxScale,
+			ContainerConcurrency: deployConcurrency,
+			AutoscalingTarget:    deployTarget,
+			ScaleDownDelay:       int(deployScaleDownDelay.Seconds()),
+			ScaleToZeroRetention: int(deployScaleToZeroRetention.Seconds()),
+
+			TimeoutSeconds:              int(deployTimeout.Seconds()),
+			ResponseStartTimeoutSeconds: int(deployResponseStartTimeout.Seconds()),
+			IdleTimeoutSeconds:          int(deployIdleTimeout.Seconds()),
+		}
+		if deployHealthPath != "" {
+			req.Probes = healthProbes(deployHealthPath)
 		}
 
 		svc, err := client.Deploy(req)
@@ -51,11 +88,51 @@ var deployCmd = &cobra.Command{
 		fmt.Printf("  Name:   %s\n", svc.Name)
 		fmt.Printf("  Image:  %s\n", svc.Image)
 		fmt.Printf("  Status: %s\n", svc.Status)
+		fmt.Printf("  CPU:    %s\n", svc.CPU)
+		fmt.Printf("  Memory: %s\n", svc.Memory)
+		fmt.Printf("  Scale:  %d-%d\n", svc.MinScale, svc.MaxScale)
 		fmt.Printf("  URL:    %s\n", svc.URL)
+		printTagURLs(svc)
 		return nil
 	},
 }
 
+// parseEnvPairs wandelt KEY=VALUE-Angaben in eine Map um.
+func parseEnvPairs(pairs []string) (map[string]string, error) {
+	envVars := make(map[string]string, len(pairs))
+	for _, e := range pairs {
+		parts := strings.SplitN(e, "=", 2)
+		if len(parts) != 2 {
+			return nil, fmt.Errorf("invalid env format %q, expected KEY=VALUE", e)
+		}
+		envVars[parts[0]] = parts[1]
+	}
+	return envVars, nil
+}
+
+// Startup-Probe von --health-path: alle 5s prüfen, bis zu 2 Minuten Bootzeit erlauben.
+const (
+	healthStartupPeriod    = 5
+	healthStartupThreshold = 24
+)
+
+// healthProbes erzeugt HTTP-Readiness- und Startup-Probes für path.
+// Ein leerer Pfad ergibt leere Probes.
+func healthProbes(path string) *models.Probes {
+	if path == "" {
+		return &models.Probes{}
+	}
+	return &models.Probes{
+		Readiness: &models.Probe{Type: models.ProbeTypeHTTP, Path: path},
+		Startup: &models.Probe{
+			Type:             models.ProbeTypeHTTP,
+			Path:             path,
+			PeriodSeconds:    healthStartupPeriod,
+			FailureThreshold: healthStartupThreshold,
+		},
+	}
+}
+
 func parseCSV(s string) []string {
 	if s == "" {
 		return nil
@@ -75,7 +152,22 @@ func init() {
 	deployCmd.Flags().StringVar(&deployName, "name", "", "Service name (required)")
 	deployCmd.MarkFlagRequired("name")
 	deployCmd.Flags().StringArrayVar(&deployEnv, "env", nil, "Environment variables (KEY=VALUE, repeatable)")
+	deployCmd.Flags().StringArrayVar(&deployEnvFiles, "env-file", nil, "Read environment variables from a .env file (repeatable)")
+	deployCmd.Flags().StringArrayVar(&deploySecrets, "secret", nil, "Environment variable from a secret (ENV=SECRET, repeatable)")
 	deployCmd.Flags().IntVar(&deployPort, "port", 0, "Container port (0 = auto-detect from EXPOSE)")
 	deployCmd.Flags().StringVar(&deployCommand, "command", "", "Override ENTRYPOINT (comma-separated: python,app.py)")
 	deployCmd.Flags().StringVar(&deployArgs, "args", "", "Override CMD (comma-separated: --port,3000)")
+	deployCmd.Flags().StringVar(&deployCPU, "cpu", "", "CPU tier ("+strings.Join(models.CPUTiers, ", ")+"; default "+models.DefaultCPU+")")
+	deployCmd.Flags().StringVar(&deployMemory, "memory", "", "Memory tier ("+strings.Join(models.MemoryTiers, ", ")+"; default "+models.DefaultMemory+")")
+	deployCmd.Flags().IntVar(&deployMinScale, "min-scale", 0, "Minimum number of instances (0 = scale to zero)")
+	deployCmd.Flags().IntVar(&deployMaxScale, "max-scale", 0, fmt.Sprintf("Maximum number of instances (default %d)", models.DefaultMaxScale))
+	deployCmd.Flags().IntVar(&deployConcurrency, "concurrency", 0, "Maximum concurrent requests per instance (0 = unlimited)")
+	deployCmd.Flags().IntVar(&deployTarget, "target", 0, "Concurrent requests per instance the autoscaler aims for")
+	deployCmd.Flags().DurationVar(&deployScaleDownDelay, "scale-down-delay", 0, "Wait before scaling down (e.g. 2m)")
+	deployCmd.Flags().DurationVar(&deployScaleToZeroRetention, "scale-to-zero-retention", 0, "Keep the last instance this long before scaling to zero (e.g. 5m)")
+	deployCmd.Flags().DurationVar(&deployTimeout, "timeout", 0, "Maximum request duration (e.g. 15m; default: platform default)")
+	deployCmd.Flags().DurationVar(&deployResponseStartTimeout, "response-start-timeout", 0, "Maximum time until the first response byte (e.g. 5m)")
+	deployCmd.Flags().DurationVar(&deployIdleTimeout, "idle-timeout", 0, "Maximum time between response bytes (e.g. 2m)")
+	deployCmd.Flags().StringVar(&deployHealthPath, "health-path", "", "HTTP path for readiness and startup checks (e.g. /healthz)")
+	deployCmd.Flags().StringVar(&deployTag, "tag", "", "Traffic tag for a stable preview URL (e.g. candidate)")
 }
@@ -0,0 +1,70 @@
+package cmd
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+)
+
+var describeCmd = &cobra.Command{
+	Use:   "describe [service-name]",
+	Short: "Show details and the current status of a service",
+	Long: `Show the configuration and status of a service.
+
+If the service is not ready, the reason reported by the platform is shown,
+e.g. image pull errors, crashing containers or exceeded quotas.
+
+Example:
+  maxcloud describe myapp`,
+	Args: cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		serviceID, err := resolveServiceID(args[0])
+		if err != nil {
+			return err
+		}
+
+		svc, err := client.GetService(serviceID)
+		if err != nil {
+			return formatError(err)
+		}
+
+		fmt.Printf("Name:       %s\n", svc.Name)
+		fmt.Printf("ID:         %s\n", svc.ID)
+		fmt.Printf("Image:      %s\n", svc.Image)
+		fmt.Printf("Status:     %s\n", svc.Status)
+		if svc.StatusReason != "" {
+			fmt.Printf("Reason:     %s\n", svc.StatusReason)
+		}
+		if svc.StatusMessage != "" {
+			fmt.Printf("Message:    %s\n", svc.StatusMessage)
+		}
+		fmt.Printf("URL:        %s\n", svc.URL)
+		printTagURLs(svc)
+		fmt.Printf("Revision:   %s (generation %d)\n", svc.LatestRevision, svc.Generation)
+		if svc.Port != 0 {
+			fmt.Printf("Port:       %d\n", svc.Port)
+		}
+		if len(svc.Command) > 0 {
+			fmt.Printf("Command:    %s\n", strings.Join(svc.Command, " "))
+		}
+		if len(svc.Args) > 0 {
+			fmt.Printf("Args:       %s\n", strings.Join(svc.Args, " "))
+		}
+		fmt.Printf("CPU:        %s\n", svc.CPU)
+		fmt.Printf("Memory:     %s\n", svc.Memory)
+		fmt.Printf("Scale:      %d-%d\n", svc.MinScale, svc.MaxScale)
+		fmt.Printf("Env:        %d variables, %d from secrets\n", len(svc.EnvVars), len(svc.SecretEnv))
+		if svc.ReconcileAttempts > 0 {
+			fmt.Printf("Last error: %s (%d failed attempts)\n", svc.LastError, svc.ReconcileAttempts)
+		}
+		fmt.Printf("Created:    %s\n", svc.CreatedAt.Format(time.DateTime))
+		fmt.Printf("Updated:    %s\n", svc.UpdatedAt.Format(time.DateTime))
+		return nil
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(describeCmd)
+}
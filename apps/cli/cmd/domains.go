@@ -0,0 +1,157 @@
+package cmd
+
+import (
+	"fmt"
+	"net/url"
+	"os"
+	"text/tabwriter"
+
+	"github.com/max-cloud/shared/pkg/models"
+	"github.com/spf13/cobra"
+)
+
+var domainsAddService string
+
+var domainsCmd = &cobra.Command{
+	Use:   "domains",
+	Short: "Manage custom domains of your services",
+}
+
+var domainsAddCmd = &cobra.Command{
+	Use:   "add [hostname]",
+	Short: "Add a custom domain for a service",
+	Long: `Add a custom domain and print the DNS records needed to use it.
+
+The domain must be verified with a TXT record before traffic is routed.
+A TLS certificate is requested automatically after verification.
+
+Example:
+  maxcloud domains add www.example.com --service myapp`,
+	Args: cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		serviceID, err := resolveServiceID(domainsAddService)
+		if err != nil {
+			return err
+		}
+
+		domain, err := client.CreateDomain(models.CreateDomainRequest{Hostname: args[0], ServiceID: serviceID})
+		if err != nil {
+			return formatError(err)
+		}
+
+		fmt.Printf("Domain %s added for service %s.\n\n", domain.Hostname, domainsAddService)
+		fmt.Printf("1. Prove ownership with this DNS record:\n")
+		fmt.Printf("     TXT    %s  %q\n", domain.ChallengeRecord(), domain.VerificationToken)
+		if svc, err := client.GetService(serviceID); err == nil && svc.URL != "" {
+			if u, err := url.Parse(svc.URL); err == nil {
+				fmt.Printf("2. Point the domain at the service:\n")
+				fmt.Printf("     CNAME  %s  %s\n", domain.Hostname, u.Hostname())
+			}
+		}
+		fmt.Printf("\nThen run: maxcloud domains verify %s\n", domain.Hostname)
+		return nil
+	},
+}
+
+var domainsListCmd = &cobra.Command{
+	Use:   "list",
+	Short: "List custom domains",
+	RunE: func(cmd *cobra.Command, args []string) error {
+		domains, err := client.ListDomains()
+		if err != nil {
+			return formatError(err)
+		}
+
+		if len(domains) == 0 {
+			fmt.Println("No domains found.")
+			return nil
+		}
+
+		services, err := client.ListServices()
+		if err != nil {
+			return formatError(err)
+		}
+		serviceNames := make(map[string]string, len(services))
+		for _, svc := range services {
+			serviceNames[svc.ID] = svc.Name
+		}
+
+		w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+		fmt.Fprintln(w, "HOSTNAME\tSERVICE\tSTATUS\tCERTIFICATE\tMESSAGE")
+		for _, d := range domains {
+			cert := "pending"
+			if d.CertificateReady {
+				cert = "issued"
+			}
+			fmt.Fprintf(w, "%s\t%s\t%s\t%s\t%s\n", d.Hostname, serviceNames[d.ServiceID], d.Status, cert, d.Message)
+		}
+		w.Flush()
+		return nil
+	},
+}
+
+var domainsVerifyCmd = &cobra.Command{
+	Use:   "verify [hostname]",
+	Short: "Verify ownership of a domain via its TXT record",
+	Args:  cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		domainID, err := resolveDomainID(args[0])
+		if err != nil {
+			return err
+		}
+
+		domain, err := client.VerifyDomain(domainID)
+		if err != nil {
+			return formatError(err)
+		}
+
+		fmt.Printf("Domain %s verified.\n", domain.Hostname)
+		fmt.Printf("  Status: %s\n", domain.Status)
+		fmt.Printf("The certificate is issued in the background, check 'maxcloud domains list'.\n")
+		return nil
+	},
+}
+
+var domainsDeleteCmd = &cobra.Command{
+	Use:   "delete [hostname]",
+	Short: "Remove a custom domain",
+	Args:  cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		domainID, err := resolveDomainID(args[0])
+		if err != nil {
+			return err
+		}
+
+		if err := client.DeleteDomain(domainID); err != nil {
+			return formatError(err)
+		}
+		fmt.Printf("Domain %s deleted.\n", args[0])
+		return nil
+	},
+}
+
+// resolveDomainID löst einen Hostnamen zur Domain-ID auf.
+func resolveDomainID(hostname string) (string, error) {
+	domains, err := client.ListDomains()
+	if err != nil {
+		return "", formatError(err)
+	}
+
+	for _, d := range domains {
+		if d.Hostname == hostname || d.ID == hostname {
+			return d.ID, nil
+		}
+	}
+	return "", fmt.Errorf("domain %q not found", hostname)
+}
+
+func init() {
+	domainsAddCmd.Flags().StringVar(&domainsAddService, "service", "", "Service the domain routes to (required)")
+	domainsAddCmd.MarkFlagRequired("service")
+
+	domainsCmd.AddCommand(domainsAddCmd)
+	domainsCmd.AddCommand(domainsListCmd)
+	domainsCmd.AddCommand(domainsVerifyCmd)
+	domainsCmd.AddCommand(domainsDeleteCmd)
+	rootCmd.AddCommand(domainsCmd)
+}
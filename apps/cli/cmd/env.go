@@ -0,0 +1,202 @@
+package cmd
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"maps"
+	"os"
+	"slices"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/max-cloud/shared/pkg/models"
+	"github.com/spf13/cobra"
+)
+
+var (
+	envSetFiles     []string
+	envResetTraffic bool
+)
+
+var envCmd = &cobra.Command{
+	Use:   "env",
+	Short: "Manage environment variables of a service",
+}
+
+var envListCmd = &cobra.Command{
+	Use:   "list [service-name]",
+	Short: "List environment variables and secret references of a service",
+	Args:  cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		serviceID, err := resolveServiceID(args[0])
+		if err != nil {
+			return err
+		}
+
+		env, err := client.GetServiceEnv(serviceID)
+		if err != nil {
+			return formatError(err)
+		}
+
+		if len(env.EnvVars) == 0 && len(env.SecretEnv) == 0 {
+			fmt.Println("No environment variables set.")
+			return nil
+		}
+
+		w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+		fmt.Fprintln(w, "NAME\tVALUE")
+		for _, k := range slices.Sorted(maps.Keys(env.EnvVars)) {
+			fmt.Fprintf(w, "%s\t%s\n", k, env.EnvVars[k])
+		}
+		for _, k := range slices.Sorted(maps.Keys(env.SecretEnv)) {
+			fmt.Fprintf(w, "%s\tsecret:%s\n", k, env.SecretEnv[k])
+		}
+		w.Flush()
+		return nil
+	},
+}
+
+var envSetCmd = &cobra.Command{
+	Use:   "set [service-name] [KEY=VALUE...]",
+	Short: "Set environment variables and redeploy the service",
+	Long: `Merge environment variables into a service. Variables that are not
+mentioned keep their value. Values from --env-file are applied first,
+KEY=VALUE arguments override them.
+
+If the service has a traffic split, the change is rejected unless
+--reset-traffic routes all traffic to the new revision.
+
+Example:
+  maxcloud env set myapp LOG_LEVEL=debug FEATURE_X=on
+  maxcloud env set myapp --env-file .env.production`,
+	Args: cobra.MinimumNArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		envVars, err := loadEnv(envSetFiles, args[1:])
+		if err != nil {
+			return err
+		}
+		if len(envVars) == 0 {
+			return errors.New("no variables given: pass KEY=VALUE arguments or --env-file")
+		}
+
+		serviceID, err := resolveServiceID(args[0])
+		if err != nil {
+			return err
+		}
+
+		svc, err := client.UpdateServiceEnv(serviceID, models.UpdateEnvRequest{Set: envVars, ResetTraffic: envResetTraffic})
+		if err != nil {
+			return formatError(err)
+		}
+
+		printEnvUpdate(svc)
+		return nil
+	},
+}
+
+var envUnsetCmd = &cobra.Command{
+	Use:   "unset [service-name] [KEY...]",
+	Short: "Remove environment variables or secret references and redeploy the service",
+	Args:  cobra.MinimumNArgs(2),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		serviceID, err := resolveServiceID(args[0])
+		if err != nil {
+			return err
+		}
+
+		svc, err := client.UpdateServiceEnv(serviceID, models.UpdateEnvRequest{Unset: args[1:], ResetTraffic: envResetTraffic})
+		if err != nil {
+			return formatError(err)
+		}
+
+		printEnvUpdate(svc)
+		return nil
+	},
+}
+
+func printEnvUpdate(svc *models.Service) {
+	fmt.Printf("Environment updated.\n")
+	fmt.Printf("  Name:       %s\n", svc.Name)
+	fmt.Printf("  Status:     %s\n", svc.Status)
+	fmt.Printf("  Generation: %d\n", svc.Generation)
+	fmt.Printf("  Revision:   %s\n", svc.LatestRevision)
+}
+
+// loadEnv liest die angegebenen .env-Dateien und wendet anschließend die
+// KEY=VALUE-Angaben an, die damit Vorrang vor den Dateien haben.
+func loadEnv(files []string, pairs []string) (map[string]string, error) {
+	envVars := make(map[string]string)
+	for _, f := range files {
+		fileVars, err := parseEnvFile(f)
+		if err != nil {
+			return nil, err
+		}
+		maps.Copy(envVars, fileVars)
+	}
+
+	pairVars, err := parseEnvPairs(pairs)
+	if err != nil {
+		return nil, err
+	}
+	maps.Copy(envVars, pairVars)
+	return envVars, nil
+}
+
+// parseEnvFile liest eine .env-Datei mit KEY=VALUE-Zeilen. Leere Zeilen und
+// Kommentare (#) werden übersprungen, ein führendes "export " und umschließende
+// Anführungszeichen um den Wert werden entfernt.
+func parseEnvFile(path string) (map[string]string, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("reading env file: %w", err)
+	}
+	defer f.Close()
+
+	envVars := make(map[string]string)
+	scanner := bufio.NewScanner(f)
+	for lineNo := 1; scanner.Scan(); lineNo++ {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		line = strings.TrimPrefix(line, "export ")
+
+		key, value, ok := strings.Cut(line, "=")
+		key = strings.TrimSpace(key)
+		if !ok || key == "" {
+			return nil, fmt.Errorf("%s:%d: expected KEY=VALUE", path, lineNo)
+		}
+		envVars[key] = unquoteEnvValue(strings.TrimSpace(value))
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("reading env file: %w", err)
+	}
+	return envVars, nil
+}
+
+// unquoteEnvValue entfernt umschließende einfache oder doppelte Anführungszeichen.
+// In doppelten Anführungszeichen wird \n als Zeilenumbruch interpretiert.
+func unquoteEnvValue(value string) string {
+	if len(value) < 2 {
+		return value
+	}
+	switch {
+	case value[0] == '\'' && value[len(value)-1] == '\'':
+		return value[1 : len(value)-1]
+	case value[0] == '"' && value[len(value)-1] == '"':
+		return strings.ReplaceAll(value[1:len(value)-1], `\n`, "\n")
+	}
+	return value
+}
+
+func init() {
+	envSetCmd.Flags().StringArrayVar(&envSetFiles, "env-file", nil, "Read variables from a .env file (repeatable)")
+	envSetCmd.Flags().BoolVar(&envResetTraffic, "reset-traffic", false, "Discard the traffic split and route all traffic to the new revision")
+	envUnsetCmd.Flags().BoolVar(&envResetTraffic, "reset-traffic", false, "Discard the traffic split and route all traffic to the new revision")
+
+	envCmd.AddCommand(envListCmd)
+	envCmd.AddCommand(envSetCmd)
+	envCmd.AddCommand(envUnsetCmd)
+	rootCmd.AddCommand(envCmd)
+}
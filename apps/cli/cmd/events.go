@@ -0,0 +1,56 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"text/tabwriter"
+	"time"
+
+	"github.com/spf13/cobra"
+)
+
+var eventsLimit int
+
+var eventsCmd = &cobra.Command{
+	Use:   "events [service-name]",
+	Short: "Show the event history of a service",
+	Long: `Show what happened to a service over time, newest first.
+
+Platform events (created, deployed, status changes, failures) are merged
+with the Kubernetes events of the service's pods. ACTOR shows who triggered
+an event (user:<id> or service-account:<id>), it is empty for events
+recorded by the platform itself.
+
+Example:
+  maxcloud events myapp --limit 20`,
+	Args: cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		serviceID, err := resolveServiceID(args[0])
+		if err != nil {
+			return err
+		}
+
+		events, err := client.ListServiceEvents(serviceID, eventsLimit)
+		if err != nil {
+			return formatError(err)
+		}
+
+		if len(events) == 0 {
+			fmt.Println("No events found.")
+			return nil
+		}
+
+		w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+		fmt.Fprintln(w, "TIME\tTYPE\tREASON\tACTOR\tMESSAGE")
+		for _, e := range events {
+			fmt.Fprintf(w, "%s\t%s\t%s\t%s\t%s\n", e.CreatedAt.Local().Format(time.DateTime), e.Type, e.Reason, e.Actor, e.Message)
+		}
+		w.Flush()
+		return nil
+	},
+}
+
+func init() {
+	eventsCmd.Flags().IntVar(&eventsLimit, "limit", 0, "Maximum number of events to show (default: server default)")
+	rootCmd.AddCommand(eventsCmd)
+}
@@ -124,7 +124,7 @@ var acceptInviteCmd = &cobra.Command{
 
 func init() {
 	inviteCreateCmd.Flags().StringVar(&inviteEmail, "email", "", "Email address to invite")
-	inviteCreateCmd.Flags().StringVar(&inviteRole, "role", "member", "Role for the invited user (member or admin)")
+	inviteCreateCmd.Flags().StringVar(&inviteRole, "role", string(models.OrgRoleDeveloper), "Role for the invited user (owner, admin, developer or viewer)")
 	inviteCreateCmd.MarkFlagRequired("email")
 
 	acceptInviteCmd.Flags().StringVar(&inviteToken, "token", "", "Invitation token")
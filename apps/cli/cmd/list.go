@@ -23,9 +23,9 @@ var listCmd = &cobra.Command{
 		}
 
 		w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
-		fmt.Fprintln(w, "NAME\tIMAGE\tSTATUS\tURL")
+		fmt.Fprintln(w, "NAME\tIMAGE\tSTATUS\tREASON\tURL")
 		for _, svc := range services {
-			fmt.Fprintf(w, "%s\t%s\t%s\t%s\n", svc.Name, svc.Image, svc.Status, svc.URL)
+			fmt.Fprintf(w, "%s\t%s\t%s\t%s\t%s\n", svc.Name, svc.Image, svc.Status, svc.StatusReason, svc.URL)
 		}
 		w.Flush()
 		return nil
@@ -22,21 +22,9 @@ var logsCmd = &cobra.Command{
 	RunE: func(cmd *cobra.Command, args []string) error {
 		serviceName := args[0]
 
-		// Service-Name zu ID auflösen
-		services, err := client.ListServices()
+		serviceID, err := resolveServiceID(serviceName)
 		if err != nil {
-			return formatError(err)
-		}
-
-		var serviceID string
-		for _, svc := range services {
-			if svc.Name == serviceName {
-				serviceID = svc.ID
-				break
-			}
-		}
-		if serviceID == "" {
-			return fmt.Errorf("service %q not found", serviceName)
+			return err
 		}
 
 		ctx, cancel := signal.NotifyContext(context.Background(), os.Interrupt)
@@ -0,0 +1,106 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"text/tabwriter"
+
+	"github.com/max-cloud/shared/pkg/models"
+	"github.com/spf13/cobra"
+)
+
+var membersCmd = &cobra.Command{
+	Use:   "members",
+	Short: "Manage members of your organization",
+}
+
+var membersListCmd = &cobra.Command{
+	Use:   "list",
+	Short: "List members of the organization",
+	RunE: func(cmd *cobra.Command, args []string) error {
+		members, err := client.ListMembers()
+		if err != nil {
+			return formatError(err)
+		}
+
+		w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+		fmt.Fprintln(w, "EMAIL\tROLE\tUSER ID")
+		for _, m := range members {
+			fmt.Fprintf(w, "%s\t%s\t%s\n", m.Email, m.Role, m.UserID)
+		}
+		w.Flush()
+		return nil
+	},
+}
+
+var membersSetRoleCmd = &cobra.Command{
+	Use:   "set-role [email] [owner|admin|developer|viewer]",
+	Short: "Change the role of a member",
+	Long: `Change the role of a member.
+
+Roles and what they may do:
+  owner      everything, including deleting the organization
+  admin      manage members, secrets, services and domains
+  developer  deploy services and push images, but not delete
+  viewer     read-only access, including logs and events
+
+Only owners can grant or revoke the owner role.`,
+	Args: cobra.ExactArgs(2),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		userID, err := resolveMemberID(args[0])
+		if err != nil {
+			return err
+		}
+
+		member, err := client.UpdateMemberRole(userID, models.OrgRole(args[1]))
+		if err != nil {
+			return formatError(err)
+		}
+		fmt.Printf("%s is now %s.\n", member.Email, member.Role)
+		return nil
+	},
+}
+
+var membersRemoveCmd = &cobra.Command{
+	Use:   "remove [email]",
+	Short: "Remove a member from the organization",
+	Long: `Remove a member from the organization.
+
+All API keys of the member for this organization are revoked immediately.
+The last owner of an organization cannot be removed.`,
+	Args: cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		userID, err := resolveMemberID(args[0])
+		if err != nil {
+			return err
+		}
+
+		if err := client.RemoveMember(userID); err != nil {
+			return formatError(err)
+		}
+		fmt.Printf("Member %s removed.\n", args[0])
+		return nil
+	},
+}
+
+// resolveMemberID löst eine E-Mail-Adresse zur User-ID eines Mitglieds auf.
+func resolveMemberID(email string) (string, error) {
+	members, err := client.ListMembers()
+	if err != nil {
+		return "", formatError(err)
+	}
+
+	for _, m := range members {
+		if m.Email == email || m.UserID == email {
+			return m.UserID, nil
+		}
+	}
+	return "", fmt.Errorf("member %q not found", email)
+}
+
+func init() {
+	membersCmd.AddCommand(membersListCmd)
+	membersCmd.AddCommand(membersSetRoleCmd)
+	membersCmd.AddCommand(membersRemoveCmd)
+	rootCmd.AddCommand(membersCmd)
+}
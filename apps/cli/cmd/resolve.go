@@ -0,0 +1,18 @@
+package cmd
+
+import "fmt"
+
+// resolveServiceID löst einen Service-Namen zur ID auf.
+func resolveServiceID(name string) (string, error) {
+	services, err := client.ListServices()
+	if err != nil {
+		return "", formatError(err)
+	}
+
+	for _, svc := range services {
+		if svc.Name == name {
+			return svc.ID, nil
+		}
+	}
+	return "", fmt.Errorf("service %q not found", name)
+}
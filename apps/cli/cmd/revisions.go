@@ -0,0 +1,65 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"text/tabwriter"
+	"time"
+
+	"github.com/spf13/cobra"
+)
+
+var revisionsCmd = &cobra.Command{
+	Use:   "revisions",
+	Short: "Inspect the revision history of a service",
+}
+
+var revisionsListCmd = &cobra.Command{
+	Use:   "list [service-name]",
+	Short: "List all revisions of a service",
+	Args:  cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		serviceID, err := resolveServiceID(args[0])
+		if err != nil {
+			return err
+		}
+
+		svc, err := client.GetService(serviceID)
+		if err != nil {
+			return formatError(err)
+		}
+
+		revisions, err := client.ListRevisions(serviceID)
+		if err != nil {
+			return formatError(err)
+		}
+
+		if len(revisions) == 0 {
+			fmt.Println("No revisions found.")
+			return nil
+		}
+
+		w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+		fmt.Fprintln(w, "NAME\tIMAGE\tPORT\tSCALE\tENV\tTRAFFIC\tCREATED\tCURRENT")
+		for _, rev := range revisions {
+			current := ""
+			if rev.Name == svc.LatestRevision {
+				current = "*"
+			}
+			fmt.Fprintf(w, "%s\t%s\t%d\t%d-%d\t%d\t%d%%\t%s\t%s\n",
+				rev.Name, rev.Image, rev.Port,
+				rev.MinScale, rev.MaxScale, len(rev.EnvVars), rev.Traffic,
+				rev.CreatedAt.Format(time.DateTime),
+				current,
+			)
+		}
+		w.Flush()
+
+		return nil
+	},
+}
+
+func init() {
+	revisionsCmd.AddCommand(revisionsListCmd)
+	rootCmd.AddCommand(revisionsCmd)
+}
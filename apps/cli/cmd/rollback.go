@@ -0,0 +1,46 @@
+package cmd
+
+import (
+	"fmt"
+
+	"github.com/max-cloud/shared/pkg/models"
+	"github.com/spf13/cobra"
+)
+
+var rollbackTo string
+
+var rollbackCmd = &cobra.Command{
+	Use:   "rollback [service-name]",
+	Short: "Roll a service back to a previous revision",
+	Long: `Redeploy the exact configuration of an earlier revision.
+
+Without --to the revision before the current one is used. Use
+'maxcloud revisions list <service>' to find revision names.`,
+	Args: cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		serviceID, err := resolveServiceID(args[0])
+		if err != nil {
+			return err
+		}
+
+		svc, err := client.RollbackService(serviceID, models.RollbackRequest{
+			Revision: rollbackTo,
+		})
+		if err != nil {
+			return formatError(err)
+		}
+
+		fmt.Printf("Service rolled back!\n")
+		fmt.Printf("  Name:       %s\n", svc.Name)
+		fmt.Printf("  Image:      %s\n", svc.Image)
+		fmt.Printf("  Status:     %s\n", svc.Status)
+		fmt.Printf("  Revision:   %s\n", svc.LatestRevision)
+		fmt.Printf("  URL:        %s\n", svc.URL)
+		return nil
+	},
+}
+
+func init() {
+	rollbackCmd.Flags().StringVar(&rollbackTo, "to", "", "Target revision name or ID (default: previous revision)")
+	rootCmd.AddCommand(rollbackCmd)
+}
@@ -0,0 +1,120 @@
+package cmd
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"github.com/max-cloud/shared/pkg/models"
+	"github.com/spf13/cobra"
+)
+
+var secretsCmd = &cobra.Command{
+	Use:   "secrets",
+	Short: "Manage encrypted secrets of the organization",
+}
+
+var secretsSetCmd = &cobra.Command{
+	Use:   "set [name] [value]",
+	Short: "Create or overwrite a secret",
+	Long: `Store an encrypted secret. Without a value argument the value is read
+from stdin, which keeps it out of the shell history.
+
+Example:
+  maxcloud secrets set db-password
+  cat key.pem | maxcloud secrets set tls-key
+
+Reference it from a service with --secret ENV=NAME on deploy or update.
+Changing the value of an existing secret restarts the running revisions of
+every service that references it; the traffic split stays unchanged.`,
+	Args: cobra.RangeArgs(1, 2),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		name := args[0]
+		if !models.SecretNamePattern.MatchString(name) {
+			return fmt.Errorf("invalid secret name %q: use letters, digits, '_', '.' and '-'", name)
+		}
+
+		var value string
+		if len(args) == 2 {
+			value = args[1]
+		} else {
+			data, err := io.ReadAll(io.LimitReader(os.Stdin, models.MaxSecretSize+1))
+			if err != nil {
+				return fmt.Errorf("reading secret from stdin: %w", err)
+			}
+			value = strings.TrimSuffix(string(data), "\n")
+		}
+		if value == "" {
+			return errors.New("secret value must not be empty")
+		}
+
+		secret, err := client.SetSecret(name, value)
+		if err != nil {
+			return formatError(err)
+		}
+
+		fmt.Printf("Secret %s saved.\n", secret.Name)
+		return nil
+	},
+}
+
+var secretsListCmd = &cobra.Command{
+	Use:   "list",
+	Short: "List secrets (values are never shown)",
+	RunE: func(cmd *cobra.Command, args []string) error {
+		secrets, err := client.ListSecrets()
+		if err != nil {
+			return formatError(err)
+		}
+
+		if len(secrets) == 0 {
+			fmt.Println("No secrets found.")
+			return nil
+		}
+
+		w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+		fmt.Fprintln(w, "NAME\tUPDATED")
+		for _, s := range secrets {
+			fmt.Fprintf(w, "%s\t%s\n", s.Name, s.UpdatedAt.Format(time.DateTime))
+		}
+		w.Flush()
+		return nil
+	},
+}
+
+var secretsDeleteCmd = &cobra.Command{
+	Use:   "delete [name]",
+	Short: "Delete a secret that is no longer referenced by any service",
+	Args:  cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		if err := client.DeleteSecret(args[0]); err != nil {
+			return formatError(err)
+		}
+		fmt.Printf("Secret %s deleted.\n", args[0])
+		return nil
+	},
+}
+
+// parseSecretPairs wandelt ENV=SECRET-Angaben in eine Map um.
+func parseSecretPairs(pairs []string) (map[string]string, error) {
+	refs := make(map[string]string, len(pairs))
+	for _, p := range pairs {
+		env, secret, ok := strings.Cut(p, "=")
+		if !ok || env == "" || secret == "" {
+			return nil, fmt.Errorf("invalid secret format %q, expected ENV=SECRET", p)
+		}
+		refs[env] = secret
+	}
+	return refs, nil
+}
+
+func init() {
+	secretsCmd.AddCommand(secretsSetCmd)
+	secretsCmd.AddCommand(secretsListCmd)
+	secretsCmd.AddCommand(secretsDeleteCmd)
+	rootCmd.AddCommand(secretsCmd)
+}
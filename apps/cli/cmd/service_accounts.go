@@ -0,0 +1,177 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"text/tabwriter"
+	"time"
+
+	"github.com/max-cloud/shared/pkg/models"
+	"github.com/spf13/cobra"
+)
+
+var (
+	serviceAccountRole    string
+	serviceAccountKeyName string
+	serviceAccountScopes  []string
+	serviceAccountExpires time.Duration
+)
+
+var serviceAccountCmd = &cobra.Command{
+	Use:   "service-account",
+	Short: "Manage service accounts for CI/CD",
+	Long: `Manage service accounts for CI/CD.
+
+Service accounts belong to the organization instead of a person. They have
+no email and cannot log in, but hold a role and API keys. Their keys keep
+working when the member who created them leaves the organization.`,
+}
+
+var serviceAccountListCmd = &cobra.Command{
+	Use:   "list",
+	Short: "List service accounts of the organization",
+	RunE: func(cmd *cobra.Command, args []string) error {
+		accounts, err := client.ListServiceAccounts()
+		if err != nil {
+			return formatError(err)
+		}
+
+		w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+		fmt.Fprintln(w, "NAME\tROLE\tID\tCREATED")
+		for _, sa := range accounts {
+			fmt.Fprintf(w, "%s\t%s\t%s\t%s\n", sa.Name, sa.Role, sa.ID, sa.CreatedAt.Format(time.DateTime))
+		}
+		w.Flush()
+		return nil
+	},
+}
+
+var serviceAccountCreateCmd = &cobra.Command{
+	Use:   "create [name]",
+	Short: "Create a service account",
+	Long: `Create a service account.
+
+Service accounts can be admin, developer or viewer, never owner.
+
+Example:
+  maxcloud service-account create github-actions --role developer
+  maxcloud service-account create-key github-actions --name deploy --scope services:deploy --scope registry:push`,
+	Args: cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		sa, err := client.CreateServiceAccount(models.CreateServiceAccountRequest{
+			Name: args[0],
+			Role: models.OrgRole(serviceAccountRole),
+		})
+		if err != nil {
+			return formatError(err)
+		}
+		fmt.Printf("Service account %s created with role %s.\n", sa.Name, sa.Role)
+		return nil
+	},
+}
+
+var serviceAccountSetRoleCmd = &cobra.Command{
+	Use:   "set-role [name] [admin|developer|viewer]",
+	Short: "Change the role of a service account",
+	Args:  cobra.ExactArgs(2),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		id, err := resolveServiceAccountID(args[0])
+		if err != nil {
+			return err
+		}
+
+		sa, err := client.UpdateServiceAccountRole(id, models.OrgRole(args[1]))
+		if err != nil {
+			return formatError(err)
+		}
+		fmt.Printf("%s is now %s.\n", sa.Name, sa.Role)
+		return nil
+	},
+}
+
+var serviceAccountDeleteCmd = &cobra.Command{
+	Use:   "delete [name]",
+	Short: "Delete a service account and revoke its API keys",
+	Args:  cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		id, err := resolveServiceAccountID(args[0])
+		if err != nil {
+			return err
+		}
+
+		if err := client.DeleteServiceAccount(id); err != nil {
+			return formatError(err)
+		}
+		fmt.Printf("Service account %s deleted.\n", args[0])
+		return nil
+	},
+}
+
+var serviceAccountCreateKeyCmd = &cobra.Command{
+	Use:   "create-key [name]",
+	Short: "Create an API key for a service account",
+	Long: `Create an API key for a service account.
+
+Scopes and expiry work like for "maxcloud auth api-key create". Use
+"maxcloud auth api-key rotate" to replace the key later.`,
+	Args: cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		id, err := resolveServiceAccountID(args[0])
+		if err != nil {
+			return err
+		}
+
+		req := models.CreateAPIKeyRequest{Name: serviceAccountKeyName}
+		for _, scope := range serviceAccountScopes {
+			req.Scopes = append(req.Scopes, models.Permission(scope))
+		}
+		if serviceAccountExpires > 0 {
+			expiresAt := time.Now().Add(serviceAccountExpires)
+			req.ExpiresAt = &expiresAt
+		}
+
+		resp, err := client.CreateServiceAccountAPIKey(id, req)
+		if err != nil {
+			return formatError(err)
+		}
+
+		fmt.Printf("API key created for service account %s:\n", args[0])
+		fmt.Printf("  Name:    %s\n", resp.Info.Name)
+		fmt.Printf("  Scopes:  %s\n", formatScopes(resp.Info.Scopes))
+		fmt.Printf("  Expires: %s\n", formatExpiry(resp.Info.ExpiresAt))
+		fmt.Printf("  Key:     %s\n", resp.APIKey)
+		fmt.Printf("\nSave this key — it won't be shown again.\n")
+		return nil
+	},
+}
+
+// resolveServiceAccountID löst den Namen eines Service-Accounts zu seiner ID auf.
+func resolveServiceAccountID(name string) (string, error) {
+	accounts, err := client.ListServiceAccounts()
+	if err != nil {
+		return "", formatError(err)
+	}
+
+	for _, sa := range accounts {
+		if sa.Name == name || sa.ID == name {
+			return sa.ID, nil
+		}
+	}
+	return "", fmt.Errorf("service account %q not found", name)
+}
+
+func init() {
+	serviceAccountCreateCmd.Flags().StringVar(&serviceAccountRole, "role", string(models.OrgRoleDeveloper), "Role of the service account (admin, developer, viewer)")
+
+	serviceAccountCreateKeyCmd.Flags().StringVar(&serviceAccountKeyName, "name", "", "Name for the API key")
+	serviceAccountCreateKeyCmd.MarkFlagRequired("name")
+	serviceAccountCreateKeyCmd.Flags().StringArrayVar(&serviceAccountScopes, "scope", nil, "Restrict the key to a scope (repeatable)")
+	serviceAccountCreateKeyCmd.Flags().DurationVar(&serviceAccountExpires, "expires", 0, "Lifetime of the key, e.g. 720h (default: never expires)")
+
+	serviceAccountCmd.AddCommand(serviceAccountListCmd)
+	serviceAccountCmd.AddCommand(serviceAccountCreateCmd)
+	serviceAccountCmd.AddCommand(serviceAccountSetRoleCmd)
+	serviceAccountCmd.AddCommand(serviceAccountDeleteCmd)
+	serviceAccountCmd.AddCommand(serviceAccountCreateKeyCmd)
+	rootCmd.AddCommand(serviceAccountCmd)
+}
@@ -0,0 +1,106 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/max-cloud/shared/pkg/models"
+	"github.com/spf13/cobra"
+)
+
+var trafficCmd = &cobra.Command{
+	Use:   "traffic",
+	Short: "Split traffic between revisions of a service",
+}
+
+var trafficSetCmd = &cobra.Command{
+	Use:   "set [service-name] [revision=percent]...",
+	Short: "Set the traffic split of a service",
+	Long: `Route traffic to one or more revisions. Percentages must sum to 100.
+Append @TAG to keep a stable tag URL for a revision.
+
+Example:
+  maxcloud traffic set myapp myapp-00001=90 myapp-00002=10
+  maxcloud traffic set myapp myapp-00001=100 myapp-00002=0@candidate
+
+Without any revision=percent pairs the split is reset and the latest
+revision receives all traffic.`,
+	Args: cobra.MinimumNArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		targets, err := parseTrafficPairs(args[1:])
+		if err != nil {
+			return err
+		}
+
+		serviceID, err := resolveServiceID(args[0])
+		if err != nil {
+			return err
+		}
+
+		svc, err := client.SetTraffic(serviceID, models.SetTrafficRequest{Targets: targets})
+		if err != nil {
+			return formatError(err)
+		}
+
+		fmt.Printf("Traffic updated for %s\n", svc.Name)
+		if len(svc.Traffic) == 0 {
+			fmt.Printf("  %s: 100%%\n", svc.LatestRevision)
+			return nil
+		}
+
+		w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+		for _, t := range svc.Traffic {
+			if t.Tag != "" {
+				fmt.Fprintf(w, "  %s:\t%d%%\t@%s\n", t.RevisionName, t.Percent, t.Tag)
+				continue
+			}
+			fmt.Fprintf(w, "  %s:\t%d%%\t\n", t.RevisionName, t.Percent)
+		}
+		w.Flush()
+		return nil
+	},
+}
+
+func parseTrafficPairs(pairs []string) ([]models.TrafficTarget, error) {
+	targets := make([]models.TrafficTarget, 0, len(pairs))
+	sum := 0
+	for _, p := range pairs {
+		parts := strings.SplitN(p, "=", 2)
+		if len(parts) != 2 || parts[0] == "" {
+			return nil, fmt.Errorf("invalid traffic format %q, expected REVISION=PERCENT[@TAG]", p)
+		}
+		value, tag, _ := strings.Cut(parts[1], "@")
+		percent, err := strconv.Atoi(strings.TrimSuffix(value, "%"))
+		if err != nil || percent < 0 || percent > 100 {
+			return nil, fmt.Errorf("invalid percent in %q, expected a number between 0 and 100", p)
+		}
+		sum += percent
+		targets = append(targets, models.TrafficTarget{RevisionName: parts[0], Percent: percent, Tag: tag})
+	}
+	if len(targets) > 0 && sum != 100 {
+		return nil, fmt.Errorf("traffic percentages must sum to 100, got %d", sum)
+	}
+	return targets, nil
+}
+
+// printTagURLs gibt die Preview-URLs getaggter Revisionen aus.
+func printTagURLs(svc *models.Service) {
+	for _, t := range svc.Traffic {
+		if t.Tag == "" {
+			continue
+		}
+		url := svc.TagURLs[t.Tag]
+		if url == "" {
+			url = "pending"
+		}
+		fmt.Printf("  Tag %s:   %s (%s)\n", t.Tag, url, t.RevisionName)
+	}
+}
+
+func init() {
+	trafficCmd.AddCommand(trafficSetCmd)
+	rootCmd.AddCommand(trafficCmd)
+}
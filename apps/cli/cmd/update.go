@@ -0,0 +1,178 @@
+package cmd
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/max-cloud/shared/pkg/models"
+	"github.com/spf13/cobra"
+)
+
+var (
+	updateImage    string
+	updateEnv      []string
+	updateUnsetEnv []string
+	updatePort     int
+	updateCommand  string
+	updateArgs     string
+	updateMinScale int
+	updateMaxScale int
+	updateTag      string
+	updateReset    bool
+	updateCPU      string
+	updateMemory   string
+
+	updateConcurrency          int
+	updateTarget               int
+	updateScaleDownDelay       time.Duration
+	updateScaleToZeroRetention time.Duration
+
+	updateTimeout              time.Duration
+	updateResponseStartTimeout time.Duration
+	updateIdleTimeout          time.Duration
+
+	updateHealthPath string
+	updateSecrets    []string
+	updateEnvFiles   []string
+)
+
+var updateCmd = &cobra.Command{
+	Use:   "update [service-name]",
+	Short: "Update a deployed service in place",
+	Long: `Update image, environment, port, command, scaling, resources or timeouts of a running service.
+
+Only the given flags are changed. The service keeps its URL and is
+redeployed with the new configuration.
+
+With --tag the new revision receives no traffic and is only reachable via
+its tag URL (e.g. https://candidate-myapp...). Promote it afterwards with
+'maxcloud traffic set'.
+
+If the service has a traffic split, an update without --tag is rejected
+unless --reset-traffic routes all traffic to the new revision.`,
+	Args: cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		serviceID, err := resolveServiceID(args[0])
+		if err != nil {
+			return err
+		}
+
+		var req models.UpdateServiceRequest
+		flags := cmd.Flags()
+		if flags.Changed("image") {
+			req.Image = &updateImage
+		}
+		if flags.Changed("port") {
+			req.Port = &updatePort
+		}
+		if flags.Changed("command") {
+			command := parseCSV(updateCommand)
+			req.Command = &command
+		}
+		if flags.Changed("args") {
+			cmdArgs := parseCSV(updateArgs)
+			req.Args = &cmdArgs
+		}
+		if flags.Changed("min-scale") {
+			req.MinScale = &updateMinScale
+		}
+		if flags.Changed("max-scale") {
+			req.MaxScale = &updateMaxScale
+		}
+		if flags.Changed("cpu") {
+			req.CPU = &updateCPU
+		}
+		if flags.Changed("memory") {
+			req.Memory = &updateMemory
+		}
+		if flags.Changed("concurrency") {
+			req.ContainerConcurrency = &updateConcurrency
+		}
+		if flags.Changed("target") {
+			req.AutoscalingTarget = &updateTarget
+		}
+		if flags.Changed("scale-down-delay") {
+			seconds := int(updateScaleDownDelay.Seconds())
+			req.ScaleDownDelay = &seconds
+		}
+		if flags.Changed("scale-to-zero-retention") {
+			seconds := int(updateScaleToZeroRetention.Seconds())
+			req.ScaleToZeroRetention = &seconds
+		}
+		if flags.Changed("timeout") {
+			seconds := int(updateTimeout.Seconds())
+			req.TimeoutSeconds = &seconds
+		}
+		if flags.Changed("response-start-timeout") {
+			seconds := int(updateResponseStartTimeout.Seconds())
+			req.ResponseStartTimeoutSeconds = &seconds
+		}
+		if flags.Changed("idle-timeout") {
+			seconds := int(updateIdleTimeout.Seconds())
+			req.IdleTimeoutSeconds = &seconds
+		}
+		if flags.Changed("health-path") {
+			req.Probes = healthProbes(updateHealthPath)
+		}
+		if len(updateEnv) > 0 || len(updateEnvFiles) > 0 {
+			envVars, err := loadEnv(updateEnvFiles, updateEnv)
+			if err != nil {
+				return err
+			}
+			req.EnvVars = envVars
+		}
+		if len(updateSecrets) > 0 {
+			secretEnv, err := parseSecretPairs(updateSecrets)
+			if err != nil {
+				return err
+			}
+			req.SecretEnv = secretEnv
+		}
+		req.RemoveEnv = updateUnsetEnv
+		req.Tag = updateTag
+		req.ResetTraffic = updateReset
+
+		svc, err := client.UpdateService(serviceID, req)
+		if err != nil {
+			return formatError(err)
+		}
+
+		fmt.Printf("Service updated successfully!\n")
+		fmt.Printf("  ID:         %s\n", svc.ID)
+		fmt.Printf("  Name:       %s\n", svc.Name)
+		fmt.Printf("  Image:      %s\n", svc.Image)
+		fmt.Printf("  Status:     %s\n", svc.Status)
+		fmt.Printf("  Generation: %d\n", svc.Generation)
+		fmt.Printf("  URL:        %s\n", svc.URL)
+		printTagURLs(svc)
+		return nil
+	},
+}
+
+func init() {
+	updateCmd.Flags().StringVar(&updateImage, "image", "", "New container image")
+	updateCmd.Flags().StringArrayVar(&updateEnv, "env", nil, "Set environment variables (KEY=VALUE, repeatable)")
+	updateCmd.Flags().StringArrayVar(&updateEnvFiles, "env-file", nil, "Set environment variables from a .env file (repeatable)")
+	updateCmd.Flags().StringArrayVar(&updateSecrets, "secret", nil, "Set environment variables from secrets (ENV=SECRET, repeatable)")
+	updateCmd.Flags().StringArrayVar(&updateUnsetEnv, "unset-env", nil, "Remove environment variables or secret references (KEY, repeatable)")
+	updateCmd.Flags().IntVar(&updatePort, "port", 0, "Container port")
+	updateCmd.Flags().StringVar(&updateCommand, "command", "", "Override ENTRYPOINT (comma-separated: python,app.py)")
+	updateCmd.Flags().StringVar(&updateArgs, "args", "", "Override CMD (comma-separated: --port,3000)")
+	updateCmd.Flags().IntVar(&updateMinScale, "min-scale", 0, "Minimum number of instances")
+	updateCmd.Flags().IntVar(&updateMaxScale, "max-scale", 0, "Maximum number of instances")
+	updateCmd.Flags().StringVar(&updateCPU, "cpu", "", "CPU tier ("+strings.Join(models.CPUTiers, ", ")+")")
+	updateCmd.Flags().StringVar(&updateMemory, "memory", "", "Memory tier ("+strings.Join(models.MemoryTiers, ", ")+")")
+	updateCmd.Flags().IntVar(&updateConcurrency, "concurrency", 0, "Maximum concurrent requests per instance (0 = unlimited)")
+	updateCmd.Flags().IntVar(&updateTarget, "target", 0, "Concurrent requests per instance the autoscaler aims for")
+	updateCmd.Flags().DurationVar(&updateScaleDownDelay, "scale-down-delay", 0, "Wait before scaling down (e.g. 2m)")
+	updateCmd.Flags().DurationVar(&updateScaleToZeroRetention, "scale-to-zero-retention", 0, "Keep the last instance this long before scaling to zero (e.g. 5m)")
+	updateCmd.Flags().DurationVar(&updateTimeout, "timeout", 0, "Maximum request duration (e.g. 15m; 0 = platform default)")
+	updateCmd.Flags().DurationVar(&updateResponseStartTimeout, "response-start-timeout", 0, "Maximum time until the first response byte (e.g. 5m)")
+	updateCmd.Flags().DurationVar(&updateIdleTimeout, "idle-timeout", 0, "Maximum time between response bytes (e.g. 2m)")
+	updateCmd.Flags().StringVar(&updateHealthPath, "health-path", "", "HTTP path for readiness and startup checks (empty removes all probes)")
+	updateCmd.Flags().StringVar(&updateTag, "tag", "", "Deploy with 0% traffic behind a tag URL (e.g. candidate)")
+	updateCmd.Flags().BoolVar(&updateReset, "reset-traffic", false, "Discard the traffic split and route all traffic to the new revision")
+
+	rootCmd.AddCommand(updateCmd)
+}
@@ -8,6 +8,8 @@ import (
 	"fmt"
 	"io"
 	"net/http"
+	"net/url"
+	"strconv"
 	"strings"
 	"time"
 
@@ -108,6 +110,165 @@ func (c *Client) GetService(id string) (*models.Service, error) {
 	return &svc, nil
 }
 
+// UpdateService partially updates a service and triggers a redeploy.
+func (c *Client) UpdateService(id string, req models.UpdateServiceRequest) (*models.Service, error) {
+	body, err := json.Marshal(req)
+	if err != nil {
+		return nil, fmt.Errorf("marshal request: %w", err)
+	}
+
+	resp, err := c.doRequest(http.MethodPatch, c.BaseURL+"/api/v1/services/"+id, bytes.NewReader(body))
+	if err != nil {
+		return nil, fmt.Errorf("request failed: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, parseAPIError(resp)
+	}
+
+	var svc models.Service
+	if err := json.NewDecoder(resp.Body).Decode(&svc); err != nil {
+		return nil, fmt.Errorf("decode response: %w", err)
+	}
+	return &svc, nil
+}
+
+// GetServiceEnv returns the environment variables and secret references of a service.
+func (c *Client) GetServiceEnv(id string) (*models.ServiceEnv, error) {
+	resp, err := c.doRequest(http.MethodGet, c.BaseURL+"/api/v1/services/"+id+"/env", nil)
+	if err != nil {
+		return nil, fmt.Errorf("request failed: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, parseAPIError(resp)
+	}
+
+	var env models.ServiceEnv
+	if err := json.NewDecoder(resp.Body).Decode(&env); err != nil {
+		return nil, fmt.Errorf("decode response: %w", err)
+	}
+	return &env, nil
+}
+
+// UpdateServiceEnv sets and removes environment variables of a service and triggers a redeploy.
+func (c *Client) UpdateServiceEnv(id string, req models.UpdateEnvRequest) (*models.Service, error) {
+	body, err := json.Marshal(req)
+	if err != nil {
+		return nil, fmt.Errorf("marshal request: %w", err)
+	}
+
+	resp, err := c.doRequest(http.MethodPatch, c.BaseURL+"/api/v1/services/"+id+"/env", bytes.NewReader(body))
+	if err != nil {
+		return nil, fmt.Errorf("request failed: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, parseAPIError(resp)
+	}
+
+	var svc models.Service
+	if err := json.NewDecoder(resp.Body).Decode(&svc); err != nil {
+		return nil, fmt.Errorf("decode response: %w", err)
+	}
+	return &svc, nil
+}
+
+// ListRevisions returns the revision history of a service, newest first.
+func (c *Client) ListRevisions(serviceID string) ([]models.Revision, error) {
+	resp, err := c.doRequest(http.MethodGet, c.BaseURL+"/api/v1/services/"+serviceID+"/revisions", nil)
+	if err != nil {
+		return nil, fmt.Errorf("request failed: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, parseAPIError(resp)
+	}
+
+	var revisions []models.Revision
+	if err := json.NewDecoder(resp.Body).Decode(&revisions); err != nil {
+		return nil, fmt.Errorf("decode response: %w", err)
+	}
+	return revisions, nil
+}
+
+// ListServiceEvents returns the event history of a service, newest first.
+// A limit of 0 uses the server default.
+func (c *Client) ListServiceEvents(serviceID string, limit int) ([]models.ServiceEvent, error) {
+	endpoint := c.BaseURL + "/api/v1/services/" + serviceID + "/events"
+	if limit > 0 {
+		endpoint += "?limit=" + strconv.Itoa(limit)
+	}
+
+	resp, err := c.doRequest(http.MethodGet, endpoint, nil)
+	if err != nil {
+		return nil, fmt.Errorf("request failed: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, parseAPIError(resp)
+	}
+
+	var events []models.ServiceEvent
+	if err := json.NewDecoder(resp.Body).Decode(&events); err != nil {
+		return nil, fmt.Errorf("decode response: %w", err)
+	}
+	return events, nil
+}
+
+// RollbackService rolls a service back to a previous revision.
+func (c *Client) RollbackService(id string, req models.RollbackRequest) (*models.Service, error) {
+	body, err := json.Marshal(req)
+	if err != nil {
+		return nil, fmt.Errorf("marshal request: %w", err)
+	}
+
+	resp, err := c.doRequest(http.MethodPost, c.BaseURL+"/api/v1/services/"+id+"/rollback", bytes.NewReader(body))
+	if err != nil {
+		return nil, fmt.Errorf("request failed: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, parseAPIError(resp)
+	}
+
+	var svc models.Service
+	if err := json.NewDecoder(resp.Body).Decode(&svc); err != nil {
+		return nil, fmt.Errorf("decode response: %w", err)
+	}
+	return &svc, nil
+}
+
+// SetTraffic changes how traffic is split between the revisions of a service.
+func (c *Client) SetTraffic(id string, req models.SetTrafficRequest) (*models.Service, error) {
+	body, err := json.Marshal(req)
+	if err != nil {
+		return nil, fmt.Errorf("marshal request: %w", err)
+	}
+
+	resp, err := c.doRequest(http.MethodPut, c.BaseURL+"/api/v1/services/"+id+"/traffic", bytes.NewReader(body))
+	if err != nil {
+		return nil, fmt.Errorf("request failed: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, parseAPIError(resp)
+	}
+
+	var svc models.Service
+	if err := json.NewDecoder(resp.Body).Decode(&svc); err != nil {
+		return nil, fmt.Errorf("decode response: %w", err)
+	}
+	return &svc, nil
+}
+
 // DeleteService deletes a service by ID.
 func (c *Client) DeleteService(id string) error {
 	resp, err := c.doRequest(http.MethodDelete, c.BaseURL+"/api/v1/services/"+id, nil)
@@ -203,6 +364,178 @@ func (c *Client) DeleteAPIKey(id string) error {
 	return nil
 }
 
+// RotateAPIKey stellt einen Nachfolger für einen API-Key aus. Der alte Key bleibt für die
+// Übergangszeit gültig.
+func (c *Client) RotateAPIKey(id string, req models.RotateAPIKeyRequest) (*models.CreateAPIKeyResponse, error) {
+	body, err := json.Marshal(req)
+	if err != nil {
+		return nil, fmt.Errorf("marshal request: %w", err)
+	}
+
+	resp, err := c.doRequest(http.MethodPost, c.BaseURL+"/api/v1/auth/api-keys/"+id+"/rotate", bytes.NewReader(body))
+	if err != nil {
+		return nil, fmt.Errorf("request failed: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusCreated {
+		return nil, parseAPIError(resp)
+	}
+
+	var result models.CreateAPIKeyResponse
+	if err := json.NewDecoder(resp.Body).Decode(&result); err != nil {
+		return nil, fmt.Errorf("decode response: %w", err)
+	}
+	return &result, nil
+}
+
+// SetSecret legt ein Secret an oder überschreibt seinen Wert.
+func (c *Client) SetSecret(name, value string) (*models.Secret, error) {
+	body, err := json.Marshal(models.SetSecretRequest{Value: value})
+	if err != nil {
+		return nil, fmt.Errorf("marshal request: %w", err)
+	}
+
+	resp, err := c.doRequest(http.MethodPut, c.BaseURL+"/api/v1/secrets/"+url.PathEscape(name), bytes.NewReader(body))
+	if err != nil {
+		return nil, fmt.Errorf("request failed: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, parseAPIError(resp)
+	}
+
+	var secret models.Secret
+	if err := json.NewDecoder(resp.Body).Decode(&secret); err != nil {
+		return nil, fmt.Errorf("decode response: %w", err)
+	}
+	return &secret, nil
+}
+
+// ListSecrets gibt die Secrets der Organisation ohne Werte zurück.
+func (c *Client) ListSecrets() ([]models.Secret, error) {
+	resp, err := c.doRequest(http.MethodGet, c.BaseURL+"/api/v1/secrets", nil)
+	if err != nil {
+		return nil, fmt.Errorf("request failed: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, parseAPIError(resp)
+	}
+
+	var secrets []models.Secret
+	if err := json.NewDecoder(resp.Body).Decode(&secrets); err != nil {
+		return nil, fmt.Errorf("decode response: %w", err)
+	}
+	return secrets, nil
+}
+
+// DeleteSecret löscht ein Secret.
+func (c *Client) DeleteSecret(name string) error {
+	resp, err := c.doRequest(http.MethodDelete, c.BaseURL+"/api/v1/secrets/"+url.PathEscape(name), nil)
+	if err != nil {
+		return fmt.Errorf("request failed: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusNoContent {
+		return parseAPIError(resp)
+	}
+	return nil
+}
+
+// CreateDomain adds a custom domain for a service. It stays unverified until VerifyDomain succeeds.
+func (c *Client) CreateDomain(req models.CreateDomainRequest) (*models.Domain, error) {
+	body, err := json.Marshal(req)
+	if err != nil {
+		return nil, fmt.Errorf("marshal request: %w", err)
+	}
+
+	resp, err := c.doRequest(http.MethodPost, c.BaseURL+"/api/v1/domains", bytes.NewReader(body))
+	if err != nil {
+		return nil, fmt.Errorf("request failed: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusCreated {
+		return nil, parseAPIError(resp)
+	}
+
+	var domain models.Domain
+	if err := json.NewDecoder(resp.Body).Decode(&domain); err != nil {
+		return nil, fmt.Errorf("decode response: %w", err)
+	}
+	return &domain, nil
+}
+
+// ListDomains returns all custom domains of the organization.
+func (c *Client) ListDomains() ([]models.Domain, error) {
+	resp, err := c.doRequest(http.MethodGet, c.BaseURL+"/api/v1/domains", nil)
+	if err != nil {
+		return nil, fmt.Errorf("request failed: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, parseAPIError(resp)
+	}
+
+	var domains []models.Domain
+	if err := json.NewDecoder(resp.Body).Decode(&domains); err != nil {
+		return nil, fmt.Errorf("decode response: %w", err)
+	}
+	return domains, nil
+}
+
+// VerifyDomain checks the TXT challenge record of a domain.
+func (c *Client) VerifyDomain(id string) (*models.Domain, error) {
+	resp, err := c.doRequest(http.MethodPost, c.BaseURL+"/api/v1/domains/"+id+"/verify", nil)
+	if err != nil {
+		return nil, fmt.Errorf("request failed: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, parseAPIError(resp)
+	}
+
+	var domain models.Domain
+	if err := json.NewDecoder(resp.Body).Decode(&domain); err != nil {
+		return nil, fmt.Errorf("decode response: %w", err)
+	}
+	return &domain, nil
+}
+
+// DeleteDomain removes a custom domain.
+func (c *Client) DeleteDomain(id string) error {
+	resp, err := c.doRequest(http.MethodDelete, c.BaseURL+"/api/v1/domains/"+id, nil)
+	if err != nil {
+		return fmt.Errorf("request failed: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusNoContent {
+		return parseAPIError(resp)
+	}
+	return nil
+}
+
+// DeleteOrganization löscht die eigene Organisation mit allen Services und Daten (nur für Admins).
+func (c *Client) DeleteOrganization() error {
+	resp, err := c.doRequest(http.MethodDelete, c.BaseURL+"/api/v1/auth/org", nil)
+	if err != nil {
+		return fmt.Errorf("request failed: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusNoContent {
+		return parseAPIError(resp)
+	}
+	return nil
+}
+
 // AuthStatus gibt Informationen über den aktuellen Benutzer zurück.
 func (c *Client) AuthStatus() (*models.AuthInfo, error) {
 	resp, err := c.doRequest(http.MethodGet, c.BaseURL+"/api/v1/auth/status", nil)
@@ -389,6 +722,168 @@ func (c *Client) AcceptInvite(req models.AcceptInviteRequest) (*models.AcceptInv
 	return &result, nil
 }
 
+// ListMembers gibt alle Mitglieder der aktuellen Organisation zurück.
+func (c *Client) ListMembers() ([]models.OrgMember, error) {
+	resp, err := c.doRequest(http.MethodGet, c.BaseURL+"/api/v1/org/members", nil)
+	if err != nil {
+		return nil, fmt.Errorf("request failed: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, parseAPIError(resp)
+	}
+
+	var members []models.OrgMember
+	if err := json.NewDecoder(resp.Body).Decode(&members); err != nil {
+		return nil, fmt.Errorf("decode response: %w", err)
+	}
+	return members, nil
+}
+
+// UpdateMemberRole ändert die Rolle eines Mitglieds.
+func (c *Client) UpdateMemberRole(userID string, role models.OrgRole) (*models.OrgMember, error) {
+	body, err := json.Marshal(models.UpdateMemberRoleRequest{Role: role})
+	if err != nil {
+		return nil, fmt.Errorf("marshal request: %w", err)
+	}
+
+	resp, err := c.doRequest(http.MethodPatch, c.BaseURL+"/api/v1/org/members/"+userID, bytes.NewReader(body))
+	if err != nil {
+		return nil, fmt.Errorf("request failed: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, parseAPIError(resp)
+	}
+
+	var member models.OrgMember
+	if err := json.NewDecoder(resp.Body).Decode(&member); err != nil {
+		return nil, fmt.Errorf("decode response: %w", err)
+	}
+	return &member, nil
+}
+
+// RemoveMember entfernt ein Mitglied aus der Organisation.
+func (c *Client) RemoveMember(userID string) error {
+	resp, err := c.doRequest(http.MethodDelete, c.BaseURL+"/api/v1/org/members/"+userID, nil)
+	if err != nil {
+		return fmt.Errorf("request failed: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusNoContent {
+		return parseAPIError(resp)
+	}
+	return nil
+}
+
+// ListServiceAccounts gibt alle Service-Accounts der Organisation zurück.
+func (c *Client) ListServiceAccounts() ([]models.ServiceAccount, error) {
+	resp, err := c.doRequest(http.MethodGet, c.BaseURL+"/api/v1/org/service-accounts", nil)
+	if err != nil {
+		return nil, fmt.Errorf("request failed: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, parseAPIError(resp)
+	}
+
+	var accounts []models.ServiceAccount
+	if err := json.NewDecoder(resp.Body).Decode(&accounts); err != nil {
+		return nil, fmt.Errorf("decode response: %w", err)
+	}
+	return accounts, nil
+}
+
+// CreateServiceAccount legt einen Service-Account an.
+func (c *Client) CreateServiceAccount(req models.CreateServiceAccountRequest) (*models.ServiceAccount, error) {
+	body, err := json.Marshal(req)
+	if err != nil {
+		return nil, fmt.Errorf("marshal request: %w", err)
+	}
+
+	resp, err := c.doRequest(http.MethodPost, c.BaseURL+"/api/v1/org/service-accounts", bytes.NewReader(body))
+	if err != nil {
+		return nil, fmt.Errorf("request failed: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusCreated {
+		return nil, parseAPIError(resp)
+	}
+
+	var sa models.ServiceAccount
+	if err := json.NewDecoder(resp.Body).Decode(&sa); err != nil {
+		return nil, fmt.Errorf("decode response: %w", err)
+	}
+	return &sa, nil
+}
+
+// UpdateServiceAccountRole ändert die Rolle eines Service-Accounts.
+func (c *Client) UpdateServiceAccountRole(id string, role models.OrgRole) (*models.ServiceAccount, error) {
+	body, err := json.Marshal(models.UpdateServiceAccountRequest{Role: role})
+	if err != nil {
+		return nil, fmt.Errorf("marshal request: %w", err)
+	}
+
+	resp, err := c.doRequest(http.MethodPatch, c.BaseURL+"/api/v1/org/service-accounts/"+id, bytes.NewReader(body))
+	if err != nil {
+		return nil, fmt.Errorf("request failed: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, parseAPIError(resp)
+	}
+
+	var sa models.ServiceAccount
+	if err := json.NewDecoder(resp.Body).Decode(&sa); err != nil {
+		return nil, fmt.Errorf("decode response: %w", err)
+	}
+	return &sa, nil
+}
+
+// DeleteServiceAccount löscht einen Service-Account samt seiner API-Keys.
+func (c *Client) DeleteServiceAccount(id string) error {
+	resp, err := c.doRequest(http.MethodDelete, c.BaseURL+"/api/v1/org/service-accounts/"+id, nil)
+	if err != nil {
+		return fmt.Errorf("request failed: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusNoContent {
+		return parseAPIError(resp)
+	}
+	return nil
+}
+
+// CreateServiceAccountAPIKey erstellt einen API-Key für einen Service-Account.
+func (c *Client) CreateServiceAccountAPIKey(id string, req models.CreateAPIKeyRequest) (*models.CreateAPIKeyResponse, error) {
+	body, err := json.Marshal(req)
+	if err != nil {
+		return nil, fmt.Errorf("marshal request: %w", err)
+	}
+
+	resp, err := c.doRequest(http.MethodPost, c.BaseURL+"/api/v1/org/service-accounts/"+id+"/api-keys", bytes.NewReader(body))
+	if err != nil {
+		return nil, fmt.Errorf("request failed: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusCreated {
+		return nil, parseAPIError(resp)
+	}
+
+	var result models.CreateAPIKeyResponse
+	if err := json.NewDecoder(resp.Body).Decode(&result); err != nil {
+		return nil, fmt.Errorf("decode response: %w", err)
+	}
+	return &result, nil
+}
+
 func parseAPIError(resp *http.Response) error {
 	var errBody struct {
 		Error string `json:"error"`
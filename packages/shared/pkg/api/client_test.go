@@ -54,6 +54,133 @@ func mockAPI() *httptest.Server {
 		json.NewEncoder(w).Encode(svc)
 	})
 
+	mux.HandleFunc("PATCH /api/v1/services/{id}", func(w http.ResponseWriter, r *http.Request) {
+		id := r.PathValue("id")
+		svc, ok := services[id]
+		if !ok {
+			http.Error(w, `{"error":"not found"}`, http.StatusNotFound)
+			return
+		}
+		var req models.UpdateServiceRequest
+		json.NewDecoder(r.Body).Decode(&req)
+		if req.Image != nil {
+			svc.Image = *req.Image
+		}
+		svc.Status = "pending"
+		svc.Generation++
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(svc)
+	})
+
+	mux.HandleFunc("GET /api/v1/services/{id}/revisions", func(w http.ResponseWriter, r *http.Request) {
+		id := r.PathValue("id")
+		svc, ok := services[id]
+		if !ok {
+			http.Error(w, `{"error":"not found"}`, http.StatusNotFound)
+			return
+		}
+		revisions := []models.Revision{
+			{ID: "rev-2", ServiceID: id, Name: svc.Name + "-00002", Generation: 2, Image: svc.Image},
+			{ID: "rev-1", ServiceID: id, Name: svc.Name + "-00001", Generation: 1, Image: "nginx:1.25"},
+		}
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(revisions)
+	})
+
+	mux.HandleFunc("GET /api/v1/services/{id}/events", func(w http.ResponseWriter, r *http.Request) {
+		id := r.PathValue("id")
+		if _, ok := services[id]; !ok {
+			http.Error(w, `{"error":"not found"}`, http.StatusNotFound)
+			return
+		}
+		events := []models.ServiceEvent{
+			{ServiceID: id, Type: models.ServiceEventDeployed, Message: "generation 1 deployed"},
+			{ID: "evt-1", ServiceID: id, Type: models.ServiceEventCreated, Message: "service created"},
+		}
+		if r.URL.Query().Get("limit") == "1" {
+			events = events[:1]
+		}
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(events)
+	})
+
+	mux.HandleFunc("POST /api/v1/services/{id}/rollback", func(w http.ResponseWriter, r *http.Request) {
+		id := r.PathValue("id")
+		svc, ok := services[id]
+		if !ok {
+			http.Error(w, `{"error":"not found"}`, http.StatusNotFound)
+			return
+		}
+		var req models.RollbackRequest
+		json.NewDecoder(r.Body).Decode(&req)
+		if req.Revision != "" && req.Revision != "app1-00001" {
+			http.Error(w, `{"error":"revision not found"}`, http.StatusNotFound)
+			return
+		}
+		svc.Image = "nginx:1.25"
+		svc.Status = "pending"
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(svc)
+	})
+
+	mux.HandleFunc("PUT /api/v1/services/{id}/traffic", func(w http.ResponseWriter, r *http.Request) {
+		id := r.PathValue("id")
+		svc, ok := services[id]
+		if !ok {
+			http.Error(w, `{"error":"not found"}`, http.StatusNotFound)
+			return
+		}
+		var req models.SetTrafficRequest
+		json.NewDecoder(r.Body).Decode(&req)
+		sum := 0
+		for _, t := range req.Targets {
+			sum += t.Percent
+		}
+		if len(req.Targets) > 0 && sum != 100 {
+			http.Error(w, `{"error":"traffic percentages must sum to 100"}`, http.StatusBadRequest)
+			return
+		}
+		svc.Traffic = req.Targets
+		svc.Status = "pending"
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(svc)
+	})
+
+	mux.HandleFunc("GET /api/v1/services/{id}/env", func(w http.ResponseWriter, r *http.Request) {
+		svc, ok := services[r.PathValue("id")]
+		if !ok {
+			http.Error(w, `{"error":"not found"}`, http.StatusNotFound)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(models.ServiceEnv{EnvVars: svc.EnvVars, SecretEnv: map[string]string{}})
+	})
+
+	mux.HandleFunc("PATCH /api/v1/services/{id}/env", func(w http.ResponseWriter, r *http.Request) {
+		id := r.PathValue("id")
+		svc, ok := services[id]
+		if !ok {
+			http.Error(w, `{"error":"not found"}`, http.StatusNotFound)
+			return
+		}
+		var req models.UpdateEnvRequest
+		json.NewDecoder(r.Body).Decode(&req)
+		if svc.EnvVars == nil {
+			svc.EnvVars = map[string]string{}
+		}
+		for k, v := range req.Set {
+			svc.EnvVars[k] = v
+		}
+		for _, k := range req.Unset {
+			delete(svc.EnvVars, k)
+		}
+		services[id] = svc
+		svc.Status = "pending"
+		svc.Generation++
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(svc)
+	})
+
 	mux.HandleFunc("DELETE /api/v1/services/{id}", func(w http.ResponseWriter, r *http.Request) {
 		id := r.PathValue("id")
 		if _, ok := services[id]; !ok {
@@ -133,6 +260,22 @@ func mockAPI() *httptest.Server {
 		w.WriteHeader(http.StatusNoContent)
 	})
 
+	mux.HandleFunc("POST /api/v1/auth/api-keys/{id}/rotate", func(w http.ResponseWriter, r *http.Request) {
+		var req models.RotateAPIKeyRequest
+		json.NewDecoder(r.Body).Decode(&req)
+		if req.GracePeriodSeconds < 0 {
+			http.Error(w, `{"error":"invalid grace period"}`, http.StatusBadRequest)
+			return
+		}
+		resp := models.CreateAPIKeyResponse{
+			APIKey: "mc_rotatedkey1234567890abcdef1234567890abcdef1234567890abcdef1234",
+			Info:   models.APIKeyInfo{ID: "key-2", Prefix: "rotatedk", Name: "ci", CreatedAt: time.Now()},
+		}
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusCreated)
+		json.NewEncoder(w).Encode(resp)
+	})
+
 	mux.HandleFunc("GET /api/v1/auth/status", func(w http.ResponseWriter, r *http.Request) {
 		info := models.AuthInfo{
 			User:         models.User{ID: "user-1", Email: "test@example.com", CreatedAt: time.Now()},
@@ -165,7 +308,7 @@ func mockAPI() *httptest.Server {
 
 	mux.HandleFunc("GET /api/v1/auth/invites", func(w http.ResponseWriter, r *http.Request) {
 		invites := []models.Invitation{
-			{ID: "inv-1", OrgID: "org-1", OrgName: "TestOrg", Email: "a@example.com", Role: models.OrgRoleMember, Status: models.InviteStatusPending},
+			{ID: "inv-1", OrgID: "org-1", OrgName: "TestOrg", Email: "a@example.com", Role: models.OrgRoleDeveloper, Status: models.InviteStatusPending},
 		}
 		w.Header().Set("Content-Type", "application/json")
 		json.NewEncoder(w).Encode(invites)
@@ -175,11 +318,82 @@ func mockAPI() *httptest.Server {
 		w.WriteHeader(http.StatusNoContent)
 	})
 
+	mux.HandleFunc("DELETE /api/v1/auth/org", func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusNoContent)
+	})
+
+	mux.HandleFunc("GET /api/v1/org/members", func(w http.ResponseWriter, r *http.Request) {
+		members := []models.OrgMember{
+			{UserID: "user-1", Email: "admin@example.com", Role: models.OrgRoleAdmin},
+			{UserID: "user-2", Email: "bob@example.com", Role: models.OrgRoleDeveloper},
+		}
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(members)
+	})
+
+	mux.HandleFunc("PATCH /api/v1/org/members/{id}", func(w http.ResponseWriter, r *http.Request) {
+		var req models.UpdateMemberRoleRequest
+		json.NewDecoder(r.Body).Decode(&req)
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(models.OrgMember{UserID: r.PathValue("id"), Email: "bob@example.com", Role: req.Role})
+	})
+
+	mux.HandleFunc("DELETE /api/v1/org/members/{id}", func(w http.ResponseWriter, r *http.Request) {
+		if r.PathValue("id") == "user-1" {
+			http.Error(w, `{"error":"organization must keep at least one admin"}`, http.StatusConflict)
+			return
+		}
+		w.WriteHeader(http.StatusNoContent)
+	})
+
+	mux.HandleFunc("GET /api/v1/org/service-accounts", func(w http.ResponseWriter, r *http.Request) {
+		accounts := []models.ServiceAccount{
+			{ID: "sa-1", OrgID: "org-1", Name: "ci", Role: models.OrgRoleDeveloper, CreatedAt: time.Now()},
+		}
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(accounts)
+	})
+
+	mux.HandleFunc("POST /api/v1/org/service-accounts", func(w http.ResponseWriter, r *http.Request) {
+		var req models.CreateServiceAccountRequest
+		json.NewDecoder(r.Body).Decode(&req)
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusCreated)
+		json.NewEncoder(w).Encode(models.ServiceAccount{ID: "sa-2", OrgID: "org-1", Name: req.Name, Role: req.Role, CreatedAt: time.Now()})
+	})
+
+	mux.HandleFunc("PATCH /api/v1/org/service-accounts/{id}", func(w http.ResponseWriter, r *http.Request) {
+		var req models.UpdateServiceAccountRequest
+		json.NewDecoder(r.Body).Decode(&req)
+		if req.Role == models.OrgRoleOwner {
+			http.Error(w, `{"error":"role must be admin, developer or viewer"}`, http.StatusBadRequest)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(models.ServiceAccount{ID: r.PathValue("id"), OrgID: "org-1", Name: "ci", Role: req.Role})
+	})
+
+	mux.HandleFunc("DELETE /api/v1/org/service-accounts/{id}", func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusNoContent)
+	})
+
+	mux.HandleFunc("POST /api/v1/org/service-accounts/{id}/api-keys", func(w http.ResponseWriter, r *http.Request) {
+		var req models.CreateAPIKeyRequest
+		json.NewDecoder(r.Body).Decode(&req)
+		resp := models.CreateAPIKeyResponse{
+			APIKey: "mc_serviceaccountkey1234567890abcdef1234567890abcdef1234567890abcd",
+			Info:   models.APIKeyInfo{ID: "key-3", Prefix: "servicea", Name: req.Name, OrgID: "org-1", ServiceAccountID: r.PathValue("id"), CreatedAt: time.Now()},
+		}
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusCreated)
+		json.NewEncoder(w).Encode(resp)
+	})
+
 	mux.HandleFunc("POST /api/v1/auth/accept-invite", func(w http.ResponseWriter, r *http.Request) {
 		resp := models.AcceptInviteResponse{
 			User:         models.User{ID: "user-2", Email: "new@example.com", CreatedAt: time.Now()},
 			Organization: models.Organization{ID: "org-1", Name: "TestOrg", CreatedAt: time.Now()},
-			Role:         models.OrgRoleMember,
+			Role:         models.OrgRoleDeveloper,
 			APIKey:       "mc_newuserkey1234567890abcdef1234567890abcdef1234567890abcdef123456",
 		}
 		w.Header().Set("Content-Type", "application/json")
@@ -187,6 +401,85 @@ func mockAPI() *httptest.Server {
 		json.NewEncoder(w).Encode(resp)
 	})
 
+	domains := map[string]models.Domain{}
+
+	mux.HandleFunc("POST /api/v1/domains", func(w http.ResponseWriter, r *http.Request) {
+		var req models.CreateDomainRequest
+		json.NewDecoder(r.Body).Decode(&req)
+		if _, ok := services[req.ServiceID]; !ok {
+			http.Error(w, `{"error":"unknown service"}`, http.StatusBadRequest)
+			return
+		}
+		d := models.Domain{
+			ID:                "dom-1",
+			ServiceID:         req.ServiceID,
+			Hostname:          req.Hostname,
+			VerificationToken: "maxcloud-verify=abc",
+			Status:            models.DomainStatusPendingVerification,
+		}
+		domains[d.ID] = d
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusCreated)
+		json.NewEncoder(w).Encode(d)
+	})
+
+	mux.HandleFunc("GET /api/v1/domains", func(w http.ResponseWriter, r *http.Request) {
+		list := []models.Domain{}
+		for _, d := range domains {
+			list = append(list, d)
+		}
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(list)
+	})
+
+	mux.HandleFunc("POST /api/v1/domains/{id}/verify", func(w http.ResponseWriter, r *http.Request) {
+		d, ok := domains[r.PathValue("id")]
+		if !ok {
+			http.Error(w, `{"error":"domain not found"}`, http.StatusNotFound)
+			return
+		}
+		now := time.Now()
+		d.VerifiedAt = &now
+		d.Status = models.DomainStatusProvisioning
+		domains[d.ID] = d
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(d)
+	})
+
+	mux.HandleFunc("DELETE /api/v1/domains/{id}", func(w http.ResponseWriter, r *http.Request) {
+		if _, ok := domains[r.PathValue("id")]; !ok {
+			http.Error(w, `{"error":"domain not found"}`, http.StatusNotFound)
+			return
+		}
+		delete(domains, r.PathValue("id"))
+		w.WriteHeader(http.StatusNoContent)
+	})
+
+	mux.HandleFunc("PUT /api/v1/secrets/{name}", func(w http.ResponseWriter, r *http.Request) {
+		var req models.SetSecretRequest
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil || req.Value == "" {
+			w.WriteHeader(http.StatusBadRequest)
+			json.NewEncoder(w).Encode(map[string]string{"error": "value is required"})
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(models.Secret{ID: "sec-1", OrgID: "org-1", Name: r.PathValue("name"), CreatedAt: time.Now(), UpdatedAt: time.Now()})
+	})
+
+	mux.HandleFunc("GET /api/v1/secrets", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode([]models.Secret{{ID: "sec-1", OrgID: "org-1", Name: "db-password", CreatedAt: time.Now(), UpdatedAt: time.Now()}})
+	})
+
+	mux.HandleFunc("DELETE /api/v1/secrets/{name}", func(w http.ResponseWriter, r *http.Request) {
+		if r.PathValue("name") != "db-password" {
+			w.WriteHeader(http.StatusNotFound)
+			json.NewEncoder(w).Encode(map[string]string{"error": "secret not found"})
+			return
+		}
+		w.WriteHeader(http.StatusNoContent)
+	})
+
 	return httptest.NewServer(mux)
 }
 
@@ -257,6 +550,144 @@ func TestClientGetServiceNotFound(t *testing.T) {
 	}
 }
 
+func TestClientUpdateService(t *testing.T) {
+	srv := mockAPI()
+	defer srv.Close()
+
+	c := NewClient(srv.URL)
+	image := "nginx:1.27"
+	svc, err := c.UpdateService("svc-1", models.UpdateServiceRequest{Image: &image})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if svc.Image != "nginx:1.27" {
+		t.Fatalf("expected image nginx:1.27, got %s", svc.Image)
+	}
+	if svc.Status != models.ServiceStatusPending {
+		t.Fatalf("expected status pending, got %s", svc.Status)
+	}
+}
+
+func TestClientServiceEnv(t *testing.T) {
+	srv := mockAPI()
+	defer srv.Close()
+
+	c := NewClient(srv.URL)
+	svc, err := c.UpdateServiceEnv("svc-1", models.UpdateEnvRequest{Set: map[string]string{"LOG_LEVEL": "debug", "DEBUG": "1"}})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if svc.EnvVars["LOG_LEVEL"] != "debug" || svc.Status != models.ServiceStatusPending {
+		t.Fatalf("unexpected service after env update: %+v", svc)
+	}
+
+	if _, err := c.UpdateServiceEnv("svc-1", models.UpdateEnvRequest{Unset: []string{"DEBUG"}}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	env, err := c.GetServiceEnv("svc-1")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(env.EnvVars) != 1 || env.EnvVars["LOG_LEVEL"] != "debug" {
+		t.Fatalf("unexpected env: %+v", env.EnvVars)
+	}
+
+	_, err = c.GetServiceEnv("svc-42")
+	apiErr, ok := err.(*APIError)
+	if !ok || apiErr.StatusCode != 404 {
+		t.Fatalf("expected 404 APIError, got %v", err)
+	}
+}
+
+func TestClientListRevisions(t *testing.T) {
+	srv := mockAPI()
+	defer srv.Close()
+
+	c := NewClient(srv.URL)
+	revisions, err := c.ListRevisions("svc-1")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(revisions) != 2 {
+		t.Fatalf("expected 2 revisions, got %d", len(revisions))
+	}
+	if revisions[0].Name != "app1-00002" {
+		t.Fatalf("expected newest revision app1-00002, got %s", revisions[0].Name)
+	}
+}
+
+func TestClientListServiceEvents(t *testing.T) {
+	srv := mockAPI()
+	defer srv.Close()
+
+	c := NewClient(srv.URL)
+	events, err := c.ListServiceEvents("svc-1", 0)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(events) != 2 || events[1].Type != models.ServiceEventCreated {
+		t.Fatalf("unexpected events: %+v", events)
+	}
+
+	events, err = c.ListServiceEvents("svc-1", 1)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(events) != 1 {
+		t.Fatalf("expected limit to be sent, got %d events", len(events))
+	}
+
+	if _, err := c.ListServiceEvents("missing", 0); err == nil {
+		t.Fatal("expected error for unknown service")
+	}
+}
+
+func TestClientRollbackService(t *testing.T) {
+	srv := mockAPI()
+	defer srv.Close()
+
+	c := NewClient(srv.URL)
+	svc, err := c.RollbackService("svc-1", models.RollbackRequest{Revision: "app1-00001"})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if svc.Image != "nginx:1.25" {
+		t.Fatalf("expected image nginx:1.25, got %s", svc.Image)
+	}
+
+	_, err = c.RollbackService("svc-1", models.RollbackRequest{Revision: "app1-00042"})
+	apiErr, ok := err.(*APIError)
+	if !ok || apiErr.StatusCode != 404 {
+		t.Fatalf("expected 404 APIError, got %v", err)
+	}
+}
+
+func TestClientSetTraffic(t *testing.T) {
+	srv := mockAPI()
+	defer srv.Close()
+
+	c := NewClient(srv.URL)
+	svc, err := c.SetTraffic("svc-1", models.SetTrafficRequest{Targets: []models.TrafficTarget{
+		{RevisionName: "app1-00001", Percent: 90},
+		{RevisionName: "app1-00002", Percent: 10},
+	}})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(svc.Traffic) != 2 || svc.Traffic[1].Percent != 10 {
+		t.Fatalf("unexpected traffic: %+v", svc.Traffic)
+	}
+
+	_, err = c.SetTraffic("svc-1", models.SetTrafficRequest{Targets: []models.TrafficTarget{
+		{RevisionName: "app1-00001", Percent: 50},
+	}})
+	apiErr, ok := err.(*APIError)
+	if !ok || apiErr.StatusCode != 400 {
+		t.Fatalf("expected 400 APIError, got %v", err)
+	}
+}
+
 func TestClientDeleteService(t *testing.T) {
 	srv := mockAPI()
 	defer srv.Close()
@@ -369,6 +800,31 @@ func TestClientAuthHeader(t *testing.T) {
 	}
 }
 
+func TestClientRotateAPIKey(t *testing.T) {
+	srv := mockAPI()
+	defer srv.Close()
+
+	c := NewClient(srv.URL)
+	c.Token = "mc_testkey"
+
+	resp, err := c.RotateAPIKey("key-1", models.RotateAPIKeyRequest{GracePeriodSeconds: 3600})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if resp.APIKey == "" || resp.Info.ID != "key-2" {
+		t.Fatalf("unexpected response: %+v", resp)
+	}
+
+	_, err = c.RotateAPIKey("key-1", models.RotateAPIKeyRequest{GracePeriodSeconds: -1})
+	apiErr, ok := err.(*APIError)
+	if !ok {
+		t.Fatalf("expected *APIError, got %T", err)
+	}
+	if apiErr.StatusCode != 400 {
+		t.Fatalf("expected status 400, got %d", apiErr.StatusCode)
+	}
+}
+
 func TestClientAuthStatus(t *testing.T) {
 	srv := mockAPI()
 	defer srv.Close()
@@ -395,7 +851,7 @@ func TestClientCreateInvite(t *testing.T) {
 	c := NewClient(srv.URL)
 	c.Token = "mc_testkey"
 
-	resp, err := c.CreateInvite(models.InviteRequest{Email: "new@example.com", Role: models.OrgRoleMember})
+	resp, err := c.CreateInvite(models.InviteRequest{Email: "new@example.com", Role: models.OrgRoleDeveloper})
 	if err != nil {
 		t.Fatalf("unexpected error: %v", err)
 	}
@@ -439,6 +895,102 @@ func TestClientRevokeInvite(t *testing.T) {
 	}
 }
 
+func TestClientDeleteOrganization(t *testing.T) {
+	srv := mockAPI()
+	defer srv.Close()
+
+	c := NewClient(srv.URL)
+	c.Token = "mc_testkey"
+
+	if err := c.DeleteOrganization(); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+}
+
+func TestClientMembers(t *testing.T) {
+	srv := mockAPI()
+	defer srv.Close()
+
+	c := NewClient(srv.URL)
+	c.Token = "mc_testkey"
+
+	members, err := c.ListMembers()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(members) != 2 || members[1].Email != "bob@example.com" {
+		t.Fatalf("unexpected members: %+v", members)
+	}
+
+	member, err := c.UpdateMemberRole("user-2", models.OrgRoleAdmin)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if member.UserID != "user-2" || member.Role != models.OrgRoleAdmin {
+		t.Fatalf("unexpected member: %+v", member)
+	}
+
+	if err := c.RemoveMember("user-2"); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	err = c.RemoveMember("user-1")
+	apiErr, ok := err.(*APIError)
+	if !ok || apiErr.StatusCode != http.StatusConflict {
+		t.Fatalf("expected 409 APIError, got %v", err)
+	}
+}
+
+func TestClientServiceAccounts(t *testing.T) {
+	srv := mockAPI()
+	defer srv.Close()
+
+	c := NewClient(srv.URL)
+	c.Token = "mc_testkey"
+
+	accounts, err := c.ListServiceAccounts()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(accounts) != 1 || accounts[0].Name != "ci" {
+		t.Fatalf("unexpected service accounts: %+v", accounts)
+	}
+
+	sa, err := c.CreateServiceAccount(models.CreateServiceAccountRequest{Name: "deployer", Role: models.OrgRoleViewer})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if sa.ID != "sa-2" || sa.Name != "deployer" || sa.Role != models.OrgRoleViewer {
+		t.Fatalf("unexpected service account: %+v", sa)
+	}
+
+	sa, err = c.UpdateServiceAccountRole("sa-2", models.OrgRoleDeveloper)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if sa.Role != models.OrgRoleDeveloper {
+		t.Fatalf("expected role developer, got %s", sa.Role)
+	}
+
+	_, err = c.UpdateServiceAccountRole("sa-2", models.OrgRoleOwner)
+	apiErr, ok := err.(*APIError)
+	if !ok || apiErr.StatusCode != http.StatusBadRequest {
+		t.Fatalf("expected 400 APIError, got %v", err)
+	}
+
+	resp, err := c.CreateServiceAccountAPIKey("sa-2", models.CreateAPIKeyRequest{Name: "ci"})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if resp.APIKey == "" || resp.Info.ServiceAccountID != "sa-2" {
+		t.Fatalf("unexpected response: %+v", resp)
+	}
+
+	if err := c.DeleteServiceAccount("sa-2"); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+}
+
 func TestClientAcceptInvite(t *testing.T) {
 	srv := mockAPI()
 	defer srv.Close()
@@ -459,3 +1011,83 @@ func TestClientAcceptInvite(t *testing.T) {
 		t.Fatalf("expected org TestOrg, got %s", resp.Organization.Name)
 	}
 }
+
+func TestClientSecrets(t *testing.T) {
+	srv := mockAPI()
+	defer srv.Close()
+
+	c := NewClient(srv.URL)
+	c.Token = "mc_testkey"
+
+	secret, err := c.SetSecret("db-password", "hunter2")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if secret.Name != "db-password" {
+		t.Fatalf("expected name db-password, got %s", secret.Name)
+	}
+
+	if _, err := c.SetSecret("db-password", ""); err == nil {
+		t.Fatal("expected error for empty value")
+	}
+
+	secrets, err := c.ListSecrets()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(secrets) != 1 {
+		t.Fatalf("expected 1 secret, got %d", len(secrets))
+	}
+
+	if err := c.DeleteSecret("db-password"); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if err := c.DeleteSecret("missing"); err == nil {
+		t.Fatal("expected error for unknown secret")
+	}
+}
+
+func TestClientDomains(t *testing.T) {
+	srv := mockAPI()
+	defer srv.Close()
+
+	c := NewClient(srv.URL)
+	domain, err := c.CreateDomain(models.CreateDomainRequest{Hostname: "www.example.com", ServiceID: "svc-1"})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if domain.ChallengeRecord() != "_maxcloud-challenge.www.example.com" {
+		t.Fatalf("unexpected challenge record %q", domain.ChallengeRecord())
+	}
+
+	verified, err := c.VerifyDomain(domain.ID)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if verified.Status != models.DomainStatusProvisioning || verified.VerifiedAt == nil {
+		t.Fatalf("expected verified domain, got %+v", verified)
+	}
+
+	domains, err := c.ListDomains()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(domains) != 1 || domains[0].Hostname != "www.example.com" {
+		t.Fatalf("unexpected domains: %+v", domains)
+	}
+
+	if err := c.DeleteDomain(domain.ID); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	err = c.DeleteDomain(domain.ID)
+	apiErr, ok := err.(*APIError)
+	if !ok || apiErr.StatusCode != 404 {
+		t.Fatalf("expected 404 APIError, got %v", err)
+	}
+
+	_, err = c.CreateDomain(models.CreateDomainRequest{Hostname: "www.example.com", ServiceID: "svc-42"})
+	apiErr, ok = err.(*APIError)
+	if !ok || apiErr.StatusCode != 400 {
+		t.Fatalf("expected 400 APIError, got %v", err)
+	}
+}
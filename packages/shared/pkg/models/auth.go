@@ -1,12 +1,17 @@
 package models
 
-import "time"
+import (
+	"slices"
+	"time"
+)
 
 // Organization repräsentiert einen Mandanten (Tenant) in max-cloud.
 type Organization struct {
-	ID        string    `json:"id"`
-	Name      string    `json:"name"`
-	CreatedAt time.Time `json:"created_at"`
+	ID   string `json:"id"`
+	Name string `json:"name"`
+	// MaxScaleLimit ist die Obergrenze für MaxScale aller Services der Organisation.
+	MaxScaleLimit int       `json:"max_scale_limit"`
+	CreatedAt     time.Time `json:"created_at"`
 }
 
 // User repräsentiert einen registrierten Benutzer.
@@ -20,20 +25,95 @@ type User struct {
 type OrgRole string
 
 const (
-	OrgRoleAdmin  OrgRole = "admin"
-	OrgRoleMember OrgRole = "member"
+	// OrgRoleOwner darf alles, inklusive Löschen der Organisation und Vergabe der Owner-Rolle.
+	OrgRoleOwner OrgRole = "owner"
+	// OrgRoleAdmin verwaltet Mitglieder, Secrets und Services.
+	OrgRoleAdmin OrgRole = "admin"
+	// OrgRoleDeveloper deployt Services und pusht Images, löscht aber nichts.
+	OrgRoleDeveloper OrgRole = "developer"
+	// OrgRoleViewer hat nur lesenden Zugriff.
+	OrgRoleViewer OrgRole = "viewer"
 )
 
+// Permission ist eine einzelne Berechtigung innerhalb einer Organisation. Dieselben Werte
+// dienen als Scopes, auf die ein API-Key beschränkt werden kann.
+type Permission string
+
+const (
+	// PermissionRead erlaubt Lesen von Services, Revisionen, Domains, Secrets und Mitgliedern.
+	PermissionRead Permission = "services:read"
+	// PermissionDeploy erlaubt Erstellen, Ändern und Zurückrollen von Services und Domains.
+	PermissionDeploy Permission = "services:deploy"
+	// PermissionDelete erlaubt Löschen von Services und Domains.
+	PermissionDelete Permission = "services:delete"
+	// PermissionManageSecrets erlaubt Setzen und Löschen von Secrets.
+	PermissionManageSecrets Permission = "secrets:write"
+	// PermissionManageMembers erlaubt Einladungen sowie Ändern und Entfernen von Mitgliedern.
+	PermissionManageMembers Permission = "members:manage"
+	// PermissionReadLogs erlaubt Lesen von Logs und Events.
+	PermissionReadLogs Permission = "logs:read"
+	// PermissionPushImages erlaubt Pushen von Images in die Registry.
+	PermissionPushImages Permission = "registry:push"
+	// PermissionManageOrg erlaubt Löschen der Organisation.
+	PermissionManageOrg Permission = "org:manage"
+)
+
+// rolePermissions ist die zentrale Berechtigungsmatrix.
+var rolePermissions = map[OrgRole][]Permission{
+	OrgRoleOwner: {
+		PermissionRead, PermissionDeploy, PermissionDelete, PermissionManageSecrets,
+		PermissionManageMembers, PermissionReadLogs, PermissionPushImages, PermissionManageOrg,
+	},
+	OrgRoleAdmin: {
+		PermissionRead, PermissionDeploy, PermissionDelete, PermissionManageSecrets,
+		PermissionManageMembers, PermissionReadLogs, PermissionPushImages,
+	},
+	OrgRoleDeveloper: {PermissionRead, PermissionDeploy, PermissionReadLogs, PermissionPushImages},
+	OrgRoleViewer:    {PermissionRead, PermissionReadLogs},
+}
+
+// Valid prüft, ob die Rolle bekannt ist.
+func (r OrgRole) Valid() bool {
+	_, ok := rolePermissions[r]
+	return ok
+}
+
+// Can prüft, ob die Rolle die Berechtigung besitzt.
+func (r OrgRole) Can(p Permission) bool {
+	return slices.Contains(rolePermissions[r], p)
+}
+
+// Valid prüft, ob die Berechtigung bekannt ist. Der Owner besitzt alle Berechtigungen.
+func (p Permission) Valid() bool {
+	return OrgRoleOwner.Can(p)
+}
+
 // APIKeyInfo enthält Metadaten zu einem API-Key (ohne den Schlüssel selbst).
+// Ein Key gehört entweder einem User oder einem Service-Account (ServiceAccountID gesetzt).
+// Scopes beschränkt den Key auf diese Berechtigungen, leer = alle Berechtigungen der Rolle.
+// LifetimeSeconds ist die bei der Erstellung gewählte Laufzeit (0 = ohne Ablauf), die ein
+// Nachfolger übernimmt. RotatedAt ist gesetzt, sobald der Key rotiert wurde.
 type APIKeyInfo struct {
-	ID         string     `json:"id"`
-	Prefix     string     `json:"prefix"`
-	Name       string     `json:"name"`
-	OrgID      string     `json:"org_id"`
-	UserID     string     `json:"user_id"`
-	CreatedAt  time.Time  `json:"created_at"`
-	ExpiresAt  *time.Time `json:"expires_at,omitempty"`
-	LastUsedAt *time.Time `json:"last_used_at,omitempty"`
+	ID               string       `json:"id"`
+	Prefix           string       `json:"prefix"`
+	Name             string       `json:"name"`
+	OrgID            string       `json:"org_id"`
+	UserID           string       `json:"user_id"`
+	ServiceAccountID string       `json:"service_account_id,omitempty"`
+	Scopes           []Permission `json:"scopes,omitempty"`
+	CreatedAt        time.Time    `json:"created_at"`
+	ExpiresAt        *time.Time   `json:"expires_at,omitempty"`
+	LifetimeSeconds  int64        `json:"lifetime_seconds,omitempty"`
+	RotatedAt        *time.Time   `json:"rotated_at,omitempty"`
+	LastUsedAt       *time.Time   `json:"last_used_at,omitempty"`
+}
+
+// OwnerID gibt die ID des Besitzers zurück: den Service-Account oder den User.
+func (k APIKeyInfo) OwnerID() string {
+	if k.ServiceAccountID != "" {
+		return k.ServiceAccountID
+	}
+	return k.UserID
 }
 
 // RegisterRequest ist der Payload für die Registrierung.
@@ -52,6 +132,9 @@ type RegisterResponse struct {
 // CreateAPIKeyRequest ist der Payload zum Erstellen eines neuen API-Keys.
 type CreateAPIKeyRequest struct {
 	Name string `json:"name"`
+	// Scopes beschränkt den Key auf diese Berechtigungen, leer = alle Berechtigungen der Rolle.
+	Scopes    []Permission `json:"scopes,omitempty"`
+	ExpiresAt *time.Time   `json:"expires_at,omitempty"`
 }
 
 // CreateAPIKeyResponse enthält den neuen API-Key (einmalig sichtbar).
@@ -60,11 +143,57 @@ type CreateAPIKeyResponse struct {
 	Info   APIKeyInfo `json:"info"`
 }
 
+// DefaultAPIKeyGracePeriodSeconds ist die Zeit, die ein rotierter Key noch gültig bleibt.
+const DefaultAPIKeyGracePeriodSeconds = 24 * 60 * 60
+
+// MaxAPIKeyGracePeriodSeconds begrenzt die Übergangszeit einer Rotation.
+const MaxAPIKeyGracePeriodSeconds = 30 * 24 * 60 * 60
+
+// RotateAPIKeyRequest ist der Payload zum Rotieren eines API-Keys.
+// GracePeriodSeconds gibt an, wie lange der alte Key gültig bleibt, 0 = Default.
+type RotateAPIKeyRequest struct {
+	GracePeriodSeconds int `json:"grace_period_seconds,omitempty"`
+}
+
+// RotatedExpiry gibt das Ablaufdatum eines Nachfolgers zurück, der bei now ausgestellt wird.
+// Der Nachfolger erhält die ursprüngliche Laufzeit des Keys, nicht die durch eine Rotation
+// verkürzte. Keys ohne Ablauf bleiben ohne Ablauf.
+func (k APIKeyInfo) RotatedExpiry(now time.Time) *time.Time {
+	if k.LifetimeSeconds == 0 {
+		return nil
+	}
+	expiresAt := now.Add(time.Duration(k.LifetimeSeconds) * time.Second)
+	return &expiresAt
+}
+
+// APIKeyLifetimeSeconds gibt die Laufzeit eines Keys zurück, der bei now mit Ablauf expiresAt
+// ausgestellt wird, 0 = ohne Ablauf.
+func APIKeyLifetimeSeconds(now time.Time, expiresAt *time.Time) int64 {
+	if expiresAt == nil {
+		return 0
+	}
+	return max(1, int64(expiresAt.Sub(now).Round(time.Second)/time.Second))
+}
+
 // AuthInfo enthält Informationen über den aktuell authentifizierten Benutzer.
+// Bei Service-Accounts ist ServiceAccount statt User gesetzt.
 type AuthInfo struct {
-	User         User         `json:"user"`
-	Organization Organization `json:"organization"`
-	Role         OrgRole      `json:"role"`
+	User           User            `json:"user"`
+	ServiceAccount *ServiceAccount `json:"service_account,omitempty"`
+	Organization   Organization    `json:"organization"`
+	Role           OrgRole         `json:"role"`
+}
+
+// OrgMember ist ein Mitglied einer Organisation mit seiner Rolle.
+type OrgMember struct {
+	UserID string  `json:"user_id"`
+	Email  string  `json:"email"`
+	Role   OrgRole `json:"role"`
+}
+
+// UpdateMemberRoleRequest ist der Payload zum Ändern der Rolle eines Mitglieds.
+type UpdateMemberRoleRequest struct {
+	Role OrgRole `json:"role"`
 }
 
 // InviteStatus definiert den Status einer Einladung.
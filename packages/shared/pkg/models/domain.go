@@ -0,0 +1,57 @@
+package models
+
+import (
+	"regexp"
+	"time"
+)
+
+// DomainStatus represents the lifecycle state of a custom domain.
+type DomainStatus string
+
+const (
+	// DomainStatusPendingVerification waits for the TXT challenge record.
+	DomainStatusPendingVerification DomainStatus = "pending_verification"
+	// DomainStatusProvisioning is verified; routing and certificate are being set up.
+	DomainStatusProvisioning DomainStatus = "provisioning"
+	DomainStatusReady        DomainStatus = "ready"
+	DomainStatusFailed       DomainStatus = "failed"
+)
+
+// DomainChallengePrefix is prepended to the hostname to form the name of the
+// TXT record that proves ownership of a domain.
+const DomainChallengePrefix = "_maxcloud-challenge."
+
+// DomainHostnamePattern matches fully qualified lowercase hostnames with at least two labels.
+var DomainHostnamePattern = regexp.MustCompile(`^([a-z0-9]([a-z0-9-]{0,61}[a-z0-9])?\.)+[a-z]{2,63}$`)
+
+// MaxHostnameLength is the maximum length of a hostname in DNS.
+const MaxHostnameLength = 253
+
+// Domain maps a custom hostname to a service of the same organization.
+type Domain struct {
+	ID        string `json:"id"`
+	OrgID     string `json:"org_id,omitempty"`
+	ServiceID string `json:"service_id"`
+	Hostname  string `json:"hostname"`
+	// VerificationToken must be published as TXT record at ChallengeRecord().
+	VerificationToken string       `json:"verification_token"`
+	Status            DomainStatus `json:"status"`
+	// CertificateReady reports whether a TLS certificate has been issued for the hostname.
+	CertificateReady bool `json:"certificate_ready"`
+	// Message explains a failed or incomplete provisioning.
+	Message    string     `json:"message,omitempty"`
+	VerifiedAt *time.Time `json:"verified_at,omitempty"`
+	CreatedAt  time.Time  `json:"created_at"`
+	UpdatedAt  time.Time  `json:"updated_at"`
+}
+
+// ChallengeRecord returns the DNS name of the TXT record used for verification.
+func (d Domain) ChallengeRecord() string {
+	return DomainChallengePrefix + d.Hostname
+}
+
+// CreateDomainRequest is the payload for adding a custom domain.
+type CreateDomainRequest struct {
+	Hostname  string `json:"hostname"`
+	ServiceID string `json:"service_id"`
+}
@@ -0,0 +1,40 @@
+package models
+
+import "time"
+
+// ServiceEventType classifies an entry in the event history of a service.
+type ServiceEventType string
+
+const (
+	ServiceEventCreated       ServiceEventType = "created"
+	ServiceEventUpdated       ServiceEventType = "updated"
+	ServiceEventScaled        ServiceEventType = "scaled"
+	ServiceEventDeployed      ServiceEventType = "deployed"
+	ServiceEventStatusChanged ServiceEventType = "status_changed"
+	ServiceEventFailed        ServiceEventType = "failed"
+	ServiceEventDrift         ServiceEventType = "drift_detected"
+	ServiceEventDeleted       ServiceEventType = "deleted"
+	// ServiceEventKubernetes marks events Kubernetes reported for the pods of a service.
+	// They are read from the cluster on request and never stored.
+	ServiceEventKubernetes ServiceEventType = "kubernetes"
+)
+
+// DefaultEventLimit is the number of events returned when no limit is given.
+const DefaultEventLimit = 100
+
+// MaxEventLimit caps the number of events returned by a single request.
+const MaxEventLimit = 1000
+
+// ServiceEvent is an entry in the event history of a service. Actor identifies who
+// triggered the event ("user:<id>" or "service-account:<id>"), it is empty for
+// events recorded by the reconciler.
+type ServiceEvent struct {
+	ID        string           `json:"id,omitempty"`
+	ServiceID string           `json:"service_id"`
+	OrgID     string           `json:"org_id,omitempty"`
+	Type      ServiceEventType `json:"type"`
+	Reason    string           `json:"reason,omitempty"`
+	Message   string           `json:"message"`
+	Actor     string           `json:"actor,omitempty"`
+	CreatedAt time.Time        `json:"created_at"`
+}
@@ -1,23 +1,124 @@
 package models
 
-import "time"
+import (
+	"fmt"
+	"time"
+)
 
 // Service represents a deployed container service.
 type Service struct {
-	ID        string            `json:"id"`
-	OrgID     string            `json:"org_id,omitempty"`
-	Name      string            `json:"name"`
-	Image     string            `json:"image"`
-	Status    ServiceStatus     `json:"status"`
-	URL       string            `json:"url"`
-	Port      int               `json:"port,omitempty"`
-	Command   []string          `json:"command,omitempty"`
-	Args      []string          `json:"args,omitempty"`
-	EnvVars   map[string]string `json:"env_vars,omitempty"`
-	MinScale  int               `json:"min_scale"`
-	MaxScale  int               `json:"max_scale"`
-	CreatedAt time.Time         `json:"created_at"`
-	UpdatedAt time.Time         `json:"updated_at"`
+	ID         string            `json:"id"`
+	OrgID      string            `json:"org_id,omitempty"`
+	Name       string            `json:"name"`
+	Image      string            `json:"image"`
+	Status     ServiceStatus     `json:"status"`
+	URL        string            `json:"url"`
+	Port       int               `json:"port,omitempty"`
+	Command    []string          `json:"command,omitempty"`
+	Args       []string          `json:"args,omitempty"`
+	EnvVars    map[string]string `json:"env_vars,omitempty"`
+	MinScale   int               `json:"min_scale"`
+	MaxScale   int               `json:"max_scale"`
+	CPU        string            `json:"cpu,omitempty"`
+	Memory     string            `json:"memory,omitempty"`
+	Generation int64             `json:"generation"`
+	// Autoscaling settings; zero values use the Knative defaults.
+	ContainerConcurrency int `json:"container_concurrency,omitempty"`
+	AutoscalingTarget    int `json:"autoscaling_target,omitempty"`
+	ScaleDownDelay       int `json:"scale_down_delay_seconds,omitempty"`
+	ScaleToZeroRetention int `json:"scale_to_zero_retention_seconds,omitempty"`
+	// Request timeouts in seconds; zero values use the Knative defaults.
+	TimeoutSeconds              int `json:"timeout_seconds,omitempty"`
+	ResponseStartTimeoutSeconds int `json:"response_start_timeout_seconds,omitempty"`
+	IdleTimeoutSeconds          int `json:"idle_timeout_seconds,omitempty"`
+	// Probes configures health checks for the container; nil means no probes.
+	Probes *Probes `json:"probes,omitempty"`
+	// SecretEnv maps environment variable names to secrets of the organization.
+	SecretEnv map[string]string `json:"secret_env,omitempty"`
+	// LatestRevision is the name of the revision holding the current spec.
+	LatestRevision string `json:"latest_revision,omitempty"`
+	// Traffic is the traffic split between revisions. Empty means 100% to LatestRevision.
+	Traffic []TrafficTarget `json:"traffic,omitempty"`
+	// TagURLs maps traffic tags to their stable preview URLs, as reported by the orchestrator.
+	TagURLs map[string]string `json:"tag_urls,omitempty"`
+	// StatusReason and StatusMessage explain the status as reported by the orchestrator,
+	// e.g. why a deployment failed. Both are empty once the service is ready.
+	StatusReason  string `json:"status_reason,omitempty"`
+	StatusMessage string `json:"status_message,omitempty"`
+	// ReconcileAttempts counts consecutive failed reconcile attempts; LastError holds the latest failure.
+	// Both are reset once the service reconciles successfully.
+	ReconcileAttempts int       `json:"reconcile_attempts,omitempty"`
+	LastError         string    `json:"last_error,omitempty"`
+	CreatedAt         time.Time `json:"created_at"`
+	UpdatedAt         time.Time `json:"updated_at"`
+}
+
+// TrafficTarget routes a percentage of requests to a named revision.
+// A Tag gives the revision an additional stable URL, even at 0 percent.
+type TrafficTarget struct {
+	RevisionName string `json:"revision_name"`
+	Percent      int    `json:"percent"`
+	Tag          string `json:"tag,omitempty"`
+}
+
+// SetTrafficRequest is the payload for changing the traffic split of a service.
+// An empty Targets list routes all traffic to the latest revision.
+type SetTrafficRequest struct {
+	Targets []TrafficTarget `json:"targets"`
+}
+
+// Probe types supported by Probe.Type.
+const (
+	ProbeTypeHTTP = "http"
+	ProbeTypeTCP  = "tcp"
+	ProbeTypeGRPC = "grpc"
+)
+
+// Probe is a single health check. An empty Type means ProbeTypeHTTP; Path is
+// only used for HTTP probes. A Port of 0 probes the container port. Zero
+// timings and thresholds use the Kubernetes defaults.
+type Probe struct {
+	Type                string `json:"type,omitempty"`
+	Path                string `json:"path,omitempty"`
+	Port                int    `json:"port,omitempty"`
+	InitialDelaySeconds int    `json:"initial_delay_seconds,omitempty"`
+	PeriodSeconds       int    `json:"period_seconds,omitempty"`
+	TimeoutSeconds      int    `json:"timeout_seconds,omitempty"`
+	FailureThreshold    int    `json:"failure_threshold,omitempty"`
+	SuccessThreshold    int    `json:"success_threshold,omitempty"`
+}
+
+// Probes groups the health checks of a service. The startup probe holds off
+// the other probes until it succeeds, which suits slow-booting services.
+type Probes struct {
+	Liveness  *Probe `json:"liveness,omitempty"`
+	Readiness *Probe `json:"readiness,omitempty"`
+	Startup   *Probe `json:"startup,omitempty"`
+}
+
+// IsZero reports whether no probe is configured.
+func (p *Probes) IsZero() bool {
+	return p == nil || (p.Liveness == nil && p.Readiness == nil && p.Startup == nil)
+}
+
+// Clone returns a deep copy of p. Zero probes are returned as nil.
+func (p *Probes) Clone() *Probes {
+	if p.IsZero() {
+		return nil
+	}
+	return &Probes{
+		Liveness:  cloneProbe(p.Liveness),
+		Readiness: cloneProbe(p.Readiness),
+		Startup:   cloneProbe(p.Startup),
+	}
+}
+
+func cloneProbe(p *Probe) *Probe {
+	if p == nil {
+		return nil
+	}
+	c := *p
+	return &c
 }
 
 // ServiceStatus represents the current state of a service.
@@ -32,13 +133,64 @@ const (
 
 // Revision represents an immutable snapshot of a service configuration.
 type Revision struct {
-	ID        string    `json:"id"`
-	ServiceID string    `json:"service_id"`
-	Image     string    `json:"image"`
-	Traffic   int       `json:"traffic"`
-	CreatedAt time.Time `json:"created_at"`
+	ID                          string            `json:"id"`
+	ServiceID                   string            `json:"service_id"`
+	Name                        string            `json:"name"`
+	Generation                  int64             `json:"generation"`
+	Image                       string            `json:"image"`
+	Port                        int               `json:"port,omitempty"`
+	Command                     []string          `json:"command,omitempty"`
+	Args                        []string          `json:"args,omitempty"`
+	EnvVars                     map[string]string `json:"env_vars,omitempty"`
+	MinScale                    int               `json:"min_scale"`
+	MaxScale                    int               `json:"max_scale"`
+	CPU                         string            `json:"cpu,omitempty"`
+	Memory                      string            `json:"memory,omitempty"`
+	Traffic                     int               `json:"traffic"`
+	ContainerConcurrency        int               `json:"container_concurrency,omitempty"`
+	AutoscalingTarget           int               `json:"autoscaling_target,omitempty"`
+	ScaleDownDelay              int               `json:"scale_down_delay_seconds,omitempty"`
+	ScaleToZeroRetention        int               `json:"scale_to_zero_retention_seconds,omitempty"`
+	TimeoutSeconds              int               `json:"timeout_seconds,omitempty"`
+	ResponseStartTimeoutSeconds int               `json:"response_start_timeout_seconds,omitempty"`
+	IdleTimeoutSeconds          int               `json:"idle_timeout_seconds,omitempty"`
+	Probes                      *Probes           `json:"probes,omitempty"`
+	SecretEnv                   map[string]string `json:"secret_env,omitempty"`
+	CreatedAt                   time.Time         `json:"created_at"`
+}
+
+// RevisionName returns the name of the revision created for the given service generation.
+func RevisionName(serviceName string, generation int64) string {
+	return fmt.Sprintf("%s-%05d", serviceName, generation)
 }
 
+// Default resources for services that do not request a specific tier.
+const (
+	DefaultCPU    = "1"
+	DefaultMemory = "512Mi"
+)
+
+// Autoscaling defaults and bounds.
+const (
+	DefaultMaxScale = 10
+	// DefaultMaxScaleLimit is the per-organization ceiling for MaxScale unless configured otherwise.
+	DefaultMaxScaleLimit = 20
+	// MaxContainerConcurrency bounds ContainerConcurrency and AutoscalingTarget.
+	MaxContainerConcurrency = 1000
+	// MaxScaleDelay bounds ScaleDownDelay and ScaleToZeroRetention (seconds).
+	MaxScaleDelay = 3600
+	// MaxTimeoutSeconds bounds all request timeouts and probe timings.
+	MaxTimeoutSeconds = 3600
+	// MaxProbeThreshold bounds the failure and success thresholds of probes.
+	MaxProbeThreshold = 100
+)
+
+// CPUTiers lists the allowed CPU values (Kubernetes quantities, 1 = one vCPU).
+var CPUTiers = []string{"250m", "500m", "1", "2", "4"}
+
+// MemoryTiers lists the allowed memory values (Kubernetes quantities).
+var MemoryTiers = []string{"256Mi", "512Mi", "1Gi", "2Gi", "4Gi", "8Gi"}
+
 // DeployRequest is the payload for deploying a new service.
 type DeployRequest struct {
 	Name    string            `json:"name"`
@@ -47,6 +199,96 @@ type DeployRequest struct {
 	Command []string          `json:"command,omitempty"`
 	Args    []string          `json:"args,omitempty"`
 	EnvVars map[string]string `json:"env_vars,omitempty"`
+	// CPU and Memory select a resource tier (see CPUTiers and MemoryTiers).
+	// Empty values fall back to DefaultCPU and DefaultMemory.
+	CPU    string `json:"cpu,omitempty"`
+	Memory string `json:"memory,omitempty"`
+	// MinScale and MaxScale bound the number of instances. A MaxScale of 0
+	// means DefaultMaxScale.
+	MinScale int `json:"min_scale,omitempty"`
+	MaxScale int `json:"max_scale,omitempty"`
+	// ContainerConcurrency limits concurrent requests per instance (0 = unlimited).
+	// AutoscalingTarget is the concurrency the autoscaler aims for per instance.
+	// ScaleDownDelay and ScaleToZeroRetention are given in seconds.
+	ContainerConcurrency int `json:"container_concurrency,omitempty"`
+	AutoscalingTarget    int `json:"autoscaling_target,omitempty"`
+	ScaleDownDelay       int `json:"scale_down_delay_seconds,omitempty"`
+	ScaleToZeroRetention int `json:"scale_to_zero_retention_seconds,omitempty"`
+	// TimeoutSeconds is the maximum duration of a request. ResponseStartTimeoutSeconds
+	// bounds the time until the first response byte, IdleTimeoutSeconds the time
+	// between bytes (useful for streaming). Zero values use the Knative defaults.
+	TimeoutSeconds              int `json:"timeout_seconds,omitempty"`
+	ResponseStartTimeoutSeconds int `json:"response_start_timeout_seconds,omitempty"`
+	IdleTimeoutSeconds          int `json:"idle_timeout_seconds,omitempty"`
+	// Probes configures liveness, readiness and startup checks.
+	Probes *Probes `json:"probes,omitempty"`
+	// SecretEnv maps environment variable names to secret names. The values
+	// are injected from the encrypted secrets store at deploy time.
+	SecretEnv map[string]string `json:"secret_env,omitempty"`
+	// Tag assigns a traffic tag to the first revision, giving it a preview URL.
+	Tag string `json:"tag,omitempty"`
+}
+
+// UpdateServiceRequest is the payload for partially updating a service.
+// Nil fields are left unchanged. EnvVars and SecretEnv are merged into the
+// existing variables; RemoveEnv lists keys to delete from both. If Generation is set, the update
+// is rejected when the service has been modified in the meantime. If Tag is
+// set, the new revision receives 0% traffic and is only reachable via its tag
+// URL; the current traffic split stays in place until it is promoted. Without
+// a tag, an existing traffic split is only discarded if ResetTraffic is set.
+type UpdateServiceRequest struct {
+	Image     *string           `json:"image,omitempty"`
+	Port      *int              `json:"port,omitempty"`
+	Command   *[]string         `json:"command,omitempty"`
+	Args      *[]string         `json:"args,omitempty"`
+	EnvVars   map[string]string `json:"env_vars,omitempty"`
+	SecretEnv map[string]string `json:"secret_env,omitempty"`
+	RemoveEnv []string          `json:"remove_env,omitempty"`
+	MinScale  *int              `json:"min_scale,omitempty"`
+	MaxScale  *int              `json:"max_scale,omitempty"`
+	CPU       *string           `json:"cpu,omitempty"`
+	Memory    *string           `json:"memory,omitempty"`
+
+	ContainerConcurrency *int `json:"container_concurrency,omitempty"`
+	AutoscalingTarget    *int `json:"autoscaling_target,omitempty"`
+	ScaleDownDelay       *int `json:"scale_down_delay_seconds,omitempty"`
+	ScaleToZeroRetention *int `json:"scale_to_zero_retention_seconds,omitempty"`
+
+	TimeoutSeconds              *int `json:"timeout_seconds,omitempty"`
+	ResponseStartTimeoutSeconds *int `json:"response_start_timeout_seconds,omitempty"`
+	IdleTimeoutSeconds          *int `json:"idle_timeout_seconds,omitempty"`
+
+	// Probes replaces all probes of the service; an empty object removes them.
+	Probes *Probes `json:"probes,omitempty"`
+
+	Generation   int64  `json:"generation,omitempty"`
+	Tag          string `json:"tag,omitempty"`
+	ResetTraffic bool   `json:"reset_traffic,omitempty"`
+}
+
+// RollbackRequest is the payload for rolling a service back to a previous revision.
+// Revision accepts a revision ID or name; if empty, the revision before the
+// current one is used.
+type RollbackRequest struct {
+	Revision string `json:"revision,omitempty"`
+}
+
+// ServiceEnv is the environment of a service: plain values and secret references.
+type ServiceEnv struct {
+	EnvVars   map[string]string `json:"env_vars"`
+	SecretEnv map[string]string `json:"secret_env"`
+}
+
+// UpdateEnvRequest is the payload for changing the environment of a service.
+// Set merges values into the existing variables (replacing secret references
+// of the same name), Unset removes variables and secret references. If the
+// service has a traffic split, the change is rejected unless ResetTraffic
+// routes all traffic to the new revision.
+type UpdateEnvRequest struct {
+	Set          map[string]string `json:"set,omitempty"`
+	Unset        []string          `json:"unset,omitempty"`
+	Generation   int64             `json:"generation,omitempty"`
+	ResetTraffic bool              `json:"reset_traffic,omitempty"`
 }
 
 // LogEntry represents a single log line from a service.
@@ -0,0 +1,28 @@
+package models
+
+import (
+	"regexp"
+	"time"
+)
+
+// Secret beschreibt einen verschlüsselt gespeicherten Wert einer Organisation.
+// Der Klartext wird von der API nie zurückgegeben.
+type Secret struct {
+	ID        string    `json:"id"`
+	OrgID     string    `json:"org_id"`
+	Name      string    `json:"name"`
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+// SetSecretRequest ist der Payload zum Anlegen oder Überschreiben eines Secrets.
+type SetSecretRequest struct {
+	Value string `json:"value"`
+}
+
+// MaxSecretSize begrenzt die Größe eines Secret-Werts in Bytes.
+const MaxSecretSize = 64 * 1024
+
+// SecretNamePattern beschreibt gültige Secret-Namen. Sie werden als Schlüssel
+// im Kubernetes-Secret verwendet und müssen daher dessen Zeichensatz einhalten.
+var SecretNamePattern = regexp.MustCompile(`^[A-Za-z0-9][A-Za-z0-9_.-]{0,62}$`)
@@ -0,0 +1,36 @@
+package models
+
+import (
+	"regexp"
+	"time"
+)
+
+// ServiceAccount ist eine Identität ohne E-Mail und Login, die einer Organisation gehört.
+// Ihre API-Keys bleiben gültig, wenn das Mitglied, das sie angelegt hat, die Organisation verlässt.
+type ServiceAccount struct {
+	ID        string    `json:"id"`
+	OrgID     string    `json:"org_id"`
+	Name      string    `json:"name"`
+	Role      OrgRole   `json:"role"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// CreateServiceAccountRequest ist der Payload zum Anlegen eines Service-Accounts.
+type CreateServiceAccountRequest struct {
+	Name string  `json:"name"`
+	Role OrgRole `json:"role"`
+}
+
+// UpdateServiceAccountRequest ist der Payload zum Ändern der Rolle eines Service-Accounts.
+type UpdateServiceAccountRequest struct {
+	Role OrgRole `json:"role"`
+}
+
+// ServiceAccountNamePattern beschreibt gültige Namen von Service-Accounts.
+var ServiceAccountNamePattern = regexp.MustCompile(`^[a-z0-9]([a-z0-9-]{0,61}[a-z0-9])?$`)
+
+// ValidServiceAccountRole prüft, ob ein Service-Account die Rolle erhalten darf.
+// Die Owner-Rolle bleibt Mitgliedern vorbehalten.
+func ValidServiceAccountRole(role OrgRole) bool {
+	return role.Valid() && role != OrgRoleOwner
+}